@@ -16,6 +16,8 @@ package fileexporter
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 )
@@ -26,8 +28,45 @@ type Config struct {
 
 	// Path of the file to write to. Path is relative to current directory.
 	Path string `mapstructure:"path"`
+
+	// Rotation defines an option about rotation of telemetry files, rotation is disabled when not set.
+	Rotation *Rotation `mapstructure:"rotation"`
+
+	// FormatType define the data format of encoded telemetry data,
+	// options: "json" (default), "proto".
+	FormatType string `mapstructure:"format"`
+
+	// Compression applied to the rotated files, options: "" (default, no compression), "gzip", "zstd".
+	Compression string `mapstructure:"compression"`
+}
+
+// Rotation an option to rolling log files
+type Rotation struct {
+	// MaxMegabytes is the maximum size in megabytes of the file before it gets
+	// rotated. Zero disables size based rotation.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+
+	// MaxAge is the maximum time a file is written to before it gets rotated.
+	// Zero disables age based rotation.
+	MaxAge time.Duration `mapstructure:"max_age"`
+
+	// MaxBackups is the maximum number of rotated files to retain. Zero retains
+	// all of them.
+	MaxBackups int `mapstructure:"max_backups"`
+
+	// LocalTime determines if the time used for formatting the timestamps in
+	// backup files is the computer's local time. The default is to use UTC time.
+	LocalTime bool `mapstructure:"localtime"`
 }
 
+const (
+	formatTypeJSON  = "json"
+	formatTypeProto = "proto"
+
+	compressionGzip = "gzip"
+	compressionZstd = "zstd"
+)
+
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
@@ -35,6 +74,26 @@ func (cfg *Config) Validate() error {
 	if cfg.Path == "" {
 		return errors.New("path must be non-empty")
 	}
+	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto {
+		return fmt.Errorf("format type %q is not supported", cfg.FormatType)
+	}
+	if cfg.Compression != "" && cfg.Compression != compressionGzip && cfg.Compression != compressionZstd {
+		return fmt.Errorf("compression %q is not supported", cfg.Compression)
+	}
+	if cfg.Compression != "" && cfg.Rotation == nil {
+		return errors.New("compression is only supported when rotation is enabled")
+	}
+	if cfg.Rotation != nil {
+		if cfg.Rotation.MaxMegabytes < 0 {
+			return errors.New("rotation max_megabytes must not be negative")
+		}
+		if cfg.Rotation.MaxAge < 0 {
+			return errors.New("rotation max_age must not be negative")
+		}
+		if cfg.Rotation.MaxBackups < 0 {
+			return errors.New("rotation max_backups must not be negative")
+		}
+	}
 
 	return nil
 }
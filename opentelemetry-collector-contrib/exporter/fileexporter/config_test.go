@@ -17,6 +17,7 @@ package fileexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,5 +44,65 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "2")),
 			Path:             "./filename.json",
+			FormatType:       formatTypeJSON,
 		})
+
+	e2 := cfg.Exporters[config.NewIDWithName(typeStr, "3")]
+	assert.Equal(t, e2,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewIDWithName(typeStr, "3")),
+			Path:             "./filename.pb",
+			FormatType:       formatTypeProto,
+			Compression:      compressionZstd,
+			Rotation: &Rotation{
+				MaxMegabytes: 10,
+				MaxAge:       24 * time.Hour,
+				MaxBackups:   3,
+				LocalTime:    true,
+			},
+		})
+	assert.NoError(t, e2.Validate())
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			name: "valid",
+			cfg:  &Config{Path: "file.json", FormatType: formatTypeJSON},
+		},
+		{
+			name:    "unknown format",
+			cfg:     &Config{Path: "file.json", FormatType: "binary"},
+			wantErr: `format type "binary" is not supported`,
+		},
+		{
+			name:    "unknown compression",
+			cfg:     &Config{Path: "file.json", FormatType: formatTypeJSON, Compression: "lz4", Rotation: &Rotation{}},
+			wantErr: `compression "lz4" is not supported`,
+		},
+		{
+			name:    "compression without rotation",
+			cfg:     &Config{Path: "file.json", FormatType: formatTypeJSON, Compression: compressionGzip},
+			wantErr: "compression is only supported when rotation is enabled",
+		},
+		{
+			name:    "negative max_backups",
+			cfg:     &Config{Path: "file.json", FormatType: formatTypeJSON, Rotation: &Rotation{MaxBackups: -1}},
+			wantErr: "rotation max_backups must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
 }
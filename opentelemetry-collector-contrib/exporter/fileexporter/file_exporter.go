@@ -16,6 +16,7 @@ package fileexporter
 
 import (
 	"context"
+	"encoding/binary"
 	"io"
 	"os"
 	"sync"
@@ -31,12 +32,29 @@ var tracesMarshaler = otlp.NewJSONTracesMarshaler()
 var metricsMarshaler = otlp.NewJSONMetricsMarshaler()
 var logsMarshaler = otlp.NewJSONLogsMarshaler()
 
+// Marshaler configuration used for marshaling to length-delimited Protobuf.
+var tracesProtoMarshaler = otlp.NewProtobufTracesMarshaler()
+var metricsProtoMarshaler = otlp.NewProtobufMetricsMarshaler()
+var logsProtoMarshaler = otlp.NewProtobufLogsMarshaler()
+
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
-// in Protobuf-JSON format.
+// in Protobuf-JSON format, or as length-delimited Protobuf messages.
 type fileExporter struct {
-	path  string
-	file  io.WriteCloser
-	mutex sync.Mutex
+	path        string
+	formatType  string
+	rotation    *Rotation
+	compression string
+	file        io.WriteCloser
+	mutex       sync.Mutex
+}
+
+func newFileExporter(cfg *Config) *fileExporter {
+	return &fileExporter{
+		path:        cfg.Path,
+		formatType:  cfg.FormatType,
+		rotation:    cfg.Rotation,
+		compression: cfg.Compression,
+	}
 }
 
 func (e *fileExporter) Capabilities() consumer.Capabilities {
@@ -44,6 +62,13 @@ func (e *fileExporter) Capabilities() consumer.Capabilities {
 }
 
 func (e *fileExporter) ConsumeTraces(_ context.Context, td pdata.Traces) error {
+	if e.formatType == formatTypeProto {
+		buf, err := tracesProtoMarshaler.MarshalTraces(td)
+		if err != nil {
+			return err
+		}
+		return exportMessageAsBuffer(e, buf)
+	}
 	buf, err := tracesMarshaler.MarshalTraces(td)
 	if err != nil {
 		return err
@@ -52,6 +77,13 @@ func (e *fileExporter) ConsumeTraces(_ context.Context, td pdata.Traces) error {
 }
 
 func (e *fileExporter) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
+	if e.formatType == formatTypeProto {
+		buf, err := metricsProtoMarshaler.MarshalMetrics(md)
+		if err != nil {
+			return err
+		}
+		return exportMessageAsBuffer(e, buf)
+	}
 	buf, err := metricsMarshaler.MarshalMetrics(md)
 	if err != nil {
 		return err
@@ -60,6 +92,13 @@ func (e *fileExporter) ConsumeMetrics(_ context.Context, md pdata.Metrics) error
 }
 
 func (e *fileExporter) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
+	if e.formatType == formatTypeProto {
+		buf, err := logsProtoMarshaler.MarshalLogs(ld)
+		if err != nil {
+			return err
+		}
+		return exportMessageAsBuffer(e, buf)
+	}
 	buf, err := logsMarshaler.MarshalLogs(ld)
 	if err != nil {
 		return err
@@ -71,17 +110,28 @@ func exportMessageAsLine(e *fileExporter, buf []byte) error {
 	// Ensure only one write operation happens at a time.
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	if _, err := e.file.Write(buf); err != nil {
-		return err
-	}
-	if _, err := io.WriteString(e.file, "\n"); err != nil {
-		return err
-	}
-	return nil
+	// Write the message and the new line at once, so a rotation never splits a line.
+	_, err := e.file.Write(append(buf, '\n'))
+	return err
+}
+
+// exportMessageAsBuffer writes the message prefixed by its length as a 4 bytes big-endian integer.
+func exportMessageAsBuffer(e *fileExporter, buf []byte) error {
+	// Ensure only one write operation happens at a time.
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	data := make([]byte, 4, 4+len(buf))
+	binary.BigEndian.PutUint32(data, uint32(len(buf)))
+	_, err := e.file.Write(append(data, buf...))
+	return err
 }
 
 func (e *fileExporter) Start(context.Context, component.Host) error {
 	var err error
+	if e.rotation != nil {
+		e.file, err = newRotatingFile(e.path, *e.rotation, e.compression)
+		return err
+	}
 	e.file, err = os.OpenFile(e.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	return err
 }
@@ -14,10 +14,13 @@
 package fileexporter
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -112,6 +115,74 @@ func TestFileLogsExporterErrors(t *testing.T) {
 	assert.NoError(t, fe.Shutdown(context.Background()))
 }
 
+func TestFileExporterProtoFormat(t *testing.T) {
+	fe := &fileExporter{path: tempFileName(t), formatType: formatTypeProto}
+	require.NotNil(t, fe)
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+
+	buf, err := ioutil.ReadFile(fe.path)
+	require.NoError(t, err)
+
+	msgs := splitLengthDelimited(t, buf)
+	require.Len(t, msgs, 2)
+	gotTraces, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces(msgs[0])
+	assert.NoError(t, err)
+	assert.EqualValues(t, td, gotTraces)
+	gotLogs, err := otlp.NewProtobufLogsUnmarshaler().UnmarshalLogs(msgs[1])
+	assert.NoError(t, err)
+	assert.EqualValues(t, ld, gotLogs)
+}
+
+func TestFileExporterWithRotation(t *testing.T) {
+	dir := t.TempDir()
+	fe := newFileExporter(&Config{
+		Path:        filepath.Join(dir, "data.json"),
+		FormatType:  formatTypeJSON,
+		Rotation:    &Rotation{MaxMegabytes: 1, MaxBackups: 2},
+		Compression: compressionGzip,
+	})
+
+	md := testdata.GenerateMetricsTwoMetrics()
+	assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	buf, err := metricsMarshaler.MarshalMetrics(md)
+	require.NoError(t, err)
+	// Write enough data to rotate several times.
+	for i := 0; i < 4*megabyte/len(buf); i++ {
+		assert.NoError(t, fe.ConsumeMetrics(context.Background(), md))
+	}
+	assert.NoError(t, fe.Shutdown(context.Background()))
+
+	backups, err := filepath.Glob(filepath.Join(dir, "data-*.json.gz"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+
+	// Every line of the current file is a complete message.
+	current, err := ioutil.ReadFile(filepath.Join(dir, "data.json"))
+	require.NoError(t, err)
+	for _, line := range bytes.Split(bytes.TrimSpace(current), []byte("\n")) {
+		_, err = otlp.NewJSONMetricsUnmarshaler().UnmarshalMetrics(line)
+		assert.NoError(t, err)
+	}
+}
+
+func splitLengthDelimited(t *testing.T, buf []byte) [][]byte {
+	var msgs [][]byte
+	for len(buf) > 0 {
+		require.GreaterOrEqual(t, len(buf), 4)
+		size := int(binary.BigEndian.Uint32(buf))
+		require.GreaterOrEqual(t, len(buf), 4+size)
+		msgs = append(msgs, buf[4:4+size])
+		buf = buf[4+size:]
+	}
+	return msgs
+}
+
 // tempFileName provides a temporary file name for testing.
 func tempFileName(t *testing.T) string {
 	tmpfile, err := ioutil.TempFile("", "*.json")
@@ -3,10 +3,12 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileex
 go 1.17
 
 require (
+	github.com/klauspost/compress v1.13.5
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal v0.34.0
 	github.com/stretchr/testify v1.7.0
 	go.opentelemetry.io/collector v0.34.1-0.20210906070714-e676d678f9fd
 	go.opentelemetry.io/collector/model v0.34.1-0.20210906070714-e676d678f9fd
+	go.uber.org/multierr v1.6.0
 )
 
 require (
@@ -25,7 +27,6 @@ require (
 	go.opentelemetry.io/otel v1.0.0-RC3 // indirect
 	go.opentelemetry.io/otel/trace v1.0.0-RC3 // indirect
 	go.uber.org/atomic v1.9.0 // indirect
-	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.19.0 // indirect
 	golang.org/x/net v0.0.0-20210614182718-04defd469f4e // indirect
 	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
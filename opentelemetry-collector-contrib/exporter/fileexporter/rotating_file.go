@@ -0,0 +1,312 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/multierr"
+)
+
+const (
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+	megabyte         = 1024 * 1024
+)
+
+// rotatingFile is an io.WriteCloser that writes to the file at path and rotates it
+// once it grows over the configured size or gets older than the configured age.
+// Rotated files are renamed to <name>-<timestamp><ext>, optionally compressed,
+// and only the most recent MaxBackups of them are retained.
+//
+// Writes are not synchronized, the caller must ensure only one happens at a time.
+// A single Write is never split across files.
+type rotatingFile struct {
+	path        string
+	rotation    Rotation
+	compression string
+	now         func() time.Time
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// millCh notifies the background goroutine that compresses and
+	// removes the rotated files that there is work to do.
+	millCh chan struct{}
+	millWG sync.WaitGroup
+}
+
+func newRotatingFile(path string, rotation Rotation, compression string) (*rotatingFile, error) {
+	rf := &rotatingFile{
+		path:        path,
+		rotation:    rotation,
+		compression: compression,
+		now:         time.Now,
+		millCh:      make(chan struct{}, 1),
+	}
+
+	// Data from a previous run is kept as a backup instead of being truncated.
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		if err = rf.backupCurrent(); err != nil {
+			return nil, err
+		}
+	}
+	if err := rf.openNew(); err != nil {
+		return nil, err
+	}
+
+	rf.millWG.Add(1)
+	go rf.millLoop()
+	rf.notifyMill()
+	return rf, nil
+}
+
+// Write implements io.Writer.
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	if rf.shouldRotate(len(p)) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Close implements io.Closer, it waits for all the pending compressions and removals to finish.
+func (rf *rotatingFile) Close() error {
+	err := rf.file.Close()
+	close(rf.millCh)
+	rf.millWG.Wait()
+	return err
+}
+
+func (rf *rotatingFile) shouldRotate(writeLen int) bool {
+	if rf.size == 0 {
+		// Never rotate an empty file, it would only create empty backups.
+		return false
+	}
+	if rf.rotation.MaxMegabytes > 0 && rf.size+int64(writeLen) > int64(rf.rotation.MaxMegabytes)*megabyte {
+		return true
+	}
+	return rf.rotation.MaxAge > 0 && rf.now().Sub(rf.openedAt) >= rf.rotation.MaxAge
+}
+
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	if err := rf.backupCurrent(); err != nil {
+		return err
+	}
+	if err := rf.openNew(); err != nil {
+		return err
+	}
+	rf.notifyMill()
+	return nil
+}
+
+func (rf *rotatingFile) openNew() error {
+	file, err := os.OpenFile(rf.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	rf.file = file
+	rf.size = 0
+	rf.openedAt = rf.now()
+	return nil
+}
+
+// backupCurrent moves the file at path to a new timestamped backup name.
+func (rf *rotatingFile) backupCurrent() error {
+	t := rf.now()
+	if !rf.rotation.LocalTime {
+		t = t.UTC()
+	}
+	prefix, ext := rf.prefixAndExt()
+	backup := filepath.Join(filepath.Dir(rf.path), prefix+t.Format(backupTimeFormat)+ext)
+	for fileExists(backup) || fileExists(backup+compressedSuffix(rf.compression)) {
+		// Rotated more than once within the timestamp resolution.
+		t = t.Add(time.Millisecond)
+		backup = filepath.Join(filepath.Dir(rf.path), prefix+t.Format(backupTimeFormat)+ext)
+	}
+	if err := os.Rename(rf.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate file %q: %w", rf.path, err)
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (rf *rotatingFile) prefixAndExt() (string, string) {
+	base := filepath.Base(rf.path)
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-", ext
+}
+
+func (rf *rotatingFile) notifyMill() {
+	select {
+	case rf.millCh <- struct{}{}:
+	default:
+	}
+}
+
+func (rf *rotatingFile) millLoop() {
+	defer rf.millWG.Done()
+	for range rf.millCh {
+		// Errors can't be reported back to the pipeline, the next rotation retries.
+		_ = rf.millRunOnce()
+	}
+}
+
+// millRunOnce compresses the uncompressed backups and removes the ones exceeding MaxBackups.
+func (rf *rotatingFile) millRunOnce() error {
+	backups, err := rf.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	if rf.rotation.MaxBackups > 0 && len(backups) > rf.rotation.MaxBackups {
+		for _, old := range backups[rf.rotation.MaxBackups:] {
+			errs = multierr.Append(errs, os.Remove(old))
+		}
+		backups = backups[:rf.rotation.MaxBackups]
+	}
+
+	if rf.compression == "" {
+		return errs
+	}
+	suffix := compressedSuffix(rf.compression)
+	for _, backup := range backups {
+		if strings.HasSuffix(backup, suffix) {
+			continue
+		}
+		errs = multierr.Append(errs, compressFile(backup, backup+suffix, rf.compression))
+	}
+	return errs
+}
+
+// backups returns the rotated files for this path, newest first.
+func (rf *rotatingFile) backups() ([]string, error) {
+	dir := filepath.Dir(rf.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type backup struct {
+		name      string
+		timestamp time.Time
+	}
+	prefix, ext := rf.prefixAndExt()
+	var found []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		ts := strings.TrimPrefix(name, prefix)
+		if suffix := compressedSuffix(rf.compression); suffix != "" {
+			ts = strings.TrimSuffix(ts, suffix)
+		}
+		if !strings.HasSuffix(ts, ext) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(ts, ext))
+		if err != nil {
+			continue
+		}
+		found = append(found, backup{name: filepath.Join(dir, name), timestamp: t})
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].timestamp.After(found[j].timestamp)
+	})
+	names := make([]string, len(found))
+	for i, b := range found {
+		names[i] = b.name
+	}
+	return names, nil
+}
+
+func compressedSuffix(compression string) string {
+	switch compression {
+	case compressionGzip:
+		return ".gz"
+	case compressionZstd:
+		return ".zst"
+	}
+	return ""
+}
+
+// compressFile compresses src into dst and removes src once it succeeded.
+func compressFile(src, dst, compression string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(dst)
+		}
+	}()
+
+	var cw io.WriteCloser
+	switch compression {
+	case compressionGzip:
+		cw = gzip.NewWriter(out)
+	case compressionZstd:
+		if cw, err = zstd.NewWriter(out); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("compression %q is not supported", compression)
+	}
+
+	if _, err = io.Copy(cw, in); err != nil {
+		return err
+	}
+	if err = cw.Close(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
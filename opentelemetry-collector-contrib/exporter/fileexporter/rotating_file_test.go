@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	rf, err := newRotatingFile(path, Rotation{MaxMegabytes: 1}, "")
+	require.NoError(t, err)
+
+	chunk := bytes.Repeat([]byte("a"), megabyte/2+1)
+	for i := 0; i < 3; i++ {
+		_, err = rf.Write(chunk)
+		require.NoError(t, err)
+	}
+	require.NoError(t, rf.Close())
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "out-*.json"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+	for _, b := range backups {
+		assertFileSize(t, b, int64(len(chunk)))
+	}
+	assertFileSize(t, path, int64(len(chunk)))
+}
+
+func TestRotatingFileByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	rf, err := newRotatingFile(path, Rotation{MaxAge: time.Hour}, "")
+	require.NoError(t, err)
+
+	now := time.Now()
+	rf.now = func() time.Time { return now }
+	rf.openedAt = now
+
+	_, err = rf.Write([]byte("first\n"))
+	require.NoError(t, err)
+	now = now.Add(30 * time.Minute)
+	_, err = rf.Write([]byte("second\n"))
+	require.NoError(t, err)
+	now = now.Add(time.Hour)
+	_, err = rf.Write([]byte("third\n"))
+	require.NoError(t, err)
+	require.NoError(t, rf.Close())
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "out-*.json"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	content, err := ioutil.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(content))
+	content, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(content))
+}
+
+func TestRotatingFileKeepsPreviousRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("previous\n"), 0600))
+
+	rf, err := newRotatingFile(path, Rotation{}, "")
+	require.NoError(t, err)
+	require.NoError(t, rf.Close())
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "out-*.json"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	content, err := ioutil.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "previous\n", string(content))
+}
+
+func TestRotatingFileMaxBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	rf, err := newRotatingFile(path, Rotation{MaxBackups: 2}, "")
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, err = rf.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, rf.rotate())
+	}
+	require.NoError(t, rf.Close())
+
+	backups, err := rf.backups()
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+}
+
+func TestRotatingFileCompression(t *testing.T) {
+	tests := []struct {
+		compression string
+		decompress  func(t *testing.T, data []byte) []byte
+	}{
+		{
+			compression: compressionGzip,
+			decompress: func(t *testing.T, data []byte) []byte {
+				r, err := gzip.NewReader(bytes.NewReader(data))
+				require.NoError(t, err)
+				out, err := ioutil.ReadAll(r)
+				require.NoError(t, err)
+				return out
+			},
+		},
+		{
+			compression: compressionZstd,
+			decompress: func(t *testing.T, data []byte) []byte {
+				r, err := zstd.NewReader(bytes.NewReader(data))
+				require.NoError(t, err)
+				defer r.Close()
+				out, err := ioutil.ReadAll(r)
+				require.NoError(t, err)
+				return out
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.compression, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.json")
+			rf, err := newRotatingFile(path, Rotation{}, tt.compression)
+			require.NoError(t, err)
+
+			_, err = rf.Write([]byte("compressed\n"))
+			require.NoError(t, err)
+			require.NoError(t, rf.rotate())
+			require.NoError(t, rf.Close())
+
+			backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "out-*.json*"))
+			require.NoError(t, err)
+			require.Len(t, backups, 1)
+			assert.Equal(t, compressedSuffix(tt.compression), filepath.Ext(backups[0]))
+
+			data, err := ioutil.ReadFile(backups[0])
+			require.NoError(t, err)
+			assert.Equal(t, "compressed\n", string(tt.decompress(t, data)))
+		})
+	}
+}
+
+func assertFileSize(t *testing.T, path string, size int64) {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, size, info.Size())
+}
@@ -34,6 +34,13 @@ type Config struct {
 	ProtocolVersion string `mapstructure:"protocol_version"`
 	// The name of the kafka topic to export to (default otlp_spans for traces, otlp_metrics for metrics)
 	Topic string `mapstructure:"topic"`
+	// TopicFromAttribute is the name of the resource attribute whose value is used as the topic of
+	// the data of that resource. Topic is used when the attribute is missing or empty.
+	TopicFromAttribute string `mapstructure:"topic_from_attribute"`
+
+	// PartitionTracesByID splits the traces per trace ID and keys the messages by trace ID,
+	// so that all the spans of a trace are published to the same partition.
+	PartitionTracesByID bool `mapstructure:"partition_traces_by_id"`
 
 	// Encoding of messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
@@ -23,16 +23,20 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
 )
 
 var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 
 // kafkaTracesProducer uses sarama to produce trace messages to Kafka.
 type kafkaTracesProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler TracesMarshaler
-	logger    *zap.Logger
+	producer            sarama.SyncProducer
+	topic               string
+	topicFromAttribute  string
+	partitionTracesByID bool
+	marshaler           TracesMarshaler
+	logger              *zap.Logger
 }
 
 type kafkaErrors struct {
@@ -45,20 +49,24 @@ func (ke kafkaErrors) Error() string {
 }
 
 func (e *kafkaTracesProducer) tracesPusher(_ context.Context, td pdata.Traces) error {
-	messages, err := e.marshaler.Marshal(td, e.topic)
-	if err != nil {
-		return consumererror.Permanent(err)
-	}
-	err = e.producer.SendMessages(messages)
-	if err != nil {
-		if value, ok := err.(sarama.ProducerErrors); ok {
-			if len(value) > 0 {
-				return kafkaErrors{len(value), value[0].Err.Error()}
+	var messages []*sarama.ProducerMessage
+	for _, tt := range tracesPerTopic(td, e.topic, e.topicFromAttribute) {
+		batches := []pdata.Traces{tt.traces}
+		if e.partitionTracesByID {
+			batches = batchpersignal.SplitTraces(tt.traces)
+		}
+		for _, batch := range batches {
+			msgs, err := e.marshaler.Marshal(batch, tt.topic)
+			if err != nil {
+				return consumererror.Permanent(err)
+			}
+			if e.partitionTracesByID {
+				keyByTraceID(msgs, batch)
 			}
+			messages = append(messages, msgs...)
 		}
-		return err
 	}
-	return nil
+	return sendMessages(e.producer, messages)
 }
 
 func (e *kafkaTracesProducer) Close(context.Context) error {
@@ -67,27 +75,23 @@ func (e *kafkaTracesProducer) Close(context.Context) error {
 
 // kafkaMetricsProducer uses sarama to produce metrics messages to kafka
 type kafkaMetricsProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler MetricsMarshaler
-	logger    *zap.Logger
+	producer           sarama.SyncProducer
+	topic              string
+	topicFromAttribute string
+	marshaler          MetricsMarshaler
+	logger             *zap.Logger
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(_ context.Context, md pdata.Metrics) error {
-	messages, err := e.marshaler.Marshal(md, e.topic)
-	if err != nil {
-		return consumererror.Permanent(err)
-	}
-	err = e.producer.SendMessages(messages)
-	if err != nil {
-		if value, ok := err.(sarama.ProducerErrors); ok {
-			if len(value) > 0 {
-				return kafkaErrors{len(value), value[0].Err.Error()}
-			}
+	var messages []*sarama.ProducerMessage
+	for _, tm := range metricsPerTopic(md, e.topic, e.topicFromAttribute) {
+		msgs, err := e.marshaler.Marshal(tm.metrics, tm.topic)
+		if err != nil {
+			return consumererror.Permanent(err)
 		}
-		return err
+		messages = append(messages, msgs...)
 	}
-	return nil
+	return sendMessages(e.producer, messages)
 }
 
 func (e *kafkaMetricsProducer) Close(context.Context) error {
@@ -96,18 +100,31 @@ func (e *kafkaMetricsProducer) Close(context.Context) error {
 
 // kafkaLogsProducer uses sarama to produce logs messages to kafka
 type kafkaLogsProducer struct {
-	producer  sarama.SyncProducer
-	topic     string
-	marshaler LogsMarshaler
-	logger    *zap.Logger
+	producer           sarama.SyncProducer
+	topic              string
+	topicFromAttribute string
+	marshaler          LogsMarshaler
+	logger             *zap.Logger
 }
 
 func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld pdata.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, e.topic)
-	if err != nil {
-		return consumererror.Permanent(err)
+	var messages []*sarama.ProducerMessage
+	for _, tl := range logsPerTopic(ld, e.topic, e.topicFromAttribute) {
+		msgs, err := e.marshaler.Marshal(tl.logs, tl.topic)
+		if err != nil {
+			return consumererror.Permanent(err)
+		}
+		messages = append(messages, msgs...)
 	}
-	err = e.producer.SendMessages(messages)
+	return sendMessages(e.producer, messages)
+}
+
+func (e *kafkaLogsProducer) Close(context.Context) error {
+	return e.producer.Close()
+}
+
+func sendMessages(producer sarama.SyncProducer, messages []*sarama.ProducerMessage) error {
+	err := producer.SendMessages(messages)
 	if err != nil {
 		if value, ok := err.(sarama.ProducerErrors); ok {
 			if len(value) > 0 {
@@ -119,8 +136,22 @@ func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld pdata.Logs) err
 	return nil
 }
 
-func (e *kafkaLogsProducer) Close(context.Context) error {
-	return e.producer.Close()
+// keyByTraceID keys the messages without a key by the trace ID of the batch,
+// which is expected to contain the spans of a single trace.
+func keyByTraceID(messages []*sarama.ProducerMessage, batch pdata.Traces) {
+	if batch.ResourceSpans().Len() == 0 {
+		return
+	}
+	ilss := batch.ResourceSpans().At(0).InstrumentationLibrarySpans()
+	if ilss.Len() == 0 || ilss.At(0).Spans().Len() == 0 {
+		return
+	}
+	key := sarama.ByteEncoder(ilss.At(0).Spans().At(0).TraceID().HexString())
+	for _, msg := range messages {
+		if msg.Key == nil {
+			msg.Key = key
+		}
+	}
 }
 
 func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
@@ -164,10 +195,11 @@ func newMetricsExporter(config Config, set component.ExporterCreateSettings, mar
 	}
 
 	return &kafkaMetricsProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		producer:           producer,
+		topic:              config.Topic,
+		topicFromAttribute: config.TopicFromAttribute,
+		marshaler:          marshaler,
+		logger:             set.Logger,
 	}, nil
 
 }
@@ -183,10 +215,12 @@ func newTracesExporter(config Config, set component.ExporterCreateSettings, mars
 		return nil, err
 	}
 	return &kafkaTracesProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		producer:            producer,
+		topic:               config.Topic,
+		topicFromAttribute:  config.TopicFromAttribute,
+		partitionTracesByID: config.PartitionTracesByID,
+		marshaler:           marshaler,
+		logger:              set.Logger,
 	}, nil
 }
 
@@ -201,10 +235,11 @@ func newLogsExporter(config Config, set component.ExporterCreateSettings, marsha
 	}
 
 	return &kafkaLogsProducer{
-		producer:  producer,
-		topic:     config.Topic,
-		marshaler: marshaler,
-		logger:    set.Logger,
+		producer:           producer,
+		topic:              config.Topic,
+		topicFromAttribute: config.TopicFromAttribute,
+		marshaler:          marshaler,
+		logger:             set.Logger,
 	}, nil
 
 }
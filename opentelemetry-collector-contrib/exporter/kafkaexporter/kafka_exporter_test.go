@@ -259,6 +259,99 @@ func TestLogsDataPusher_marshal_error(t *testing.T) {
 	assert.Contains(t, err.Error(), expErr.Error())
 }
 
+func TestTracesPusher_partition_traces_by_id(t *testing.T) {
+	producer := &recordingProducer{}
+	p := kafkaTracesProducer{
+		producer:            producer,
+		topic:               defaultTracesTopic,
+		partitionTracesByID: true,
+		marshaler:           newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+		logger:              zap.NewNop(),
+	}
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetTraceID(pdata.NewTraceID([16]byte{1}))
+	spans.AppendEmpty().SetTraceID(pdata.NewTraceID([16]byte{2}))
+	spans.AppendEmpty().SetTraceID(pdata.NewTraceID([16]byte{1}))
+
+	require.NoError(t, p.tracesPusher(context.Background(), td))
+	require.Len(t, producer.messages, 2)
+	assert.Equal(t, sarama.ByteEncoder(pdata.NewTraceID([16]byte{1}).HexString()), producer.messages[0].Key)
+	assert.Equal(t, sarama.ByteEncoder(pdata.NewTraceID([16]byte{2}).HexString()), producer.messages[1].Key)
+
+	unmarshaler := otlp.NewProtobufTracesUnmarshaler()
+	first, err := unmarshaler.UnmarshalTraces(producer.messages[0].Value.(sarama.ByteEncoder))
+	require.NoError(t, err)
+	assert.Equal(t, 2, first.SpanCount())
+}
+
+func TestTracesPusher_topic_from_attribute(t *testing.T) {
+	producer := &recordingProducer{}
+	p := kafkaTracesProducer{
+		producer:           producer,
+		topic:              defaultTracesTopic,
+		topicFromAttribute: "kafka.topic",
+		marshaler:          newPdataTracesMarshaler(otlp.NewProtobufTracesMarshaler(), defaultEncoding),
+		logger:             zap.NewNop(),
+	}
+	td := pdata.NewTraces()
+	for _, topic := range []string{"team_a", "", "team_a"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		if topic != "" {
+			rs.Resource().Attributes().InsertString("kafka.topic", topic)
+		}
+		rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	}
+
+	require.NoError(t, p.tracesPusher(context.Background(), td))
+	require.Len(t, producer.messages, 2)
+	assert.Equal(t, "team_a", producer.messages[0].Topic)
+	assert.Equal(t, defaultTracesTopic, producer.messages[1].Topic)
+
+	unmarshaler := otlp.NewProtobufTracesUnmarshaler()
+	teamA, err := unmarshaler.UnmarshalTraces(producer.messages[0].Value.(sarama.ByteEncoder))
+	require.NoError(t, err)
+	assert.Equal(t, 2, teamA.ResourceSpans().Len())
+}
+
+func TestMetricsDataPusher_topic_from_attribute(t *testing.T) {
+	producer := &recordingProducer{}
+	p := kafkaMetricsProducer{
+		producer:           producer,
+		topic:              defaultMetricsTopic,
+		topicFromAttribute: "kafka.topic",
+		marshaler:          newPdataMetricsMarshaler(otlp.NewProtobufMetricsMarshaler(), defaultEncoding),
+		logger:             zap.NewNop(),
+	}
+	md := testdata.GenerateMetricsOneMetric()
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("kafka.topic", "team_a")
+
+	require.NoError(t, p.metricsDataPusher(context.Background(), md))
+	require.Len(t, producer.messages, 1)
+	assert.Equal(t, "team_a", producer.messages[0].Topic)
+}
+
+// recordingProducer is a sarama.SyncProducer recording the messages it sends.
+type recordingProducer struct {
+	messages []*sarama.ProducerMessage
+}
+
+var _ sarama.SyncProducer = (*recordingProducer)(nil)
+
+func (r *recordingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	r.messages = append(r.messages, msg)
+	return 0, int64(len(r.messages) - 1), nil
+}
+
+func (r *recordingProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	r.messages = append(r.messages, msgs...)
+	return nil
+}
+
+func (r *recordingProducer) Close() error {
+	return nil
+}
+
 type tracesErrorMarshaler struct {
 	err error
 }
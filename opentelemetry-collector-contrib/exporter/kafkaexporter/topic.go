@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// topicForResource returns the value of the given resource attribute, or defaultTopic
+// when the attribute is not configured, missing or empty.
+func topicForResource(res pdata.Resource, defaultTopic, attribute string) string {
+	if attribute == "" {
+		return defaultTopic
+	}
+	if v, ok := res.Attributes().Get(attribute); ok {
+		if topic := v.AsString(); topic != "" {
+			return topic
+		}
+	}
+	return defaultTopic
+}
+
+type topicTraces struct {
+	topic  string
+	traces pdata.Traces
+}
+
+// tracesPerTopic splits the traces per topic, in the order the topics first appear.
+func tracesPerTopic(td pdata.Traces, defaultTopic, attribute string) []topicTraces {
+	if attribute == "" {
+		return []topicTraces{{topic: defaultTopic, traces: td}}
+	}
+	var result []topicTraces
+	index := map[string]int{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		topic := topicForResource(rs.Resource(), defaultTopic, attribute)
+		idx, ok := index[topic]
+		if !ok {
+			idx = len(result)
+			index[topic] = idx
+			result = append(result, topicTraces{topic: topic, traces: pdata.NewTraces()})
+		}
+		rs.CopyTo(result[idx].traces.ResourceSpans().AppendEmpty())
+	}
+	return result
+}
+
+type topicMetrics struct {
+	topic   string
+	metrics pdata.Metrics
+}
+
+// metricsPerTopic splits the metrics per topic, in the order the topics first appear.
+func metricsPerTopic(md pdata.Metrics, defaultTopic, attribute string) []topicMetrics {
+	if attribute == "" {
+		return []topicMetrics{{topic: defaultTopic, metrics: md}}
+	}
+	var result []topicMetrics
+	index := map[string]int{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		topic := topicForResource(rm.Resource(), defaultTopic, attribute)
+		idx, ok := index[topic]
+		if !ok {
+			idx = len(result)
+			index[topic] = idx
+			result = append(result, topicMetrics{topic: topic, metrics: pdata.NewMetrics()})
+		}
+		rm.CopyTo(result[idx].metrics.ResourceMetrics().AppendEmpty())
+	}
+	return result
+}
+
+type topicLogs struct {
+	topic string
+	logs  pdata.Logs
+}
+
+// logsPerTopic splits the logs per topic, in the order the topics first appear.
+func logsPerTopic(ld pdata.Logs, defaultTopic, attribute string) []topicLogs {
+	if attribute == "" {
+		return []topicLogs{{topic: defaultTopic, logs: ld}}
+	}
+	var result []topicLogs
+	index := map[string]int{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		topic := topicForResource(rl.Resource(), defaultTopic, attribute)
+		idx, ok := index[topic]
+		if !ok {
+			idx = len(result)
+			index[topic] = idx
+			result = append(result, topicLogs{topic: topic, logs: pdata.NewLogs()})
+		}
+		rl.CopyTo(result[idx].logs.ResourceLogs().AppendEmpty())
+	}
+	return result
+}
@@ -46,6 +46,9 @@ type oidcExtension struct {
 	logger *zap.Logger
 }
 
+// subjectCtxKey is the key of the subject of the authenticated requests in their context.
+type subjectCtxKey struct{}
+
 var (
 	_ configauth.ServerAuthenticator = (*oidcExtension)(nil)
 
@@ -127,7 +130,7 @@ func (e *oidcExtension) Authenticate(ctx context.Context, headers map[string][]s
 		return ctx, errFailedToObtainClaimsFromToken
 	}
 
-	_, err = getSubjectFromClaims(claims, e.cfg.UsernameClaim, idToken.Subject)
+	subject, err := getSubjectFromClaims(claims, e.cfg.UsernameClaim, idToken.Subject)
 	if err != nil {
 		return ctx, fmt.Errorf("failed to get subject from claims in the token: %w", err)
 	}
@@ -137,9 +140,16 @@ func (e *oidcExtension) Authenticate(ctx context.Context, headers map[string][]s
 		return ctx, fmt.Errorf("failed to get groups from claims in the token: %w", err)
 	}
 
-	// TODO: once the design for #2734 is determined, we will probably need to add the auth data to the context
+	// TODO: once the design for #2734 is determined, we will probably need to add the groups to the context
 	// https://github.com/open-telemetry/opentelemetry-collector/issues/2734
-	return ctx, nil
+	return context.WithValue(ctx, subjectCtxKey{}, subject), nil
+}
+
+// Principal returns the subject of the token of the request authenticated with the given context,
+// so that the receivers expose it as the principal of their clients.
+func (e *oidcExtension) Principal(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(subjectCtxKey{}).(string)
+	return subject, ok
 }
 
 // GRPCUnaryServerInterceptor is a helper method to provide a gRPC-compatible UnaryInterceptor, typically calling the authenticator's Authenticate method.
@@ -73,8 +73,11 @@ func TestOIDCAuthenticationSucceeded(t *testing.T) {
 	// verify
 	assert.NoError(t, err)
 	assert.NotNil(t, ctx)
+	principal, ok := p.Principal(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "jdoe@example.com", principal)
 
-	// TODO(jpkroehling): assert that the authentication routine set the subject/membership to the resource
+	// TODO(jpkroehling): assert that the authentication routine set the membership to the resource
 }
 
 func TestOIDCProviderForConfigWithTLS(t *testing.T) {
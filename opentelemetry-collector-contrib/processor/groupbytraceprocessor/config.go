@@ -42,9 +42,12 @@ type Config struct {
 	// Not yet implemented, and an error will be returned when this option is used.
 	DiscardOrphans bool `mapstructure:"discard_orphans"`
 
-	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to disk.
+	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to
+	// the storage extension specified by StorageID. The traces in the storage are released after a restart.
 	// Useful when the duration to wait for traces to complete is high.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
 	StoreOnDisk bool `mapstructure:"store_on_disk"`
+
+	// StorageID is the storage extension to use when StoreOnDisk is enabled.
+	StorageID *config.ComponentID `mapstructure:"storage"`
 }
@@ -40,6 +40,9 @@ const (
 
 	// traceID to be removed
 	traceRemoved
+
+	// traceID found in the storage at startup
+	traceRecovered
 )
 
 var (
@@ -80,10 +83,11 @@ type eventMachine struct {
 
 	logger *zap.Logger
 
-	onTraceReceived func(td tracesWithID, worker *eventMachineWorker) error
-	onTraceExpired  func(traceID pdata.TraceID, worker *eventMachineWorker) error
-	onTraceReleased func(rss []pdata.ResourceSpans) error
-	onTraceRemoved  func(traceID pdata.TraceID) error
+	onTraceReceived  func(td tracesWithID, worker *eventMachineWorker) error
+	onTraceExpired   func(traceID pdata.TraceID, worker *eventMachineWorker) error
+	onTraceReleased  func(rss []pdata.ResourceSpans) error
+	onTraceRemoved   func(traceID pdata.TraceID) error
+	onTraceRecovered func(traceID pdata.TraceID, worker *eventMachineWorker) error
 
 	onError func(event)
 
@@ -213,6 +217,22 @@ func (em *eventMachine) handleEvent(e event, w *eventMachineWorker) {
 		em.handleEventWithObservability("onTraceRemoved", func() error {
 			return em.onTraceRemoved(payload)
 		})
+	case traceRecovered:
+		if em.onTraceRecovered == nil {
+			em.logger.Debug("onTraceRecovered not set, skipping event")
+			em.callOnError(e)
+			return
+		}
+		payload, ok := e.payload.(pdata.TraceID)
+		if !ok {
+			// the payload had an unexpected type!
+			em.callOnError(e)
+			return
+		}
+
+		em.handleEventWithObservability("onTraceRecovered", func() error {
+			return em.onTraceRecovered(payload, w)
+		})
 	default:
 		em.logger.Info("unknown event type", zap.Any("event", e.typ))
 		em.callOnError(e)
@@ -241,6 +261,20 @@ func (em *eventMachine) consume(td pdata.Traces) error {
 	return nil
 }
 
+// recover schedules the release of a trace which is already in the storage, such as
+// one persisted by a previous run.
+func (em *eventMachine) recover(traceID pdata.TraceID) {
+	var bucket uint64
+	if len(em.workers) != 1 {
+		bucket = workerIndexForTraceID(traceID, len(em.workers))
+	}
+
+	em.workers[bucket].fire(event{
+		typ:     traceRecovered,
+		payload: traceID,
+	})
+}
+
 func workerIndexForTraceID(traceID pdata.TraceID, numWorkers int) uint64 {
 	hash := hashPool.Get().(*maphash.Hash)
 	defer func() {
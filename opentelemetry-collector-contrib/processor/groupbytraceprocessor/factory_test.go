@@ -19,7 +19,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 )
 
 func TestDefaultConfiguration(t *testing.T) {
@@ -47,6 +49,23 @@ func TestCreateTestProcessor(t *testing.T) {
 	assert.NotNil(t, p)
 }
 
+func TestCreateTestProcessorWithDiskStorage(t *testing.T) {
+	// prepare
+	c := createDefaultConfig().(*Config)
+	c.StoreOnDisk = true
+	storageID := config.NewIDWithName("file_storage", "groupbytrace")
+	c.StorageID = &storageID
+	next := &mockProcessor{}
+
+	// test
+	p, err := createTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), c, next)
+
+	// verify
+	assert.NoError(t, err)
+	require.NotNil(t, p)
+	assert.IsType(t, &diskStorage{}, p.(*groupByTraceProcessor).st)
+}
+
 func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
 	// prepare
 	f := NewFactory()
@@ -67,7 +86,7 @@ func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
 			&Config{
 				StoreOnDisk: true,
 			},
-			errDiskStorageNotSupported,
+			errStorageNotConfigured,
 		},
 	} {
 		p, err := f.CreateTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), tt.config, next)
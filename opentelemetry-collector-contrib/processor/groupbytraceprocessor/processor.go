@@ -75,6 +75,7 @@ func newGroupByTraceProcessor(logger *zap.Logger, st storage, nextConsumer consu
 	eventMachine.onTraceExpired = sp.onTraceExpired
 	eventMachine.onTraceReleased = sp.onTraceReleased
 	eventMachine.onTraceRemoved = sp.onTraceRemoved
+	eventMachine.onTraceRecovered = sp.onTraceRecovered
 
 	return sp
 }
@@ -94,14 +95,26 @@ func (sp *groupByTraceProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (sp *groupByTraceProcessor) Start(context.Context, component.Host) error {
+func (sp *groupByTraceProcessor) Start(ctx context.Context, host component.Host) error {
 	// start these metrics, as it might take a while for them to receive their first event
 	stats.Record(context.Background(), mTracesEvicted.M(0))
 	stats.Record(context.Background(), mIncompleteReleases.M(0))
 	stats.Record(context.Background(), mNumTracesConf.M(int64(sp.config.NumTraces)))
 
+	if err := sp.st.start(ctx, host); err != nil {
+		return err
+	}
 	sp.eventMachine.startInBackground()
-	return sp.st.start()
+
+	// traces left in a persistent storage by a previous run wait for the full duration again
+	recovered := sp.st.traceIDs()
+	if len(recovered) > 0 {
+		sp.logger.Info("releasing traces recovered from the storage", zap.Int("num-traces", len(recovered)))
+	}
+	for _, traceID := range recovered {
+		sp.eventMachine.recover(traceID)
+	}
+	return nil
 }
 
 // Shutdown is invoked during service shutdown.
@@ -126,7 +139,27 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 
 	// at this point, we determined that we haven't seen the trace yet, so, record the
 	// traceID in the map and the spans to the storage
+	sp.track(traceID, worker)
+
+	// we have the traceID in the memory, place the spans in the storage too
+	if err := sp.addSpans(traceID, trace.td); err != nil {
+		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
+	}
+	return nil
+}
 
+func (sp *groupByTraceProcessor) onTraceRecovered(traceID pdata.TraceID, worker *eventMachineWorker) error {
+	if worker.buffer.contains(traceID) {
+		// spans for this trace were received since the processor started
+		return nil
+	}
+	sp.track(traceID, worker)
+	return nil
+}
+
+// track places the trace ID in the worker's buffer, evicting the oldest trace if needed,
+// and schedules the release of the trace once the wait duration is over.
+func (sp *groupByTraceProcessor) track(traceID pdata.TraceID, worker *eventMachineWorker) {
 	// place the trace ID in the buffer, and check if an item had to be evicted
 	evicted := worker.buffer.put(traceID)
 	if !evicted.IsEmpty() {
@@ -142,11 +175,6 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 			zap.String("traceID", evicted.HexString()))
 	}
 
-	// we have the traceID in the memory, place the spans in the storage too
-	if err := sp.addSpans(traceID, trace.td); err != nil {
-		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
-	}
-
 	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", sp.config.WaitDuration))
 
 	time.AfterFunc(sp.config.WaitDuration, func() {
@@ -156,7 +184,6 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 			payload: traceID,
 		})
 	})
-	return nil
 }
 
 func (sp *groupByTraceProcessor) onTraceExpired(traceID pdata.TraceID, worker *eventMachineWorker) error {
@@ -601,6 +601,7 @@ type mockStorage struct {
 	onCreateOrAppend func(pdata.TraceID, pdata.Traces) error
 	onGet            func(pdata.TraceID) ([]pdata.ResourceSpans, error)
 	onDelete         func(pdata.TraceID) ([]pdata.ResourceSpans, error)
+	onTraceIDs       func() []pdata.TraceID
 	onStart          func() error
 	onShutdown       func() error
 }
@@ -625,7 +626,13 @@ func (st *mockStorage) delete(traceID pdata.TraceID) ([]pdata.ResourceSpans, err
 	}
 	return nil, nil
 }
-func (st *mockStorage) start() error {
+func (st *mockStorage) traceIDs() []pdata.TraceID {
+	if st.onTraceIDs != nil {
+		return st.onTraceIDs()
+	}
+	return nil
+}
+func (st *mockStorage) start(context.Context, component.Host) error {
 	if st.onStart != nil {
 		return st.onStart()
 	}
@@ -15,6 +15,9 @@
 package groupbytraceprocessor
 
 import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -34,8 +37,12 @@ type storage interface {
 	// or nil in case a trace cannot be found
 	delete(pdata.TraceID) ([]pdata.ResourceSpans, error)
 
+	// traceIDs returns the IDs of all the traces currently in the storage, which for a persistent
+	// storage includes the ones left by a previous run
+	traceIDs() []pdata.TraceID
+
 	// start gives the storage the opportunity to initialize any resources or procedures
-	start() error
+	start(ctx context.Context, host component.Host) error
 
 	// shutdown signals the storage that the processor is shutting down
 	shutdown() error
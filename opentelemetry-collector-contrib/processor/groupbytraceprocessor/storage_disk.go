@@ -0,0 +1,342 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	storageextension "go.opentelemetry.io/collector/extension/storage"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+const (
+	readIndexKey  = "ri"
+	writeIndexKey = "wi"
+
+	// indexKeyPrefix prefixes the keys of the index entries, holding the ID of a trace in the storage.
+	indexKeyPrefix = "i"
+	// traceKeyPrefix prefixes the keys holding the spans of a trace.
+	traceKeyPrefix = "t"
+)
+
+var (
+	errNoStorageClient    = errors.New("no storage client extension found")
+	errWrongExtensionType = errors.New("requested extension is not a storage extension")
+
+	tracesMarshaler   = otlp.NewProtobufTracesMarshaler()
+	tracesUnmarshaler = otlp.NewProtobufTracesUnmarshaler()
+)
+
+// diskStorage keeps the spans of the traces in a storage extension, only the trace IDs are kept in memory.
+//
+// Besides the spans, the storage holds an index of the traces, so that the ones left by a
+// previous run can be found when the processor starts. Every new trace gets the next entry
+// of the index, pointed to by the write index. The entries are removed with their trace and
+// the read index points to the oldest entry still in use, so that a restart only has to look
+// at the entries between the read and write indexes, which are stored as well.
+type diskStorage struct {
+	logger      *zap.Logger
+	storageID   config.ComponentID
+	processorID config.ComponentID
+	client      storageextension.Client
+
+	// mu protects the index state below
+	mu         sync.Mutex
+	readIndex  uint64
+	writeIndex uint64
+	// entries maps the trace IDs to their index entry and back
+	entries map[pdata.TraceID]uint64
+	traces  map[uint64]pdata.TraceID
+
+	stopped                   bool
+	stoppedLock               sync.RWMutex
+	metricsCollectionInterval time.Duration
+}
+
+var _ storage = (*diskStorage)(nil)
+
+func newDiskStorage(logger *zap.Logger, storageID config.ComponentID, processorID config.ComponentID) *diskStorage {
+	return &diskStorage{
+		logger:                    logger,
+		storageID:                 storageID,
+		processorID:               processorID,
+		entries:                   make(map[pdata.TraceID]uint64),
+		traces:                    make(map[uint64]pdata.TraceID),
+		metricsCollectionInterval: time.Second,
+	}
+}
+
+func (st *diskStorage) createOrAppend(traceID pdata.TraceID, td pdata.Traces) error {
+	ctx := context.Background()
+	traceKey := getTraceKey(traceID)
+
+	st.mu.Lock()
+	_, exists := st.entries[traceID]
+	st.mu.Unlock()
+
+	if exists {
+		// spans of a given trace are always handled by the same worker,
+		// so nothing else updates this trace in the meantime
+		existing, err := st.load(ctx, traceKey)
+		if err != nil {
+			return err
+		}
+		td.ResourceSpans().MoveAndAppendTo(existing.ResourceSpans())
+		td = existing
+	}
+
+	buf, err := tracesMarshaler.MarshalTraces(td)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return st.client.Set(ctx, traceKey, buf)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	entry := st.writeIndex
+	id := traceID.Bytes()
+	err = st.client.Batch(ctx,
+		storageextension.SetOperation(traceKey, buf),
+		storageextension.SetOperation(getIndexKey(entry), id[:]),
+		storageextension.SetOperation(writeIndexKey, indexToBytes(entry+1)),
+	)
+	if err != nil {
+		return err
+	}
+	st.writeIndex++
+	st.entries[traceID] = entry
+	st.traces[entry] = traceID
+	return nil
+}
+
+func (st *diskStorage) get(traceID pdata.TraceID) ([]pdata.ResourceSpans, error) {
+	td, err := st.load(context.Background(), getTraceKey(traceID))
+	if err != nil || td.ResourceSpans().Len() == 0 {
+		return nil, err
+	}
+	return resourceSpansOf(td), nil
+}
+
+func (st *diskStorage) delete(traceID pdata.TraceID) ([]pdata.ResourceSpans, error) {
+	ctx := context.Background()
+	traceKey := getTraceKey(traceID)
+	td, err := st.load(ctx, traceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	ops := []storageextension.Operation{storageextension.DeleteOperation(traceKey)}
+	entry, ok := st.entries[traceID]
+	readIndex := st.readIndex
+	if ok {
+		ops = append(ops, storageextension.DeleteOperation(getIndexKey(entry)))
+		if entry == readIndex {
+			// move the read index past this entry and the ones removed before it
+			readIndex++
+			for readIndex < st.writeIndex {
+				if _, inUse := st.traces[readIndex]; inUse {
+					break
+				}
+				readIndex++
+			}
+			ops = append(ops, storageextension.SetOperation(readIndexKey, indexToBytes(readIndex)))
+		}
+	}
+	if err = st.client.Batch(ctx, ops...); err != nil {
+		return nil, err
+	}
+	if ok {
+		delete(st.entries, traceID)
+		delete(st.traces, entry)
+		st.readIndex = readIndex
+	}
+
+	if td.ResourceSpans().Len() == 0 {
+		return nil, nil
+	}
+	return resourceSpansOf(td), nil
+}
+
+func (st *diskStorage) traceIDs() []pdata.TraceID {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	ids := make([]pdata.TraceID, 0, len(st.traces))
+	for entry := st.readIndex; entry < st.writeIndex; entry++ {
+		if traceID, ok := st.traces[entry]; ok {
+			ids = append(ids, traceID)
+		}
+	}
+	return ids
+}
+
+func (st *diskStorage) start(ctx context.Context, host component.Host) error {
+	client, err := getStorageClient(ctx, host, st.storageID, st.processorID)
+	if err != nil {
+		return err
+	}
+	st.client = client
+
+	if err = st.loadIndex(ctx); err != nil {
+		return err
+	}
+
+	go st.periodicMetrics()
+	return nil
+}
+
+func (st *diskStorage) shutdown() error {
+	st.stoppedLock.Lock()
+	st.stopped = true
+	st.stoppedLock.Unlock()
+
+	if st.client == nil {
+		return nil
+	}
+	return st.client.Close(context.Background())
+}
+
+// loadIndex reads the index entries left by a previous run.
+func (st *diskStorage) loadIndex(ctx context.Context) error {
+	readIndexOp := storageextension.GetOperation(readIndexKey)
+	writeIndexOp := storageextension.GetOperation(writeIndexKey)
+	if err := st.client.Batch(ctx, readIndexOp, writeIndexOp); err != nil {
+		return err
+	}
+	readIndex, err := bytesToIndex(readIndexOp.Value)
+	if err != nil {
+		return fmt.Errorf("invalid read index in the storage: %w", err)
+	}
+	writeIndex, err := bytesToIndex(writeIndexOp.Value)
+	if err != nil {
+		return fmt.Errorf("invalid write index in the storage: %w", err)
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.readIndex = readIndex
+	st.writeIndex = writeIndex
+	for entry := readIndex; entry < writeIndex; entry++ {
+		buf, err := st.client.Get(ctx, getIndexKey(entry))
+		if err != nil {
+			return err
+		}
+		if len(buf) != 16 {
+			// removed with its trace
+			continue
+		}
+		var id [16]byte
+		copy(id[:], buf)
+		traceID := pdata.NewTraceID(id)
+		st.entries[traceID] = entry
+		st.traces[entry] = traceID
+	}
+	if len(st.traces) > 0 {
+		st.logger.Info("found traces from a previous run in the storage", zap.Int("num-traces", len(st.traces)))
+	}
+	return nil
+}
+
+// load retrieves the trace stored under the given key, or empty traces if there is none.
+func (st *diskStorage) load(ctx context.Context, traceKey string) (pdata.Traces, error) {
+	buf, err := st.client.Get(ctx, traceKey)
+	if err != nil {
+		return pdata.Traces{}, err
+	}
+	if buf == nil {
+		return pdata.NewTraces(), nil
+	}
+	return tracesUnmarshaler.UnmarshalTraces(buf)
+}
+
+func (st *diskStorage) periodicMetrics() {
+	st.mu.Lock()
+	numTraces := len(st.traces)
+	st.mu.Unlock()
+	stats.Record(context.Background(), mNumTracesInMemory.M(int64(numTraces)))
+
+	st.stoppedLock.RLock()
+	stopped := st.stopped
+	st.stoppedLock.RUnlock()
+	if stopped {
+		return
+	}
+
+	time.AfterFunc(st.metricsCollectionInterval, func() {
+		st.periodicMetrics()
+	})
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID config.ComponentID, processorID config.ComponentID) (storageextension.Client, error) {
+	ext, found := host.GetExtensions()[storageID]
+	if !found {
+		return nil, errNoStorageClient
+	}
+
+	storageExt, ok := ext.(storageextension.Extension)
+	if !ok {
+		return nil, errWrongExtensionType
+	}
+
+	return storageExt.GetClient(ctx, component.KindProcessor, processorID, "")
+}
+
+func resourceSpansOf(td pdata.Traces) []pdata.ResourceSpans {
+	rss := td.ResourceSpans()
+	result := make([]pdata.ResourceSpans, 0, rss.Len())
+	for i := 0; i < rss.Len(); i++ {
+		result = append(result, rss.At(i))
+	}
+	return result
+}
+
+func getTraceKey(traceID pdata.TraceID) string {
+	return traceKeyPrefix + traceID.HexString()
+}
+
+func getIndexKey(entry uint64) string {
+	return indexKeyPrefix + strconv.FormatUint(entry, 10)
+}
+
+func indexToBytes(index uint64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, index)
+	return buf
+}
+
+// bytesToIndex decodes an index, a missing value being the zero index of a new storage.
+func bytesToIndex(buf []byte) (uint64, error) {
+	if buf == nil {
+		return 0, nil
+	}
+	if len(buf) != 8 {
+		return 0, fmt.Errorf("unexpected index length %d", len(buf))
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	storageextension "go.opentelemetry.io/collector/extension/storage"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+var testStorageID = config.NewIDWithName("mockstorage", "test")
+
+type mockStorageClient struct {
+	mu sync.Mutex
+	st map[string][]byte
+}
+
+func newMockStorageClient() *mockStorageClient {
+	return &mockStorageClient{st: map[string][]byte{}}
+}
+
+func (m *mockStorageClient) Get(ctx context.Context, key string) ([]byte, error) {
+	op := storageextension.GetOperation(key)
+	err := m.Batch(ctx, op)
+	return op.Value, err
+}
+
+func (m *mockStorageClient) Set(ctx context.Context, key string, value []byte) error {
+	return m.Batch(ctx, storageextension.SetOperation(key, value))
+}
+
+func (m *mockStorageClient) Delete(ctx context.Context, key string) error {
+	return m.Batch(ctx, storageextension.DeleteOperation(key))
+}
+
+func (m *mockStorageClient) Close(context.Context) error {
+	return nil
+}
+
+func (m *mockStorageClient) Batch(_ context.Context, ops ...storageextension.Operation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, op := range ops {
+		switch op.Type {
+		case storageextension.Get:
+			op.Value = m.st[op.Key]
+		case storageextension.Set:
+			m.st[op.Key] = op.Value
+		case storageextension.Delete:
+			delete(m.st, op.Key)
+		default:
+			return errors.New("wrong operation type")
+		}
+	}
+	return nil
+}
+
+func (m *mockStorageClient) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for key := range m.st {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+type mockStorageExtension struct {
+	client storageextension.Client
+}
+
+func (m *mockStorageExtension) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (m *mockStorageExtension) Shutdown(context.Context) error {
+	return nil
+}
+
+func (m *mockStorageExtension) GetClient(context.Context, component.Kind, config.ComponentID, string) (storageextension.Client, error) {
+	return m.client, nil
+}
+
+type mockHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h *mockHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+func newStorageHost(client storageextension.Client) component.Host {
+	return &mockHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{
+			testStorageID: &mockStorageExtension{client: client},
+		},
+	}
+}
+
+func startDiskStorage(t *testing.T, client storageextension.Client) *diskStorage {
+	st := newDiskStorage(zap.NewNop(), testStorageID, config.NewID(typeStr))
+	require.NoError(t, st.start(context.Background(), newStorageHost(client)))
+	return st
+}
+
+func TestDiskCreateGetAndDeleteTrace(t *testing.T) {
+	// prepare
+	st := startDiskStorage(t, newMockStorageClient())
+	defer st.shutdown()
+
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4})
+	first := simpleTracesWithID(traceID)
+	second := simpleTracesWithID(traceID)
+	second.ResourceSpans().At(0).Resource().Attributes().InsertString("k", "v")
+
+	// test
+	require.NoError(t, st.createOrAppend(traceID, first.Clone()))
+	require.NoError(t, st.createOrAppend(traceID, second.Clone()))
+	retrieved, err := st.get(traceID)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []pdata.ResourceSpans{first.ResourceSpans().At(0), second.ResourceSpans().At(0)}, retrieved)
+	assert.Equal(t, []pdata.TraceID{traceID}, st.traceIDs())
+
+	deleted, err := st.delete(traceID)
+	require.NoError(t, err)
+	assert.Len(t, deleted, 2)
+
+	retrieved, err = st.get(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+	assert.Empty(t, st.traceIDs())
+}
+
+func TestDiskDeleteNonExistingTrace(t *testing.T) {
+	st := startDiskStorage(t, newMockStorageClient())
+	defer st.shutdown()
+
+	deleted, err := st.delete(pdata.NewTraceID([16]byte{1, 2, 3, 4}))
+	assert.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestDiskKeepsTracesAcrossRestarts(t *testing.T) {
+	// prepare
+	client := newMockStorageClient()
+	st := startDiskStorage(t, client)
+
+	var traceIDs []pdata.TraceID
+	for i := byte(1); i <= 4; i++ {
+		traceID := pdata.NewTraceID([16]byte{i})
+		traceIDs = append(traceIDs, traceID)
+		require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	}
+
+	// remove a trace in the middle and the oldest one
+	_, err := st.delete(traceIDs[2])
+	require.NoError(t, err)
+	_, err = st.delete(traceIDs[0])
+	require.NoError(t, err)
+	require.NoError(t, st.shutdown())
+
+	// test
+	st = startDiskStorage(t, client)
+
+	// verify
+	assert.Equal(t, []pdata.TraceID{traceIDs[1], traceIDs[3]}, st.traceIDs())
+	retrieved, err := st.get(traceIDs[3])
+	require.NoError(t, err)
+	require.Len(t, retrieved, 1)
+	assert.Equal(t, traceIDs[3], retrieved[0].InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+
+	for _, traceID := range st.traceIDs() {
+		_, err = st.delete(traceID)
+		require.NoError(t, err)
+	}
+	require.NoError(t, st.shutdown())
+
+	// only the read and write indexes are left
+	assert.ElementsMatch(t, []string{readIndexKey, writeIndexKey}, client.keys())
+}
+
+func TestDiskStorageExtensionNotFound(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		host        component.Host
+		expectedErr error
+	}{
+		{
+			name:        "missing",
+			host:        componenttest.NewNopHost(),
+			expectedErr: errNoStorageClient,
+		},
+		{
+			name: "wrong type",
+			host: &mockHost{
+				Host: componenttest.NewNopHost(),
+				extensions: map[config.ComponentID]component.Extension{
+					testStorageID: componenthelper.New(),
+				},
+			},
+			expectedErr: errWrongExtensionType,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newDiskStorage(zap.NewNop(), testStorageID, config.NewID(typeStr))
+			assert.Equal(t, tt.expectedErr, st.start(context.Background(), tt.host))
+		})
+	}
+}
+
+func TestDiskTracesAreReleasedAfterRestart(t *testing.T) {
+	// prepare
+	client := newMockStorageClient()
+	config := Config{
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+		NumWorkers:   1,
+	}
+	traces := simpleTraces()
+
+	p := newGroupByTraceProcessor(zap.NewNop(), newDiskStorage(zap.NewNop(), testStorageID, config.ID()), &mockProcessor{}, config)
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, newStorageHost(client)))
+	require.NoError(t, p.ConsumeTraces(ctx, traces))
+	assert.Eventually(t, func() bool {
+		return len(client.keys()) > 0
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, p.Shutdown(ctx))
+
+	// test
+	received := make(chan pdata.Traces, 1)
+	next := &mockProcessor{
+		onTraces: func(ctx context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
+		},
+	}
+	config.WaitDuration = time.Millisecond
+	p = newGroupByTraceProcessor(zap.NewNop(), newDiskStorage(zap.NewNop(), testStorageID, config.ID()), next, config)
+	require.NoError(t, p.Start(ctx, newStorageHost(client)))
+	defer p.Shutdown(ctx)
+
+	// verify
+	select {
+	case td := <-received:
+		assert.Equal(t, traces, td)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the recovered trace wasn't released")
+	}
+}
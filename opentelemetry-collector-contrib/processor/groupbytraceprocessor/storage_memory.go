@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -84,7 +85,17 @@ func (st *memoryStorage) delete(traceID pdata.TraceID) ([]pdata.ResourceSpans, e
 	return st.content[traceID], nil
 }
 
-func (st *memoryStorage) start() error {
+func (st *memoryStorage) traceIDs() []pdata.TraceID {
+	st.RLock()
+	defer st.RUnlock()
+	ids := make([]pdata.TraceID, 0, len(st.content))
+	for traceID := range st.content {
+		ids = append(ids, traceID)
+	}
+	return ids
+}
+
+func (st *memoryStorage) start(context.Context, component.Host) error {
 	go st.periodicMetrics()
 	return nil
 }
@@ -15,9 +15,18 @@
 package routingprocessor
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/config"
 )
 
+const (
+	// contextAttributeSource is the attribute source of the routes read from the context of the requests.
+	contextAttributeSource = "context"
+	// resourceAttributeSource is the attribute source of the routes read from the resources of the data.
+	resourceAttributeSource = "resource"
+)
+
 // Config defines configuration for the Routing processor.
 type Config struct {
 	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -34,6 +43,12 @@ type Config struct {
 	// Required.
 	FromAttribute string `mapstructure:"from_attribute"`
 
+	// AttributeSource defines where the attribute named by FromAttribute is looked up, either "context" for the
+	// context of the request (e.g. an HTTP header) or "resource" for the resource attributes of the data (e.g. service.name).
+	// When looking up resource attributes, a batch holding several resources is split across the matching routes.
+	// Optional. Defaults to "context".
+	AttributeSource string `mapstructure:"attribute_source"`
+
 	// Table contains the routing table for this processor.
 	// Required.
 	Table []RoutingTableItem `mapstructure:"table"`
@@ -50,3 +65,16 @@ type RoutingTableItem struct {
 	// Optional.
 	Exporters []string `mapstructure:"exporters"`
 }
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	for _, item := range cfg.Table {
+		// the empty value is the key of the default route
+		if item.Value == "" {
+			return fmt.Errorf("invalid route with exporters %v: %w", item.Exporters, errEmptyRouteValue)
+		}
+	}
+	return nil
+}
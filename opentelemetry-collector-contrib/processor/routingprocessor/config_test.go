@@ -15,6 +15,7 @@
 package routingprocessor
 
 import (
+	"errors"
 	"path"
 	"testing"
 
@@ -50,6 +51,7 @@ func TestLoadConfig(t *testing.T) {
 			ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
 			DefaultExporters:  []string{"otlp"},
 			FromAttribute:     "X-Tenant",
+			AttributeSource:   contextAttributeSource,
 			Table: []RoutingTableItem{
 				{
 					Value:     "acme",
@@ -61,4 +63,33 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		})
+
+	parsed = cfg.Processors[config.NewIDWithName(typeStr, "resource")]
+	assert.Equal(t, parsed,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewIDWithName(typeStr, "resource")),
+			DefaultExporters:  []string{"otlp"},
+			FromAttribute:     "service.name",
+			AttributeSource:   resourceAttributeSource,
+			Table: []RoutingTableItem{
+				{
+					Value:     "platform-demo",
+					Exporters: []string{"otlp/acme"},
+				},
+				{
+					Value:     "vendor-availability",
+					Exporters: []string{"otlp/globex"},
+				},
+			},
+		})
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Table = []RoutingTableItem{{Value: "acme", Exporters: []string{"otlp"}}}
+	assert.NoError(t, cfg.Validate())
+
+	// the empty value would collide with the default route
+	cfg.Table = append(cfg.Table, RoutingTableItem{Value: "", Exporters: []string{"jaeger"}})
+	assert.True(t, errors.Is(cfg.Validate(), errEmptyRouteValue))
 }
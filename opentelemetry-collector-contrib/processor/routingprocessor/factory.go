@@ -34,19 +34,36 @@ func NewFactory() component.ProcessorFactory {
 		typeStr,
 		createDefaultConfig,
 		processorhelper.WithTraces(createTracesProcessor),
+		processorhelper.WithMetrics(createMetricsProcessor),
+		processorhelper.WithLogs(createLogsProcessor),
 	)
 }
 
 func createDefaultConfig() config.Processor {
 	return &Config{
 		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
+		AttributeSource:   contextAttributeSource,
 	}
 }
 
 func createTracesProcessor(_ context.Context, params component.ProcessorCreateSettings, cfg config.Processor, nextConsumer consumer.Traces) (component.TracesProcessor, error) {
+	warnIfNextIsProcessor(params, nextConsumer)
+	return newProcessor(params.Logger, cfg, config.TracesDataType)
+}
+
+func createMetricsProcessor(_ context.Context, params component.ProcessorCreateSettings, cfg config.Processor, nextConsumer consumer.Metrics) (component.MetricsProcessor, error) {
+	warnIfNextIsProcessor(params, nextConsumer)
+	return newProcessor(params.Logger, cfg, config.MetricsDataType)
+}
+
+func createLogsProcessor(_ context.Context, params component.ProcessorCreateSettings, cfg config.Processor, nextConsumer consumer.Logs) (component.LogsProcessor, error) {
+	warnIfNextIsProcessor(params, nextConsumer)
+	return newProcessor(params.Logger, cfg, config.LogsDataType)
+}
+
+func warnIfNextIsProcessor(params component.ProcessorCreateSettings, nextConsumer interface{}) {
 	_, ok := nextConsumer.(component.Processor)
 	if ok {
 		params.Logger.Warn("another processor has been defined after the routing processor: it will NOT receive any data!")
 	}
-	return newProcessor(params.Logger, cfg)
 }
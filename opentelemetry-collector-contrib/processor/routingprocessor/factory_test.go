@@ -52,6 +52,34 @@ func TestProcessorGetsCreatedWithValidConfiguration(t *testing.T) {
 	assert.NotNil(t, exp)
 }
 
+func TestMetricsAndLogsProcessorsGetCreatedWithValidConfiguration(t *testing.T) {
+	// prepare
+	factory := NewFactory()
+	creationParams := componenttest.NewNopProcessorCreateSettings()
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
+		DefaultExporters:  []string{"otlp"},
+		FromAttribute:     "service.name",
+		AttributeSource:   resourceAttributeSource,
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp"},
+			},
+		},
+	}
+
+	// test
+	mp, err := factory.CreateMetricsProcessor(context.Background(), creationParams, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	lp, err := factory.CreateLogsProcessor(context.Background(), creationParams, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// verify
+	assert.Equal(t, config.MetricsDataType, mp.(*processorImp).dataType)
+	assert.Equal(t, config.LogsDataType, lp.(*processorImp).dataType)
+}
+
 func TestFailOnEmptyConfiguration(t *testing.T) {
 	// prepare
 	factory := NewFactory()
@@ -24,29 +24,45 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 )
 
 var (
-	errNoExporters            = errors.New("no exporters defined for the route")
-	errNoTableItems           = errors.New("the routing table is empty")
-	errNoMissingFromAttribute = errors.New("the FromAttribute property is empty")
-	errExporterNotFound       = errors.New("exporter not found")
+	errNoExporters             = errors.New("no exporters defined for the route")
+	errNoTableItems            = errors.New("the routing table is empty")
+	errNoMissingFromAttribute  = errors.New("the FromAttribute property is empty")
+	errExporterNotFound        = errors.New("exporter not found")
+	errInvalidAttributeSource  = errors.New("the AttributeSource property must be either context or resource")
+	errUnsupportedDataType     = errors.New("unsupported data type")
+	errExporterOfWrongDataType = errors.New("the exporter doesn't support the data type of the pipeline")
+	errEmptyRouteValue         = errors.New("the value of the route is empty")
 )
 
-var _ component.TracesProcessor = (*processorImp)(nil)
+// defaultRoute is the route of the data without value for the attribute, or with a value not in the routing table.
+const defaultRoute = ""
+
+var (
+	_ component.TracesProcessor  = (*processorImp)(nil)
+	_ component.MetricsProcessor = (*processorImp)(nil)
+	_ component.LogsProcessor    = (*processorImp)(nil)
+)
 
 type processorImp struct {
 	logger *zap.Logger
 	config Config
 
-	defaultTracesExporters []component.TracesExporter
-	traceExporters         map[string][]component.TracesExporter
+	// dataType is the type of the pipeline of the processor, the exporters of the routes
+	// being looked up among the exporters of this type.
+	dataType config.DataType
+
+	defaultExporters []component.Exporter
+	exporters        map[string][]component.Exporter
 }
 
 // Crete new processor
-func newProcessor(logger *zap.Logger, cfg config.Processor) (*processorImp, error) {
+func newProcessor(logger *zap.Logger, cfg config.Processor, dataType config.DataType) (*processorImp, error) {
 	logger.Info("building processor")
 
 	oCfg := cfg.(*Config)
@@ -68,23 +84,29 @@ func newProcessor(logger *zap.Logger, cfg config.Processor) (*processorImp, erro
 		return nil, fmt.Errorf("invalid attribute to read the route's value from: %w", errNoMissingFromAttribute)
 	}
 
+	switch oCfg.AttributeSource {
+	case "", contextAttributeSource, resourceAttributeSource:
+	default:
+		return nil, fmt.Errorf("invalid attribute source %q: %w", oCfg.AttributeSource, errInvalidAttributeSource)
+	}
+
 	return &processorImp{
-		logger:         logger,
-		config:         *oCfg,
-		traceExporters: make(map[string][]component.TracesExporter),
+		logger:    logger,
+		config:    *oCfg,
+		dataType:  dataType,
+		exporters: make(map[string][]component.Exporter),
 	}, nil
 }
 
 func (e *processorImp) Start(_ context.Context, host component.Host) error {
 	// first, let's build a map of exporter names with the exporter instances
 	source := host.GetExporters()
-	availableExporters := map[string]component.TracesExporter{}
-	for k, exp := range source[config.TracesDataType] {
-		traceExp, ok := exp.(component.TracesExporter)
-		if !ok {
-			return fmt.Errorf("the exporter %q isn't a trace exporter", k.Name())
+	availableExporters := map[string]component.Exporter{}
+	for k, exp := range source[e.dataType] {
+		if err := validateExporterDataType(exp, e.dataType); err != nil {
+			return fmt.Errorf("the exporter %q isn't a %s exporter: %w", k.Name(), e.dataType, err)
 		}
-		availableExporters[k.String()] = traceExp
+		availableExporters[k.String()] = exp
 	}
 
 	// default exporters
@@ -102,25 +124,44 @@ func (e *processorImp) Start(_ context.Context, host component.Host) error {
 	return nil
 }
 
-func (e *processorImp) registerExportersForDefaultRoute(available map[string]component.TracesExporter, requested []string) error {
+// validateExporterDataType checks the exporter consumes data of the given type.
+func validateExporterDataType(exp component.Exporter, dataType config.DataType) error {
+	var ok bool
+	switch dataType {
+	case config.TracesDataType:
+		_, ok = exp.(component.TracesExporter)
+	case config.MetricsDataType:
+		_, ok = exp.(component.MetricsExporter)
+	case config.LogsDataType:
+		_, ok = exp.(component.LogsExporter)
+	default:
+		return errUnsupportedDataType
+	}
+	if !ok {
+		return errExporterOfWrongDataType
+	}
+	return nil
+}
+
+func (e *processorImp) registerExportersForDefaultRoute(available map[string]component.Exporter, requested []string) error {
 	for _, exp := range requested {
 		v, ok := available[exp]
 		if !ok {
 			return fmt.Errorf("error registering default exporter %q: %w", exp, errExporterNotFound)
 		}
-		e.defaultTracesExporters = append(e.defaultTracesExporters, v)
+		e.defaultExporters = append(e.defaultExporters, v)
 	}
 
 	return nil
 }
 
-func (e *processorImp) registerExportersForRoute(route string, available map[string]component.TracesExporter, requested []string) error {
+func (e *processorImp) registerExportersForRoute(route string, available map[string]component.Exporter, requested []string) error {
 	for _, exp := range requested {
 		v, ok := available[exp]
 		if !ok {
 			return fmt.Errorf("error registering route %q for exporter %q: %w", route, exp, errExporterNotFound)
 		}
-		e.traceExporters[route] = append(e.traceExporters[route], v)
+		e.exporters[route] = append(e.exporters[route], v)
 	}
 
 	return nil
@@ -131,29 +172,143 @@ func (e *processorImp) Shutdown(context.Context) error {
 }
 
 func (e *processorImp) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
-	value := e.extractValueFromContext(ctx)
-	if len(value) == 0 {
-		// the attribute's value hasn't been found, send data to the default exporter
-		return e.pushDataToExporters(ctx, td, e.defaultTracesExporters)
+	if e.config.AttributeSource != resourceAttributeSource {
+		return e.pushTracesToExporters(ctx, td, e.exportersForRoute(e.routeForValue(e.extractValueFromContext(ctx))))
 	}
 
-	if _, ok := e.traceExporters[value]; !ok {
-		// the value has been found, but there are no exporters for the value
-		return e.pushDataToExporters(ctx, td, e.defaultTracesExporters)
+	// split the traces by the route of their resources
+	groups := map[string]pdata.Traces{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		route := e.routeForValue(e.extractValueFromResource(rs.Resource()))
+		group, ok := groups[route]
+		if !ok {
+			group = pdata.NewTraces()
+			groups[route] = group
+		}
+		rs.CopyTo(group.ResourceSpans().AppendEmpty())
 	}
 
-	// found the appropriate router, using it
-	return e.pushDataToExporters(ctx, td, e.traceExporters[value])
+	// try every route, so that a failing route doesn't prevent the other ones from getting their data
+	var errs error
+	for route, group := range groups {
+		errs = multierr.Append(errs, e.pushTracesToExporters(ctx, group, e.exportersForRoute(route)))
+	}
+	return errs
+}
+
+func (e *processorImp) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if e.config.AttributeSource != resourceAttributeSource {
+		return e.pushMetricsToExporters(ctx, md, e.exportersForRoute(e.routeForValue(e.extractValueFromContext(ctx))))
+	}
+
+	// split the metrics by the route of their resources
+	groups := map[string]pdata.Metrics{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		route := e.routeForValue(e.extractValueFromResource(rm.Resource()))
+		group, ok := groups[route]
+		if !ok {
+			group = pdata.NewMetrics()
+			groups[route] = group
+		}
+		rm.CopyTo(group.ResourceMetrics().AppendEmpty())
+	}
+
+	// try every route, so that a failing route doesn't prevent the other ones from getting their data
+	var errs error
+	for route, group := range groups {
+		errs = multierr.Append(errs, e.pushMetricsToExporters(ctx, group, e.exportersForRoute(route)))
+	}
+	return errs
+}
+
+func (e *processorImp) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if e.config.AttributeSource != resourceAttributeSource {
+		return e.pushLogsToExporters(ctx, ld, e.exportersForRoute(e.routeForValue(e.extractValueFromContext(ctx))))
+	}
+
+	// split the logs by the route of their resources
+	groups := map[string]pdata.Logs{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		route := e.routeForValue(e.extractValueFromResource(rl.Resource()))
+		group, ok := groups[route]
+		if !ok {
+			group = pdata.NewLogs()
+			groups[route] = group
+		}
+		rl.CopyTo(group.ResourceLogs().AppendEmpty())
+	}
+
+	// try every route, so that a failing route doesn't prevent the other ones from getting their data
+	var errs error
+	for route, group := range groups {
+		errs = multierr.Append(errs, e.pushLogsToExporters(ctx, group, e.exportersForRoute(route)))
+	}
+	return errs
 }
 
 func (e *processorImp) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
-func (e *processorImp) pushDataToExporters(ctx context.Context, td pdata.Traces, exporters []component.TracesExporter) error {
+// routeForValue returns the route of the given attribute value, which is the default route
+// when the value is empty or isn't in the routing table.
+func (e *processorImp) routeForValue(value string) string {
+	if len(value) == 0 {
+		// the attribute's value hasn't been found, send data to the default exporters
+		return defaultRoute
+	}
+
+	if _, ok := e.exporters[value]; !ok {
+		// the value has been found, but there are no exporters for the value
+		return defaultRoute
+	}
+
+	return value
+}
+
+func (e *processorImp) exportersForRoute(route string) []component.Exporter {
+	if route == defaultRoute {
+		return e.defaultExporters
+	}
+	return e.exporters[route]
+}
+
+// pushTracesToExporters pushes the traces to the exporters, which were checked to be traces
+// exporters when the processor started.
+func (e *processorImp) pushTracesToExporters(ctx context.Context, td pdata.Traces, exporters []component.Exporter) error {
 	// TODO: determine the proper action when errors happen
 	for _, exp := range exporters {
-		if err := exp.ConsumeTraces(ctx, td); err != nil {
+		if err := exp.(component.TracesExporter).ConsumeTraces(ctx, td); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pushMetricsToExporters pushes the metrics to the exporters, which were checked to be metrics
+// exporters when the processor started.
+func (e *processorImp) pushMetricsToExporters(ctx context.Context, md pdata.Metrics, exporters []component.Exporter) error {
+	for _, exp := range exporters {
+		if err := exp.(component.MetricsExporter).ConsumeMetrics(ctx, md); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pushLogsToExporters pushes the logs to the exporters, which were checked to be logs
+// exporters when the processor started.
+func (e *processorImp) pushLogsToExporters(ctx context.Context, ld pdata.Logs, exporters []component.Exporter) error {
+	for _, exp := range exporters {
+		if err := exp.(component.LogsExporter).ConsumeLogs(ctx, ld); err != nil {
 			return err
 		}
 	}
@@ -181,3 +336,12 @@ func (e *processorImp) extractValueFromContext(ctx context.Context) string {
 
 	return values[0]
 }
+
+func (e *processorImp) extractValueFromResource(resource pdata.Resource) string {
+	value, ok := resource.Attributes().Get(e.config.FromAttribute)
+	if !ok {
+		return ""
+	}
+
+	return value.AsString()
+}
@@ -43,7 +43,7 @@ func TestRouteIsFoundForGRPCContexts(t *testing.T) {
 			FromAttribute: "X-Tenant",
 		},
 		logger: zap.NewNop(),
-		traceExporters: map[string][]component.TracesExporter{
+		exporters: map[string][]component.Exporter{
 			"acme": {
 				&mockExporter{
 					ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
@@ -89,7 +89,7 @@ func TestDefaultRouteIsUsedWhenRouteCantBeDetermined(t *testing.T) {
 					FromAttribute: "X-Tenant",
 				},
 				logger: zap.NewNop(),
-				defaultTracesExporters: []component.TracesExporter{
+				defaultExporters: []component.Exporter{
 					&mockExporter{
 						ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
 							wg.Done()
@@ -111,6 +111,317 @@ func TestDefaultRouteIsUsedWhenRouteCantBeDetermined(t *testing.T) {
 	}
 }
 
+func TestMetricsRouteIsFoundForGRPCContexts(t *testing.T) {
+	// prepare
+	var received []pdata.Metrics
+	exp := &processorImp{
+		config: Config{
+			FromAttribute: "X-Tenant",
+		},
+		logger: zap.NewNop(),
+		exporters: map[string][]component.Exporter{
+			"acme": {
+				&mockExporter{
+					ConsumeMetricsFunc: func(_ context.Context, md pdata.Metrics) error {
+						received = append(received, md)
+						return nil
+					},
+				},
+			},
+		},
+	}
+	metrics := pdata.NewMetrics()
+
+	// test
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Tenant", "acme"))
+	err := exp.ConsumeMetrics(ctx, metrics)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, []pdata.Metrics{metrics}, received)
+}
+
+func TestLogsRouteIsFoundForGRPCContexts(t *testing.T) {
+	// prepare
+	var received []pdata.Logs
+	exp := &processorImp{
+		config: Config{
+			FromAttribute: "X-Tenant",
+		},
+		logger: zap.NewNop(),
+		exporters: map[string][]component.Exporter{
+			"acme": {
+				&mockExporter{
+					ConsumeLogsFunc: func(_ context.Context, ld pdata.Logs) error {
+						received = append(received, ld)
+						return nil
+					},
+				},
+			},
+		},
+	}
+	logs := pdata.NewLogs()
+
+	// test
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Tenant", "acme"))
+	err := exp.ConsumeLogs(ctx, logs)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, []pdata.Logs{logs}, received)
+}
+
+// newResourceRoutingProcessor creates a processor routing on the service.name resource attribute,
+// with the given exporters for the "acme" route and the default route.
+func newResourceRoutingProcessor(acme, defaultExp component.Exporter) *processorImp {
+	return &processorImp{
+		config: Config{
+			FromAttribute:   "service.name",
+			AttributeSource: resourceAttributeSource,
+		},
+		logger:           zap.NewNop(),
+		defaultExporters: []component.Exporter{defaultExp},
+		exporters: map[string][]component.Exporter{
+			"acme": {acme},
+		},
+	}
+}
+
+func TestTracesAreSplitByResourceRoute(t *testing.T) {
+	// prepare
+	var acmeTraces, defaultTraces []pdata.Traces
+	exp := newResourceRoutingProcessor(
+		&mockExporter{ConsumeTracesFunc: func(_ context.Context, td pdata.Traces) error {
+			acmeTraces = append(acmeTraces, td)
+			return nil
+		}},
+		&mockExporter{ConsumeTracesFunc: func(_ context.Context, td pdata.Traces) error {
+			defaultTraces = append(defaultTraces, td)
+			return nil
+		}},
+	)
+
+	traces := pdata.NewTraces()
+	for _, service := range []string{"acme", "globex", "acme", ""} {
+		rs := traces.ResourceSpans().AppendEmpty()
+		if service != "" {
+			rs.Resource().Attributes().InsertString("service.name", service)
+		}
+		rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty().SetName(service + "-span")
+	}
+
+	// test
+	err := exp.ConsumeTraces(context.Background(), traces)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, acmeTraces, 1)
+	require.Len(t, defaultTraces, 1)
+	assert.Equal(t, 2, acmeTraces[0].ResourceSpans().Len())
+	assert.Equal(t, 2, acmeTraces[0].SpanCount())
+	for i := 0; i < acmeTraces[0].ResourceSpans().Len(); i++ {
+		assert.Equal(t, "acme-span", acmeTraces[0].ResourceSpans().At(i).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	}
+	// the resources without the attribute or with a value not in the table take the default route
+	assert.Equal(t, 2, defaultTraces[0].ResourceSpans().Len())
+}
+
+func TestResourceRoutesAreAllTried(t *testing.T) {
+	// prepare
+	acmeErr := errors.New("acme error")
+	defaultErr := errors.New("default error")
+	var acmeTraces, defaultTraces int
+	exp := newResourceRoutingProcessor(
+		&mockExporter{ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
+			acmeTraces++
+			return acmeErr
+		}},
+		&mockExporter{ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
+			defaultTraces++
+			return defaultErr
+		}},
+	)
+
+	traces := pdata.NewTraces()
+	for _, service := range []string{"acme", "globex"} {
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString("service.name", service)
+		rs.InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	}
+
+	// test
+	err := exp.ConsumeTraces(context.Background(), traces)
+
+	// verify
+	assert.Equal(t, 1, acmeTraces)
+	assert.Equal(t, 1, defaultTraces)
+	assert.True(t, errors.Is(err, acmeErr))
+	assert.True(t, errors.Is(err, defaultErr))
+}
+
+func TestMetricsAreSplitByResourceRoute(t *testing.T) {
+	// prepare
+	var acmeMetrics, defaultMetrics []pdata.Metrics
+	exp := newResourceRoutingProcessor(
+		&mockExporter{ConsumeMetricsFunc: func(_ context.Context, md pdata.Metrics) error {
+			acmeMetrics = append(acmeMetrics, md)
+			return nil
+		}},
+		&mockExporter{ConsumeMetricsFunc: func(_ context.Context, md pdata.Metrics) error {
+			defaultMetrics = append(defaultMetrics, md)
+			return nil
+		}},
+	)
+
+	metrics := pdata.NewMetrics()
+	for _, service := range []string{"acme", "globex"} {
+		rm := metrics.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().InsertString("service.name", service)
+		rm.InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty().SetName(service + "-metric")
+	}
+
+	// test
+	err := exp.ConsumeMetrics(context.Background(), metrics)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, acmeMetrics, 1)
+	require.Len(t, defaultMetrics, 1)
+	assert.Equal(t, "acme-metric", acmeMetrics[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+	assert.Equal(t, "globex-metric", defaultMetrics[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+}
+
+func TestLogsAreSplitByResourceRoute(t *testing.T) {
+	// prepare
+	var acmeLogs, defaultLogs []pdata.Logs
+	exp := newResourceRoutingProcessor(
+		&mockExporter{ConsumeLogsFunc: func(_ context.Context, ld pdata.Logs) error {
+			acmeLogs = append(acmeLogs, ld)
+			return nil
+		}},
+		&mockExporter{ConsumeLogsFunc: func(_ context.Context, ld pdata.Logs) error {
+			defaultLogs = append(defaultLogs, ld)
+			return nil
+		}},
+	)
+
+	logs := pdata.NewLogs()
+	for _, service := range []string{"acme", "acme"} {
+		rl := logs.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().InsertString("service.name", service)
+		rl.InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty().SetName(service + "-log")
+	}
+
+	// test
+	err := exp.ConsumeLogs(context.Background(), logs)
+
+	// verify
+	require.NoError(t, err)
+	require.Len(t, acmeLogs, 1)
+	assert.Empty(t, defaultLogs)
+	assert.Equal(t, 2, acmeLogs[0].LogRecordCount())
+}
+
+func TestResourceRouteIgnoresContext(t *testing.T) {
+	// prepare
+	var acmeTraces, defaultTraces int
+	exp := newResourceRoutingProcessor(
+		&mockExporter{ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
+			acmeTraces++
+			return nil
+		}},
+		&mockExporter{ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
+			defaultTraces++
+			return nil
+		}},
+	)
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().AppendEmpty()
+
+	// test
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("service.name", "acme"))
+	err := exp.ConsumeTraces(ctx, traces)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, 0, acmeTraces)
+	assert.Equal(t, 1, defaultTraces)
+}
+
+func TestValueFromResourceAttribute(t *testing.T) {
+	// prepare
+	exp := &processorImp{
+		config: Config{FromAttribute: "tenant"},
+	}
+	resource := pdata.NewResource()
+	resource.Attributes().InsertInt("tenant", 42)
+
+	// test
+	val := exp.extractValueFromResource(resource)
+
+	// verify
+	assert.Equal(t, "42", val)
+	assert.Equal(t, "", exp.extractValueFromResource(pdata.NewResource()))
+}
+
+func TestInvalidAttributeSource(t *testing.T) {
+	// test
+	_, err := newProcessor(zap.NewNop(), &Config{
+		FromAttribute:   "X-Tenant",
+		AttributeSource: "header",
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp"},
+			},
+		},
+	}, config.TracesDataType)
+
+	// verify
+	assert.True(t, errors.Is(err, errInvalidAttributeSource))
+}
+
+func TestRegisterExportersForValidMetricsRoute(t *testing.T) {
+	//  prepare
+	exp, err := newProcessor(zap.NewNop(), &Config{
+		DefaultExporters: []string{"otlp"},
+		FromAttribute:    "X-Tenant",
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/acme"},
+			},
+		},
+	}, config.MetricsDataType)
+	require.NoError(t, err)
+
+	defaultExp := &mockExporter{}
+	acmeExp := &mockExporter{}
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[config.DataType]map[config.ComponentID]component.Exporter {
+			return map[config.DataType]map[config.ComponentID]component.Exporter{
+				// the exporters of other data types aren't used
+				config.TracesDataType: {
+					config.NewIDWithName("otlp", "acme"): &mockComponent{},
+				},
+				config.MetricsDataType: {
+					config.NewID("otlp"):                 defaultExp,
+					config.NewIDWithName("otlp", "acme"): acmeExp,
+				},
+			}
+		},
+	}
+
+	// test
+	err = exp.Start(context.Background(), host)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []component.Exporter{defaultExp}, exp.defaultExporters)
+	assert.Equal(t, []component.Exporter{acmeExp}, exp.exporters["acme"])
+}
+
 func TestRegisterExportersForValidRoute(t *testing.T) {
 	//  prepare
 	exp, err := newProcessor(zap.NewNop(), &Config{
@@ -122,7 +433,7 @@ func TestRegisterExportersForValidRoute(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 
 	otlpExpFactory := otlpexporter.NewFactory()
@@ -149,7 +460,7 @@ func TestRegisterExportersForValidRoute(t *testing.T) {
 	exp.Start(context.Background(), host)
 
 	// verify
-	assert.Contains(t, exp.traceExporters["acme"], otlpExp)
+	assert.Contains(t, exp.exporters["acme"], otlpExp)
 }
 
 func TestErrorRequestedExporterNotFoundForRoute(t *testing.T) {
@@ -162,7 +473,7 @@ func TestErrorRequestedExporterNotFoundForRoute(t *testing.T) {
 				Exporters: []string{"non-existing"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 	host := &mockHost{
 		Host: componenttest.NewNopHost(),
@@ -186,7 +497,7 @@ func TestErrorRequestedExporterNotFoundForDefaultRoute(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 
 	otlpExpFactory := otlpexporter.NewFactory()
@@ -227,7 +538,7 @@ func TestInvalidExporter(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 
 	host := &mockHost{
@@ -259,7 +570,7 @@ func TestValueFromExistingGRPCAttribute(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Tenant", "acme"))
 
@@ -281,7 +592,7 @@ func TestMultipleValuesFromExistingGRPCAttribute(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Tenant", "globex", "X-Tenant", "acme"))
 
@@ -303,7 +614,7 @@ func TestNoValuesFromExistingGRPCAttribute(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("X-Tenant", ""))
 
@@ -325,7 +636,7 @@ func TestAttributeFromExistingGRPCContext(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{}))
 
@@ -347,7 +658,7 @@ func TestNoAttributeInContext(t *testing.T) {
 				Exporters: []string{"otlp"},
 			},
 		},
-	})
+	}, config.TracesDataType)
 	require.NoError(t, err)
 
 	// test
@@ -364,7 +675,7 @@ func TestFailedToPushDataToExporter(t *testing.T) {
 	wg.Add(2)
 	exp := &processorImp{
 		logger: zap.NewNop(),
-		traceExporters: map[string][]component.TracesExporter{
+		exporters: map[string][]component.Exporter{
 			"acme": {
 				&mockExporter{
 					ConsumeTracesFunc: func(context.Context, pdata.Traces) error {
@@ -384,7 +695,7 @@ func TestFailedToPushDataToExporter(t *testing.T) {
 	traces := pdata.NewTraces()
 
 	// test
-	err := exp.pushDataToExporters(context.Background(), traces, exp.traceExporters["acme"])
+	err := exp.pushTracesToExporters(context.Background(), traces, exp.exporters["acme"])
 
 	// verify
 	wg.Wait() // ensure that the exporter has been called
@@ -393,7 +704,7 @@ func TestFailedToPushDataToExporter(t *testing.T) {
 
 func TestProcessorCapabilities(t *testing.T) {
 	// prepare
-	config := &Config{
+	cfg := &Config{
 		FromAttribute: "X-Tenant",
 		Table: []RoutingTableItem{{
 			Exporters: []string{"otlp"},
@@ -401,7 +712,7 @@ func TestProcessorCapabilities(t *testing.T) {
 	}
 
 	// test
-	p, err := newProcessor(zap.NewNop(), config)
+	p, err := newProcessor(zap.NewNop(), cfg, config.TracesDataType)
 	caps := p.Capabilities()
 
 	// verify
@@ -433,7 +744,9 @@ func (m *mockComponent) Shutdown(context.Context) error {
 
 type mockExporter struct {
 	mockComponent
-	ConsumeTracesFunc func(ctx context.Context, td pdata.Traces) error
+	ConsumeTracesFunc  func(ctx context.Context, td pdata.Traces) error
+	ConsumeMetricsFunc func(ctx context.Context, md pdata.Metrics) error
+	ConsumeLogsFunc    func(ctx context.Context, ld pdata.Logs) error
 }
 
 func (m *mockExporter) Capabilities() consumer.Capabilities {
@@ -446,3 +759,17 @@ func (m *mockExporter) ConsumeTraces(ctx context.Context, td pdata.Traces) error
 	}
 	return nil
 }
+
+func (m *mockExporter) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if m.ConsumeMetricsFunc != nil {
+		return m.ConsumeMetricsFunc(ctx, md)
+	}
+	return nil
+}
+
+func (m *mockExporter) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if m.ConsumeLogsFunc != nil {
+		return m.ConsumeLogsFunc(ctx, ld)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines the configuration options for servicegraphprocessor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// MetricsExporter is the name of the metrics exporter to use to ship metrics.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// LatencyHistogramBuckets is the list of durations representing latency histogram buckets.
+	// See defaultLatencyHistogramBucketsSeconds in processor.go for the default value.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+
+	// Dimensions defines the list of additional span attributes to add as dimensions of the edge metrics.
+	// Each attribute is looked up in both the client and the server spans, the dimensions being prefixed
+	// with "client_" and "server_" respectively. The attribute is omitted if it is missing from the span.
+	Dimensions []string `mapstructure:"dimensions"`
+
+	// Store configures the in-flight edges store, holding the client or server span of an edge until
+	// the span at the other end is received.
+	Store StoreConfig `mapstructure:"store"`
+}
+
+// StoreConfig defines the bounds of the in-flight edges store.
+type StoreConfig struct {
+	// TTL is the time an edge waits for its other end before expiring.
+	TTL time.Duration `mapstructure:"ttl"`
+
+	// MaxItems is the maximum number of edges kept in the store, new edges are dropped once it is full.
+	MaxItems int `mapstructure:"max_items"`
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestNewProcessor(t *testing.T) {
+	for _, tc := range []struct {
+		name                        string
+		latencyHistogramBuckets     []time.Duration
+		wantLatencyHistogramBuckets []float64
+	}{
+		{
+			name:                        "simplest config (use defaults)",
+			wantLatencyHistogramBuckets: defaultLatencyHistogramBucketsSeconds,
+		},
+		{
+			name:                        "latency histogram configured",
+			latencyHistogramBuckets:     []time.Duration{2 * time.Millisecond, time.Second},
+			wantLatencyHistogramBuckets: []float64{0.002, 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Prepare
+			factory := NewFactory()
+
+			creationParams := componenttest.NewNopProcessorCreateSettings()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.LatencyHistogramBuckets = tc.latencyHistogramBuckets
+
+			// Test
+			traceProcessor, err := factory.CreateTracesProcessor(context.Background(), creationParams, cfg, consumertest.NewNop())
+			require.NoError(t, err)
+			sgp := traceProcessor.(*processorImp)
+
+			// Verify
+			assert.Equal(t, tc.wantLatencyHistogramBuckets, sgp.latencyBounds)
+		})
+	}
+}
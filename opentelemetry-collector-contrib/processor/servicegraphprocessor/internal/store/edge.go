@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// Edge is a request between a client and a server service, built from the client span and
+// its server child span.
+type Edge struct {
+	key string
+
+	TraceID pdata.TraceID
+
+	ClientService, ServerService string
+	ClientLatency, ServerLatency time.Duration
+
+	// Failed is true if either the client or the server span has an error status.
+	Failed bool
+
+	// Dimensions are the additional dimensions of the edge metrics, taken from the attributes of both spans.
+	Dimensions map[string]string
+
+	// expiration is the time the edge stops waiting for the span at its other end.
+	expiration time.Time
+}
+
+func newEdge(key string, expiration time.Time) *Edge {
+	return &Edge{
+		key:        key,
+		Dimensions: make(map[string]string),
+		expiration: expiration,
+	}
+}
+
+// isComplete returns true if both the client and the server spans were recorded.
+func (e *Edge) isComplete() bool {
+	return e.ClientService != "" && e.ServerService != ""
+}
+
+func (e *Edge) isExpired(now time.Time) bool {
+	return now.After(e.expiration)
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTooManyItems is returned when an edge can't be added because the store is full.
+var ErrTooManyItems = errors.New("too many items")
+
+// Callback is called with the edges leaving the store.
+type Callback func(e *Edge)
+
+// Store holds the in-flight edges, which wait for the span at their other end until they expire.
+// The edges are kept in a list ordered by creation, hence by expiration, time, along with a map indexing
+// them by key.
+//
+// Store is safe for concurrent use.
+type Store struct {
+	mtx sync.Mutex
+	l   *list.List
+	m   map[string]*list.Element
+
+	onComplete Callback
+	onExpire   Callback
+
+	ttl      time.Duration
+	maxItems int
+
+	// now is the clock of the store, replaced in tests.
+	now func() time.Time
+}
+
+// NewStore creates a Store of at most maxItems edges, each waiting up to ttl for its other end.
+// onComplete is called with the edges as soon as both their client and server spans are recorded,
+// onExpire with the edges expiring before that.
+func NewStore(ttl time.Duration, maxItems int, onComplete, onExpire Callback) *Store {
+	return &Store{
+		l:          list.New(),
+		m:          make(map[string]*list.Element),
+		onComplete: onComplete,
+		onExpire:   onExpire,
+		ttl:        ttl,
+		maxItems:   maxItems,
+		now:        time.Now,
+	}
+}
+
+// Len returns the number of in-flight edges.
+func (s *Store) Len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.l.Len()
+}
+
+// UpsertEdge updates the edge of the given key with the given callback, creating the edge first if it
+// doesn't exist yet. Once the update completes the edge, it is removed from the store and onComplete is
+// called, under the lock of the store. ErrTooManyItems is returned when a new edge can't be added.
+func (s *Store) UpsertEdge(key string, update Callback) (isNew bool, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if elem, ok := s.m[key]; ok {
+		edge := elem.Value.(*Edge)
+		update(edge)
+
+		if edge.isComplete() {
+			s.onComplete(edge)
+			s.remove(elem)
+		}
+		return false, nil
+	}
+
+	edge := newEdge(key, s.now().Add(s.ttl))
+	update(edge)
+
+	if edge.isComplete() {
+		s.onComplete(edge)
+		return true, nil
+	}
+
+	if s.l.Len() >= s.maxItems {
+		return true, ErrTooManyItems
+	}
+
+	s.m[key] = s.l.PushBack(edge)
+	return true, nil
+}
+
+// Expire removes the expired edges, calling onExpire for each of them.
+func (s *Store) Expire() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := s.now()
+	// The edges are ordered by expiration time, the oldest at the front.
+	for elem := s.l.Front(); elem != nil; elem = s.l.Front() {
+		edge := elem.Value.(*Edge)
+		if !edge.isExpired(now) {
+			break
+		}
+		s.onExpire(edge)
+		s.remove(elem)
+	}
+}
+
+func (s *Store) remove(elem *list.Element) {
+	delete(s.m, elem.Value.(*Edge).key)
+	s.l.Remove(elem)
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTTL = time.Second
+
+func newTestStore(maxItems int, now *time.Time) (*Store, *[]*Edge, *[]*Edge) {
+	var completed, expired []*Edge
+	s := NewStore(testTTL, maxItems,
+		func(e *Edge) { completed = append(completed, e) },
+		func(e *Edge) { expired = append(expired, e) },
+	)
+	s.now = func() time.Time { return *now }
+	return s, &completed, &expired
+}
+
+func setClient(e *Edge) {
+	e.ClientService = "client"
+	e.ClientLatency = time.Second
+}
+
+func setServer(e *Edge) {
+	e.ServerService = "server"
+	e.ServerLatency = time.Millisecond
+}
+
+func TestStoreUpsertEdge(t *testing.T) {
+	now := time.Now()
+	s, completed, expired := newTestStore(10, &now)
+
+	isNew, err := s.UpsertEdge("key", setClient)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, 1, s.Len())
+	assert.Empty(t, *completed)
+
+	isNew, err = s.UpsertEdge("key", setServer)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, 0, s.Len())
+	assert.Empty(t, *expired)
+
+	require.Len(t, *completed, 1)
+	edge := (*completed)[0]
+	assert.Equal(t, "client", edge.ClientService)
+	assert.Equal(t, "server", edge.ServerService)
+	assert.Equal(t, time.Second, edge.ClientLatency)
+	assert.Equal(t, time.Millisecond, edge.ServerLatency)
+}
+
+func TestStoreUpsertCompleteEdge(t *testing.T) {
+	now := time.Now()
+	s, completed, _ := newTestStore(10, &now)
+
+	isNew, err := s.UpsertEdge("key", func(e *Edge) {
+		setClient(e)
+		setServer(e)
+	})
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, 0, s.Len())
+	assert.Len(t, *completed, 1)
+}
+
+func TestStoreExpire(t *testing.T) {
+	now := time.Now()
+	s, completed, expired := newTestStore(10, &now)
+
+	_, err := s.UpsertEdge("key-1", setClient)
+	require.NoError(t, err)
+	now = now.Add(testTTL / 2)
+	_, err = s.UpsertEdge("key-2", setServer)
+	require.NoError(t, err)
+
+	// Neither edge expired yet.
+	s.Expire()
+	assert.Equal(t, 2, s.Len())
+
+	// Only the first edge expired.
+	now = now.Add(testTTL/2 + time.Millisecond)
+	s.Expire()
+	assert.Equal(t, 1, s.Len())
+	require.Len(t, *expired, 1)
+	assert.Equal(t, "key-1", (*expired)[0].key)
+
+	// An expired edge isn't completed by its other end.
+	_, err = s.UpsertEdge("key-1", setServer)
+	require.NoError(t, err)
+	assert.Empty(t, *completed)
+	assert.Equal(t, 2, s.Len())
+
+	now = now.Add(testTTL * 2)
+	s.Expire()
+	assert.Equal(t, 0, s.Len())
+	assert.Len(t, *expired, 3)
+}
+
+func TestStoreMaxItems(t *testing.T) {
+	now := time.Now()
+	s, completed, _ := newTestStore(1, &now)
+
+	_, err := s.UpsertEdge("key-1", setClient)
+	require.NoError(t, err)
+
+	isNew, err := s.UpsertEdge("key-2", setClient)
+	assert.True(t, isNew)
+	assert.ErrorIs(t, err, ErrTooManyItems)
+	assert.Equal(t, 1, s.Len())
+
+	// A new edge completed right away doesn't take a place in the store.
+	_, err = s.UpsertEdge("key-3", func(e *Edge) {
+		setClient(e)
+		setServer(e)
+	})
+	require.NoError(t, err)
+	assert.Len(t, *completed, 1)
+
+	// The existing edges can still be completed.
+	_, err = s.UpsertEdge("key-1", setServer)
+	require.NoError(t, err)
+	assert.Len(t, *completed, 2)
+	assert.Equal(t, 0, s.Len())
+}
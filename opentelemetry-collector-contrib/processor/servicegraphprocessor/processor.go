@@ -0,0 +1,398 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor/internal/store"
+)
+
+const (
+	clientKey          = "client"
+	serverKey          = "server"
+	metricKeySeparator = string(byte(0))
+)
+
+var (
+	defaultLatencyHistogramBucketsSeconds = []float64{
+		0.002, 0.004, 0.006, 0.008, 0.01, 0.05, 0.1, 0.2, 0.4, 0.8, 1, 1.4, 2, 5, 10, 15,
+	}
+)
+
+type metricKey string
+
+// histogram is the latency histogram of an edge.
+type histogram struct {
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+type processorImp struct {
+	lock   sync.Mutex
+	logger *zap.Logger
+	config Config
+
+	metricsExporter component.MetricsExporter
+	nextConsumer    consumer.Traces
+
+	// The in-flight edges, waiting for the span at their other end.
+	store *store.Store
+
+	// The starting time of the data points.
+	startTime time.Time
+
+	// Request & failed request counts.
+	reqTotal       map[metricKey]int64
+	reqFailedTotal map[metricKey]int64
+
+	// Latency histograms, as seen by the client and by the server.
+	reqClientSeconds map[metricKey]*histogram
+	reqServerSeconds map[metricKey]*histogram
+	latencyBounds    []float64
+
+	// The dimensions of the edges keyed by a unique identifier formed by a concatenation of their values.
+	keyToDimensions map[metricKey]pdata.AttributeMap
+}
+
+func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer consumer.Traces) (*processorImp, error) {
+	logger.Info("Building servicegraphprocessor")
+	pConfig := config.(*Config)
+
+	bounds := defaultLatencyHistogramBucketsSeconds
+	if pConfig.LatencyHistogramBuckets != nil {
+		bounds = mapDurationsToSeconds(pConfig.LatencyHistogramBuckets)
+	}
+
+	if pConfig.Store.TTL <= 0 {
+		return nil, fmt.Errorf("invalid store ttl %v, must be positive", pConfig.Store.TTL)
+	}
+	if pConfig.Store.MaxItems <= 0 {
+		return nil, fmt.Errorf("invalid store max_items %d, must be positive", pConfig.Store.MaxItems)
+	}
+
+	p := &processorImp{
+		logger:           logger,
+		config:           *pConfig,
+		nextConsumer:     nextConsumer,
+		startTime:        time.Now(),
+		reqTotal:         make(map[metricKey]int64),
+		reqFailedTotal:   make(map[metricKey]int64),
+		reqClientSeconds: make(map[metricKey]*histogram),
+		reqServerSeconds: make(map[metricKey]*histogram),
+		latencyBounds:    bounds,
+		keyToDimensions:  make(map[metricKey]pdata.AttributeMap),
+	}
+	p.store = store.NewStore(pConfig.Store.TTL, pConfig.Store.MaxItems, p.onComplete, p.onExpire)
+	return p, nil
+}
+
+func mapDurationsToSeconds(vs []time.Duration) []float64 {
+	vsm := make([]float64, len(vs))
+	for i, v := range vs {
+		vsm[i] = v.Seconds()
+	}
+	return vsm
+}
+
+// Start implements the component.Component interface.
+func (p *processorImp) Start(_ context.Context, host component.Host) error {
+	p.logger.Info("Starting servicegraphprocessor")
+	exporters := host.GetExporters()
+
+	var availableMetricsExporters []string
+
+	// The available list of exporters come from any configured metrics pipelines' exporters.
+	for k, exp := range exporters[config.MetricsDataType] {
+		metricsExp, ok := exp.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a metrics exporter", k.String())
+		}
+
+		availableMetricsExporters = append(availableMetricsExporters, k.String())
+
+		if k.String() == p.config.MetricsExporter {
+			p.metricsExporter = metricsExp
+			p.logger.Info("Found exporter", zap.String("servicegraph-exporter", p.config.MetricsExporter))
+			break
+		}
+	}
+	if p.metricsExporter == nil {
+		return fmt.Errorf("failed to find metrics exporter: '%s'; please configure metrics_exporter from one of: %+v",
+			p.config.MetricsExporter, availableMetricsExporters)
+	}
+	p.logger.Info("Started servicegraphprocessor")
+	return nil
+}
+
+// Shutdown implements the component.Component interface.
+func (p *processorImp) Shutdown(context.Context) error {
+	p.logger.Info("Shutting down servicegraphprocessor")
+	return nil
+}
+
+// Capabilities implements the consumer interface.
+func (p *processorImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements the consumer.Traces interface.
+// It pairs the client and server spans into edges to generate metrics, forwarding these metrics to the
+// discovered metrics exporter. The original input trace data will be forwarded to the next consumer, unmodified.
+func (p *processorImp) ConsumeTraces(ctx context.Context, traces pdata.Traces) error {
+	// The edges are expired as new spans arrive, before they can complete an expired edge.
+	p.store.Expire()
+
+	p.aggregateMetrics(traces)
+
+	m := p.buildMetrics()
+
+	// Firstly, export metrics to avoid being impacted by downstream trace processor errors/latency.
+	if err := p.metricsExporter.ConsumeMetrics(ctx, m); err != nil {
+		return err
+	}
+
+	// Forward trace data unmodified.
+	return p.nextConsumer.ConsumeTraces(ctx, traces)
+}
+
+// aggregateMetrics records the client and server spans of the given traces in their edges, the metrics
+// being aggregated once an edge completes.
+func (p *processorImp) aggregateMetrics(traces pdata.Traces) {
+	droppedSpans := 0
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rspans := traces.ResourceSpans().At(i)
+		serviceAttr, ok := rspans.Resource().Attributes().Get(conventions.AttributeServiceName)
+		if !ok || serviceAttr.StringVal() == "" {
+			continue
+		}
+		serviceName := serviceAttr.StringVal()
+
+		ilsSlice := rspans.InstrumentationLibrarySpans()
+		for j := 0; j < ilsSlice.Len(); j++ {
+			spans := ilsSlice.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				err := p.aggregateMetricsForSpan(serviceName, spans.At(k))
+				if errors.Is(err, store.ErrTooManyItems) {
+					droppedSpans++
+				}
+			}
+		}
+	}
+
+	if droppedSpans > 0 {
+		p.logger.Warn("Too many in-flight edges, dropped spans", zap.Int("dropped_spans", droppedSpans))
+	}
+}
+
+// aggregateMetricsForSpan records a client span in the edge keyed by its own span ID and a server span
+// in the edge keyed by its parent span ID, which is the span ID of the client span.
+func (p *processorImp) aggregateMetricsForSpan(serviceName string, span pdata.Span) error {
+	var err error
+	switch span.Kind() {
+	case pdata.SpanKindClient:
+		key := buildEdgeKey(span.TraceID(), span.SpanID())
+		_, err = p.store.UpsertEdge(key, func(e *store.Edge) {
+			e.TraceID = span.TraceID()
+			e.ClientService = serviceName
+			e.ClientLatency = spanDuration(span)
+			e.Failed = e.Failed || span.Status().Code() == pdata.StatusCodeError
+			p.upsertDimensions(clientKey, e.Dimensions, span.Attributes())
+		})
+	case pdata.SpanKindServer:
+		if span.ParentSpanID().IsEmpty() {
+			return nil
+		}
+		key := buildEdgeKey(span.TraceID(), span.ParentSpanID())
+		_, err = p.store.UpsertEdge(key, func(e *store.Edge) {
+			e.TraceID = span.TraceID()
+			e.ServerService = serviceName
+			e.ServerLatency = spanDuration(span)
+			e.Failed = e.Failed || span.Status().Code() == pdata.StatusCodeError
+			p.upsertDimensions(serverKey, e.Dimensions, span.Attributes())
+		})
+	}
+	return err
+}
+
+func (p *processorImp) upsertDimensions(prefix string, dims map[string]string, attrs pdata.AttributeMap) {
+	for _, dim := range p.config.Dimensions {
+		if v, ok := attrs.Get(dim); ok {
+			dims[prefix+"_"+dim] = v.AsString()
+		}
+	}
+}
+
+func buildEdgeKey(traceID pdata.TraceID, spanID pdata.SpanID) string {
+	return traceID.HexString() + "-" + spanID.HexString()
+}
+
+func spanDuration(span pdata.Span) time.Duration {
+	return span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime())
+}
+
+// onComplete aggregates the metrics of a complete edge.
+func (p *processorImp) onComplete(e *store.Edge) {
+	p.logger.Debug(
+		"Edge completed",
+		zap.String("client_service", e.ClientService),
+		zap.String("server_service", e.ServerService),
+		zap.String("trace_id", e.TraceID.HexString()),
+	)
+
+	key := p.cache(e)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.reqTotal[key]++
+	if e.Failed {
+		p.reqFailedTotal[key]++
+	}
+	p.updateHistogram(p.reqClientSeconds, key, e.ClientLatency)
+	p.updateHistogram(p.reqServerSeconds, key, e.ServerLatency)
+}
+
+// onExpire drops an edge whose other end was never received.
+func (p *processorImp) onExpire(e *store.Edge) {
+	p.logger.Debug(
+		"Edge expired",
+		zap.String("client_service", e.ClientService),
+		zap.String("server_service", e.ServerService),
+		zap.String("trace_id", e.TraceID.HexString()),
+	)
+}
+
+// updateHistogram records the given latency in the histogram of the given metric key.
+func (p *processorImp) updateHistogram(histograms map[metricKey]*histogram, key metricKey, latency time.Duration) {
+	h, ok := histograms[key]
+	if !ok {
+		h = &histogram{bucketCounts: make([]uint64, len(p.latencyBounds)+1)}
+		histograms[key] = h
+	}
+	seconds := latency.Seconds()
+	h.count++
+	h.sum += seconds
+	// Binary search to find the bucket index, the last bucket holding the latencies above all the bounds.
+	h.bucketCounts[sort.SearchFloat64s(p.latencyBounds, seconds)]++
+}
+
+// cache the dimensions of the edge, returning the metric key uniquely identifying them.
+func (p *processorImp) cache(e *store.Edge) metricKey {
+	dimNames := make([]string, 0, len(e.Dimensions))
+	for k := range e.Dimensions {
+		dimNames = append(dimNames, k)
+	}
+	sort.Strings(dimNames)
+
+	var b strings.Builder
+	b.WriteString(e.ClientService)
+	b.WriteString(metricKeySeparator)
+	b.WriteString(e.ServerService)
+	for _, k := range dimNames {
+		b.WriteString(metricKeySeparator)
+		b.WriteString(k)
+		b.WriteString(metricKeySeparator)
+		b.WriteString(e.Dimensions[k])
+	}
+	key := metricKey(b.String())
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.keyToDimensions[key]; !ok {
+		dims := pdata.NewAttributeMap()
+		dims.UpsertString(clientKey, e.ClientService)
+		dims.UpsertString(serverKey, e.ServerService)
+		for _, k := range dimNames {
+			dims.UpsertString(k, e.Dimensions[k])
+		}
+		p.keyToDimensions[key] = dims
+	}
+	return key
+}
+
+// buildMetrics collects the computed raw metrics data, builds the metrics object and
+// writes the raw metrics data into the metrics object.
+func (p *processorImp) buildMetrics() pdata.Metrics {
+	m := pdata.NewMetrics()
+	ilm := m.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName("servicegraphprocessor")
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := pdata.NewTimestampFromTime(time.Now())
+	start := pdata.NewTimestampFromTime(p.startTime)
+	p.collectCountMetrics(ilm, "traces_service_graph_request_total", p.reqTotal, start, now)
+	p.collectCountMetrics(ilm, "traces_service_graph_request_failed_total", p.reqFailedTotal, start, now)
+	p.collectHistogramMetrics(ilm, "traces_service_graph_request_client_seconds", p.reqClientSeconds, start, now)
+	p.collectHistogramMetrics(ilm, "traces_service_graph_request_server_seconds", p.reqServerSeconds, start, now)
+
+	return m
+}
+
+func (p *processorImp) collectCountMetrics(ilm pdata.InstrumentationLibraryMetrics, name string, counts map[metricKey]int64, start, now pdata.Timestamp) {
+	for key, count := range counts {
+		mCount := ilm.Metrics().AppendEmpty()
+		mCount.SetDataType(pdata.MetricDataTypeSum)
+		mCount.SetName(name)
+		mCount.Sum().SetIsMonotonic(true)
+		mCount.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+		dp := mCount.Sum().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(now)
+		dp.SetIntVal(count)
+
+		p.keyToDimensions[key].CopyTo(dp.Attributes())
+	}
+}
+
+func (p *processorImp) collectHistogramMetrics(ilm pdata.InstrumentationLibraryMetrics, name string, histograms map[metricKey]*histogram, start, now pdata.Timestamp) {
+	for key, h := range histograms {
+		mLatency := ilm.Metrics().AppendEmpty()
+		mLatency.SetDataType(pdata.MetricDataTypeHistogram)
+		mLatency.SetName(name)
+		mLatency.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+		dp := mLatency.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(now)
+		dp.SetExplicitBounds(p.latencyBounds)
+		// Copy the bucket counts so that the exported data point doesn't change with the next edges.
+		bucketCounts := make([]uint64, len(h.bucketCounts))
+		copy(bucketCounts, h.bucketCounts)
+		dp.SetBucketCounts(bucketCounts)
+		dp.SetCount(h.count)
+		dp.SetSum(h.sum)
+
+		p.keyToDimensions[key].CopyTo(dp.Attributes())
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+	"go.uber.org/zap"
+)
+
+const (
+	metricsExporterName = "otlp/servicegraph"
+
+	requestTotalMetric       = "traces_service_graph_request_total"
+	requestFailedTotalMetric = "traces_service_graph_request_failed_total"
+	requestClientMetric      = "traces_service_graph_request_client_seconds"
+	requestServerMetric      = "traces_service_graph_request_server_seconds"
+)
+
+var (
+	traceID = pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	// The spans of the sample trace.
+	demoClientSpanID         = pdata.NewSpanID([8]byte{1})
+	vendorServerSpanID       = pdata.NewSpanID([8]byte{2})
+	vendorClientSpanID       = pdata.NewSpanID([8]byte{3})
+	estimationServerSpanID   = pdata.NewSpanID([8]byte{4})
+	unpairedClientSpanID     = pdata.NewSpanID([8]byte{5})
+	demoClientLatency        = 50 * time.Millisecond
+	vendorServerLatency      = 40 * time.Millisecond
+	vendorClientLatency      = 20 * time.Millisecond
+	estimationServerLatency  = 3 * time.Second
+	defaultTestStoreMaxItems = 10
+)
+
+type metricsExporterSink struct {
+	component.Component
+	*consumertest.MetricsSink
+}
+
+type mockHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (m *mockHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return m.exporters
+}
+
+func newTestProcessor(t *testing.T, cfg *Config) (*processorImp, *consumertest.MetricsSink, *consumertest.TracesSink) {
+	tracesSink := new(consumertest.TracesSink)
+	p, err := newProcessor(zap.NewNop(), cfg, tracesSink)
+	require.NoError(t, err)
+
+	metricsSink := new(consumertest.MetricsSink)
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.MetricsDataType: {
+				config.NewIDWithName("otlp", "servicegraph"): metricsExporterSink{componenthelper.New(), metricsSink},
+			},
+		},
+	}
+	require.NoError(t, p.Start(context.Background(), host))
+	return p, metricsSink, tracesSink
+}
+
+func newTestConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetricsExporter = metricsExporterName
+	cfg.Store.MaxItems = defaultTestStoreMaxItems
+	return cfg
+}
+
+func TestProcessorStartWithoutExporter(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.MetricsExporter = "prometheus"
+	p, err := newProcessor(zap.NewNop(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	assert.EqualError(t, err, "failed to find metrics exporter: 'prometheus'; please configure metrics_exporter from one of: []")
+}
+
+func TestProcessorInvalidStore(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Store.MaxItems = 0
+	_, err := newProcessor(zap.NewNop(), cfg, consumertest.NewNop())
+	assert.EqualError(t, err, "invalid store max_items 0, must be positive")
+
+	cfg = newTestConfig()
+	cfg.Store.TTL = 0
+	_, err = newProcessor(zap.NewNop(), cfg, consumertest.NewNop())
+	assert.EqualError(t, err, "invalid store ttl 0s, must be positive")
+}
+
+func TestProcessorCapabilities(t *testing.T) {
+	p, err := newProcessor(zap.NewNop(), newTestConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	assert.False(t, p.Capabilities().MutatesData)
+}
+
+func TestProcessorConsumeTraces(t *testing.T) {
+	// Prepare
+	cfg := newTestConfig()
+	cfg.Dimensions = []string{"http.method"}
+	p, metricsSink, tracesSink := newTestProcessor(t, cfg)
+
+	traces := buildSampleTrace()
+
+	// Test
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+	// Verify
+	assert.Equal(t, []pdata.Traces{traces}, tracesSink.AllTraces())
+	// The client span without server child waits for it in the store.
+	assert.Equal(t, 1, p.store.Len())
+
+	require.Len(t, metricsSink.AllMetrics(), 1)
+	metrics := metricsSink.AllMetrics()[0]
+	ilms := metrics.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	assert.Equal(t, "servicegraphprocessor", ilms.At(0).InstrumentationLibrary().Name())
+
+	// 2 edges for the total, client and server latency metrics, 1 edge for the failed requests.
+	ms := ilms.At(0).Metrics()
+	require.Equal(t, 7, ms.Len())
+
+	demoToVendor := edgeID{client: "platform-demo", server: "vendor-availability"}
+	vendorToEstimation := edgeID{client: "vendor-availability", server: "time-estimation"}
+	counts := make(map[string]map[edgeID]int64)
+	histograms := make(map[string]map[edgeID]pdata.HistogramDataPoint)
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		switch m.DataType() {
+		case pdata.MetricDataTypeSum:
+			assert.True(t, m.Sum().IsMonotonic())
+			assert.Equal(t, pdata.AggregationTemporalityCumulative, m.Sum().AggregationTemporality())
+			dp := m.Sum().DataPoints().At(0)
+			if counts[m.Name()] == nil {
+				counts[m.Name()] = make(map[edgeID]int64)
+			}
+			counts[m.Name()][getEdgeID(t, dp.Attributes())] = dp.IntVal()
+		case pdata.MetricDataTypeHistogram:
+			assert.Equal(t, pdata.AggregationTemporalityCumulative, m.Histogram().AggregationTemporality())
+			dp := m.Histogram().DataPoints().At(0)
+			if histograms[m.Name()] == nil {
+				histograms[m.Name()] = make(map[edgeID]pdata.HistogramDataPoint)
+			}
+			histograms[m.Name()][getEdgeID(t, dp.Attributes())] = dp
+		default:
+			assert.Failf(t, "unexpected metric type", "%v", m.DataType())
+		}
+	}
+
+	assert.Equal(t, map[edgeID]int64{demoToVendor: 1, vendorToEstimation: 1}, counts[requestTotalMetric])
+	assert.Equal(t, map[edgeID]int64{vendorToEstimation: 1}, counts[requestFailedTotalMetric])
+
+	assertHistogram(t, histograms[requestClientMetric][demoToVendor], demoClientLatency)
+	assertHistogram(t, histograms[requestServerMetric][demoToVendor], vendorServerLatency)
+	assertHistogram(t, histograms[requestClientMetric][vendorToEstimation], vendorClientLatency)
+	assertHistogram(t, histograms[requestServerMetric][vendorToEstimation], estimationServerLatency)
+
+	// The dimensions are taken from both spans.
+	attrs := histograms[requestClientMetric][demoToVendor].Attributes()
+	method, ok := attrs.Get("client_http.method")
+	require.True(t, ok)
+	assert.Equal(t, "GET", method.StringVal())
+	method, ok = attrs.Get("server_http.method")
+	require.True(t, ok)
+	assert.Equal(t, "POST", method.StringVal())
+	_, ok = histograms[requestClientMetric][vendorToEstimation].Attributes().Get("client_http.method")
+	assert.False(t, ok)
+}
+
+func TestProcessorConsumeTracesExpiredEdges(t *testing.T) {
+	// Prepare
+	cfg := newTestConfig()
+	cfg.Store.TTL = time.Nanosecond
+	p, metricsSink, _ := newTestProcessor(t, cfg)
+
+	traces := pdata.NewTraces()
+	rspans := traces.ResourceSpans().AppendEmpty()
+	rspans.Resource().Attributes().InsertString(conventions.AttributeServiceName, "platform-demo")
+	appendSpan(rspans, unpairedClientSpanID, pdata.SpanID{}, pdata.SpanKindClient, time.Millisecond)
+
+	// Test
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	assert.Equal(t, 1, p.store.Len())
+	time.Sleep(time.Millisecond)
+	require.NoError(t, p.ConsumeTraces(context.Background(), pdata.NewTraces()))
+
+	// Verify
+	assert.Equal(t, 0, p.store.Len())
+	require.Len(t, metricsSink.AllMetrics(), 2)
+	assert.Equal(t, 0, metricsSink.AllMetrics()[1].MetricCount())
+}
+
+func TestProcessorConsumeTracesTooManyEdges(t *testing.T) {
+	// Prepare
+	cfg := newTestConfig()
+	cfg.Store.MaxItems = 1
+	p, _, tracesSink := newTestProcessor(t, cfg)
+
+	traces := pdata.NewTraces()
+	rspans := traces.ResourceSpans().AppendEmpty()
+	rspans.Resource().Attributes().InsertString(conventions.AttributeServiceName, "platform-demo")
+	appendSpan(rspans, demoClientSpanID, pdata.SpanID{}, pdata.SpanKindClient, time.Millisecond)
+	appendSpan(rspans, unpairedClientSpanID, pdata.SpanID{}, pdata.SpanKindClient, time.Millisecond)
+
+	// Test
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+	// Verify
+	assert.Equal(t, 1, p.store.Len())
+	// The traces are forwarded despite the dropped edge.
+	assert.Len(t, tracesSink.AllTraces(), 1)
+}
+
+type edgeID struct {
+	client string
+	server string
+}
+
+func getEdgeID(t *testing.T, attrs pdata.AttributeMap) edgeID {
+	client, ok := attrs.Get(clientKey)
+	require.True(t, ok)
+	server, ok := attrs.Get(serverKey)
+	require.True(t, ok)
+	return edgeID{client: client.StringVal(), server: server.StringVal()}
+}
+
+func assertHistogram(t *testing.T, dp pdata.HistogramDataPoint, latency time.Duration) {
+	assert.Equal(t, uint64(1), dp.Count())
+	assert.InDelta(t, latency.Seconds(), dp.Sum(), 1e-9)
+	assert.Equal(t, defaultLatencyHistogramBucketsSeconds, dp.ExplicitBounds())
+	require.Len(t, dp.BucketCounts(), len(defaultLatencyHistogramBucketsSeconds)+1)
+
+	var total uint64
+	for i, count := range dp.BucketCounts() {
+		total += count
+		if count == 0 {
+			continue
+		}
+		if i < len(defaultLatencyHistogramBucketsSeconds) {
+			assert.LessOrEqual(t, latency.Seconds(), defaultLatencyHistogramBucketsSeconds[i])
+		}
+		if i > 0 {
+			assert.Greater(t, latency.Seconds(), defaultLatencyHistogramBucketsSeconds[i-1])
+		}
+	}
+	assert.Equal(t, uint64(1), total)
+}
+
+// buildSampleTrace builds the following trace, split across the resources of the services:
+//
+//	platform-demo (client) ->
+//	  vendor-availability (server)
+//	  vendor-availability (client) ->
+//	    time-estimation (server, error)
+//	platform-demo (client, without server child)
+func buildSampleTrace() pdata.Traces {
+	traces := pdata.NewTraces()
+
+	demo := traces.ResourceSpans().AppendEmpty()
+	demo.Resource().Attributes().InsertString(conventions.AttributeServiceName, "platform-demo")
+	appendSpan(demo, demoClientSpanID, pdata.SpanID{}, pdata.SpanKindClient, demoClientLatency).
+		Attributes().InsertString("http.method", "GET")
+	appendSpan(demo, unpairedClientSpanID, pdata.SpanID{}, pdata.SpanKindClient, time.Millisecond)
+
+	vendor := traces.ResourceSpans().AppendEmpty()
+	vendor.Resource().Attributes().InsertString(conventions.AttributeServiceName, "vendor-availability")
+	appendSpan(vendor, vendorServerSpanID, demoClientSpanID, pdata.SpanKindServer, vendorServerLatency).
+		Attributes().InsertString("http.method", "POST")
+	appendSpan(vendor, vendorClientSpanID, vendorServerSpanID, pdata.SpanKindClient, vendorClientLatency)
+
+	estimation := traces.ResourceSpans().AppendEmpty()
+	estimation.Resource().Attributes().InsertString(conventions.AttributeServiceName, "time-estimation")
+	appendSpan(estimation, estimationServerSpanID, vendorClientSpanID, pdata.SpanKindServer, estimationServerLatency).
+		Status().SetCode(pdata.StatusCodeError)
+	// Internal spans don't make edges.
+	appendSpan(estimation, pdata.NewSpanID([8]byte{6}), estimationServerSpanID, pdata.SpanKindInternal, time.Millisecond)
+
+	// Spans without service name are ignored.
+	appendSpan(traces.ResourceSpans().AppendEmpty(), pdata.NewSpanID([8]byte{7}), demoClientSpanID, pdata.SpanKindServer, time.Millisecond)
+
+	return traces
+}
+
+func appendSpan(rspans pdata.ResourceSpans, spanID, parentSpanID pdata.SpanID, kind pdata.SpanKind, latency time.Duration) pdata.Span {
+	if rspans.InstrumentationLibrarySpans().Len() == 0 {
+		rspans.InstrumentationLibrarySpans().AppendEmpty()
+	}
+	span := rspans.InstrumentationLibrarySpans().At(0).Spans().AppendEmpty()
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetParentSpanID(parentSpanID)
+	span.SetKind(kind)
+	now := time.Now()
+	span.SetStartTimestamp(pdata.NewTimestampFromTime(now))
+	span.SetEndTimestamp(pdata.NewTimestampFromTime(now.Add(latency)))
+	return span
+}
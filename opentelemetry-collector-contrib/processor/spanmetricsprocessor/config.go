@@ -18,6 +18,12 @@ import (
 	"time"
 
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+const (
+	delta      = "AGGREGATION_TEMPORALITY_DELTA"
+	cumulative = "AGGREGATION_TEMPORALITY_CUMULATIVE"
 )
 
 // Dimension defines the dimension name and optional default value if the Dimension is missing from a span attribute.
@@ -45,4 +51,22 @@ type Config struct {
 	// The dimensions will be fetched from the span's attributes. Examples of some conventionally used attributes:
 	// https://github.com/open-telemetry/opentelemetry-collector/blob/main/model/semconv/opentelemetry.go.
 	Dimensions []Dimension `mapstructure:"dimensions"`
+
+	// DimensionsCacheSize defines the size of cache for storing Dimensions, which helps to avoid cache memory growing
+	// indefinitely over the lifetime of the collector.
+	// Optional. See defaultDimensionsCacheSize in processor.go for the default value.
+	DimensionsCacheSize int `mapstructure:"dimensions_cache_size"`
+
+	// AggregationTemporality is the aggregation temporality of the generated metrics, either
+	// AGGREGATION_TEMPORALITY_CUMULATIVE or AGGREGATION_TEMPORALITY_DELTA.
+	// Optional. Defaults to AGGREGATION_TEMPORALITY_CUMULATIVE.
+	AggregationTemporality string `mapstructure:"aggregation_temporality"`
+}
+
+// GetAggregationTemporality converts the string value given in the config into a pdata.AggregationTemporality.
+func (c Config) GetAggregationTemporality() pdata.AggregationTemporality {
+	if c.AggregationTemporality == delta {
+		return pdata.AggregationTemporalityDelta
+	}
+	return pdata.AggregationTemporalityCumulative
 }
@@ -40,9 +40,21 @@ func TestLoadConfig(t *testing.T) {
 		wantMetricsExporter         string
 		wantLatencyHistogramBuckets []time.Duration
 		wantDimensions              []Dimension
+		wantDimensionsCacheSize     int
+		wantAggregationTemporality  string
 	}{
-		{configFile: "config-2-pipelines.yaml", wantMetricsExporter: "prometheus"},
-		{configFile: "config-3-pipelines.yaml", wantMetricsExporter: "otlp/spanmetrics"},
+		{
+			configFile:                 "config-2-pipelines.yaml",
+			wantMetricsExporter:        "prometheus",
+			wantAggregationTemporality: cumulative,
+			wantDimensionsCacheSize:    defaultDimensionsCacheSize,
+		},
+		{
+			configFile:                 "config-3-pipelines.yaml",
+			wantMetricsExporter:        "otlp/spanmetrics",
+			wantAggregationTemporality: cumulative,
+			wantDimensionsCacheSize:    defaultDimensionsCacheSize,
+		},
 		{
 			configFile:          "config-full.yaml",
 			wantMetricsExporter: "otlp/spanmetrics",
@@ -59,6 +71,8 @@ func TestLoadConfig(t *testing.T) {
 				{"http.method", &defaultMethod},
 				{"http.status_code", nil},
 			},
+			wantDimensionsCacheSize:    1500,
+			wantAggregationTemporality: delta,
 		},
 	}
 	for _, tc := range testcases {
@@ -89,6 +103,8 @@ func TestLoadConfig(t *testing.T) {
 					MetricsExporter:         tc.wantMetricsExporter,
 					LatencyHistogramBuckets: tc.wantLatencyHistogramBuckets,
 					Dimensions:              tc.wantDimensions,
+					DimensionsCacheSize:     tc.wantDimensionsCacheSize,
+					AggregationTemporality:  tc.wantAggregationTemporality,
 				},
 				cfg.Processors[config.NewID(typeStr)],
 			)
@@ -39,7 +39,9 @@ func NewFactory() component.ProcessorFactory {
 
 func createDefaultConfig() config.Processor {
 	return &Config{
-		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
+		ProcessorSettings:      config.NewProcessorSettings(config.NewID(typeStr)),
+		AggregationTemporality: cumulative,
+		DimensionsCacheSize:    defaultDimensionsCacheSize,
 	}
 }
 
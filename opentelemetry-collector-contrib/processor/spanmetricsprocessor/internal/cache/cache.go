@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"github.com/hashicorp/golang-lru/simplelru"
+)
+
+// Cache consists of an LRU cache and the items evicted from it.
+// All the cached items can be retrieved either from the LRU cache or from the evicted items,
+// which are kept until RemoveEvictedItems is called. In spanmetricsprocessor's use case, the
+// items used while processing a batch of spans must all be available when building the metrics
+// at the end of the batch, after which the evicted items can be safely removed.
+//
+// Cache is not safe for concurrent use.
+type Cache struct {
+	*simplelru.LRU
+	evictedItems map[interface{}]interface{}
+}
+
+// NewCache creates a Cache holding at most size items in its LRU cache.
+func NewCache(size int) (*Cache, error) {
+	evictedItems := make(map[interface{}]interface{})
+	lru, err := simplelru.NewLRU(size, func(key, value interface{}) {
+		evictedItems[key] = value
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{
+		LRU:          lru,
+		evictedItems: evictedItems,
+	}, nil
+}
+
+// RemoveEvictedItems cleans up the items evicted from the LRU cache.
+func (c *Cache) RemoveEvictedItems() {
+	for key := range c.evictedItems {
+		delete(c.evictedItems, key)
+	}
+}
+
+// Add adds the item to the LRU cache, it is no longer an evicted item if it was evicted before.
+func (c *Cache) Add(key, value interface{}) bool {
+	delete(c.evictedItems, key)
+	return c.LRU.Add(key, value)
+}
+
+// Get retrieves an item from the LRU cache or from the evicted items.
+func (c *Cache) Get(key interface{}) (interface{}, bool) {
+	if val, ok := c.LRU.Get(key); ok {
+		return val, ok
+	}
+	val, ok := c.evictedItems[key]
+	return val, ok
+}
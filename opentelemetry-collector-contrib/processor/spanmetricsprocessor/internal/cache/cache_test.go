@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCache(t *testing.T) {
+	_, err := NewCache(0)
+	assert.Error(t, err)
+
+	c, err := NewCache(1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestCache_Get(t *testing.T) {
+	c, err := NewCache(1)
+	require.NoError(t, err)
+
+	c.Add("key1", "value1")
+	c.Add("key2", "value2")
+
+	// key1 was evicted from the LRU cache but is still available.
+	assert.False(t, c.Contains("key1"))
+	val, ok := c.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", val)
+
+	val, ok = c.Get("key2")
+	assert.True(t, ok)
+	assert.Equal(t, "value2", val)
+
+	_, ok = c.Get("key3")
+	assert.False(t, ok)
+}
+
+func TestCache_RemoveEvictedItems(t *testing.T) {
+	c, err := NewCache(1)
+	require.NoError(t, err)
+
+	c.Add("key1", "value1")
+	c.Add("key2", "value2")
+	c.RemoveEvictedItems()
+
+	_, ok := c.Get("key1")
+	assert.False(t, ok)
+	val, ok := c.Get("key2")
+	assert.True(t, ok)
+	assert.Equal(t, "value2", val)
+}
+
+func TestCache_AddEvictedItem(t *testing.T) {
+	c, err := NewCache(1)
+	require.NoError(t, err)
+
+	c.Add("key1", "value1")
+	c.Add("key2", "value2")
+	// Adding key1 again evicts key2 and brings key1 back to the LRU cache.
+	c.Add("key1", "value1")
+	c.RemoveEvictedItems()
+
+	assert.True(t, c.Contains("key1"))
+	_, ok := c.Get("key2")
+	assert.False(t, ok)
+}
@@ -30,6 +30,8 @@ import (
 	"go.opentelemetry.io/collector/model/pdata"
 	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/cache"
 )
 
 const (
@@ -38,6 +40,9 @@ const (
 	spanKindKey        = "span.kind"   // OpenTelemetry non-standard constant.
 	statusCodeKey      = "status.code" // OpenTelemetry non-standard constant.
 	metricKeySeparator = string(byte(0))
+	traceIDKey         = "trace_id"
+
+	defaultDimensionsCacheSize = 1000
 )
 
 var (
@@ -51,8 +56,15 @@ var (
 
 type metricKey string
 
+// exemplarData is the latest span seen for a latency histogram bucket.
+type exemplarData struct {
+	traceID   pdata.TraceID
+	value     float64
+	timestamp pdata.Timestamp
+}
+
 type processorImp struct {
-	lock   sync.RWMutex
+	lock   sync.Mutex
 	logger *zap.Logger
 	config Config
 
@@ -62,9 +74,14 @@ type processorImp struct {
 	// Additional dimensions to add to metrics.
 	dimensions []Dimension
 
-	// The starting time of the data points.
+	// The starting time of the data points. With delta temporality, it is the time the
+	// metrics were last built.
 	startTime time.Time
 
+	// With cumulative temporality, the starting time of the data points of each metric key, the
+	// time it was first counted since the processor started or since it was evicted from the cache.
+	metricKeyStartTime map[metricKey]time.Time
+
 	// Call & Error counts.
 	callSum map[metricKey]int64
 
@@ -74,9 +91,12 @@ type processorImp struct {
 	latencyBucketCounts map[metricKey][]uint64
 	latencyBounds       []float64
 
-	// A cache of dimension key-value maps keyed by a unique identifier formed by a concatenation of its values:
+	// Latency exemplars, holding the latest span seen for each bucket since the metrics were last built.
+	latencyExemplarsData map[metricKey][]*exemplarData
+
+	// An LRU cache of dimension key-value maps keyed by a unique identifier formed by a concatenation of its values:
 	// e.g. { "foo/barOK": { "serviceName": "foo", "operation": "/bar", "status_code": "OK" }}
-	metricKeyToDimensions map[metricKey]pdata.AttributeMap
+	metricKeyToDimensions *cache.Cache
 }
 
 func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer consumer.Traces) (*processorImp, error) {
@@ -97,18 +117,29 @@ func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer cons
 		return nil, err
 	}
 
+	if err := validateAggregationTemporality(pConfig.AggregationTemporality); err != nil {
+		return nil, err
+	}
+
+	metricKeyToDimensionsCache, err := cache.NewCache(pConfig.DimensionsCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dimensions_cache_size %d: %w", pConfig.DimensionsCacheSize, err)
+	}
+
 	return &processorImp{
 		logger:                logger,
 		config:                *pConfig,
 		startTime:             time.Now(),
+		metricKeyStartTime:    make(map[metricKey]time.Time),
 		callSum:               make(map[metricKey]int64),
 		latencyBounds:         bounds,
 		latencySum:            make(map[metricKey]float64),
 		latencyCount:          make(map[metricKey]uint64),
 		latencyBucketCounts:   make(map[metricKey][]uint64),
+		latencyExemplarsData:  make(map[metricKey][]*exemplarData),
 		nextConsumer:          nextConsumer,
 		dimensions:            pConfig.Dimensions,
-		metricKeyToDimensions: make(map[metricKey]pdata.AttributeMap),
+		metricKeyToDimensions: metricKeyToDimensionsCache,
 	}, nil
 }
 
@@ -155,6 +186,17 @@ func validateDimensions(dimensions []Dimension) error {
 	return nil
 }
 
+// validateAggregationTemporality checks the aggregation temporality is either cumulative or delta,
+// an empty value defaulting to cumulative.
+func validateAggregationTemporality(temporality string) error {
+	switch temporality {
+	case "", cumulative, delta:
+		return nil
+	default:
+		return fmt.Errorf("invalid aggregation_temporality %q, must be one of %s or %s", temporality, cumulative, delta)
+	}
+}
+
 // Start implements the component.Component interface.
 func (p *processorImp) Start(ctx context.Context, host component.Host) error {
 	p.logger.Info("Starting spanmetricsprocessor")
@@ -224,52 +266,109 @@ func (p *processorImp) buildMetrics() *pdata.Metrics {
 	ilm := m.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
 	ilm.InstrumentationLibrary().SetName("spanmetricsprocessor")
 
-	p.lock.RLock()
-	p.collectCallMetrics(ilm)
-	p.collectLatencyMetrics(ilm)
-	p.lock.RUnlock()
+	p.lock.Lock()
+	now := time.Now()
+	p.collectCallMetrics(ilm, now)
+	p.collectLatencyMetrics(ilm, now)
+	p.resetAccumulatedMetrics(now)
+	p.lock.Unlock()
 
 	return &m
 }
 
+// resetAccumulatedMetrics resets the accumulated metrics once they were built at the given time.
+// The exemplars are always reset, the other metrics only with delta temporality.
+// The metrics of the dimension sets evicted from the cache are removed.
+func (p *processorImp) resetAccumulatedMetrics(now time.Time) {
+	p.latencyExemplarsData = make(map[metricKey][]*exemplarData)
+
+	if p.config.GetAggregationTemporality() == pdata.AggregationTemporalityDelta {
+		p.startTime = now
+		p.callSum = make(map[metricKey]int64)
+		p.latencyCount = make(map[metricKey]uint64)
+		p.latencySum = make(map[metricKey]float64)
+		p.latencyBucketCounts = make(map[metricKey][]uint64)
+	}
+
+	p.metricKeyToDimensions.RemoveEvictedItems()
+	for key := range p.callSum {
+		if !p.metricKeyToDimensions.Contains(key) {
+			delete(p.metricKeyStartTime, key)
+			delete(p.callSum, key)
+			delete(p.latencyCount, key)
+			delete(p.latencySum, key)
+			delete(p.latencyBucketCounts, key)
+		}
+	}
+}
+
 // collectLatencyMetrics collects the raw latency metrics, writing the data
 // into the given instrumentation library metrics.
-func (p *processorImp) collectLatencyMetrics(ilm pdata.InstrumentationLibraryMetrics) {
+func (p *processorImp) collectLatencyMetrics(ilm pdata.InstrumentationLibraryMetrics, now time.Time) {
 	for key := range p.latencyCount {
 		mLatency := ilm.Metrics().AppendEmpty()
 		mLatency.SetDataType(pdata.MetricDataTypeHistogram)
 		mLatency.SetName("latency")
-		mLatency.Histogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		mLatency.Histogram().SetAggregationTemporality(p.config.GetAggregationTemporality())
 
 		dpLatency := mLatency.Histogram().DataPoints().AppendEmpty()
-		dpLatency.SetStartTimestamp(pdata.NewTimestampFromTime(p.startTime))
-		dpLatency.SetTimestamp(pdata.NewTimestampFromTime(time.Now()))
+		dpLatency.SetStartTimestamp(pdata.NewTimestampFromTime(p.startTimeOf(key)))
+		dpLatency.SetTimestamp(pdata.NewTimestampFromTime(now))
 		dpLatency.SetExplicitBounds(p.latencyBounds)
-		dpLatency.SetBucketCounts(p.latencyBucketCounts[key])
+		// Copy the bucket counts so that the exported data point doesn't change with the next spans.
+		bucketCounts := make([]uint64, len(p.latencyBucketCounts[key]))
+		copy(bucketCounts, p.latencyBucketCounts[key])
+		dpLatency.SetBucketCounts(bucketCounts)
 		dpLatency.SetCount(p.latencyCount[key])
 		dpLatency.SetSum(p.latencySum[key])
 
-		p.metricKeyToDimensions[key].CopyTo(dpLatency.Attributes())
+		setLatencyExemplars(p.latencyExemplarsData[key], dpLatency.Exemplars())
+		p.getDimensionsByMetricKey(key).CopyTo(dpLatency.Attributes())
+	}
+}
+
+// setLatencyExemplars appends the exemplars of the latency histogram buckets, identifying the spans by their
+// trace ID.
+func setLatencyExemplars(exemplarsData []*exemplarData, exemplars pdata.ExemplarSlice) {
+	for _, ed := range exemplarsData {
+		if ed == nil {
+			continue
+		}
+		exemplar := exemplars.AppendEmpty()
+		exemplar.SetDoubleVal(ed.value)
+		exemplar.SetTimestamp(ed.timestamp)
+		exemplar.FilteredAttributes().InsertString(traceIDKey, ed.traceID.HexString())
 	}
 }
 
 // collectCallMetrics collects the raw call count metrics, writing the data
 // into the given instrumentation library metrics.
-func (p *processorImp) collectCallMetrics(ilm pdata.InstrumentationLibraryMetrics) {
+func (p *processorImp) collectCallMetrics(ilm pdata.InstrumentationLibraryMetrics, now time.Time) {
 	for key := range p.callSum {
 		mCalls := ilm.Metrics().AppendEmpty()
 		mCalls.SetDataType(pdata.MetricDataTypeSum)
 		mCalls.SetName("calls_total")
 		mCalls.Sum().SetIsMonotonic(true)
-		mCalls.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		mCalls.Sum().SetAggregationTemporality(p.config.GetAggregationTemporality())
 
 		dpCalls := mCalls.Sum().DataPoints().AppendEmpty()
-		dpCalls.SetStartTimestamp(pdata.NewTimestampFromTime(p.startTime))
-		dpCalls.SetTimestamp(pdata.NewTimestampFromTime(time.Now()))
+		dpCalls.SetStartTimestamp(pdata.NewTimestampFromTime(p.startTimeOf(key)))
+		dpCalls.SetTimestamp(pdata.NewTimestampFromTime(now))
 		dpCalls.SetIntVal(p.callSum[key])
 
-		p.metricKeyToDimensions[key].CopyTo(dpCalls.Attributes())
+		p.getDimensionsByMetricKey(key).CopyTo(dpCalls.Attributes())
+	}
+}
+
+// getDimensionsByMetricKey gets the dimensions of the given metric key from the cache, which holds them
+// at least until the metrics are built.
+func (p *processorImp) getDimensionsByMetricKey(k metricKey) pdata.AttributeMap {
+	if item, ok := p.metricKeyToDimensions.Get(k); ok {
+		return item.(pdata.AttributeMap)
 	}
+	// Not expected, every metric key is cached when aggregating its span.
+	p.logger.Error("Dimensions not found in cache", zap.String("metric_key", string(k)))
+	return pdata.NewAttributeMap()
 }
 
 // aggregateMetrics aggregates the raw metrics from the input trace data.
@@ -314,14 +413,26 @@ func (p *processorImp) aggregateMetricsForSpan(serviceName string, span pdata.Sp
 	p.cache(serviceName, span, key)
 	p.updateCallMetrics(key)
 	p.updateLatencyMetrics(key, latencyInMilliseconds, index)
+	p.updateLatencyExemplars(key, span, latencyInMilliseconds, index)
 	p.lock.Unlock()
 }
 
 // updateCallMetrics increments the call count for the given metric key.
 func (p *processorImp) updateCallMetrics(key metricKey) {
+	if _, ok := p.callSum[key]; !ok && p.config.GetAggregationTemporality() == pdata.AggregationTemporalityCumulative {
+		p.metricKeyStartTime[key] = time.Now()
+	}
 	p.callSum[key]++
 }
 
+// startTimeOf returns the starting time of the data points of the given metric key.
+func (p *processorImp) startTimeOf(key metricKey) time.Time {
+	if startTime, ok := p.metricKeyStartTime[key]; ok {
+		return startTime
+	}
+	return p.startTime
+}
+
 // updateLatencyMetrics increments the histogram counts for the given metric key and bucket index.
 func (p *processorImp) updateLatencyMetrics(key metricKey, latency float64, index int) {
 	if _, ok := p.latencyBucketCounts[key]; !ok {
@@ -332,6 +443,21 @@ func (p *processorImp) updateLatencyMetrics(key metricKey, latency float64, inde
 	p.latencyBucketCounts[key][index]++
 }
 
+// updateLatencyExemplars keeps the given span as the exemplar of the given metric key and bucket index.
+func (p *processorImp) updateLatencyExemplars(key metricKey, span pdata.Span, latency float64, index int) {
+	if span.TraceID().IsEmpty() {
+		return
+	}
+	if _, ok := p.latencyExemplarsData[key]; !ok {
+		p.latencyExemplarsData[key] = make([]*exemplarData, len(p.latencyBounds))
+	}
+	p.latencyExemplarsData[key][index] = &exemplarData{
+		traceID:   span.TraceID(),
+		value:     latency,
+		timestamp: span.EndTimestamp(),
+	}
+}
+
 func buildDimensionKVs(serviceName string, span pdata.Span, optionalDims []Dimension) pdata.AttributeMap {
 	dims := pdata.NewAttributeMap()
 	dims.UpsertString(serviceNameKey, serviceName)
@@ -387,11 +513,12 @@ func buildKey(serviceName string, span pdata.Span, optionalDims []Dimension) met
 }
 
 // cache the dimension key-value map for the metricKey if there is a cache miss.
-// This enables a lookup of the dimension key-value map when constructing the metric like so:
-//   LabelsMap().InitFromMap(p.metricKeyToDimensions[key])
+// This enables a lookup of the dimension key-value map when constructing the metric
+// with getDimensionsByMetricKey. A hit marks the metricKey as recently used in the LRU cache,
+// a metricKey evicted since the metrics were last built is cached again.
 func (p *processorImp) cache(serviceName string, span pdata.Span, k metricKey) {
-	if _, ok := p.metricKeyToDimensions[k]; !ok {
-		p.metricKeyToDimensions[k] = buildDimensionKVs(serviceName, span, p.dimensions)
+	if _, ok := p.metricKeyToDimensions.LRU.Get(k); !ok {
+		p.metricKeyToDimensions.Add(k, buildDimensionKVs(serviceName, span, p.dimensions))
 	}
 }
 
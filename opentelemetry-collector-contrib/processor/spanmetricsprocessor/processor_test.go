@@ -34,6 +34,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/mocks"
 )
 
@@ -50,6 +51,8 @@ const (
 
 	sampleLatency         = float64(11)
 	sampleLatencyDuration = time.Duration(sampleLatency) * time.Millisecond
+
+	dimensionsCacheSize = 2
 )
 
 // metricID represents the minimum attributes that uniquely identifies a metric in our tests.
@@ -200,7 +203,7 @@ func TestProcessorConsumeTracesErrors(t *testing.T) {
 			tcon := &mocks.TracesConsumer{}
 			tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(tc.consumeTracesErr)
 
-			p := newProcessorImp(mexp, tcon, nil)
+			p := newProcessorImp(mexp, tcon, nil, cumulative, t)
 
 			traces := buildSampleTrace()
 
@@ -215,26 +218,52 @@ func TestProcessorConsumeTracesErrors(t *testing.T) {
 }
 
 func TestProcessorConsumeTraces(t *testing.T) {
-	// Prepare
-	mexp := &mocks.MetricsExporter{}
-	tcon := &mocks.TracesConsumer{}
+	testcases := []struct {
+		name                   string
+		aggregationTemporality string
+		wantTemporality        pdata.AggregationTemporality
+		// The expected call count of each metric after the sample trace was consumed twice.
+		wantCallCount int64
+	}{
+		{
+			name:                   "Test cumulative temporality",
+			aggregationTemporality: cumulative,
+			wantTemporality:        pdata.AggregationTemporalityCumulative,
+			wantCallCount:          2,
+		},
+		{
+			name:                   "Test delta temporality",
+			aggregationTemporality: delta,
+			wantTemporality:        pdata.AggregationTemporalityDelta,
+			wantCallCount:          1,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Prepare
+			mexp := &mocks.MetricsExporter{}
+			tcon := &mocks.TracesConsumer{}
 
-	mexp.On("ConsumeMetrics", mock.Anything, mock.MatchedBy(func(input pdata.Metrics) bool {
-		return verifyConsumeMetricsInput(input, t)
-	})).Return(nil)
-	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+			mexp.On("ConsumeMetrics", mock.Anything, mock.Anything).Return(nil)
+			tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
 
-	defaultNullValue := "defaultNullValue"
-	p := newProcessorImp(mexp, tcon, &defaultNullValue)
+			defaultNullValue := "defaultNullValue"
+			p := newProcessorImp(mexp, tcon, &defaultNullValue, tc.aggregationTemporality, t)
 
-	traces := buildSampleTrace()
+			traces := buildSampleTrace()
 
-	// Test
-	ctx := metadata.NewIncomingContext(context.Background(), nil)
-	err := p.ConsumeTraces(ctx, traces)
+			// Test
+			ctx := metadata.NewIncomingContext(context.Background(), nil)
+			require.NoError(t, p.ConsumeTraces(ctx, traces))
+			err := p.ConsumeTraces(ctx, traces)
 
-	// Verify
-	assert.NoError(t, err)
+			// Verify
+			assert.NoError(t, err)
+			require.Len(t, mexp.Calls, 2)
+			verifyConsumeMetricsInput(mexp.Calls[0].Arguments.Get(1).(pdata.Metrics), tc.wantTemporality, 1, t)
+			verifyConsumeMetricsInput(mexp.Calls[1].Arguments.Get(1).(pdata.Metrics), tc.wantTemporality, tc.wantCallCount, t)
+		})
+	}
 }
 
 func TestMetricKeyCache(t *testing.T) {
@@ -246,24 +275,123 @@ func TestMetricKeyCache(t *testing.T) {
 	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
 
 	defaultNullValue := "defaultNullValue"
-	p := newProcessorImp(mexp, tcon, &defaultNullValue)
+	p := newProcessorImp(mexp, tcon, &defaultNullValue, cumulative, t)
+	// Use a cache smaller than the number of dimension sets of the sample trace to test the eviction.
+	metricKeyToDimensions, err := cache.NewCache(dimensionsCacheSize)
+	require.NoError(t, err)
+	p.metricKeyToDimensions = metricKeyToDimensions
 
 	traces := buildSampleTrace()
 
 	// Test
 	ctx := metadata.NewIncomingContext(context.Background(), nil)
-	err := p.ConsumeTraces(ctx, traces)
 
+	// 0 key was cached at beginning
+	assert.Zero(t, p.metricKeyToDimensions.Len())
+
+	err = p.ConsumeTraces(ctx, traces)
 	// Validate
 	require.NoError(t, err)
+	// 2 keys were cached, 1 key was evicted and cleaned after the processing
+	assert.Equal(t, dimensionsCacheSize, p.metricKeyToDimensions.Len())
 
-	origKeyCache := make(map[metricKey]pdata.AttributeMap)
-	for k, v := range p.metricKeyToDimensions {
-		origKeyCache[k] = v
-	}
+	// consume another batch of traces
 	err = p.ConsumeTraces(ctx, traces)
 	require.NoError(t, err)
-	assert.Equal(t, origKeyCache, p.metricKeyToDimensions)
+
+	// 2 keys were cached, other keys were evicted and cleaned after the processing
+	assert.Equal(t, dimensionsCacheSize, p.metricKeyToDimensions.Len())
+	// The metrics of the evicted keys were removed as well
+	assert.Len(t, p.callSum, dimensionsCacheSize)
+	assert.Len(t, p.latencyCount, dimensionsCacheSize)
+	assert.Len(t, p.metricKeyStartTime, dimensionsCacheSize)
+}
+
+func TestEvictedMetricKeyStartTime(t *testing.T) {
+	defaultNullValue := "defaultNullValue"
+	p := newProcessorImp(&mocks.MetricsExporter{}, &mocks.TracesConsumer{}, &defaultNullValue, cumulative, t)
+	metricKeyToDimensions, err := cache.NewCache(1)
+	require.NoError(t, err)
+	p.metricKeyToDimensions = metricKeyToDimensions
+
+	span := pdata.NewSpan()
+	span.SetName("/ping")
+	keyA := buildKey("service-a", span, p.dimensions)
+
+	p.aggregateMetricsForSpan("service-a", span)
+	p.buildMetrics()
+	firstStartTime := p.startTimeOf(keyA)
+
+	// service-b evicts the key of service-a and its cumulative counts.
+	p.aggregateMetricsForSpan("service-b", span)
+	p.buildMetrics()
+	assert.NotContains(t, p.callSum, keyA)
+	assert.NotContains(t, p.metricKeyStartTime, keyA)
+
+	// The counts of service-a start over, from a new start time.
+	time.Sleep(time.Millisecond)
+	p.aggregateMetricsForSpan("service-a", span)
+	m := p.buildMetrics()
+
+	assert.True(t, p.startTimeOf(keyA).After(firstStartTime))
+	metrics := m.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	found := false
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() != "calls_total" {
+			continue
+		}
+		dp := metrics.At(i).Sum().DataPoints().At(0)
+		if service, _ := dp.Attributes().Get(serviceNameKey); service.StringVal() != "service-a" {
+			continue
+		}
+		found = true
+		assert.EqualValues(t, 1, dp.IntVal())
+		assert.Equal(t, pdata.NewTimestampFromTime(p.startTimeOf(keyA)), dp.StartTimestamp())
+	}
+	assert.True(t, found, "no calls_total data point of service-a")
+}
+
+func TestLatencyExemplars(t *testing.T) {
+	// Prepare
+	mexp := &mocks.MetricsExporter{}
+	tcon := &mocks.TracesConsumer{}
+	mexp.On("ConsumeMetrics", mock.Anything, mock.Anything).Return(nil)
+	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+
+	p := newProcessorImp(mexp, tcon, nil, cumulative, t)
+
+	traces := buildSampleTrace()
+	span := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	key := buildKey("service-a", span, p.dimensions)
+
+	// Test
+	p.aggregateMetrics(traces)
+	m := p.buildMetrics()
+
+	// Verify
+	var latencyDP pdata.HistogramDataPoint
+	ms := m.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() != "latency" {
+			continue
+		}
+		dp := ms.At(i).Histogram().DataPoints().At(0)
+		if serviceName, _ := dp.Attributes().Get(serviceNameKey); serviceName.StringVal() == "service-a" {
+			if kind, _ := dp.Attributes().Get(spanKindKey); kind.StringVal() == span.Kind().String() {
+				latencyDP = dp
+			}
+		}
+	}
+	require.Equal(t, 1, latencyDP.Exemplars().Len())
+	exemplar := latencyDP.Exemplars().At(0)
+	assert.Equal(t, sampleLatency, exemplar.DoubleVal())
+	assert.Equal(t, span.EndTimestamp(), exemplar.Timestamp())
+	traceID, ok := exemplar.FilteredAttributes().Get(traceIDKey)
+	require.True(t, ok)
+	assert.Equal(t, span.TraceID().HexString(), traceID.StringVal())
+
+	// The exemplars are reset once the metrics were built.
+	assert.Empty(t, p.latencyExemplarsData[key])
 }
 
 func BenchmarkProcessorConsumeTraces(b *testing.B) {
@@ -275,7 +403,7 @@ func BenchmarkProcessorConsumeTraces(b *testing.B) {
 	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
 
 	defaultNullValue := "defaultNullValue"
-	p := newProcessorImp(mexp, tcon, &defaultNullValue)
+	p := newProcessorImp(mexp, tcon, &defaultNullValue, cumulative, b)
 
 	traces := buildSampleTrace()
 
@@ -286,19 +414,24 @@ func BenchmarkProcessorConsumeTraces(b *testing.B) {
 	}
 }
 
-func newProcessorImp(mexp *mocks.MetricsExporter, tcon *mocks.TracesConsumer, defaultNullValue *string) *processorImp {
+func newProcessorImp(mexp *mocks.MetricsExporter, tcon *mocks.TracesConsumer, defaultNullValue *string, temporality string, tb testing.TB) *processorImp {
 	defaultNotInSpanAttrVal := "defaultNotInSpanAttrVal"
+	metricKeyToDimensions, err := cache.NewCache(defaultDimensionsCacheSize)
+	require.NoError(tb, err)
 	return &processorImp{
 		logger:          zap.NewNop(),
+		config:          Config{AggregationTemporality: temporality},
 		metricsExporter: mexp,
 		nextConsumer:    tcon,
 
-		startTime:           time.Now(),
-		callSum:             make(map[metricKey]int64),
-		latencySum:          make(map[metricKey]float64),
-		latencyCount:        make(map[metricKey]uint64),
-		latencyBucketCounts: make(map[metricKey][]uint64),
-		latencyBounds:       defaultLatencyHistogramBucketsMs,
+		startTime:            time.Now(),
+		metricKeyStartTime:   make(map[metricKey]time.Time),
+		callSum:              make(map[metricKey]int64),
+		latencySum:           make(map[metricKey]float64),
+		latencyCount:         make(map[metricKey]uint64),
+		latencyBucketCounts:  make(map[metricKey][]uint64),
+		latencyBounds:        defaultLatencyHistogramBucketsMs,
+		latencyExemplarsData: make(map[metricKey][]*exemplarData),
 		dimensions: []Dimension{
 			// Set nil defaults to force a lookup for the attribute in the span.
 			{stringAttrName, nil},
@@ -313,13 +446,13 @@ func newProcessorImp(mexp *mocks.MetricsExporter, tcon *mocks.TracesConsumer, de
 			// Leave the default value unset to test that this dimension should not be added to the metric.
 			{notInSpanAttrName1, nil},
 		},
-		metricKeyToDimensions: make(map[metricKey]pdata.AttributeMap),
+		metricKeyToDimensions: metricKeyToDimensions,
 	}
 }
 
 // verifyConsumeMetricsInput verifies the input of the ConsumeMetrics call from this processor.
 // This is the best point to verify the computed metrics from spans are as expected.
-func verifyConsumeMetricsInput(input pdata.Metrics, t *testing.T) bool {
+func verifyConsumeMetricsInput(input pdata.Metrics, wantTemporality pdata.AggregationTemporality, wantCallCount int64, t *testing.T) bool {
 	require.Equal(t, 6, input.MetricCount(),
 		"Should be 3 for each of call count and latency. Each group of 3 metrics is made of: "+
 			"service-a (server kind) -> service-a (client kind) -> service-b (service kind)",
@@ -342,14 +475,14 @@ func verifyConsumeMetricsInput(input pdata.Metrics, t *testing.T) bool {
 		assert.Equal(t, "calls_total", m.At(mi).Name())
 
 		data := m.At(mi).Sum()
-		assert.Equal(t, pdata.AggregationTemporalityCumulative, data.AggregationTemporality())
+		assert.Equal(t, wantTemporality, data.AggregationTemporality())
 		assert.True(t, data.IsMonotonic())
 
 		dps := data.DataPoints()
 		require.Equal(t, 1, dps.Len())
 
 		dp := dps.At(0)
-		assert.Equal(t, int64(1), dp.IntVal(), "There should only be one metric per Service/operation/kind combination")
+		assert.Equal(t, wantCallCount, dp.IntVal(), "There should only be one metric per Service/operation/kind combination")
 		assert.NotZero(t, dp.StartTimestamp(), "StartTimestamp should be set")
 		assert.NotZero(t, dp.Timestamp(), "Timestamp should be set")
 
@@ -362,13 +495,13 @@ func verifyConsumeMetricsInput(input pdata.Metrics, t *testing.T) bool {
 		assert.Equal(t, "latency", m.At(mi).Name())
 
 		data := m.At(mi).Histogram()
-		assert.Equal(t, pdata.AggregationTemporalityCumulative, data.AggregationTemporality())
+		assert.Equal(t, wantTemporality, data.AggregationTemporality())
 
 		dps := data.DataPoints()
 		require.Equal(t, 1, dps.Len())
 
 		dp := dps.At(0)
-		assert.Equal(t, sampleLatency, dp.Sum(), "Should be a single 11ms latency measurement")
+		assert.Equal(t, float64(wantCallCount)*sampleLatency, dp.Sum(), "Should only be 11ms latency measurements")
 		assert.NotZero(t, dp.Timestamp(), "Timestamp should be set")
 
 		// Verify bucket counts. Firstly, find the bucket index where the 11ms latency should belong in.
@@ -384,7 +517,7 @@ func verifyConsumeMetricsInput(input pdata.Metrics, t *testing.T) bool {
 		for bi := 0; bi < len(dp.BucketCounts()); bi++ {
 			wantBucketCount = 0
 			if bi == foundLatencyIndex {
-				wantBucketCount = 1
+				wantBucketCount = uint64(wantCallCount)
 			}
 			assert.Equal(t, wantBucketCount, dp.BucketCounts()[bi])
 		}
@@ -483,6 +616,7 @@ func initServiceSpans(serviceSpans serviceSpans, spans pdata.ResourceSpans) {
 func initSpan(span span, s pdata.Span) {
 	s.SetName(span.operation)
 	s.SetKind(span.kind)
+	s.SetTraceID(pdata.NewTraceID([16]byte{byte(span.kind), byte(span.statusCode), 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14}))
 	s.Status().SetCode(span.statusCode)
 	now := time.Now()
 	s.SetStartTimestamp(pdata.NewTimestampFromTime(now))
@@ -540,6 +674,32 @@ func TestProcessorDuplicateDimensions(t *testing.T) {
 	assert.Nil(t, p)
 }
 
+func TestProcessorInvalidAggregationTemporality(t *testing.T) {
+	// Prepare
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.AggregationTemporality = "AGGREGATION_TEMPORALITY_UNSPECIFIED"
+
+	// Test
+	next := new(consumertest.TracesSink)
+	p, err := newProcessor(zap.NewNop(), cfg, next)
+	assert.EqualError(t, err, `invalid aggregation_temporality "AGGREGATION_TEMPORALITY_UNSPECIFIED", must be one of AGGREGATION_TEMPORALITY_CUMULATIVE or AGGREGATION_TEMPORALITY_DELTA`)
+	assert.Nil(t, p)
+}
+
+func TestProcessorInvalidDimensionsCacheSize(t *testing.T) {
+	// Prepare
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.DimensionsCacheSize = 0
+
+	// Test
+	next := new(consumertest.TracesSink)
+	p, err := newProcessor(zap.NewNop(), cfg, next)
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
+
 func TestValidateDimensions(t *testing.T) {
 	for _, tc := range []struct {
 		name        string
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAndHelper(t *testing.T) {
+	eval, err := getNewAndPolicy(zap.NewNop(), &AndCfg{
+		SubPolicyCfg: []AndSubPolicyCfg{
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "test-and-policy-1", Type: StringAttribute, StringAttributeCfg: StringAttributeCfg{Key: "service.name", Values: []string{"payment-service"}}}},
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "test-and-policy-2", Type: Latency, LatencyCfg: LatencyCfg{ThresholdMs: 500}}},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, eval)
+}
+
+func TestAndHelperInvalidConfig(t *testing.T) {
+	_, err := getNewAndPolicy(zap.NewNop(), &AndCfg{})
+	assert.EqualError(t, err, "and policy requires at least one sub-policy")
+
+	_, err = getNewAndPolicy(zap.NewNop(), &AndCfg{
+		SubPolicyCfg: []AndSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "test-and-policy-1", Type: StatusCode, StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"BAD"}}}}},
+	})
+	assert.Error(t, err)
+}
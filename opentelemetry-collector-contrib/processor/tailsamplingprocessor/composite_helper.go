@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+func getNewCompositePolicy(logger *zap.Logger, cfg *CompositeCfg) (sampling.PolicyEvaluator, error) {
+	if len(cfg.SubPolicyCfg) == 0 {
+		return nil, errors.New("composite policy requires at least one sub-policy")
+	}
+	if cfg.MaxTotalSpansPerSecond <= 0 {
+		return nil, errors.New("composite policy requires a positive max_total_spans_per_second")
+	}
+
+	ordered, err := orderCompositeSubPolicies(cfg)
+	if err != nil {
+		return nil, err
+	}
+	rateAllocations, err := getRateAllocations(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	subPolicyEvalParams := make([]sampling.SubPolicyEvalParams, len(ordered))
+	for i, subCfg := range ordered {
+		eval, err := getCompositeSubPolicyEvaluator(logger, subCfg)
+		if err != nil {
+			return nil, fmt.Errorf("composite sub-policy %q: %w", subCfg.Name, err)
+		}
+		subPolicyEvalParams[i] = sampling.SubPolicyEvalParams{
+			Evaluator:         eval,
+			MaxSpansPerSecond: rateAllocations[subCfg.Name],
+		}
+	}
+	return sampling.NewComposite(logger, cfg.MaxTotalSpansPerSecond, subPolicyEvalParams, sampling.MonotonicClock{}), nil
+}
+
+func getCompositeSubPolicyEvaluator(logger *zap.Logger, cfg *CompositeSubPolicyCfg) (sampling.PolicyEvaluator, error) {
+	if cfg.Type == And {
+		return getNewAndPolicy(logger, &cfg.AndCfg)
+	}
+	return getSharedPolicyEvaluator(logger, &cfg.sharedPolicyCfg)
+}
+
+// orderCompositeSubPolicies returns the sub-policies listed in the policy order first,
+// followed by the other ones in the order they are defined.
+func orderCompositeSubPolicies(cfg *CompositeCfg) ([]*CompositeSubPolicyCfg, error) {
+	byName := make(map[string]*CompositeSubPolicyCfg, len(cfg.SubPolicyCfg))
+	for i := range cfg.SubPolicyCfg {
+		subCfg := &cfg.SubPolicyCfg[i]
+		if _, ok := byName[subCfg.Name]; ok {
+			return nil, fmt.Errorf("duplicate composite sub-policy name %q", subCfg.Name)
+		}
+		byName[subCfg.Name] = subCfg
+	}
+
+	ordered := make([]*CompositeSubPolicyCfg, 0, len(cfg.SubPolicyCfg))
+	added := make(map[string]bool, len(cfg.SubPolicyCfg))
+	for _, name := range cfg.PolicyOrder {
+		subCfg, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown composite sub-policy %q in policy_order", name)
+		}
+		if added[name] {
+			continue
+		}
+		added[name] = true
+		ordered = append(ordered, subCfg)
+	}
+	for i := range cfg.SubPolicyCfg {
+		if subCfg := &cfg.SubPolicyCfg[i]; !added[subCfg.Name] {
+			ordered = append(ordered, subCfg)
+		}
+	}
+	return ordered, nil
+}
+
+// getRateAllocations returns the spans per second allocated to every sub-policy, the ones
+// without an allocation share equally what is left of the total.
+func getRateAllocations(cfg *CompositeCfg) (map[string]int64, error) {
+	known := make(map[string]bool, len(cfg.SubPolicyCfg))
+	for _, subCfg := range cfg.SubPolicyCfg {
+		known[subCfg.Name] = true
+	}
+
+	allocations := make(map[string]int64, len(cfg.SubPolicyCfg))
+	var totalPercent, allocated int64
+	for _, rAlloc := range cfg.RateAllocation {
+		if !known[rAlloc.Policy] {
+			return nil, fmt.Errorf("unknown composite sub-policy %q in rate_allocation", rAlloc.Policy)
+		}
+		if rAlloc.Percent < 0 {
+			return nil, fmt.Errorf("negative rate allocation for composite sub-policy %q", rAlloc.Policy)
+		}
+		totalPercent += rAlloc.Percent
+		sps := cfg.MaxTotalSpansPerSecond * rAlloc.Percent / 100
+		allocations[rAlloc.Policy] = sps
+		allocated += sps
+	}
+	if totalPercent > 100 {
+		return nil, fmt.Errorf("composite rate allocations add up to %d%%, more than 100%%", totalPercent)
+	}
+
+	if unallocated := len(cfg.SubPolicyCfg) - len(allocations); unallocated > 0 {
+		defaultSPS := (cfg.MaxTotalSpansPerSecond - allocated) / int64(unallocated)
+		for _, subCfg := range cfg.SubPolicyCfg {
+			if _, ok := allocations[subCfg.Name]; !ok {
+				allocations[subCfg.Name] = defaultSPS
+			}
+		}
+	}
+	return allocations, nil
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCompositeHelper(t *testing.T) {
+	cfg := &CompositeCfg{
+		MaxTotalSpansPerSecond: 1000,
+		PolicyOrder:            []string{"test-composite-policy-3", "test-composite-policy-1"},
+		SubPolicyCfg: []CompositeSubPolicyCfg{
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "test-composite-policy-1", Type: AlwaysSample}},
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "test-composite-policy-2", Type: Latency, LatencyCfg: LatencyCfg{ThresholdMs: 500}}},
+			{sharedPolicyCfg: sharedPolicyCfg{Name: "test-composite-policy-3", Type: NumericAttribute, NumericAttributeCfg: NumericAttributeCfg{Key: "key1", MinValue: 50, MaxValue: 100}}},
+			{
+				sharedPolicyCfg: sharedPolicyCfg{Name: "test-composite-policy-4", Type: And},
+				AndCfg: AndCfg{
+					SubPolicyCfg: []AndSubPolicyCfg{
+						{sharedPolicyCfg: sharedPolicyCfg{Name: "test-and-policy-1", Type: AlwaysSample}},
+					},
+				},
+			},
+		},
+		RateAllocation: []RateAllocationCfg{
+			{Policy: "test-composite-policy-1", Percent: 50},
+			{Policy: "test-composite-policy-3", Percent: 10},
+		},
+	}
+
+	ordered, err := orderCompositeSubPolicies(cfg)
+	require.NoError(t, err)
+	var names []string
+	for _, subCfg := range ordered {
+		names = append(names, subCfg.Name)
+	}
+	assert.Equal(t, []string{"test-composite-policy-3", "test-composite-policy-1", "test-composite-policy-2", "test-composite-policy-4"}, names)
+
+	allocations, err := getRateAllocations(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		"test-composite-policy-1": 500,
+		"test-composite-policy-2": 200,
+		"test-composite-policy-3": 100,
+		"test-composite-policy-4": 200,
+	}, allocations)
+
+	eval, err := getNewCompositePolicy(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, eval)
+}
+
+func TestCompositeHelperInvalidConfig(t *testing.T) {
+	subPolicies := []CompositeSubPolicyCfg{
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "test-composite-policy-1", Type: AlwaysSample}},
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "test-composite-policy-2", Type: AlwaysSample}},
+	}
+	tests := []struct {
+		name    string
+		cfg     CompositeCfg
+		wantErr string
+	}{
+		{
+			name:    "no sub-policy",
+			cfg:     CompositeCfg{MaxTotalSpansPerSecond: 10},
+			wantErr: "composite policy requires at least one sub-policy",
+		},
+		{
+			name:    "no rate",
+			cfg:     CompositeCfg{SubPolicyCfg: subPolicies},
+			wantErr: "composite policy requires a positive max_total_spans_per_second",
+		},
+		{
+			name: "duplicate sub-policy",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 10,
+				SubPolicyCfg:           append(append([]CompositeSubPolicyCfg{}, subPolicies...), subPolicies[0]),
+			},
+			wantErr: `duplicate composite sub-policy name "test-composite-policy-1"`,
+		},
+		{
+			name: "unknown sub-policy in order",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 10,
+				SubPolicyCfg:           subPolicies,
+				PolicyOrder:            []string{"unknown"},
+			},
+			wantErr: `unknown composite sub-policy "unknown" in policy_order`,
+		},
+		{
+			name: "unknown sub-policy in allocation",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 10,
+				SubPolicyCfg:           subPolicies,
+				RateAllocation:         []RateAllocationCfg{{Policy: "unknown", Percent: 10}},
+			},
+			wantErr: `unknown composite sub-policy "unknown" in rate_allocation`,
+		},
+		{
+			name: "over allocated",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 10,
+				SubPolicyCfg:           subPolicies,
+				RateAllocation: []RateAllocationCfg{
+					{Policy: "test-composite-policy-1", Percent: 60},
+					{Policy: "test-composite-policy-2", Percent: 60},
+				},
+			},
+			wantErr: "composite rate allocations add up to 120%, more than 100%",
+		},
+		{
+			name: "invalid sub-policy",
+			cfg: CompositeCfg{
+				MaxTotalSpansPerSecond: 10,
+				SubPolicyCfg:           []CompositeSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "test-composite-policy-1", Type: "unknown"}}},
+			},
+			wantErr: `composite sub-policy "test-composite-policy-1": unknown sampling policy type unknown`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := getNewCompositePolicy(zap.NewNop(), &tt.cfg)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -40,10 +40,23 @@ const (
 	StringAttribute PolicyType = "string_attribute"
 	// RateLimiting allows all traces until the specified limits are satisfied.
 	RateLimiting PolicyType = "rate_limiting"
+	// SpanCount sample traces that have a number of spans in a specified range.
+	SpanCount PolicyType = "span_count"
+	// TraceState sample traces that have a trace state entry, for a given key, matching
+	// one of the listed values.
+	TraceState PolicyType = "trace_state"
+	// BooleanAttribute sample traces that have an attribute, of type bool, with the given value.
+	BooleanAttribute PolicyType = "boolean_attribute"
+	// And samples traces only when all of its sub-policies decide to sample them.
+	And PolicyType = "and"
+	// Composite allocates a share of a total rate of spans per second to each of its sub-policies,
+	// evaluated in order, sampling the trace with the first sub-policy which has room left for it.
+	Composite PolicyType = "composite"
 )
 
-// PolicyCfg holds the common configuration to all policies.
-type PolicyCfg struct {
+// sharedPolicyCfg holds the common configuration to all policies, including the ones
+// used as sub-policies of the and and composite policies.
+type sharedPolicyCfg struct {
 	// Name given to the instance of the policy to make easy to identify it in metrics and logs.
 	Name string `mapstructure:"name"`
 	// Type of the policy this will be used to match the proper configuration of the policy.
@@ -60,6 +73,33 @@ type PolicyCfg struct {
 	StringAttributeCfg StringAttributeCfg `mapstructure:"string_attribute"`
 	// Configs for rate limiting filter sampling policy evaluator.
 	RateLimitingCfg RateLimitingCfg `mapstructure:"rate_limiting"`
+	// Configs for span count filter sampling policy evaluator.
+	SpanCountCfg SpanCountCfg `mapstructure:"span_count"`
+	// Configs for trace state filter sampling policy evaluator.
+	TraceStateCfg TraceStateCfg `mapstructure:"trace_state"`
+	// Configs for boolean attribute filter sampling policy evaluator.
+	BooleanAttributeCfg BooleanAttributeCfg `mapstructure:"boolean_attribute"`
+}
+
+// AndSubPolicyCfg holds the configuration of a sub-policy of an and policy.
+type AndSubPolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+}
+
+// CompositeSubPolicyCfg holds the configuration of a sub-policy of a composite policy.
+type CompositeSubPolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+	// Configs for and policy evaluator.
+	AndCfg AndCfg `mapstructure:"and"`
+}
+
+// PolicyCfg holds the common configuration to all policies.
+type PolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+	// Configs for and policy evaluator.
+	AndCfg AndCfg `mapstructure:"and"`
+	// Configs for composite policy evaluator.
+	CompositeCfg CompositeCfg `mapstructure:"composite"`
 }
 
 // LatencyCfg holds the configurable settings to create a latency filter sampling policy
@@ -121,6 +161,62 @@ type RateLimitingCfg struct {
 	SpansPerSecond int64 `mapstructure:"spans_per_second"`
 }
 
+// SpanCountCfg holds the configurable settings to create a span count filter sampling
+// policy evaluator.
+type SpanCountCfg struct {
+	// MinSpans is the minimum number of spans of a trace to be considered a match.
+	MinSpans int64 `mapstructure:"min_spans"`
+	// MaxSpans is the maximum number of spans of a trace to be considered a match.
+	// Defaults to zero, i.e.: no maximum.
+	MaxSpans int64 `mapstructure:"max_spans"`
+}
+
+// TraceStateCfg holds the configurable settings to create a trace state filter sampling
+// policy evaluator.
+type TraceStateCfg struct {
+	// Key of the trace state entry that the filter is going to be matching against.
+	Key string `mapstructure:"key"`
+	// Values indicate the set of values to use when matching against the trace state entry.
+	Values []string `mapstructure:"values"`
+}
+
+// BooleanAttributeCfg holds the configurable settings to create a boolean attribute filter
+// sampling policy evaluator.
+type BooleanAttributeCfg struct {
+	// Tag that the filter is going to be matching against.
+	Key string `mapstructure:"key"`
+	// Value indicates the bool value to use when matching against the attribute value.
+	Value bool `mapstructure:"value"`
+}
+
+// AndCfg holds the configurable settings to create an and sampling policy evaluator.
+type AndCfg struct {
+	// SubPolicyCfg lists the policies which must all decide to sample a trace.
+	SubPolicyCfg []AndSubPolicyCfg `mapstructure:"and_sub_policy"`
+}
+
+// CompositeCfg holds the configurable settings to create a composite sampling policy evaluator.
+type CompositeCfg struct {
+	// MaxTotalSpansPerSecond is the maximum number of spans per second sampled by all the sub-policies together.
+	MaxTotalSpansPerSecond int64 `mapstructure:"max_total_spans_per_second"`
+	// PolicyOrder lists the names of the sub-policies in the order they are evaluated. The sub-policies
+	// which are not listed are evaluated afterwards, in the order they are defined.
+	PolicyOrder []string `mapstructure:"policy_order"`
+	// SubPolicyCfg lists the policies sharing the rate of spans per second.
+	SubPolicyCfg []CompositeSubPolicyCfg `mapstructure:"composite_sub_policy"`
+	// RateAllocation gives each sub-policy its share of MaxTotalSpansPerSecond. The sub-policies without
+	// an allocation share what is left equally.
+	RateAllocation []RateAllocationCfg `mapstructure:"rate_allocation"`
+}
+
+// RateAllocationCfg gives a sub-policy of a composite policy its share of the spans per second.
+type RateAllocationCfg struct {
+	// Policy is the name of the sub-policy.
+	Policy string `mapstructure:"policy"`
+	// Percent is the percentage of MaxTotalSpansPerSecond allocated to the sub-policy.
+	Percent int64 `mapstructure:"percent"`
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
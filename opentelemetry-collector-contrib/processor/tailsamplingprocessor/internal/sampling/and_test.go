@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+type fixedDecision struct {
+	decision Decision
+	err      error
+}
+
+func (f *fixedDecision) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	return f.err
+}
+
+func (f *fixedDecision) Evaluate(pdata.TraceID, *TraceData) (Decision, error) {
+	return f.decision, f.err
+}
+
+func TestAndEvaluator(t *testing.T) {
+	var empty = map[string]pdata.AttributeValue{}
+	traceID := pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+	cases := []struct {
+		Desc        string
+		Trace       *TraceData
+		Decision    Decision
+		subpolicies []PolicyEvaluator
+	}{
+		{
+			Desc:     "all sub-policies sample",
+			Trace:    newTraceStringAttrs(empty, "service", "payment-service"),
+			Decision: Sampled,
+			subpolicies: []PolicyEvaluator{
+				NewStringAttributeFilter(zap.NewNop(), "service", []string{"payment-service"}, false, 0),
+				NewAlwaysSample(zap.NewNop()),
+			},
+		},
+		{
+			Desc:     "one sub-policy doesn't sample",
+			Trace:    newTraceStringAttrs(empty, "service", "payment-service"),
+			Decision: NotSampled,
+			subpolicies: []PolicyEvaluator{
+				NewStringAttributeFilter(zap.NewNop(), "service", []string{"payment-service"}, false, 0),
+				NewStringAttributeFilter(zap.NewNop(), "service", []string{"order-transmission"}, false, 0),
+			},
+		},
+		{
+			Desc:     "no sub-policy samples",
+			Trace:    newTraceStringAttrs(empty, "service", "platform-demo"),
+			Decision: NotSampled,
+			subpolicies: []PolicyEvaluator{
+				NewStringAttributeFilter(zap.NewNop(), "service", []string{"payment-service"}, false, 0),
+				NewStringAttributeFilter(zap.NewNop(), "service", []string{"order-transmission"}, false, 0),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			and := NewAnd(zap.NewNop(), c.subpolicies)
+			decision, err := and.Evaluate(traceID, c.Trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestAndEvaluatorError(t *testing.T) {
+	expectedErr := errors.New("failed")
+	and := NewAnd(zap.NewNop(), []PolicyEvaluator{
+		NewAlwaysSample(zap.NewNop()),
+		&fixedDecision{err: expectedErr},
+	})
+
+	decision, err := and.Evaluate(pdata.NewTraceID([16]byte{1}), &TraceData{})
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, Unspecified, decision)
+}
+
+func TestOnLateArrivingSpans_And(t *testing.T) {
+	and := NewAnd(zap.NewNop(), []PolicyEvaluator{NewAlwaysSample(zap.NewNop())})
+	err := and.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
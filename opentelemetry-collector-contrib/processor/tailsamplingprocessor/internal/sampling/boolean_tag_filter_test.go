@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestBooleanTagFilter(t *testing.T) {
+	var empty = map[string]pdata.AttributeValue{}
+	filter := NewBooleanAttributeFilter(zap.NewNop(), "example", true)
+
+	resAttr := map[string]pdata.AttributeValue{}
+	resAttr["example"] = pdata.NewAttributeValueBool(true)
+
+	cases := []struct {
+		Desc     string
+		Trace    *TraceData
+		Decision Decision
+	}{
+		{
+			Desc:     "nonmatching span attribute",
+			Trace:    newTraceBoolAttrs(empty, "non_matching", true),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "span attribute with the expected value",
+			Trace:    newTraceBoolAttrs(empty, "example", true),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "span attribute with the other value",
+			Trace:    newTraceBoolAttrs(empty, "example", false),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "resource attribute with the expected value",
+			Trace:    newTraceBoolAttrs(resAttr, "non_matching", false),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "span attribute of another type",
+			Trace:    newTraceStringAttrs(empty, "example", "true"),
+			Decision: NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), c.Trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestOnLateArrivingSpans_BooleanTagFilter(t *testing.T) {
+	filter := NewBooleanAttributeFilter(zap.NewNop(), "example", true)
+	err := filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
+
+func newTraceBoolAttrs(nodeAttrs map[string]pdata.AttributeValue, spanAttrKey string, spanAttrValue bool) *TraceData {
+	var traceBatches []pdata.Traces
+	traces := pdata.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InitFromMap(nodeAttrs)
+	ils := rs.InstrumentationLibrarySpans().AppendEmpty()
+	span := ils.Spans().AppendEmpty()
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	attributes := make(map[string]pdata.AttributeValue)
+	attributes[spanAttrKey] = pdata.NewAttributeValueBool(spanAttrValue)
+	span.Attributes().InitFromMap(attributes)
+	traceBatches = append(traceBatches, traces)
+	return &TraceData{
+		ReceivedBatches: traceBatches,
+	}
+}
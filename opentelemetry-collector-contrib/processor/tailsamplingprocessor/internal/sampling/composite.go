@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+// TimeProvider gives the current second, used to reset the rates every second.
+type TimeProvider interface {
+	getCurSecond() int64
+}
+
+// MonotonicClock is the TimeProvider based on the system clock.
+type MonotonicClock struct{}
+
+func (MonotonicClock) getCurSecond() int64 {
+	return time.Now().Unix()
+}
+
+// SubPolicyEvalParams defines a sub-policy of a composite policy.
+type SubPolicyEvalParams struct {
+	// Evaluator of the sub-policy.
+	Evaluator PolicyEvaluator
+	// MaxSpansPerSecond is the share of spans per second allocated to the sub-policy.
+	MaxSpansPerSecond int64
+}
+
+type subpolicy struct {
+	evaluator    PolicyEvaluator
+	allocatedSPS int64
+
+	// spans sampled by this sub-policy in the current second
+	sampledSPS int64
+}
+
+type composite struct {
+	maxTotalSPS int64
+	subpolicies []*subpolicy
+
+	// spans sampled by all the sub-policies in the current second
+	sampledSPS    int64
+	currentSecond int64
+	timeProvider  TimeProvider
+
+	logger *zap.Logger
+}
+
+var _ PolicyEvaluator = (*composite)(nil)
+
+// NewComposite creates a policy evaluator sampling the traces sampled by the given sub-policies,
+// as long as neither the rate allocated to the sub-policy nor the total rate are exceeded.
+// The sub-policies are evaluated in order and the first one sampling the trace takes the decision.
+func NewComposite(
+	logger *zap.Logger,
+	maxTotalSpansPerSecond int64,
+	subPolicyParams []SubPolicyEvalParams,
+	timeProvider TimeProvider,
+) PolicyEvaluator {
+	var subpolicies []*subpolicy
+	for _, params := range subPolicyParams {
+		subpolicies = append(subpolicies, &subpolicy{
+			evaluator:    params.Evaluator,
+			allocatedSPS: params.MaxSpansPerSecond,
+		})
+	}
+
+	return &composite{
+		maxTotalSPS:  maxTotalSpansPerSecond,
+		subpolicies:  subpolicies,
+		timeProvider: timeProvider,
+		logger:       logger,
+	}
+}
+
+// OnLateArrivingSpans notifies the evaluator that the given list of spans arrived
+// after the sampling decision was already taken for the trace.
+// This gives the evaluator a chance to log any message/metrics and/or update any
+// related internal state.
+func (c *composite) OnLateArrivingSpans(Decision, []*pdata.Span) error {
+	c.logger.Debug("Triggering action for late arriving spans in composite filter")
+	return nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (c *composite) Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error) {
+	c.logger.Debug("Evaluating spans in composite filter")
+
+	currSecond := c.timeProvider.getCurSecond()
+	if c.currentSecond != currSecond {
+		c.currentSecond = currSecond
+		c.sampledSPS = 0
+		for _, sub := range c.subpolicies {
+			sub.sampledSPS = 0
+		}
+	}
+
+	trace.Lock()
+	spanCount := trace.SpanCount
+	trace.Unlock()
+
+	for _, sub := range c.subpolicies {
+		decision, err := sub.evaluator.Evaluate(traceID, trace)
+		if err != nil {
+			return Unspecified, err
+		}
+		if decision != Sampled {
+			continue
+		}
+
+		// the first sub-policy sampling the trace decides, even when it has no room left
+		if sub.sampledSPS+spanCount > sub.allocatedSPS || c.sampledSPS+spanCount > c.maxTotalSPS {
+			return NotSampled, nil
+		}
+		sub.sampledSPS += spanCount
+		c.sampledSPS += spanCount
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
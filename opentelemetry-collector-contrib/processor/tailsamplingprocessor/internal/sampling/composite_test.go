@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+type fakeTimeProvider struct {
+	second int64
+}
+
+func (f *fakeTimeProvider) getCurSecond() int64 {
+	return f.second
+}
+
+var traceID = pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+
+func newTraceWithSpanCount(attrValue string, spanCount int64) *TraceData {
+	trace := newTraceStringAttrs(map[string]pdata.AttributeValue{}, "service", attrValue)
+	trace.SpanCount = spanCount
+	return trace
+}
+
+func newTestComposite(timeProvider TimeProvider) PolicyEvaluator {
+	return NewComposite(zap.NewNop(), 10, []SubPolicyEvalParams{
+		{
+			Evaluator:         NewStringAttributeFilter(zap.NewNop(), "service", []string{"payment-service"}, false, 0),
+			MaxSpansPerSecond: 4,
+		},
+		{
+			Evaluator:         NewAlwaysSample(zap.NewNop()),
+			MaxSpansPerSecond: 8,
+		},
+	}, timeProvider)
+}
+
+func TestCompositeEvaluatorNotSampled(t *testing.T) {
+	c := NewComposite(zap.NewNop(), 10, []SubPolicyEvalParams{
+		{
+			Evaluator:         NewStringAttributeFilter(zap.NewNop(), "service", []string{"payment-service"}, false, 0),
+			MaxSpansPerSecond: 10,
+		},
+	}, &fakeTimeProvider{})
+
+	decision, err := c.Evaluate(traceID, newTraceWithSpanCount("platform-demo", 1))
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+}
+
+func TestCompositeEvaluatorSubPolicyRate(t *testing.T) {
+	timeProvider := &fakeTimeProvider{second: 1}
+	c := newTestComposite(timeProvider)
+
+	// the first sub-policy has room for 4 spans
+	decision, err := c.Evaluate(traceID, newTraceWithSpanCount("payment-service", 3))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	decision, err = c.Evaluate(traceID, newTraceWithSpanCount("payment-service", 2))
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+
+	// other traces are still sampled by the second sub-policy
+	decision, err = c.Evaluate(traceID, newTraceWithSpanCount("platform-demo", 2))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// the rates are reset every second
+	timeProvider.second = 2
+	decision, err = c.Evaluate(traceID, newTraceWithSpanCount("payment-service", 2))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+}
+
+func TestCompositeEvaluatorTotalRate(t *testing.T) {
+	c := newTestComposite(&fakeTimeProvider{second: 1})
+
+	decision, err := c.Evaluate(traceID, newTraceWithSpanCount("payment-service", 4))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	decision, err = c.Evaluate(traceID, newTraceWithSpanCount("platform-demo", 6))
+	require.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// the second sub-policy has room left, but the total of 10 spans is reached
+	decision, err = c.Evaluate(traceID, newTraceWithSpanCount("platform-demo", 1))
+	require.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+}
+
+func TestCompositeEvaluatorError(t *testing.T) {
+	expectedErr := errors.New("failed")
+	c := NewComposite(zap.NewNop(), 10, []SubPolicyEvalParams{
+		{Evaluator: &fixedDecision{err: expectedErr}, MaxSpansPerSecond: 10},
+	}, &fakeTimeProvider{})
+
+	decision, err := c.Evaluate(traceID, newTraceWithSpanCount("payment-service", 1))
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, Unspecified, decision)
+}
+
+func TestOnLateArrivingSpans_Composite(t *testing.T) {
+	c := newTestComposite(MonotonicClock{})
+	err := c.OnLateArrivingSpans(Sampled, nil)
+	assert.Nil(t, err)
+}
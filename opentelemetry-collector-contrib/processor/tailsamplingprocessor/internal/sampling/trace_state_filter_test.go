@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+func TestTraceStateFilter(t *testing.T) {
+	filter := NewTraceStateFilter(zap.NewNop(), "example", []string{"value1", "value2"})
+
+	cases := []struct {
+		Desc       string
+		TraceState string
+		Decision   Decision
+	}{
+		{
+			Desc:       "empty trace state",
+			TraceState: "",
+			Decision:   NotSampled,
+		},
+		{
+			Desc:       "matching key and value",
+			TraceState: "example=value1",
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "matching key and value among other members",
+			TraceState: "vendor=abc, example=value2,other=1",
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "matching key with another value",
+			TraceState: "example=value3",
+			Decision:   NotSampled,
+		},
+		{
+			Desc:       "matching value with another key",
+			TraceState: "other=value1",
+			Decision:   NotSampled,
+		},
+		{
+			Desc:       "key prefix",
+			TraceState: "examples=value1",
+			Decision:   NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			decision, err := filter.Evaluate(pdata.NewTraceID([16]byte{1, 2, 3, 4}), newTraceWithTraceState(c.TraceState))
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestOnLateArrivingSpans_TraceState(t *testing.T) {
+	filter := NewTraceStateFilter(zap.NewNop(), "example", []string{"value"})
+	err := filter.OnLateArrivingSpans(NotSampled, nil)
+	assert.Nil(t, err)
+}
+
+func newTraceWithTraceState(traceState string) *TraceData {
+	traces := pdata.NewTraces()
+	span := traces.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetTraceState(pdata.TraceState(traceState))
+	return &TraceData{
+		ReceivedBatches: []pdata.Traces{traces},
+	}
+}
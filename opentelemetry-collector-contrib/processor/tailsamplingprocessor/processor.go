@@ -17,6 +17,7 @@ package tailsamplingprocessor
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -114,6 +115,17 @@ func newTracesProcessor(logger *zap.Logger, nextConsumer consumer.Traces, cfg Co
 }
 
 func getPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	switch cfg.Type {
+	case And:
+		return getNewAndPolicy(logger, &cfg.AndCfg)
+	case Composite:
+		return getNewCompositePolicy(logger, &cfg.CompositeCfg)
+	default:
+		return getSharedPolicyEvaluator(logger, &cfg.sharedPolicyCfg)
+	}
+}
+
+func getSharedPolicyEvaluator(logger *zap.Logger, cfg *sharedPolicyCfg) (sampling.PolicyEvaluator, error) {
 	switch cfg.Type {
 	case AlwaysSample:
 		return sampling.NewAlwaysSample(logger), nil
@@ -128,6 +140,13 @@ func getPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEval
 		return sampling.NewProbabilisticSampler(logger, pCfg.HashSalt, pCfg.SamplingPercentage), nil
 	case StringAttribute:
 		safCfg := cfg.StringAttributeCfg
+		if safCfg.EnabledRegexMatching {
+			for _, value := range safCfg.Values {
+				if _, err := regexp.Compile(value); err != nil {
+					return nil, fmt.Errorf("invalid regular expression %q for string attribute policy: %w", value, err)
+				}
+			}
+		}
 		return sampling.NewStringAttributeFilter(logger, safCfg.Key, safCfg.Values, safCfg.EnabledRegexMatching, safCfg.CacheMaxSize), nil
 	case StatusCode:
 		scfCfg := cfg.StatusCodeCfg
@@ -135,6 +154,18 @@ func getPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEval
 	case RateLimiting:
 		rlfCfg := cfg.RateLimitingCfg
 		return sampling.NewRateLimiting(logger, rlfCfg.SpansPerSecond), nil
+	case SpanCount:
+		scCfg := cfg.SpanCountCfg
+		if scCfg.MaxSpans > 0 && scCfg.MinSpans > scCfg.MaxSpans {
+			return nil, fmt.Errorf("invalid span count policy: min_spans %d is greater than max_spans %d", scCfg.MinSpans, scCfg.MaxSpans)
+		}
+		return sampling.NewSpanCount(logger, scCfg.MinSpans, scCfg.MaxSpans), nil
+	case TraceState:
+		tsfCfg := cfg.TraceStateCfg
+		return sampling.NewTraceStateFilter(logger, tsfCfg.Key, tsfCfg.Values), nil
+	case BooleanAttribute:
+		bafCfg := cfg.BooleanAttributeCfg
+		return sampling.NewBooleanAttributeFilter(logger, bafCfg.Key, bafCfg.Value), nil
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
 	}
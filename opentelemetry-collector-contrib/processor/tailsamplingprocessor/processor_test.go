@@ -36,7 +36,7 @@ const (
 	defaultTestDecisionWait = 30 * time.Second
 )
 
-var testPolicy = []PolicyCfg{{Name: "test-policy", Type: AlwaysSample}}
+var testPolicy = []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "test-policy", Type: AlwaysSample}}}
 
 func TestSequentialTraceArrival(t *testing.T) {
 	traceIds, batches := generateIdsAndBatches(128)
@@ -149,6 +149,53 @@ func TestConcurrentTraceMapSize(t *testing.T) {
 	require.Equal(t, maxSize, cnt, "Incorrect traces count on idToTrace")
 }
 
+func TestInvalidStringAttributeRegex(t *testing.T) {
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+		PolicyCfgs: []PolicyCfg{
+			{
+				sharedPolicyCfg: sharedPolicyCfg{
+					Name: "invalid-regex",
+					Type: StringAttribute,
+					StringAttributeCfg: StringAttributeCfg{
+						Key:                  "key",
+						Values:               []string{"valid.*", "(invalid"},
+						EnabledRegexMatching: true,
+					},
+				},
+			},
+		},
+	}
+	sp, err := newTracesProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+	require.Error(t, err)
+	require.Nil(t, sp)
+}
+
+func TestInvalidSpanCountRange(t *testing.T) {
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+		PolicyCfgs: []PolicyCfg{
+			{
+				sharedPolicyCfg: sharedPolicyCfg{
+					Name: "invalid-span-count",
+					Type: SpanCount,
+					SpanCountCfg: SpanCountCfg{
+						MinSpans: 10,
+						MaxSpans: 2,
+					},
+				},
+			},
+		},
+	}
+	sp, err := newTracesProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+	require.EqualError(t, err, "invalid span count policy: min_spans 10 is greater than max_spans 2")
+	require.Nil(t, sp)
+}
+
 func TestSamplingPolicyTypicalPath(t *testing.T) {
 	const maxSize = 100
 	const decisionWaitSeconds = 5
@@ -15,6 +15,10 @@
 package kafkareceiver
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
 	"go.opentelemetry.io/collector/config"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
@@ -35,6 +39,14 @@ type Config struct {
 	GroupID string `mapstructure:"group_id"`
 	// The consumer client ID that receiver will use (default "otel-collector")
 	ClientID string `mapstructure:"client_id"`
+	// The initial offset to use if no offset was previously committed, "latest" or "earliest" (default "latest")
+	InitialOffset string `mapstructure:"initial_offset"`
+	// The strategy used to assign the partitions to the members of the consumer group,
+	// "range", "roundrobin" or "sticky" (default "range")
+	RebalanceStrategy string `mapstructure:"rebalance_strategy"`
+
+	// AutoCommit controls how the offsets of the consumed messages are committed.
+	AutoCommit AutoCommit `mapstructure:"autocommit"`
 
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
@@ -43,9 +55,37 @@ type Config struct {
 	Authentication kafkaexporter.Authentication `mapstructure:"auth"`
 }
 
+// AutoCommit defines how the offsets of the consumed messages are committed.
+type AutoCommit struct {
+	// Whether the offset of a message is marked as soon as the message is claimed and periodically
+	// committed (default true). When disabled, the offset is only marked and committed once the next
+	// consumer successfully consumed the data of the message, so that the messages whose data was not
+	// consumed are delivered again after a restart or a rebalance.
+	Enable bool `mapstructure:"enable"`
+	// How often the marked offsets are committed when Enable is true (default 1s)
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+const (
+	offsetLatest   = "latest"
+	offsetEarliest = "earliest"
+)
+
 var _ config.Receiver = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	switch cfg.InitialOffset {
+	case offsetLatest, offsetEarliest:
+	default:
+		return fmt.Errorf("invalid initial_offset %q, must be %q or %q", cfg.InitialOffset, offsetLatest, offsetEarliest)
+	}
+	if _, ok := rebalanceStrategies[cfg.RebalanceStrategy]; !ok {
+		return fmt.Errorf("invalid rebalance_strategy %q, must be %q, %q or %q",
+			cfg.RebalanceStrategy, sarama.BalanceStrategyRange.Name(), sarama.BalanceStrategyRoundRobin.Name(), sarama.BalanceStrategySticky.Name())
+	}
+	if cfg.AutoCommit.Enable && cfg.AutoCommit.Interval <= 0 {
+		return fmt.Errorf("autocommit interval must be positive")
+	}
 	return nil
 }
@@ -41,12 +41,18 @@ func TestLoadConfig(t *testing.T) {
 
 	r := cfg.Receivers[config.NewID(typeStr)].(*Config)
 	assert.Equal(t, &Config{
-		ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
-		Topic:            "spans",
-		Encoding:         "otlp_proto",
-		Brokers:          []string{"foo:123", "bar:456"},
-		ClientID:         "otel-collector",
-		GroupID:          "otel-collector",
+		ReceiverSettings:  config.NewReceiverSettings(config.NewID(typeStr)),
+		Topic:             "spans",
+		Encoding:          "otlp_proto",
+		Brokers:           []string{"foo:123", "bar:456"},
+		ClientID:          "otel-collector",
+		GroupID:           "otel-collector",
+		InitialOffset:     "earliest",
+		RebalanceStrategy: "sticky",
+		AutoCommit: AutoCommit{
+			Enable:   false,
+			Interval: time.Second,
+		},
 		Authentication: kafkaexporter.Authentication{
 			TLS: &configtls.TLSClientSetting{
 				TLSSetting: configtls.TLSSetting{
@@ -65,3 +71,49 @@ func TestLoadConfig(t *testing.T) {
 		},
 	}, r)
 }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "default",
+			modify: func(*Config) {},
+		},
+		{
+			name:   "invalid initial offset",
+			modify: func(cfg *Config) { cfg.InitialOffset = "middle" },
+			err:    `invalid initial_offset "middle", must be "latest" or "earliest"`,
+		},
+		{
+			name:   "invalid rebalance strategy",
+			modify: func(cfg *Config) { cfg.RebalanceStrategy = "random" },
+			err:    `invalid rebalance_strategy "random", must be "range", "roundrobin" or "sticky"`,
+		},
+		{
+			name:   "invalid autocommit interval",
+			modify: func(cfg *Config) { cfg.AutoCommit.Interval = 0 },
+			err:    "autocommit interval must be positive",
+		},
+		{
+			name: "autocommit disabled without interval",
+			modify: func(cfg *Config) {
+				cfg.AutoCommit = AutoCommit{Enable: false}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			test.modify(cfg)
+			err := cfg.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -41,6 +41,14 @@ const (
 	defaultMetadataRetryBackoff = time.Millisecond * 250
 	// default from sarama.NewConfig()
 	defaultMetadataFull = true
+	// default from sarama.NewConfig()
+	defaultInitialOffset = offsetLatest
+	// default from sarama.NewConfig()
+	defaultRebalanceStrategy = "range"
+	// default from sarama.NewConfig()
+	defaultAutoCommitEnable = true
+	// default from sarama.NewConfig()
+	defaultAutoCommitInterval = time.Second
 )
 
 // FactoryOption applies changes to kafkaExporterFactory.
@@ -94,12 +102,18 @@ func NewFactory(options ...FactoryOption) component.ReceiverFactory {
 
 func createDefaultConfig() config.Receiver {
 	return &Config{
-		ReceiverSettings: config.NewReceiverSettings(config.NewID(typeStr)),
-		Topic:            defaultTopic,
-		Encoding:         defaultEncoding,
-		Brokers:          []string{defaultBroker},
-		ClientID:         defaultClientID,
-		GroupID:          defaultGroupID,
+		ReceiverSettings:  config.NewReceiverSettings(config.NewID(typeStr)),
+		Topic:             defaultTopic,
+		Encoding:          defaultEncoding,
+		Brokers:           []string{defaultBroker},
+		ClientID:          defaultClientID,
+		GroupID:           defaultGroupID,
+		InitialOffset:     defaultInitialOffset,
+		RebalanceStrategy: defaultRebalanceStrategy,
+		AutoCommit: AutoCommit{
+			Enable:   defaultAutoCommitEnable,
+			Interval: defaultAutoCommitInterval,
+		},
 		Metadata: kafkaexporter.Metadata{
 			Full: defaultMetadataFull,
 			Retry: kafkaexporter.MetadataRetry{
@@ -17,6 +17,7 @@ package kafkareceiver
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/Shopify/sarama"
@@ -37,6 +38,12 @@ const (
 
 var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 
+var rebalanceStrategies = map[string]sarama.BalanceStrategy{
+	sarama.BalanceStrategyRange.Name():      sarama.BalanceStrategyRange,
+	sarama.BalanceStrategyRoundRobin.Name(): sarama.BalanceStrategyRoundRobin,
+	sarama.BalanceStrategySticky.Name():     sarama.BalanceStrategySticky,
+}
+
 // kafkaTracesConsumer uses sarama to consume and handle messages from kafka.
 type kafkaTracesConsumer struct {
 	id                config.ComponentID
@@ -45,6 +52,7 @@ type kafkaTracesConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       TracesUnmarshaler
+	autocommit        bool
 
 	logger *zap.Logger
 }
@@ -57,6 +65,7 @@ type kafkaMetricsConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       MetricsUnmarshaler
+	autocommit        bool
 
 	logger *zap.Logger
 }
@@ -69,6 +78,7 @@ type kafkaLogsConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       LogsUnmarshaler
+	autocommit        bool
 
 	logger *zap.Logger
 }
@@ -88,6 +98,7 @@ func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unma
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	configureConsumer(config, c)
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
 		if err != nil {
@@ -108,20 +119,38 @@ func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unma
 		topics:        []string{config.Topic},
 		nextConsumer:  nextConsumer,
 		unmarshaler:   unmarshaler,
+		autocommit:    config.AutoCommit.Enable,
 		logger:        set.Logger,
 	}, nil
 }
 
+// configureConsumer applies the offset and rebalance settings of the receiver to the sarama config.
+func configureConsumer(config Config, c *sarama.Config) {
+	if config.InitialOffset == offsetEarliest {
+		c.Consumer.Offsets.Initial = sarama.OffsetOldest
+	} else {
+		c.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+	if strategy, ok := rebalanceStrategies[config.RebalanceStrategy]; ok {
+		c.Consumer.Group.Rebalance.Strategy = strategy
+	}
+	c.Consumer.Offsets.AutoCommit.Enable = config.AutoCommit.Enable
+	if config.AutoCommit.Interval > 0 {
+		c.Consumer.Offsets.AutoCommit.Interval = config.AutoCommit.Interval
+	}
+}
+
 func (c *kafkaTracesConsumer) Start(context.Context, component.Host) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	consumerGroup := &tracesConsumerGroupHandler{
-		id:           c.id,
-		logger:       c.logger,
-		unmarshaler:  c.unmarshaler,
-		nextConsumer: c.nextConsumer,
-		ready:        make(chan bool),
-		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: c.id, Transport: transport}),
+		id:                 c.id,
+		logger:             c.logger,
+		unmarshaler:        c.unmarshaler,
+		nextConsumer:       c.nextConsumer,
+		ready:              make(chan bool),
+		commitAfterConsume: !c.autocommit,
+		obsrecv:            obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: c.id, Transport: transport}),
 	}
 	go c.consumeLoop(ctx, consumerGroup) // nolint:errcheck
 	<-consumerGroup.ready
@@ -160,6 +189,7 @@ func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unm
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	configureConsumer(config, c)
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
 		if err != nil {
@@ -180,6 +210,7 @@ func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unm
 		topics:        []string{config.Topic},
 		nextConsumer:  nextConsumer,
 		unmarshaler:   unmarshaler,
+		autocommit:    config.AutoCommit.Enable,
 		logger:        set.Logger,
 	}, nil
 }
@@ -188,12 +219,13 @@ func (c *kafkaMetricsConsumer) Start(context.Context, component.Host) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	metricsConsumerGroup := &metricsConsumerGroupHandler{
-		id:           c.id,
-		logger:       c.logger,
-		unmarshaler:  c.unmarshaler,
-		nextConsumer: c.nextConsumer,
-		ready:        make(chan bool),
-		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: c.id, Transport: transport}),
+		id:                 c.id,
+		logger:             c.logger,
+		unmarshaler:        c.unmarshaler,
+		nextConsumer:       c.nextConsumer,
+		ready:              make(chan bool),
+		commitAfterConsume: !c.autocommit,
+		obsrecv:            obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: c.id, Transport: transport}),
 	}
 	go c.consumeLoop(ctx, metricsConsumerGroup)
 	<-metricsConsumerGroup.ready
@@ -231,6 +263,7 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	configureConsumer(config, c)
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
 		if err != nil {
@@ -251,6 +284,7 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 		topics:        []string{config.Topic},
 		nextConsumer:  nextConsumer,
 		unmarshaler:   unmarshaler,
+		autocommit:    config.AutoCommit.Enable,
 		logger:        set.Logger,
 	}, nil
 }
@@ -259,12 +293,13 @@ func (c *kafkaLogsConsumer) Start(context.Context, component.Host) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
 	logsConsumerGroup := &logsConsumerGroupHandler{
-		id:           c.id,
-		logger:       c.logger,
-		unmarshaler:  c.unmarshaler,
-		nextConsumer: c.nextConsumer,
-		ready:        make(chan bool),
-		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: c.id, Transport: transport}),
+		id:                 c.id,
+		logger:             c.logger,
+		unmarshaler:        c.unmarshaler,
+		nextConsumer:       c.nextConsumer,
+		ready:              make(chan bool),
+		commitAfterConsume: !c.autocommit,
+		obsrecv:            obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: c.id, Transport: transport}),
 	}
 	go c.consumeLoop(ctx, logsConsumerGroup)
 	<-logsConsumerGroup.ready
@@ -298,6 +333,9 @@ type tracesConsumerGroupHandler struct {
 	nextConsumer consumer.Traces
 	ready        chan bool
 	readyCloser  sync.Once
+	// commitAfterConsume marks and commits the offset of a message only once its data
+	// was consumed, instead of as soon as the message is claimed.
+	commitAfterConsume bool
 
 	logger *zap.Logger
 
@@ -310,6 +348,9 @@ type metricsConsumerGroupHandler struct {
 	nextConsumer consumer.Metrics
 	ready        chan bool
 	readyCloser  sync.Once
+	// commitAfterConsume marks and commits the offset of a message only once its data
+	// was consumed, instead of as soon as the message is claimed.
+	commitAfterConsume bool
 
 	logger *zap.Logger
 
@@ -322,6 +363,9 @@ type logsConsumerGroupHandler struct {
 	nextConsumer consumer.Logs
 	ready        chan bool
 	readyCloser  sync.Once
+	// commitAfterConsume marks and commits the offset of a message only once its data
+	// was consumed, instead of as soon as the message is claimed.
+	commitAfterConsume bool
 
 	logger *zap.Logger
 
@@ -349,15 +393,22 @@ func (c *tracesConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession
 
 func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
+	partition := strconv.Itoa(int(claim.Partition()))
+	if c.commitAfterConsume {
+		// Commit the offsets marked since the last commit when the claim ends.
+		defer session.Commit()
+	}
 	for message := range claim.Messages() {
 		c.logger.Debug("Kafka message claimed",
 			zap.String("value", string(message.Value)),
 			zap.Time("timestamp", message.Timestamp),
 			zap.String("topic", message.Topic))
-		session.MarkMessage(message, "")
+		if !c.commitAfterConsume {
+			session.MarkMessage(message, "")
+		}
 
 		ctx := c.obsrecv.StartTracesOp(session.Context())
-		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.String())}
+		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.String()), tag.Insert(tagPartition, partition)}
 		_ = stats.RecordWithTags(ctx, statsTags,
 			statMessageCount.M(1),
 			statMessageOffset.M(message.Offset),
@@ -366,6 +417,12 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 		traces, err := c.unmarshaler.Unmarshal(message.Value)
 		if err != nil {
 			c.logger.Error("failed to unmarshal message", zap.Error(err))
+			if c.commitAfterConsume {
+				// The message can never be consumed, skip it instead of delivering it again.
+				session.MarkMessage(message, "")
+				commitFetched(session, claim)
+				continue
+			}
 			return err
 		}
 
@@ -375,6 +432,10 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 		if err != nil {
 			return err
 		}
+		if c.commitAfterConsume {
+			session.MarkMessage(message, "")
+			commitFetched(session, claim)
+		}
 	}
 	return nil
 }
@@ -396,15 +457,22 @@ func (c *metricsConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSessio
 
 func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
+	partition := strconv.Itoa(int(claim.Partition()))
+	if c.commitAfterConsume {
+		// Commit the offsets marked since the last commit when the claim ends.
+		defer session.Commit()
+	}
 	for message := range claim.Messages() {
 		c.logger.Debug("Kafka message claimed",
 			zap.String("value", string(message.Value)),
 			zap.Time("timestamp", message.Timestamp),
 			zap.String("topic", message.Topic))
-		session.MarkMessage(message, "")
+		if !c.commitAfterConsume {
+			session.MarkMessage(message, "")
+		}
 
 		ctx := c.obsrecv.StartMetricsOp(session.Context())
-		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.String())}
+		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.id.String()), tag.Insert(tagPartition, partition)}
 		_ = stats.RecordWithTags(ctx, statsTags,
 			statMessageCount.M(1),
 			statMessageOffset.M(message.Offset),
@@ -413,6 +481,12 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 		metrics, err := c.unmarshaler.Unmarshal(message.Value)
 		if err != nil {
 			c.logger.Error("failed to unmarshal message", zap.Error(err))
+			if c.commitAfterConsume {
+				// The message can never be consumed, skip it instead of delivering it again.
+				session.MarkMessage(message, "")
+				commitFetched(session, claim)
+				continue
+			}
 			return err
 		}
 
@@ -422,6 +496,10 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 		if err != nil {
 			return err
 		}
+		if c.commitAfterConsume {
+			session.MarkMessage(message, "")
+			commitFetched(session, claim)
+		}
 	}
 	return nil
 }
@@ -447,17 +525,24 @@ func (c *logsConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession)
 
 func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
+	partition := strconv.Itoa(int(claim.Partition()))
+	if c.commitAfterConsume {
+		// Commit the offsets marked since the last commit when the claim ends.
+		defer session.Commit()
+	}
 	for message := range claim.Messages() {
 		c.logger.Debug("Kafka message claimed",
 			zap.String("value", string(message.Value)),
 			zap.Time("timestamp", message.Timestamp),
 			zap.String("topic", message.Topic))
-		session.MarkMessage(message, "")
+		if !c.commitAfterConsume {
+			session.MarkMessage(message, "")
+		}
 
 		ctx := c.obsrecv.StartTracesOp(session.Context())
 		_ = stats.RecordWithTags(
 			ctx,
-			[]tag.Mutator{tag.Insert(tagInstanceName, c.id.String())},
+			[]tag.Mutator{tag.Insert(tagInstanceName, c.id.String()), tag.Insert(tagPartition, partition)},
 			statMessageCount.M(1),
 			statMessageOffset.M(message.Offset),
 			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
@@ -465,6 +550,12 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 		logs, err := c.unmarshaler.Unmarshal(message.Value)
 		if err != nil {
 			c.logger.Error("failed to unmarshal message", zap.Error(err))
+			if c.commitAfterConsume {
+				// The message can never be consumed, skip it instead of delivering it again.
+				session.MarkMessage(message, "")
+				commitFetched(session, claim)
+				continue
+			}
 			return err
 		}
 
@@ -474,6 +565,18 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 		if err != nil {
 			return err
 		}
+		if c.commitAfterConsume {
+			session.MarkMessage(message, "")
+			commitFetched(session, claim)
+		}
 	}
 	return nil
 }
+
+// commitFetched commits the marked offsets once all the messages fetched for the claim
+// were consumed, so that the offsets are committed once per fetched batch of messages.
+func commitFetched(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) {
+	if len(claim.Messages()) == 0 {
+		session.Commit()
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
@@ -592,6 +593,93 @@ func TestLogsConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	wg.Wait()
 }
 
+func TestConsumerGroupHandler_commitAfterConsume(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().AppendEmpty()
+	bts, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		values       [][]byte
+		nextConsumer consumer.Traces
+		wantErr      bool
+		wantMarked   int
+		wantCommits  int
+	}{
+		{
+			name:         "consumed",
+			values:       [][]byte{bts, bts, bts},
+			nextConsumer: consumertest.NewNop(),
+			wantMarked:   3,
+			// Once after the fetched messages were consumed and once when the claim ends.
+			wantCommits: 2,
+		},
+		{
+			name:         "next consumer error",
+			values:       [][]byte{bts},
+			nextConsumer: consumertest.NewErr(errors.New("failed to consume")),
+			wantErr:      true,
+			wantCommits:  1,
+		},
+		{
+			name:         "unmarshal error",
+			values:       [][]byte{[]byte("!@#"), bts},
+			nextConsumer: consumertest.NewNop(),
+			wantMarked:   2,
+			wantCommits:  2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := tracesConsumerGroupHandler{
+				unmarshaler:        newPdataTracesUnmarshaler(otlp.NewProtobufTracesUnmarshaler(), defaultEncoding),
+				logger:             zap.NewNop(),
+				ready:              make(chan bool),
+				nextConsumer:       test.nextConsumer,
+				obsrecv:            obsreport.NewReceiver(obsreport.ReceiverSettings{}),
+				commitAfterConsume: true,
+			}
+			session := &commitRecordingSession{}
+			groupClaim := testConsumerGroupClaim{
+				messageChan: make(chan *sarama.ConsumerMessage, len(test.values)),
+			}
+			for _, value := range test.values {
+				groupClaim.messageChan <- &sarama.ConsumerMessage{Value: value}
+			}
+			close(groupClaim.messageChan)
+
+			err := c.ConsumeClaim(session, groupClaim)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.wantMarked, session.marked)
+			assert.Equal(t, test.wantCommits, session.commits)
+		})
+	}
+}
+
+func TestConfigureConsumer(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	c := sarama.NewConfig()
+	configureConsumer(*cfg, c)
+	assert.Equal(t, sarama.OffsetNewest, c.Consumer.Offsets.Initial)
+	assert.Equal(t, sarama.BalanceStrategyRange, c.Consumer.Group.Rebalance.Strategy)
+	assert.True(t, c.Consumer.Offsets.AutoCommit.Enable)
+
+	cfg.InitialOffset = offsetEarliest
+	cfg.RebalanceStrategy = "roundrobin"
+	cfg.AutoCommit = AutoCommit{Enable: false}
+	c = sarama.NewConfig()
+	configureConsumer(*cfg, c)
+	assert.Equal(t, sarama.OffsetOldest, c.Consumer.Offsets.Initial)
+	assert.Equal(t, sarama.BalanceStrategyRoundRobin, c.Consumer.Group.Rebalance.Strategy)
+	assert.False(t, c.Consumer.Offsets.AutoCommit.Enable)
+	assert.NoError(t, c.Validate())
+}
+
 type testConsumerGroupClaim struct {
 	messageChan chan *sarama.ConsumerMessage
 }
@@ -681,3 +769,18 @@ func (t *testConsumerGroup) Errors() <-chan error {
 func (t *testConsumerGroup) Close() error {
 	return nil
 }
+
+// commitRecordingSession records the marked messages and the commits.
+type commitRecordingSession struct {
+	testConsumerGroupSession
+	marked  int
+	commits int
+}
+
+func (t *commitRecordingSession) MarkMessage(*sarama.ConsumerMessage, string) {
+	t.marked++
+}
+
+func (t *commitRecordingSession) Commit() {
+	t.commits++
+}
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpjsonfilereceiver
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	startAtBeginning = "beginning"
+	startAtEnd       = "end"
+)
+
+// Config defines configuration for the OTLP JSON file receiver.
+type Config struct {
+	config.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Include is a list of glob patterns of the files to read.
+	Include []string `mapstructure:"include"`
+
+	// Exclude is a list of glob patterns of the files to skip, even if they match Include.
+	Exclude []string `mapstructure:"exclude"`
+
+	// StartAt defines where to start reading the files found when the receiver starts
+	// while following them, options: "beginning" (default), "end".
+	// Files which appear afterwards are always read from the beginning.
+	StartAt string `mapstructure:"start_at"`
+
+	// PollInterval is how often the files are checked for new data.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// Replay configures the replay mode, which reads the files once instead of following them.
+	Replay ReplaySettings `mapstructure:"replay"`
+}
+
+// ReplaySettings defines configuration for replaying the files.
+type ReplaySettings struct {
+	// Enabled reads every file once from the beginning, then stops.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Speed is the factor applied to the original time between two batches, based on
+	// their timestamps: 1 replays in real time, 2 twice as fast. Zero replays as fast as possible.
+	Speed float64 `mapstructure:"speed"`
+
+	// RebaseTimestamps shifts all the timestamps so that the first replayed batch appears to happen now.
+	RebaseTimestamps bool `mapstructure:"rebase_timestamps"`
+}
+
+var _ config.Receiver = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if len(cfg.Include) == 0 {
+		return errors.New("include must not be empty")
+	}
+	for _, pattern := range append(append([]string{}, cfg.Include...), cfg.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	if cfg.StartAt != startAtBeginning && cfg.StartAt != startAtEnd {
+		return fmt.Errorf("start_at %q is not supported, must be %q or %q", cfg.StartAt, startAtBeginning, startAtEnd)
+	}
+	if cfg.PollInterval <= 0 {
+		return errors.New("poll_interval must be positive")
+	}
+	if cfg.Replay.Speed < 0 {
+		return errors.New("replay speed must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpjsonfilereceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := configtest.LoadConfig(path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	r0 := cfg.Receivers[config.NewID(typeStr)]
+	assert.Equal(t, factory.CreateDefaultConfig(), r0)
+	assert.EqualError(t, r0.Validate(), "include must not be empty")
+
+	r1 := cfg.Receivers[config.NewIDWithName(typeStr, "follow")]
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "follow")),
+			Include:          []string{"/var/log/otel/*.json"},
+			Exclude:          []string{"/var/log/otel/debug*.json"},
+			StartAt:          startAtEnd,
+			PollInterval:     time.Second,
+			Replay:           ReplaySettings{Speed: 1},
+		}, r1)
+	assert.NoError(t, r1.Validate())
+
+	r2 := cfg.Receivers[config.NewIDWithName(typeStr, "replay")]
+	assert.Equal(t,
+		&Config{
+			ReceiverSettings: config.NewReceiverSettings(config.NewIDWithName(typeStr, "replay")),
+			Include:          []string{"./traces.json"},
+			StartAt:          startAtBeginning,
+			PollInterval:     defaultPollInterval,
+			Replay: ReplaySettings{
+				Enabled:          true,
+				Speed:            10,
+				RebaseTimestamps: true,
+			},
+		}, r2)
+	assert.NoError(t, r2.Validate())
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:    "invalid include pattern",
+			modify:  func(cfg *Config) { cfg.Include = []string{"[a-"} },
+			wantErr: `invalid glob pattern "[a-": syntax error in pattern`,
+		},
+		{
+			name:    "invalid exclude pattern",
+			modify:  func(cfg *Config) { cfg.Exclude = []string{"[a-"} },
+			wantErr: `invalid glob pattern "[a-": syntax error in pattern`,
+		},
+		{
+			name:    "invalid start_at",
+			modify:  func(cfg *Config) { cfg.StartAt = "middle" },
+			wantErr: `start_at "middle" is not supported, must be "beginning" or "end"`,
+		},
+		{
+			name:    "invalid poll_interval",
+			modify:  func(cfg *Config) { cfg.PollInterval = 0 },
+			wantErr: "poll_interval must be positive",
+		},
+		{
+			name:    "negative speed",
+			modify:  func(cfg *Config) { cfg.Replay.Speed = -1 },
+			wantErr: "replay speed must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Include = []string{"*.json"}
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
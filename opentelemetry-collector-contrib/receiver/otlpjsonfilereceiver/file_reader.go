@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpjsonfilereceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// lineHandler processes a single line read from a file.
+type lineHandler func(ctx context.Context, line []byte)
+
+// fileReader finds the files matching the configured patterns and reads them line by line,
+// either once (replay) or following them as they grow.
+type fileReader struct {
+	logger *zap.Logger
+	cfg    *Config
+	handle lineHandler
+
+	// offsets holds, per file, the position up to which it has been read when following the files.
+	offsets   map[string]int64
+	firstPoll bool
+}
+
+func newFileReader(logger *zap.Logger, cfg *Config, handle lineHandler) *fileReader {
+	return &fileReader{
+		logger:    logger,
+		cfg:       cfg,
+		handle:    handle,
+		offsets:   map[string]int64{},
+		firstPoll: true,
+	}
+}
+
+// matchFiles returns the sorted list of regular files matching Include but not Exclude.
+func (fr *fileReader) matchFiles() []string {
+	seen := map[string]struct{}{}
+	var files []string
+	for _, pattern := range fr.cfg.Include {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			fr.logger.Error("Invalid include pattern", zap.String("pattern", pattern), zap.Error(err))
+			continue
+		}
+		for _, match := range matches {
+			if _, ok := seen[match]; ok || fr.excluded(match) {
+				continue
+			}
+			if info, err := os.Stat(match); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			seen[match] = struct{}{}
+			files = append(files, match)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func (fr *fileReader) excluded(path string) bool {
+	for _, pattern := range fr.cfg.Exclude {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// replay reads all the matching files once, from the beginning to the end.
+func (fr *fileReader) replay(ctx context.Context) {
+	for _, path := range fr.matchFiles() {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := fr.readFrom(ctx, path, 0, true); err != nil {
+			fr.logger.Error("Failed to read file", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
+
+// follow polls the matching files until the context is done, reading the data appended to them.
+func (fr *fileReader) follow(ctx context.Context) {
+	ticker := time.NewTicker(fr.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		fr.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads the complete lines which were appended to the matching files since the previous poll.
+func (fr *fileReader) poll(ctx context.Context) {
+	files := fr.matchFiles()
+	present := make(map[string]struct{}, len(files))
+	for _, path := range files {
+		present[path] = struct{}{}
+		if ctx.Err() != nil {
+			return
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		offset, known := fr.offsets[path]
+		switch {
+		case !known && fr.firstPoll && fr.cfg.StartAt == startAtEnd:
+			fr.offsets[path] = info.Size()
+			continue
+		case info.Size() < offset:
+			// The file was truncated or replaced, start over.
+			fr.logger.Debug("File was truncated, reading it from the beginning", zap.String("path", path))
+			offset = 0
+		case info.Size() == offset:
+			continue
+		}
+
+		newOffset, err := fr.readFrom(ctx, path, offset, false)
+		if err != nil {
+			fr.logger.Error("Failed to read file", zap.String("path", path), zap.Error(err))
+		}
+		fr.offsets[path] = newOffset
+	}
+	fr.firstPoll = false
+
+	// Forget the files which disappeared, so they are read again if they come back.
+	for path := range fr.offsets {
+		if _, ok := present[path]; !ok {
+			delete(fr.offsets, path)
+		}
+	}
+}
+
+// readFrom reads the lines of the file starting at offset and returns the offset after the last
+// line handled. The last line is only handled when it is not terminated by a new line if
+// includePartial is set, otherwise it is expected to be completed later.
+func (fr *fileReader) readFrom(ctx context.Context, path string, offset int64, includePartial bool) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return offset, err
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+
+	reader := bufio.NewReader(f)
+	for ctx.Err() == nil {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if includePartial && len(line) > 0 {
+				fr.handleLine(ctx, line)
+				offset += int64(len(line))
+			}
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+		fr.handleLine(ctx, line)
+		offset += int64(len(line))
+	}
+	return offset, nil
+}
+
+func (fr *fileReader) handleLine(ctx context.Context, line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	fr.handle(ctx, line)
+}
@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpjsonfilereceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+const (
+	transport = "file"
+	format    = "otlp_json"
+
+	// Top level fields of the OTLP JSON messages, used to tell the signals apart
+	// since a single file can hold all of them.
+	tracesField  = "resourceSpans"
+	metricsField = "resourceMetrics"
+	logsField    = "resourceLogs"
+)
+
+var tracesUnmarshaler = otlp.NewJSONTracesUnmarshaler()
+var metricsUnmarshaler = otlp.NewJSONMetricsUnmarshaler()
+var logsUnmarshaler = otlp.NewJSONLogsUnmarshaler()
+
+// otlpJSONFileReceiver reads the configured files and hands the lines holding
+// its signal to consumeLine.
+type otlpJSONFileReceiver struct {
+	logger  *zap.Logger
+	cfg     *Config
+	obsrecv *obsreport.Receiver
+	clock   *replayClock
+
+	// field is the top level field the lines of this receiver's signal have.
+	field       string
+	consumeLine func(ctx context.Context, line []byte)
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newReceiver(set component.ReceiverCreateSettings, cfg *Config, field string) *otlpJSONFileReceiver {
+	return &otlpJSONFileReceiver{
+		logger: set.Logger,
+		cfg:    cfg,
+		obsrecv: obsreport.NewReceiver(obsreport.ReceiverSettings{
+			ReceiverID:   cfg.ID(),
+			Transport:    transport,
+			LongLivedCtx: true,
+		}),
+		clock: newReplayClock(cfg.Replay),
+		field: field,
+	}
+}
+
+func newTracesReceiver(set component.ReceiverCreateSettings, cfg *Config, nextConsumer consumer.Traces) *otlpJSONFileReceiver {
+	r := newReceiver(set, cfg, tracesField)
+	r.consumeLine = func(ctx context.Context, line []byte) {
+		td, err := tracesUnmarshaler.UnmarshalTraces(line)
+		if err != nil {
+			r.logger.Error("Failed to unmarshal traces", zap.Error(err))
+			return
+		}
+		if cfg.Replay.Enabled {
+			var batchTime pdata.Timestamp
+			mapTracesTimestamps(td, earliest(&batchTime))
+			shift, err := r.clock.wait(ctx, batchTime)
+			if err != nil {
+				return
+			}
+			if shift != 0 {
+				mapTracesTimestamps(td, shiftBy(shift))
+			}
+		}
+		obsCtx := r.obsrecv.StartTracesOp(ctx)
+		err = nextConsumer.ConsumeTraces(obsCtx, td)
+		r.obsrecv.EndTracesOp(obsCtx, format, td.SpanCount(), err)
+	}
+	return r
+}
+
+func newMetricsReceiver(set component.ReceiverCreateSettings, cfg *Config, nextConsumer consumer.Metrics) *otlpJSONFileReceiver {
+	r := newReceiver(set, cfg, metricsField)
+	r.consumeLine = func(ctx context.Context, line []byte) {
+		md, err := metricsUnmarshaler.UnmarshalMetrics(line)
+		if err != nil {
+			r.logger.Error("Failed to unmarshal metrics", zap.Error(err))
+			return
+		}
+		if cfg.Replay.Enabled {
+			var batchTime pdata.Timestamp
+			mapMetricsTimestamps(md, earliest(&batchTime), true)
+			shift, err := r.clock.wait(ctx, batchTime)
+			if err != nil {
+				return
+			}
+			if shift != 0 {
+				mapMetricsTimestamps(md, shiftBy(shift), false)
+			}
+		}
+		obsCtx := r.obsrecv.StartMetricsOp(ctx)
+		err = nextConsumer.ConsumeMetrics(obsCtx, md)
+		r.obsrecv.EndMetricsOp(obsCtx, format, md.DataPointCount(), err)
+	}
+	return r
+}
+
+func newLogsReceiver(set component.ReceiverCreateSettings, cfg *Config, nextConsumer consumer.Logs) *otlpJSONFileReceiver {
+	r := newReceiver(set, cfg, logsField)
+	r.consumeLine = func(ctx context.Context, line []byte) {
+		ld, err := logsUnmarshaler.UnmarshalLogs(line)
+		if err != nil {
+			r.logger.Error("Failed to unmarshal logs", zap.Error(err))
+			return
+		}
+		if cfg.Replay.Enabled {
+			var batchTime pdata.Timestamp
+			mapLogsTimestamps(ld, earliest(&batchTime))
+			shift, err := r.clock.wait(ctx, batchTime)
+			if err != nil {
+				return
+			}
+			if shift != 0 {
+				mapLogsTimestamps(ld, shiftBy(shift))
+			}
+		}
+		obsCtx := r.obsrecv.StartLogsOp(ctx)
+		err = nextConsumer.ConsumeLogs(obsCtx, ld)
+		r.obsrecv.EndLogsOp(obsCtx, format, ld.LogRecordCount(), err)
+	}
+	return r
+}
+
+// Start starts reading the files in the background.
+func (r *otlpJSONFileReceiver) Start(_ context.Context, _ component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	reader := newFileReader(r.logger, r.cfg, r.handleLine)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if r.cfg.Replay.Enabled {
+			reader.replay(ctx)
+			r.logger.Info("Finished replaying files")
+			return
+		}
+		reader.follow(ctx)
+	}()
+	return nil
+}
+
+// Shutdown stops reading the files.
+func (r *otlpJSONFileReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	return nil
+}
+
+// handleLine passes the line to consumeLine if it holds this receiver's signal.
+func (r *otlpJSONFileReceiver) handleLine(ctx context.Context, line []byte) {
+	ok, err := hasField(line, r.field)
+	if err != nil {
+		r.logger.Error("Failed to parse line as JSON", zap.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	r.consumeLine(ctx, line)
+}
+
+// hasField reports whether the JSON object of the line has the given top level field.
+// The values of the other fields are skipped without being decoded.
+func hasField(line []byte, field string) (bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil {
+		return false, err
+	} else if tok != json.Delim('{') {
+		return false, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return false, err
+		}
+		if tok == field {
+			return true, nil
+		}
+		var skipped json.RawMessage
+		if err := dec.Decode(&skipped); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,294 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpjsonfilereceiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+var testTime = time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func testTraces(start time.Time, spans int) pdata.Traces {
+	td := pdata.NewTraces()
+	ils := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty()
+	for i := 0; i < spans; i++ {
+		span := ils.Spans().AppendEmpty()
+		span.SetName("span")
+		span.SetStartTimestamp(pdata.NewTimestampFromTime(start))
+		span.SetEndTimestamp(pdata.NewTimestampFromTime(start.Add(time.Second)))
+	}
+	return td
+}
+
+func testMetrics(ts time.Time) pdata.Metrics {
+	md := pdata.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("gauge")
+	metric.SetDataType(pdata.MetricDataTypeGauge)
+	dp := metric.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pdata.NewTimestampFromTime(ts))
+	dp.SetIntVal(1)
+	return md
+}
+
+func testLogs(ts time.Time) pdata.Logs {
+	ld := pdata.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.SetTimestamp(pdata.NewTimestampFromTime(ts))
+	lr.Body().SetStringVal("log")
+	return ld
+}
+
+func writeLines(t *testing.T, path string, lines ...[]byte) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	defer f.Close()
+	for _, line := range lines {
+		_, err = f.Write(append(line, '\n'))
+		require.NoError(t, err)
+	}
+}
+
+func marshalTraces(t *testing.T, td pdata.Traces) []byte {
+	buf, err := otlp.NewJSONTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+	return buf
+}
+
+func marshalMetrics(t *testing.T, md pdata.Metrics) []byte {
+	buf, err := otlp.NewJSONMetricsMarshaler().MarshalMetrics(md)
+	require.NoError(t, err)
+	return buf
+}
+
+func marshalLogs(t *testing.T, ld pdata.Logs) []byte {
+	buf, err := otlp.NewJSONLogsMarshaler().MarshalLogs(ld)
+	require.NoError(t, err)
+	return buf
+}
+
+func testConfig(include ...string) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = include
+	cfg.PollInterval = 10 * time.Millisecond
+	return cfg
+}
+
+func TestReceiverFollowsFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	writeLines(t, path,
+		marshalTraces(t, testTraces(testTime, 2)),
+		marshalMetrics(t, testMetrics(testTime)),
+		marshalLogs(t, testLogs(testTime)))
+
+	tracesSink := new(consumertest.TracesSink)
+	metricsSink := new(consumertest.MetricsSink)
+	logsSink := new(consumertest.LogsSink)
+	set := componenttest.NewNopReceiverCreateSettings()
+	cfg := testConfig(filepath.Join(dir, "*.json"))
+	tr := newTracesReceiver(set, cfg, tracesSink)
+	mr := newMetricsReceiver(set, cfg, metricsSink)
+	lr := newLogsReceiver(set, cfg, logsSink)
+	for _, r := range []*otlpJSONFileReceiver{tr, mr, lr} {
+		require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	}
+
+	require.Eventually(t, func() bool {
+		return tracesSink.SpanCount() == 2 && len(metricsSink.AllMetrics()) == 1 && logsSink.LogRecordCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Appended lines are picked up, the partial one only once it is complete.
+	line := marshalTraces(t, testTraces(testTime, 3))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.Write(line[:10])
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2, tracesSink.SpanCount())
+	_, err = f.Write(append(line[10:], '\n'))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// New files are picked up too.
+	writeLines(t, filepath.Join(dir, "other.json"), marshalTraces(t, testTraces(testTime, 1)))
+
+	require.Eventually(t, func() bool {
+		return tracesSink.SpanCount() == 6
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for _, r := range []*otlpJSONFileReceiver{tr, mr, lr} {
+		require.NoError(t, r.Shutdown(context.Background()))
+	}
+	// Timestamps are untouched outside of replay.
+	span := tracesSink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, pdata.NewTimestampFromTime(testTime), span.StartTimestamp())
+}
+
+func TestReceiverStartAtEnd(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "traces.json")
+	writeLines(t, path, marshalTraces(t, testTraces(testTime, 2)))
+
+	sink := new(consumertest.TracesSink)
+	cfg := testConfig(path)
+	cfg.StartAt = startAtEnd
+	r := newTracesReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	time.Sleep(50 * time.Millisecond)
+	writeLines(t, path, marshalTraces(t, testTraces(testTime, 1)))
+
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestReceiverExcludesFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeLines(t, filepath.Join(dir, "a.json"), marshalTraces(t, testTraces(testTime, 1)))
+	writeLines(t, filepath.Join(dir, "b.json"), marshalTraces(t, testTraces(testTime, 2)))
+
+	sink := new(consumertest.TracesSink)
+	cfg := testConfig(filepath.Join(dir, "*.json"))
+	cfg.Exclude = []string{filepath.Join(dir, "b.json")}
+	cfg.Replay.Enabled = true
+	cfg.Replay.Speed = 0
+	r := newTracesReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestReceiverReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	writeLines(t, path,
+		marshalTraces(t, testTraces(testTime, 1)),
+		[]byte("not json"),
+		marshalTraces(t, testTraces(testTime.Add(time.Second), 1)))
+
+	sink := new(consumertest.TracesSink)
+	cfg := testConfig(path)
+	cfg.Replay = ReplaySettings{Enabled: true, Speed: 10, RebaseTimestamps: true}
+	r := newTracesReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+
+	start := time.Now()
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	// One second apart, replayed ten times faster.
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	first := sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	second := sink.AllTraces()[1].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.WithinDuration(t, start, first.StartTimestamp().AsTime(), time.Second)
+	assert.Equal(t, time.Second, first.EndTimestamp().AsTime().Sub(first.StartTimestamp().AsTime()))
+	// The original spacing between the timestamps is kept.
+	assert.Equal(t, time.Second, second.StartTimestamp().AsTime().Sub(first.StartTimestamp().AsTime()))
+}
+
+func TestReceiverShutdownStopsReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	writeLines(t, path,
+		marshalMetrics(t, testMetrics(testTime)),
+		marshalMetrics(t, testMetrics(testTime.Add(time.Hour))))
+
+	sink := new(consumertest.MetricsSink)
+	cfg := testConfig(path)
+	cfg.Replay.Enabled = true
+	r := newMetricsReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The second batch is due in an hour, shutdown must not wait for it.
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func TestMapTimestamps(t *testing.T) {
+	md := testMetrics(testTime)
+	dp := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+	dp.Exemplars().AppendEmpty().SetTimestamp(pdata.NewTimestampFromTime(testTime.Add(-time.Minute)))
+
+	var min pdata.Timestamp
+	mapMetricsTimestamps(md, earliest(&min), true)
+	// Exemplars are ignored and the zero start timestamp too.
+	assert.Equal(t, pdata.NewTimestampFromTime(testTime), min)
+
+	mapMetricsTimestamps(md, shiftBy(time.Hour), false)
+	assert.Equal(t, pdata.NewTimestampFromTime(testTime.Add(time.Hour)), dp.Timestamp())
+	assert.Equal(t, pdata.Timestamp(0), dp.StartTimestamp())
+	assert.Equal(t, pdata.NewTimestampFromTime(testTime.Add(59*time.Minute)), dp.Exemplars().At(0).Timestamp())
+
+	ld := testLogs(testTime)
+	mapLogsTimestamps(ld, shiftBy(-time.Hour))
+	assert.Equal(t, pdata.NewTimestampFromTime(testTime.Add(-time.Hour)),
+		ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Timestamp())
+}
+
+func TestHasField(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    bool
+		wantErr bool
+	}{
+		{line: `{"resourceSpans":[{"resource":{}}]}`, want: true},
+		{line: `{"other":{"resourceSpans":[]},"resourceSpans":[]}`, want: true},
+		{line: `{"resourceMetrics":[{"resource":{}}]}`, want: false},
+		{line: `{}`, want: false},
+		{line: `[]`, wantErr: true},
+		{line: `{"resourceMetrics":[`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := hasField([]byte(tt.line), tracesField)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReplayClockAsFastAsPossible(t *testing.T) {
+	clock := newReplayClock(ReplaySettings{Speed: 0})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		shift, err := clock.wait(context.Background(), pdata.NewTimestampFromTime(testTime.Add(time.Duration(i)*time.Hour)))
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), shift)
+	}
+	assert.Less(t, time.Since(start), time.Second)
+}
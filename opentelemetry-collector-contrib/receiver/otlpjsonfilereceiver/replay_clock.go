@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpjsonfilereceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// replayClock paces the replayed batches according to their timestamps and the replay speed,
+// and computes the shift to apply to their timestamps when rebasing them.
+type replayClock struct {
+	speed  float64
+	rebase bool
+	now    func() time.Time
+
+	started    bool
+	firstBatch time.Time
+	startWall  time.Time
+}
+
+func newReplayClock(cfg ReplaySettings) *replayClock {
+	return &replayClock{
+		speed:  cfg.Speed,
+		rebase: cfg.RebaseTimestamps,
+		now:    time.Now,
+	}
+}
+
+// wait blocks until the batch which started at batchTime is due and returns the duration
+// its timestamps must be shifted by. A zero batchTime is never delayed.
+func (rc *replayClock) wait(ctx context.Context, batchTime pdata.Timestamp) (time.Duration, error) {
+	if batchTime == 0 {
+		return 0, ctx.Err()
+	}
+
+	t := batchTime.AsTime()
+	if !rc.started {
+		rc.started = true
+		rc.firstBatch = t
+		rc.startWall = rc.now()
+	}
+
+	if rc.speed > 0 {
+		elapsed := time.Duration(float64(t.Sub(rc.firstBatch)) / rc.speed)
+		if delay := rc.startWall.Add(elapsed).Sub(rc.now()); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return 0, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+
+	if !rc.rebase {
+		return 0, nil
+	}
+	return rc.startWall.Sub(rc.firstBatch), nil
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpjsonfilereceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// timestampFunc maps a timestamp to its new value, it is never called with zero timestamps.
+type timestampFunc func(pdata.Timestamp) pdata.Timestamp
+
+func (f timestampFunc) apply(ts pdata.Timestamp) pdata.Timestamp {
+	if ts == 0 {
+		return ts
+	}
+	return f(ts)
+}
+
+// shiftBy returns a timestampFunc moving the timestamps by the given duration.
+func shiftBy(delta time.Duration) timestampFunc {
+	return func(ts pdata.Timestamp) pdata.Timestamp {
+		return pdata.Timestamp(int64(ts) + delta.Nanoseconds())
+	}
+}
+
+// earliest returns a timestampFunc which records the earliest timestamp it has seen
+// in the given pointer, leaving the timestamps untouched.
+func earliest(min *pdata.Timestamp) timestampFunc {
+	return func(ts pdata.Timestamp) pdata.Timestamp {
+		if *min == 0 || ts < *min {
+			*min = ts
+		}
+		return ts
+	}
+}
+
+// mapTracesTimestamps applies f to the start, end and event timestamps of all spans.
+func mapTracesTimestamps(td pdata.Traces, f timestampFunc) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				span.SetStartTimestamp(f.apply(span.StartTimestamp()))
+				span.SetEndTimestamp(f.apply(span.EndTimestamp()))
+				events := span.Events()
+				for l := 0; l < events.Len(); l++ {
+					events.At(l).SetTimestamp(f.apply(events.At(l).Timestamp()))
+				}
+			}
+		}
+	}
+}
+
+// mapMetricsTimestamps applies f to the timestamps of all data points and exemplars.
+// When pointsOnly is set, only the data point timestamps are visited, which is what
+// determines when a batch of metrics was recorded.
+func mapMetricsTimestamps(md pdata.Metrics, f timestampFunc, pointsOnly bool) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				switch metric.DataType() {
+				case pdata.MetricDataTypeGauge:
+					mapNumberDataPointsTimestamps(metric.Gauge().DataPoints(), f, pointsOnly)
+				case pdata.MetricDataTypeSum:
+					mapNumberDataPointsTimestamps(metric.Sum().DataPoints(), f, pointsOnly)
+				case pdata.MetricDataTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.SetTimestamp(f.apply(dp.Timestamp()))
+						if !pointsOnly {
+							dp.SetStartTimestamp(f.apply(dp.StartTimestamp()))
+							mapExemplarsTimestamps(dp.Exemplars(), f)
+						}
+					}
+				case pdata.MetricDataTypeSummary:
+					dps := metric.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.SetTimestamp(f.apply(dp.Timestamp()))
+						if !pointsOnly {
+							dp.SetStartTimestamp(f.apply(dp.StartTimestamp()))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func mapNumberDataPointsTimestamps(dps pdata.NumberDataPointSlice, f timestampFunc, pointsOnly bool) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		dp.SetTimestamp(f.apply(dp.Timestamp()))
+		if !pointsOnly {
+			dp.SetStartTimestamp(f.apply(dp.StartTimestamp()))
+			mapExemplarsTimestamps(dp.Exemplars(), f)
+		}
+	}
+}
+
+func mapExemplarsTimestamps(exemplars pdata.ExemplarSlice, f timestampFunc) {
+	for i := 0; i < exemplars.Len(); i++ {
+		exemplars.At(i).SetTimestamp(f.apply(exemplars.At(i).Timestamp()))
+	}
+}
+
+// mapLogsTimestamps applies f to the timestamps of all log records.
+func mapLogsTimestamps(ld pdata.Logs, f timestampFunc) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				logs.At(k).SetTimestamp(f.apply(logs.At(k).Timestamp()))
+			}
+		}
+	}
+}
@@ -17,6 +17,7 @@ package testbed
 import (
 	"context"
 	"log"
+	"math/rand"
 	"os"
 	"sync"
 	"time"
@@ -24,8 +25,26 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.uber.org/atomic"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// ChaosSettings configures the failures injected by the MockBackend to exercise the retries
+// of the exporters sending to it. The zero value accepts all the data.
+type ChaosSettings struct {
+	// ErrorRate is the fraction of the requests refused with a retryable UNAVAILABLE error.
+	ErrorRate float64
+	// ThrottleRate is the fraction of the requests refused with RESOURCE_EXHAUSTED, which the
+	// OTLP/HTTP receiver reports as 429 Too Many Requests.
+	ThrottleRate float64
+	// RetryAfter is the delay the throttled requests are asked to wait before being retried.
+	RetryAfter time.Duration
+	// Latency is added before answering every request.
+	Latency time.Duration
+}
+
 // MockBackend is a backend that allows receiving the data locally.
 type MockBackend struct {
 	// Metric and trace consumers
@@ -50,6 +69,12 @@ type MockBackend struct {
 	ReceivedTraces  []pdata.Traces
 	ReceivedMetrics []pdata.Metrics
 	ReceivedLogs    []pdata.Logs
+
+	// Failure injection fields.
+	chaosMutex      sync.Mutex
+	chaos           ChaosSettings
+	outageUntil     time.Time
+	numItemsRefused atomic.Uint64
 }
 
 // NewMockBackend creates a new mock backend that receives data using specified receiver.
@@ -114,9 +139,70 @@ func (mb *MockBackend) EnableRecording() {
 	mb.isRecording = true
 }
 
+// SetChaos sets the failures injected in the requests received from now on.
+func (mb *MockBackend) SetChaos(cs ChaosSettings) {
+	mb.chaosMutex.Lock()
+	defer mb.chaosMutex.Unlock()
+	mb.chaos = cs
+}
+
+// StartOutage refuses all the requests received during the given duration with UNAVAILABLE,
+// which the OTLP/HTTP receiver reports as 503 Service Unavailable.
+func (mb *MockBackend) StartOutage(d time.Duration) {
+	mb.chaosMutex.Lock()
+	defer mb.chaosMutex.Unlock()
+	mb.outageUntil = time.Now().Add(d)
+}
+
+// injectFailure applies the ChaosSettings and the outage to a request of the given number of
+// data items, and returns the error to refuse it with, if any.
+func (mb *MockBackend) injectFailure(ctx context.Context, items int) error {
+	mb.chaosMutex.Lock()
+	cs := mb.chaos
+	outage := time.Now().Before(mb.outageUntil)
+	mb.chaosMutex.Unlock()
+
+	var err error
+	if cs.Latency > 0 {
+		select {
+		case <-time.After(cs.Latency):
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	if err == nil {
+		switch r := rand.Float64(); {
+		case outage:
+			err = status.Error(codes.Unavailable, "mock backend outage")
+		case r < cs.ThrottleRate:
+			err = throttledStatus(cs.RetryAfter).Err()
+		case r < cs.ThrottleRate+cs.ErrorRate:
+			err = status.Error(codes.Unavailable, "mock backend injected error")
+		}
+	}
+
+	if err != nil {
+		mb.numItemsRefused.Add(uint64(items))
+	}
+	return err
+}
+
+func throttledStatus(retryAfter time.Duration) *status.Status {
+	s := status.New(codes.ResourceExhausted, "mock backend throttled the request")
+	if retryAfter <= 0 {
+		return s
+	}
+	if sd, err := s.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		return sd
+	}
+	return s
+}
+
 func (mb *MockBackend) GetStats() string {
 	received := mb.DataItemsReceived()
-	return printer.Sprintf("Received:%10d items (%d/sec)", received, int(float64(received)/time.Since(mb.startedAt).Seconds()))
+	return printer.Sprintf("Received:%10d items (%d/sec), Refused:%10d items", received,
+		int(float64(received)/time.Since(mb.startedAt).Seconds()), mb.DataItemsRefused())
 }
 
 // DataItemsReceived returns total number of received spans and metrics.
@@ -124,6 +210,12 @@ func (mb *MockBackend) DataItemsReceived() uint64 {
 	return mb.tc.numSpansReceived.Load() + mb.mc.numMetricsReceived.Load() + mb.lc.numLogRecordsReceived.Load()
 }
 
+// DataItemsRefused returns total number of data items refused by the injected failures.
+// The exporters retrying them successfully also count them in DataItemsReceived.
+func (mb *MockBackend) DataItemsRefused() uint64 {
+	return mb.numItemsRefused.Load()
+}
+
 // ClearReceivedItems clears the list of received traces and metrics. Note: counters
 // return by DataItemsReceived() are not cleared, they are cumulative.
 func (mb *MockBackend) ClearReceivedItems() {
@@ -169,7 +261,11 @@ func (tc *MockTraceConsumer) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
-func (tc *MockTraceConsumer) ConsumeTraces(_ context.Context, td pdata.Traces) error {
+func (tc *MockTraceConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if err := tc.backend.injectFailure(ctx, td.SpanCount()); err != nil {
+		return err
+	}
+
 	tc.numSpansReceived.Add(uint64(td.SpanCount()))
 
 	rs := td.ResourceSpans()
@@ -216,7 +312,11 @@ func (mc *MockMetricConsumer) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
-func (mc *MockMetricConsumer) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
+func (mc *MockMetricConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if err := mc.backend.injectFailure(ctx, md.DataPointCount()); err != nil {
+		return err
+	}
+
 	mc.numMetricsReceived.Add(uint64(md.DataPointCount()))
 	mc.backend.ConsumeMetric(md)
 	return nil
@@ -241,8 +341,11 @@ func (lc *MockLogConsumer) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
-func (lc *MockLogConsumer) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
+func (lc *MockLogConsumer) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
 	recordCount := ld.LogRecordCount()
+	if err := lc.backend.injectFailure(ctx, recordCount); err != nil {
+		return err
+	}
 	lc.numLogRecordsReceived.Add(uint64(recordCount))
 	lc.backend.ConsumeLogs(ld)
 	return nil
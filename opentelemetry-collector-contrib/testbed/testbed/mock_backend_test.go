@@ -15,11 +15,16 @@
 package testbed
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGeneratorAndBackend(t *testing.T) {
@@ -72,6 +77,49 @@ func TestGeneratorAndBackend(t *testing.T) {
 	}
 }
 
+func TestMockBackendChaos(t *testing.T) {
+	mb := NewMockBackend("mockbackend.log", NewOTLPDataReceiver(GetAvailablePort(t)))
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i := 0; i < 10; i++ {
+		spans.AppendEmpty()
+	}
+	ld := pdata.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+	for i := 0; i < 5; i++ {
+		logs.AppendEmpty()
+	}
+
+	require.NoError(t, mb.tc.ConsumeTraces(context.Background(), td))
+	assert.EqualValues(t, 10, mb.DataItemsReceived())
+
+	mb.SetChaos(ChaosSettings{ErrorRate: 1})
+	err := mb.tc.ConsumeTraces(context.Background(), td)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	mb.SetChaos(ChaosSettings{ThrottleRate: 1, RetryAfter: time.Second})
+	err = mb.lc.ConsumeLogs(context.Background(), ld)
+	s := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, s.Code())
+	require.Len(t, s.Details(), 1)
+	assert.Equal(t, time.Second, s.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+
+	mb.SetChaos(ChaosSettings{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	require.NoError(t, mb.tc.ConsumeTraces(context.Background(), td))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	mb.SetChaos(ChaosSettings{})
+	mb.StartOutage(time.Hour)
+	err = mb.tc.ConsumeTraces(context.Background(), td)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	mb.StartOutage(0)
+	require.NoError(t, mb.tc.ConsumeTraces(context.Background(), td))
+
+	assert.EqualValues(t, 30, mb.DataItemsReceived())
+	assert.EqualValues(t, 25, mb.DataItemsRefused())
+}
+
 // WaitFor the specific condition for up to 10 seconds. Records a test error
 // if condition does not become true.
 func WaitFor(t *testing.T, cond func() bool, errMsg ...interface{}) bool {
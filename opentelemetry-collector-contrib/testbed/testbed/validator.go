@@ -37,11 +37,21 @@ type TestCaseValidator interface {
 type PerfTestValidator struct{}
 
 func (v *PerfTestValidator) Validate(tc *TestCase) {
+	// The data refused by the failures injected in the backend must have been retried
+	// by the exporter, so it is not lost.
+	msg := "Received and sent counters do not match."
+	refused := tc.MockBackend.DataItemsRefused()
+	if refused > 0 {
+		msg = fmt.Sprintf("Received and sent counters do not match, the %d data items refused by the backend were not all retried.", refused)
+	}
 	if assert.EqualValues(tc.t,
 		int64(tc.LoadGenerator.DataItemsSent()),
 		int64(tc.MockBackend.DataItemsReceived()),
-		"Received and sent counters do not match.") {
+		msg) {
 		log.Printf("Sent and received data matches.")
+		if refused > 0 {
+			log.Printf("%d data items refused by the backend were retried.", refused)
+		}
 	}
 }
 
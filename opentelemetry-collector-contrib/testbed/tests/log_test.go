@@ -43,6 +43,15 @@ func TestLog10kDPS(t *testing.T) {
 				ExpectedMaxRAM: 85,
 			},
 		},
+		{
+			name:     "OTLP-gzip",
+			sender:   testbed.NewOTLPLogsDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPDataReceiver(testbed.GetAvailablePort(t)).WithCompression("gzip"),
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 90,
+			},
+		},
 		{
 			name:     "OTLP-HTTP",
 			sender:   testbed.NewOTLPHTTPLogsDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
@@ -52,6 +61,15 @@ func TestLog10kDPS(t *testing.T) {
 				ExpectedMaxRAM: 85,
 			},
 		},
+		{
+			name:     "OTLP-HTTP-gzip",
+			sender:   testbed.NewOTLPHTTPLogsDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPHTTPDataReceiver(testbed.GetAvailablePort(t)).WithCompression("gzip"),
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 90,
+			},
+		},
 		{
 			name:     "filelog",
 			sender:   datasenders.NewFileLogWriter(),
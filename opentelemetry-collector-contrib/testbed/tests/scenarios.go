@@ -23,6 +23,7 @@ import (
 	"path"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -190,6 +191,77 @@ func Scenario10kItemsPerSecond(
 	tc.ValidateData()
 }
 
+// ScenarioBackendFailures runs 1k data items/sec test against a backend injecting the failures
+// of the chaos settings, and an outage of the given duration in the middle of the test if it is
+// not zero. The exporter must retry the refused data using the default retry_on_failure and
+// sending_queue settings, so that all the data is eventually received.
+func ScenarioBackendFailures(
+	t *testing.T,
+	sender testbed.DataSender,
+	receiver testbed.DataReceiver,
+	chaos testbed.ChaosSettings,
+	outage time.Duration,
+	resourceSpec testbed.ResourceSpec,
+	resultsSummary testbed.TestResultsSummary,
+) {
+	resultDir, err := filepath.Abs(path.Join("results", t.Name()))
+	require.NoError(t, err)
+
+	options := testbed.LoadOptions{
+		DataItemsPerSecond: 1_000,
+		ItemsPerBatch:      10,
+		Parallel:           1,
+	}
+	agentProc := testbed.NewChildProcessCollector()
+
+	configStr := createConfigYaml(t, sender, receiver, resultDir, nil, nil)
+	configCleanup, err := agentProc.PrepareConfig(configStr)
+	require.NoError(t, err)
+	defer configCleanup()
+
+	dataProvider := testbed.NewPerfTestDataProvider(options)
+	tc := testbed.NewTestCase(
+		t,
+		dataProvider,
+		sender,
+		receiver,
+		agentProc,
+		&testbed.PerfTestValidator{},
+		resultsSummary,
+		testbed.WithResourceLimits(resourceSpec),
+	)
+	defer tc.Stop()
+
+	tc.StartBackend()
+	tc.MockBackend.SetChaos(chaos)
+	tc.StartAgent()
+
+	tc.StartLoad(options)
+
+	if outage > 0 {
+		tc.Sleep(tc.Duration / 2)
+		tc.MockBackend.StartOutage(outage)
+		tc.Sleep(tc.Duration / 2)
+	} else {
+		tc.Sleep(tc.Duration)
+	}
+
+	tc.StopLoad()
+
+	tc.WaitFor(func() bool { return tc.LoadGenerator.DataItemsSent() > 0 }, "load generator started")
+	assert.NotZero(t, tc.MockBackend.DataItemsRefused(), "no data items refused by the backend")
+
+	// Stop injecting failures so that the remaining retries succeed, the retry
+	// backoff may still delay them up to its 30s maximum interval.
+	tc.MockBackend.SetChaos(testbed.ChaosSettings{})
+	tc.WaitForN(func() bool { return tc.LoadGenerator.DataItemsSent() == tc.MockBackend.DataItemsReceived() },
+		time.Minute, "all data items received")
+
+	tc.StopAgent()
+
+	tc.ValidateData()
+}
+
 // TestCase for Scenario1kSPSWithAttrs func.
 type TestCase struct {
 	attrCount      int
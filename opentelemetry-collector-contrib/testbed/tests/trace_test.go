@@ -25,6 +25,7 @@ import (
 	"path"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -185,6 +186,94 @@ func TestTraceNoBackend10kSPS(t *testing.T) {
 	}
 }
 
+func TestTraceBackendFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		sender       testbed.DataSender
+		receiver     testbed.DataReceiver
+		chaos        testbed.ChaosSettings
+		outage       time.Duration
+		resourceSpec testbed.ResourceSpec
+	}{
+		{
+			name:     "OTLP-gRPC-Errors",
+			sender:   testbed.NewOTLPTraceDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPDataReceiver(testbed.GetAvailablePort(t)),
+			chaos:    testbed.ChaosSettings{ErrorRate: 0.2, Latency: 10 * time.Millisecond},
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 120,
+			},
+		},
+		{
+			name:     "OTLP-gRPC-Throttling",
+			sender:   testbed.NewOTLPTraceDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPDataReceiver(testbed.GetAvailablePort(t)),
+			chaos:    testbed.ChaosSettings{ThrottleRate: 0.2, RetryAfter: time.Second},
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 120,
+			},
+		},
+		{
+			name:     "OTLP-gRPC-Outage",
+			sender:   testbed.NewOTLPTraceDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPDataReceiver(testbed.GetAvailablePort(t)),
+			outage:   5 * time.Second,
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 120,
+			},
+		},
+		{
+			name:     "OTLP-HTTP-Errors",
+			sender:   testbed.NewOTLPHTTPTraceDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPHTTPDataReceiver(testbed.GetAvailablePort(t)),
+			chaos:    testbed.ChaosSettings{ErrorRate: 0.2, Latency: 10 * time.Millisecond},
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 120,
+			},
+		},
+		{
+			// The collector core the testbed depends on reports the throttled requests as
+			// HTTP 500 without Retry-After, so this only checks that they are retried.
+			name:     "OTLP-HTTP-Throttling-Without-RetryAfter",
+			sender:   testbed.NewOTLPHTTPTraceDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPHTTPDataReceiver(testbed.GetAvailablePort(t)),
+			chaos:    testbed.ChaosSettings{ThrottleRate: 0.2, RetryAfter: time.Second},
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 120,
+			},
+		},
+		{
+			name:     "OTLP-HTTP-Outage",
+			sender:   testbed.NewOTLPHTTPTraceDataSender(testbed.DefaultHost, testbed.GetAvailablePort(t)),
+			receiver: testbed.NewOTLPHTTPDataReceiver(testbed.GetAvailablePort(t)),
+			outage:   5 * time.Second,
+			resourceSpec: testbed.ResourceSpec{
+				ExpectedMaxCPU: 40,
+				ExpectedMaxRAM: 120,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ScenarioBackendFailures(
+				t,
+				test.sender,
+				test.receiver,
+				test.chaos,
+				test.outage,
+				test.resourceSpec,
+				performanceResultsSummary,
+			)
+		})
+	}
+}
+
 func TestTrace1kSPSWithAttrs(t *testing.T) {
 	Scenario1kSPSWithAttrs(t, []string{}, []TestCase{
 		// No attributes.
@@ -18,14 +18,25 @@ package tracegen
 import (
 	"flag"
 	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 )
 
+// The kinds of telemetry that can be generated.
+const (
+	ModeTraces  = "traces"
+	ModeMetrics = "metrics"
+	ModeLogs    = "logs"
+)
+
 // Config describes the test scenario.
 type Config struct {
 	WorkerCount      int
@@ -34,10 +45,21 @@ type Config struct {
 	Rate             int64
 	TotalDuration    time.Duration
 	ServiceName      string
+	ScenarioFile     string
+	Mode             string
 
 	// OTLP config
 	Endpoint string
 	Insecure bool
+	UseHTTP  bool
+
+	// Scenario is the topology to simulate, loaded from ScenarioFile when not set.
+	Scenario *Scenario
+	// TracerProvider returns the tracer provider of a service of the scenario, whose resource has the given
+	// attributes on top of the service name. The global tracer provider is used for all the services when not set.
+	TracerProvider func(serviceName string, attrs []attribute.KeyValue) trace.TracerProvider
+	// Exporter sends the generated metrics and logs.
+	Exporter Exporter
 }
 
 // Flags registers config flags.
@@ -48,10 +70,13 @@ func (c *Config) Flags(fs *flag.FlagSet) {
 	fs.Int64Var(&c.Rate, "rate", 0, "Approximately how many traces per second each worker should generate. Zero means no throttling.")
 	fs.DurationVar(&c.TotalDuration, "duration", 0, "For how long to run the test")
 	fs.StringVar(&c.ServiceName, "service", "tracegen", "Service name to use")
+	fs.StringVar(&c.ScenarioFile, "scenario", "", "Path to a YAML file describing the topology of services to simulate")
+	fs.StringVar(&c.Mode, "mode", ModeTraces, "The kind of telemetry to generate: traces, metrics or logs")
 
 	// unfortunately, at this moment, the otel-go client doesn't support configuring OTLP via env vars
 	fs.StringVar(&c.Endpoint, "otlp-endpoint", "localhost:4317", "Target to which the exporter is going to send spans or metrics. This MAY be configured to include a path (e.g. example.com/v1/traces)")
 	fs.BoolVar(&c.Insecure, "otlp-insecure", false, "Whether to enable client transport security for the exporter's grpc or http connection")
+	fs.BoolVar(&c.UseHTTP, "otlp-http", false, "Whether to send the data over OTLP/HTTP instead of OTLP/gRPC")
 }
 
 // Run executes the test scenario.
@@ -62,6 +87,33 @@ func Run(c *Config, logger *zap.Logger) error {
 		return fmt.Errorf("either `traces` or `duration` must be greater than 0")
 	}
 
+	switch c.Mode {
+	case "", ModeTraces:
+	case ModeMetrics, ModeLogs:
+		if c.Exporter == nil {
+			return fmt.Errorf("an exporter is required to generate %s", c.Mode)
+		}
+	default:
+		return fmt.Errorf("unknown mode %q, must be %q, %q or %q", c.Mode, ModeTraces, ModeMetrics, ModeLogs)
+	}
+	if c.Scenario == nil && c.ScenarioFile != "" {
+		scenario, err := LoadScenario(c.ScenarioFile)
+		if err != nil {
+			return err
+		}
+		c.Scenario = scenario
+	}
+	scenario := c.Scenario
+	if scenario == nil && (c.Mode == ModeMetrics || c.Mode == ModeLogs) {
+		scenario = defaultScenario(c.ServiceName)
+	}
+	tracerProvider := c.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = func(string, []attribute.KeyValue) trace.TracerProvider {
+			return otel.GetTracerProvider()
+		}
+	}
+
 	limit := rate.Limit(c.Rate)
 	if c.Rate == 0 {
 		limit = rate.Inf
@@ -82,9 +134,18 @@ func Run(c *Config, logger *zap.Logger) error {
 			running:          &running,
 			wg:               &wg,
 			logger:           logger.With(zap.Int("worker", i)),
+			mode:             c.Mode,
+			scenario:         scenario,
+			tracerProvider:   tracerProvider,
+			exporter:         c.Exporter,
+			rnd:              rand.New(rand.NewSource(time.Now().UnixNano() + int64(i))),
 		}
 
-		go w.simulateTraces()
+		if scenario == nil {
+			go w.simulateTraces()
+		} else {
+			go w.simulateScenario()
+		}
 	}
 	if c.TotalDuration > 0 {
 		time.Sleep(c.TotalDuration)
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// Exporter sends the generated metrics and logs.
+type Exporter interface {
+	ExportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error
+	ExportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error
+}
+
+type grpcExporter struct {
+	metrics colmetricspb.MetricsServiceClient
+	logs    collogspb.LogsServiceClient
+}
+
+// NewGRPCExporter returns an Exporter sending the data over OTLP/gRPC on the given connection.
+func NewGRPCExporter(conn *grpc.ClientConn) Exporter {
+	return &grpcExporter{
+		metrics: colmetricspb.NewMetricsServiceClient(conn),
+		logs:    collogspb.NewLogsServiceClient(conn),
+	}
+}
+
+func (e *grpcExporter) ExportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	_, err := e.metrics.Export(ctx, req)
+	return err
+}
+
+func (e *grpcExporter) ExportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	_, err := e.logs.Export(ctx, req)
+	return err
+}
+
+type httpExporter struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewHTTPExporter returns an Exporter sending the data over OTLP/HTTP to the given host:port.
+func NewHTTPExporter(endpoint string, insecure bool) Exporter {
+	scheme := "https"
+	if insecure {
+		scheme = "http"
+	}
+	return &httpExporter{
+		client:  &http.Client{},
+		baseURL: scheme + "://" + endpoint,
+	}
+}
+
+func (e *httpExporter) ExportMetrics(ctx context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	return e.export(ctx, "/v1/metrics", req)
+}
+
+func (e *httpExporter) ExportLogs(ctx context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	return e.export(ctx, "/v1/logs", req)
+}
+
+func (e *httpExporter) export(ctx context.Context, path string, msg proto.Message) error {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to export to %s: %s", path, resp.Status)
+	}
+	return nil
+}
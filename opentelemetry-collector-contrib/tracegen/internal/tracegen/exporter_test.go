@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegen
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHTTPExporter(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		if r.URL.Path == "/v1/logs" {
+			req := &collogspb.ExportLogsServiceRequest{}
+			assert.NoError(t, proto.Unmarshal(body, req))
+			assert.Len(t, req.ResourceLogs, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	exp := NewHTTPExporter(strings.TrimPrefix(srv.URL, "http://"), true)
+	scenario := defaultScenario("tracegen")
+	rnd := rand.New(rand.NewSource(1))
+
+	require.NoError(t, exp.ExportMetrics(context.Background(), &colmetricspb.ExportMetricsServiceRequest{}))
+	err := exp.ExportLogs(context.Background(), logsRequest(scenario.simulate(rnd, time.Now()), rnd))
+	assert.EqualError(t, err, "failed to export to /v1/logs: 503 Service Unavailable")
+	assert.Equal(t, []string{"/v1/metrics", "/v1/logs"}, paths)
+}
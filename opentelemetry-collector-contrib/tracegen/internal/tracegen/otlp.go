@@ -0,0 +1,188 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegen
+
+import (
+	"math/rand"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+const (
+	requestsMetric = "tracegen.requests"
+	durationMetric = "tracegen.duration"
+)
+
+var instrumentationLibrary = &commonpb.InstrumentationLibrary{Name: "tracegen"}
+
+// invocationsPerService groups the invocations of the tree per service, in the order the services first appear.
+func invocationsPerService(root *invocation) ([]*Service, map[*Service][]*invocation) {
+	var services []*Service
+	perService := map[*Service][]*invocation{}
+	root.walk(func(inv *invocation) {
+		svc := inv.operation.service
+		if _, ok := perService[svc]; !ok {
+			services = append(services, svc)
+		}
+		perService[svc] = append(perService[svc], inv)
+	})
+	return services, perService
+}
+
+// metricsRequest returns, for each service of the simulated invocations, the number of requests
+// per operation and outcome, as a delta sum, and the duration in milliseconds of the last
+// invocation of each operation, as a gauge.
+func metricsRequest(root *invocation) *colmetricspb.ExportMetricsServiceRequest {
+	start := uint64(root.start.UnixNano())
+	end := uint64(root.end.UnixNano())
+	services, perService := invocationsPerService(root)
+	req := &colmetricspb.ExportMetricsServiceRequest{}
+	for _, svc := range services {
+		type requestsKey struct {
+			operation string
+			failed    bool
+		}
+		var requestsKeys []requestsKey
+		requests := map[requestsKey]int64{}
+		var operations []string
+		durations := map[string]float64{}
+		for _, inv := range perService[svc] {
+			key := requestsKey{operation: inv.operation.Name, failed: inv.failed}
+			if _, ok := requests[key]; !ok {
+				requestsKeys = append(requestsKeys, key)
+			}
+			requests[key]++
+			if _, ok := durations[inv.operation.Name]; !ok {
+				operations = append(operations, inv.operation.Name)
+			}
+			durations[inv.operation.Name] = float64(inv.end.Sub(inv.start).Microseconds()) / 1000
+		}
+
+		sum := &metricspb.Sum{
+			AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA,
+			IsMonotonic:            true,
+		}
+		for _, key := range requestsKeys {
+			sum.DataPoints = append(sum.DataPoints, &metricspb.NumberDataPoint{
+				Attributes: toProtoAttributes([]attribute.KeyValue{
+					attribute.String("operation", key.operation),
+					attribute.Bool("error", key.failed),
+				}),
+				StartTimeUnixNano: start,
+				TimeUnixNano:      end,
+				Value:             &metricspb.NumberDataPoint_AsInt{AsInt: requests[key]},
+			})
+		}
+		gauge := &metricspb.Gauge{}
+		for _, op := range operations {
+			gauge.DataPoints = append(gauge.DataPoints, &metricspb.NumberDataPoint{
+				Attributes:   toProtoAttributes([]attribute.KeyValue{attribute.String("operation", op)}),
+				TimeUnixNano: end,
+				Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: durations[op]},
+			})
+		}
+
+		req.ResourceMetrics = append(req.ResourceMetrics, &metricspb.ResourceMetrics{
+			Resource: serviceResource(svc),
+			InstrumentationLibraryMetrics: []*metricspb.InstrumentationLibraryMetrics{{
+				InstrumentationLibrary: instrumentationLibrary,
+				Metrics: []*metricspb.Metric{
+					{
+						Name:        requestsMetric,
+						Description: "Number of simulated requests",
+						Unit:        "1",
+						Data:        &metricspb.Metric_Sum{Sum: sum},
+					},
+					{
+						Name:        durationMetric,
+						Description: "Duration of the last simulated request",
+						Unit:        "ms",
+						Data:        &metricspb.Metric_Gauge{Gauge: gauge},
+					},
+				},
+			}},
+		})
+	}
+	return req
+}
+
+// logsRequest returns a log record for each simulated invocation, correlated with the
+// trace the invocations would have produced.
+func logsRequest(root *invocation, rnd *rand.Rand) *collogspb.ExportLogsServiceRequest {
+	traceID := make([]byte, 16)
+	rnd.Read(traceID)
+	services, perService := invocationsPerService(root)
+	req := &collogspb.ExportLogsServiceRequest{}
+	for _, svc := range services {
+		var records []*logspb.LogRecord
+		for _, inv := range perService[svc] {
+			spanID := make([]byte, 8)
+			rnd.Read(spanID)
+			severity, severityText, body := logspb.SeverityNumber_SEVERITY_NUMBER_INFO, "INFO", inv.operation.Name+" completed"
+			if inv.failed {
+				severity, severityText, body = logspb.SeverityNumber_SEVERITY_NUMBER_ERROR, "ERROR", inv.operation.Name+" failed"
+			}
+			records = append(records, &logspb.LogRecord{
+				TimeUnixNano:   uint64(inv.end.UnixNano()),
+				SeverityNumber: severity,
+				SeverityText:   severityText,
+				Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: body}},
+				Attributes:     toProtoAttributes(inv.operation.attributes),
+				TraceId:        traceID,
+				SpanId:         spanID,
+			})
+		}
+		req.ResourceLogs = append(req.ResourceLogs, &logspb.ResourceLogs{
+			Resource: serviceResource(svc),
+			InstrumentationLibraryLogs: []*logspb.InstrumentationLibraryLogs{{
+				InstrumentationLibrary: instrumentationLibrary,
+				Logs:                   records,
+			}},
+		})
+	}
+	return req
+}
+
+func serviceResource(svc *Service) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Attributes: toProtoAttributes(append([]attribute.KeyValue{semconv.ServiceNameKey.String(svc.Name)}, svc.attributes...)),
+	}
+}
+
+func toProtoAttributes(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	kvs := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		v := &commonpb.AnyValue{}
+		switch kv.Value.Type() {
+		case attribute.BOOL:
+			v.Value = &commonpb.AnyValue_BoolValue{BoolValue: kv.Value.AsBool()}
+		case attribute.INT64:
+			v.Value = &commonpb.AnyValue_IntValue{IntValue: kv.Value.AsInt64()}
+		case attribute.FLOAT64:
+			v.Value = &commonpb.AnyValue_DoubleValue{DoubleValue: kv.Value.AsFloat64()}
+		default:
+			v.Value = &commonpb.AnyValue_StringValue{StringValue: kv.Value.Emit()}
+		}
+		kvs = append(kvs, &commonpb.KeyValue{Key: string(kv.Key), Value: v})
+	}
+	return kvs
+}
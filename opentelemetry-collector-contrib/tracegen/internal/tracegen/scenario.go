@@ -0,0 +1,323 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario describes a topology of services calling each other, used to synthesize
+// traces, metrics and logs.
+type Scenario struct {
+	// Root is the operation starting each trace.
+	Root Call `yaml:"root"`
+	// Services are the services of the topology.
+	Services []*Service `yaml:"services"`
+
+	root *Operation
+}
+
+// Service is a service of a Scenario.
+type Service struct {
+	Name string `yaml:"name"`
+	// Attributes are added to the resource of the service, along with its name.
+	Attributes map[string]interface{} `yaml:"attributes"`
+	// Operations are the operations exposed by the service.
+	Operations []*Operation `yaml:"operations"`
+
+	attributes []attribute.KeyValue
+}
+
+// Operation is an operation of a Service. Each invocation of an operation results in a span.
+type Operation struct {
+	Name string `yaml:"name"`
+	// Latency is the time spent by the operation itself, on top of the time spent in its calls.
+	Latency Latency `yaml:"latency"`
+	// ErrorRate is the probability, between 0 and 1, of the operation to fail.
+	ErrorRate float64 `yaml:"error_rate"`
+	// Attributes are added to the spans of the operation.
+	Attributes map[string]interface{} `yaml:"attributes"`
+	// Calls are the operations called, one after the other unless they are parallel, by the operation.
+	Calls []Call `yaml:"calls"`
+
+	service    *Service
+	attributes []attribute.KeyValue
+}
+
+// Call references the operation of a service.
+type Call struct {
+	Service   string `yaml:"service"`
+	Operation string `yaml:"operation"`
+	// Count is the number of times the operation is called, 1 when not set.
+	Count int `yaml:"count"`
+	// Parallel makes the invocations of the call start at the same time, along with the ones of the
+	// adjacent parallel calls. The next call starts when the latest of them ends.
+	Parallel bool `yaml:"parallel"`
+
+	target *Operation
+}
+
+// The supported latency distributions.
+const (
+	distributionConstant    = "constant"
+	distributionUniform     = "uniform"
+	distributionNormal      = "normal"
+	distributionExponential = "exponential"
+)
+
+// Latency describes the distribution of the latency of an operation.
+type Latency struct {
+	// Distribution is one of "constant" (default), "uniform", "normal" or "exponential".
+	Distribution string `yaml:"distribution"`
+	// Mean is the latency of the constant distribution, and the mean of the normal and exponential ones.
+	Mean time.Duration `yaml:"mean"`
+	// StdDev is the standard deviation of the normal distribution.
+	StdDev time.Duration `yaml:"stddev"`
+	// Min and Max bound the sampled latencies. They are the bounds of the uniform distribution.
+	Min time.Duration `yaml:"min"`
+	Max time.Duration `yaml:"max"`
+}
+
+// LoadScenario reads and validates the scenario file at the given path.
+func LoadScenario(path string) (*Scenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	s := &Scenario{}
+	if err = dec.Decode(s); err != nil {
+		return nil, fmt.Errorf("failed to read scenario %q: %w", path, err)
+	}
+	if err = s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %q: %w", path, err)
+	}
+	return s, nil
+}
+
+// defaultScenario returns a scenario with a single operation of the given service.
+func defaultScenario(serviceName string) *Scenario {
+	s := &Scenario{
+		Root: Call{Service: serviceName, Operation: "lets-go"},
+		Services: []*Service{{
+			Name: serviceName,
+			Operations: []*Operation{{
+				Name:    "lets-go",
+				Latency: Latency{Mean: fakeSpanDuration},
+			}},
+		}},
+	}
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate checks the scenario and resolves the calls between its operations.
+func (s *Scenario) Validate() error {
+	ops := map[string]*Operation{}
+	services := map[string]bool{}
+	for _, svc := range s.Services {
+		if svc.Name == "" {
+			return errors.New("services must have a name")
+		}
+		if services[svc.Name] {
+			return fmt.Errorf("duplicate service %q", svc.Name)
+		}
+		services[svc.Name] = true
+		svc.attributes = toAttributes(svc.Attributes)
+		for _, op := range svc.Operations {
+			key := svc.Name + "/" + op.Name
+			if op.Name == "" {
+				return fmt.Errorf("operations of service %q must have a name", svc.Name)
+			}
+			if _, ok := ops[key]; ok {
+				return fmt.Errorf("duplicate operation %q", key)
+			}
+			if op.ErrorRate < 0 || op.ErrorRate > 1 {
+				return fmt.Errorf("operation %q: error_rate must be between 0 and 1", key)
+			}
+			if err := op.Latency.validate(); err != nil {
+				return fmt.Errorf("operation %q: %w", key, err)
+			}
+			op.service = svc
+			op.attributes = toAttributes(op.Attributes)
+			ops[key] = op
+		}
+	}
+
+	resolve := func(c *Call) error {
+		if c.Count < 0 {
+			return fmt.Errorf("call to \"%s/%s\": count must not be negative", c.Service, c.Operation)
+		}
+		c.target = ops[c.Service+"/"+c.Operation]
+		if c.target == nil {
+			return fmt.Errorf("call to unknown operation \"%s/%s\"", c.Service, c.Operation)
+		}
+		return nil
+	}
+	if err := resolve(&s.Root); err != nil {
+		return fmt.Errorf("root: %w", err)
+	}
+	s.root = s.Root.target
+	for _, svc := range s.Services {
+		for _, op := range svc.Operations {
+			for i := range op.Calls {
+				if err := resolve(&op.Calls[i]); err != nil {
+					return fmt.Errorf("operation \"%s/%s\": %w", svc.Name, op.Name, err)
+				}
+			}
+		}
+	}
+	return checkCycles(s.root, map[*Operation]bool{})
+}
+
+// checkCycles returns an error if an operation calls itself, directly or not.
+func checkCycles(op *Operation, visiting map[*Operation]bool) error {
+	if visiting[op] {
+		return fmt.Errorf("operation \"%s/%s\" is part of a cycle", op.service.Name, op.Name)
+	}
+	visiting[op] = true
+	for _, c := range op.Calls {
+		if err := checkCycles(c.target, visiting); err != nil {
+			return err
+		}
+	}
+	delete(visiting, op)
+	return nil
+}
+
+func (l Latency) validate() error {
+	switch l.Distribution {
+	case "", distributionConstant, distributionNormal, distributionExponential:
+	case distributionUniform:
+		if l.Max < l.Min {
+			return errors.New("latency max must not be lower than min")
+		}
+	default:
+		return fmt.Errorf("unknown latency distribution %q", l.Distribution)
+	}
+	if l.Mean < 0 || l.StdDev < 0 || l.Min < 0 || l.Max < 0 {
+		return errors.New("latency durations must not be negative")
+	}
+	return nil
+}
+
+func (l Latency) sample(rnd *rand.Rand) time.Duration {
+	var d time.Duration
+	switch l.Distribution {
+	case distributionUniform:
+		d = l.Min + time.Duration(rnd.Int63n(int64(l.Max-l.Min)+1))
+	case distributionNormal:
+		d = l.Mean + time.Duration(rnd.NormFloat64()*float64(l.StdDev))
+	case distributionExponential:
+		d = time.Duration(rnd.ExpFloat64() * float64(l.Mean))
+	default:
+		d = l.Mean
+	}
+	if d < l.Min {
+		d = l.Min
+	}
+	if l.Max > 0 && d > l.Max {
+		d = l.Max
+	}
+	return d
+}
+
+// invocation is a simulated invocation of an operation.
+type invocation struct {
+	operation *Operation
+	// remote is true when the operation was called by another service, or is the root.
+	remote   bool
+	start    time.Time
+	end      time.Time
+	failed   bool
+	children []*invocation
+}
+
+// simulate simulates an invocation of the root operation of the scenario, starting at the given time.
+func (s *Scenario) simulate(rnd *rand.Rand, start time.Time) *invocation {
+	return simulateOperation(rnd, s.root, true, start)
+}
+
+func simulateOperation(rnd *rand.Rand, op *Operation, remote bool, start time.Time) *invocation {
+	inv := &invocation{operation: op, remote: remote, start: start}
+	// cursor is the end of the latest call, and parallelStart the start of the current parallel calls.
+	cursor := start
+	parallelStart := start
+	for i, c := range op.Calls {
+		if c.Parallel && (i == 0 || !op.Calls[i-1].Parallel) {
+			parallelStart = cursor
+		}
+		count := c.Count
+		if count == 0 {
+			count = 1
+		}
+		for j := 0; j < count; j++ {
+			childStart := cursor
+			if c.Parallel {
+				childStart = parallelStart
+			}
+			child := simulateOperation(rnd, c.target, c.target.service != op.service, childStart)
+			inv.children = append(inv.children, child)
+			if child.end.After(cursor) {
+				cursor = child.end
+			}
+		}
+	}
+	inv.end = cursor.Add(op.Latency.sample(rnd))
+	inv.failed = rnd.Float64() < op.ErrorRate
+	return inv
+}
+
+// walk calls fn for the invocation and all its descendants, parents first.
+func (inv *invocation) walk(fn func(inv *invocation)) {
+	fn(inv)
+	for _, child := range inv.children {
+		child.walk(fn)
+	}
+}
+
+// toAttributes converts the attributes of a scenario, sorted by key.
+func toAttributes(m map[string]interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(m))
+	for k, v := range m {
+		switch val := v.(type) {
+		case string:
+			attrs = append(attrs, attribute.String(k, val))
+		case bool:
+			attrs = append(attrs, attribute.Bool(k, val))
+		case int:
+			attrs = append(attrs, attribute.Int(k, val))
+		case float64:
+			attrs = append(attrs, attribute.Float64(k, val))
+		default:
+			attrs = append(attrs, attribute.String(k, fmt.Sprint(val)))
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+	return attrs
+}
@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracegen
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestLoadScenario(t *testing.T) {
+	s, err := LoadScenario(filepath.Join("testdata", "scenario.yaml"))
+	require.NoError(t, err)
+
+	require.Len(t, s.Services, 3)
+	assert.Equal(t, "checkout", s.root.Name)
+	assert.Equal(t, "platform-demo", s.root.service.Name)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("deployment.environment", "demo")}, s.Services[0].attributes)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("http.method", "POST"),
+		attribute.Int("http.status_code", 200),
+	}, s.root.attributes)
+	assert.Equal(t, Latency{Distribution: distributionNormal, Mean: 20 * time.Millisecond, StdDev: 5 * time.Millisecond, Min: time.Millisecond}, s.root.Latency)
+	assert.Equal(t, "charge", s.root.Calls[1].target.Name)
+}
+
+func TestLoadScenario_error(t *testing.T) {
+	tests := []struct {
+		file string
+		err  string
+	}{
+		{
+			file: "scenario-cycle.yaml",
+			err:  `operation "a/ping" is part of a cycle`,
+		},
+		{
+			file: "scenario-unknown-field.yaml",
+			err:  "field error not found",
+		},
+		{
+			file: "missing.yaml",
+			err:  "no such file or directory",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			_, err := LoadScenario(filepath.Join("testdata", test.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestScenarioValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario *Scenario
+		err      string
+	}{
+		{
+			name:     "unknown root",
+			scenario: &Scenario{Root: Call{Service: "a", Operation: "b"}},
+			err:      `root: call to unknown operation "a/b"`,
+		},
+		{
+			name: "unknown call",
+			scenario: &Scenario{
+				Root: Call{Service: "a", Operation: "b"},
+				Services: []*Service{{Name: "a", Operations: []*Operation{
+					{Name: "b", Calls: []Call{{Service: "a", Operation: "c"}}},
+				}}},
+			},
+			err: `operation "a/b": call to unknown operation "a/c"`,
+		},
+		{
+			name: "duplicate service",
+			scenario: &Scenario{
+				Services: []*Service{{Name: "a"}, {Name: "a"}},
+			},
+			err: `duplicate service "a"`,
+		},
+		{
+			name: "invalid error rate",
+			scenario: &Scenario{
+				Services: []*Service{{Name: "a", Operations: []*Operation{{Name: "b", ErrorRate: 2}}}},
+			},
+			err: `operation "a/b": error_rate must be between 0 and 1`,
+		},
+		{
+			name: "unknown distribution",
+			scenario: &Scenario{
+				Services: []*Service{{Name: "a", Operations: []*Operation{{Name: "b", Latency: Latency{Distribution: "poisson"}}}}},
+			},
+			err: `operation "a/b": unknown latency distribution "poisson"`,
+		},
+		{
+			name: "invalid uniform bounds",
+			scenario: &Scenario{
+				Services: []*Service{{Name: "a", Operations: []*Operation{
+					{Name: "b", Latency: Latency{Distribution: distributionUniform, Min: time.Second}},
+				}}},
+			},
+			err: `operation "a/b": latency max must not be lower than min`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.EqualError(t, test.scenario.Validate(), test.err)
+		})
+	}
+}
+
+func TestLatencySample(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	assert.Equal(t, 5*time.Millisecond, Latency{Mean: 5 * time.Millisecond}.sample(rnd))
+
+	uniform := Latency{Distribution: distributionUniform, Min: time.Millisecond, Max: 2 * time.Millisecond}
+	normal := Latency{Distribution: distributionNormal, Mean: time.Millisecond, StdDev: 10 * time.Millisecond}
+	exponential := Latency{Distribution: distributionExponential, Mean: time.Millisecond, Max: 3 * time.Millisecond}
+	for i := 0; i < 1000; i++ {
+		d := uniform.sample(rnd)
+		assert.True(t, d >= time.Millisecond && d <= 2*time.Millisecond, "uniform latency out of bounds: %v", d)
+		assert.True(t, normal.sample(rnd) >= 0, "normal latency must not be negative")
+		assert.True(t, exponential.sample(rnd) <= 3*time.Millisecond, "exponential latency above max")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	s, err := LoadScenario(filepath.Join("testdata", "scenario.yaml"))
+	require.NoError(t, err)
+
+	start := time.Now()
+	root := s.simulate(rand.New(rand.NewSource(1)), start)
+
+	var names []string
+	root.walk(func(inv *invocation) {
+		names = append(names, inv.operation.service.Name+"/"+inv.operation.Name)
+		assert.False(t, inv.end.Before(inv.start))
+		for i, child := range inv.children {
+			assert.False(t, child.start.Before(inv.start))
+			assert.False(t, child.end.After(inv.end))
+			if i > 0 {
+				assert.Equal(t, inv.children[i-1].end, child.start, "calls are sequential")
+			}
+		}
+	})
+	assert.Equal(t, []string{
+		"platform-demo/checkout",
+		"platform-demo/render",
+		"payment-service/charge",
+		"order-transmission/transmit",
+		"order-transmission/transmit",
+	}, names)
+	assert.Equal(t, start, root.start)
+	assert.True(t, root.remote)
+	assert.False(t, root.children[0].remote)
+	assert.True(t, root.children[1].remote)
+}
+
+func TestSimulateParallel(t *testing.T) {
+	s, err := LoadScenario(filepath.Join("testdata", "scenario-parallel.yaml"))
+	require.NoError(t, err)
+
+	start := time.Now()
+	root := s.simulate(rand.New(rand.NewSource(1)), start)
+
+	// The parallel calls start with the operation, the next call starts when the longest one ends.
+	require.Len(t, root.children, 4)
+	for _, child := range root.children[:3] {
+		assert.Equal(t, start, child.start)
+	}
+	assert.Equal(t, start.Add(10*time.Millisecond), root.children[0].end)
+	assert.Equal(t, start.Add(4*time.Millisecond), root.children[2].end)
+	assert.Equal(t, start.Add(10*time.Millisecond), root.children[3].start)
+	assert.Equal(t, start.Add(13*time.Millisecond), root.end)
+}
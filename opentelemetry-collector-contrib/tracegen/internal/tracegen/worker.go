@@ -17,12 +17,14 @@ package tracegen
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
@@ -38,6 +40,12 @@ type worker struct {
 	limitPerSecond   rate.Limit      // how many spans per second to generate
 	wg               *sync.WaitGroup // notify when done
 	logger           *zap.Logger
+
+	mode           string    // the kind of telemetry to generate from the scenario
+	scenario       *Scenario // the topology to simulate, the fixed two-span trace is generated when nil
+	tracerProvider func(serviceName string, attrs []attribute.KeyValue) trace.TracerProvider
+	exporter       Exporter // sends the metrics and logs
+	rnd            *rand.Rand
 }
 
 const (
@@ -89,3 +97,81 @@ func (w worker) simulateTraces() {
 	w.logger.Info("traces generated", zap.Int("traces", i))
 	w.wg.Done()
 }
+
+func (w worker) simulateScenario() {
+	limiter := rate.NewLimiter(w.limitPerSecond, 1)
+	var i int
+	for atomic.LoadUint32(w.running) == 1 {
+		limiter.Wait(context.Background())
+
+		root := w.scenario.simulate(w.rnd, time.Now())
+		var err error
+		switch w.mode {
+		case ModeMetrics:
+			err = w.exporter.ExportMetrics(context.Background(), metricsRequest(root))
+		case ModeLogs:
+			err = w.exporter.ExportLogs(context.Background(), logsRequest(root, w.rnd))
+		default:
+			w.emitSpans(context.Background(), root)
+		}
+		if err != nil {
+			w.logger.Error("failed to export", zap.String("mode", w.mode), zap.Error(err))
+		}
+
+		i++
+		if w.numTraces != 0 {
+			if i >= w.numTraces {
+				break
+			}
+		}
+	}
+	w.logger.Info("scenario simulated", zap.Int("iterations", i))
+	w.wg.Done()
+}
+
+func (w worker) tracer(svc *Service) trace.Tracer {
+	return w.tracerProvider(svc.Name, svc.attributes).Tracer("tracegen")
+}
+
+// emitSpans emits the span of the invocation and of its descendants. Calls to another service
+// result in a client span in the calling service, parent of the server span of the called one.
+func (w worker) emitSpans(ctx context.Context, inv *invocation) {
+	op := inv.operation
+	kind := trace.SpanKindInternal
+	if inv.remote {
+		kind = trace.SpanKindServer
+	}
+	ctx, sp := w.tracer(op.service).Start(ctx, op.Name,
+		trace.WithSpanKind(kind),
+		trace.WithTimestamp(inv.start),
+		trace.WithAttributes(op.attributes...))
+
+	for _, child := range inv.children {
+		if !child.remote {
+			w.emitSpans(ctx, child)
+			continue
+		}
+		childCtx, client := w.tracer(op.service).Start(ctx, child.operation.Name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(child.start),
+			trace.WithAttributes(semconv.PeerServiceKey.String(child.operation.service.Name)))
+		if w.propagateContext {
+			header := propagation.HeaderCarrier{}
+			// simulates going remote
+			otel.GetTextMapPropagator().Inject(childCtx, header)
+
+			// simulates getting a request from a client
+			childCtx = otel.GetTextMapPropagator().Extract(childCtx, header)
+		}
+		w.emitSpans(childCtx, child)
+		if child.failed {
+			client.SetStatus(codes.Error, "simulated error")
+		}
+		client.End(trace.WithTimestamp(child.end))
+	}
+
+	if inv.failed {
+		sp.SetStatus(codes.Error, "simulated error")
+	}
+	sp.End(trace.WithTimestamp(inv.end))
+}
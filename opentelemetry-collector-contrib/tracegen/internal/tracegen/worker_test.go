@@ -16,13 +16,22 @@ package tracegen
 
 import (
 	"context"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	"go.uber.org/zap"
 )
 
@@ -100,6 +109,163 @@ func TestUnthrottled(t *testing.T) {
 	assert.True(t, len(syncer.spans) > 100, "there should have been more than 100 spans, had %d", len(syncer.spans))
 }
 
+func TestScenarioTraces(t *testing.T) {
+	// prepare
+	syncer := &mockSyncer{}
+	sp := sdktrace.NewSimpleSpanProcessor(syncer)
+	providers := map[string]trace.TracerProvider{}
+
+	cfg := &Config{
+		NumTraces:    1,
+		WorkerCount:  1,
+		ScenarioFile: filepath.Join("testdata", "scenario.yaml"),
+		TracerProvider: func(serviceName string, attrs []attribute.KeyValue) trace.TracerProvider {
+			if tp, ok := providers[serviceName]; ok {
+				return tp
+			}
+			tp := sdktrace.NewTracerProvider(
+				sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+					append([]attribute.KeyValue{semconv.ServiceNameKey.String(serviceName)}, attrs...)...)),
+				sdktrace.WithSpanProcessor(sp),
+			)
+			providers[serviceName] = tp
+			return tp
+		},
+	}
+
+	// test
+	require.NoError(t, Run(cfg, zap.NewNop()))
+
+	// verify
+	// five invocations, and a client span for each of the three calls to another service
+	require.Len(t, syncer.spans, 8)
+	spansPerService := map[string][]string{}
+	for _, span := range syncer.spans {
+		svc, ok := span.Resource().Set().Value(semconv.ServiceNameKey)
+		require.True(t, ok)
+		spansPerService[svc.AsString()] = append(spansPerService[svc.AsString()], span.SpanKind().String()+" "+span.Name())
+		assert.Equal(t, syncer.spans[0].SpanContext().TraceID(), span.SpanContext().TraceID())
+		assert.False(t, span.EndTime().Before(span.StartTime()))
+	}
+	assert.ElementsMatch(t, []string{"internal render", "client charge", "server checkout"}, spansPerService["platform-demo"])
+	assert.ElementsMatch(t, []string{"client transmit", "client transmit", "server charge"}, spansPerService["payment-service"])
+	assert.ElementsMatch(t, []string{"server transmit", "server transmit"}, spansPerService["order-transmission"])
+}
+
+func TestScenarioTraces_errors(t *testing.T) {
+	// prepare
+	syncer := &mockSyncer{}
+
+	tracerProvider := sdktrace.NewTracerProvider()
+	sp := sdktrace.NewSimpleSpanProcessor(syncer)
+	tracerProvider.RegisterSpanProcessor(sp)
+	otel.SetTracerProvider(tracerProvider)
+
+	scenario := &Scenario{
+		Root:     Call{Service: "svc", Operation: "op"},
+		Services: []*Service{{Name: "svc", Operations: []*Operation{{Name: "op", ErrorRate: 1}}}},
+	}
+	require.NoError(t, scenario.Validate())
+	cfg := &Config{
+		NumTraces:   3,
+		WorkerCount: 1,
+		Scenario:    scenario,
+	}
+
+	// test
+	require.NoError(t, Run(cfg, zap.NewNop()))
+
+	// verify
+	require.Len(t, syncer.spans, 3)
+	for _, span := range syncer.spans {
+		assert.Equal(t, codes.Error, span.Status().Code)
+	}
+}
+
+func TestScenarioMetrics(t *testing.T) {
+	// prepare
+	exp := &mockExporter{}
+	cfg := &Config{
+		NumTraces:    2,
+		WorkerCount:  1,
+		Mode:         ModeMetrics,
+		ScenarioFile: filepath.Join("testdata", "scenario.yaml"),
+		Exporter:     exp,
+	}
+
+	// test
+	require.NoError(t, Run(cfg, zap.NewNop()))
+
+	// verify
+	require.Len(t, exp.metrics, 2)
+	rms := exp.metrics[0].ResourceMetrics
+	require.Len(t, rms, 3)
+	assert.Equal(t, "order-transmission", rms[2].Resource.Attributes[0].Value.GetStringValue())
+	metrics := rms[2].InstrumentationLibraryMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+	assert.Equal(t, requestsMetric, metrics[0].Name)
+	requests := metrics[0].GetSum().DataPoints
+	require.Len(t, requests, 1)
+	assert.Equal(t, int64(2), requests[0].GetAsInt())
+	assert.Equal(t, durationMetric, metrics[1].Name)
+	assert.Equal(t, 5.0, metrics[1].GetGauge().DataPoints[0].GetAsDouble())
+}
+
+func TestScenarioLogs(t *testing.T) {
+	// prepare
+	exp := &mockExporter{}
+	cfg := &Config{
+		NumTraces:   1,
+		WorkerCount: 1,
+		Mode:        ModeLogs,
+		ServiceName: "tracegen",
+		Exporter:    exp,
+	}
+
+	// test
+	require.NoError(t, Run(cfg, zap.NewNop()))
+
+	// verify
+	require.Len(t, exp.logs, 1)
+	rls := exp.logs[0].ResourceLogs
+	require.Len(t, rls, 1)
+	assert.Equal(t, "tracegen", rls[0].Resource.Attributes[0].Value.GetStringValue())
+	records := rls[0].InstrumentationLibraryLogs[0].Logs
+	require.Len(t, records, 1)
+	assert.Equal(t, "lets-go completed", records[0].Body.GetStringValue())
+	assert.Len(t, records[0].TraceId, 16)
+	assert.Len(t, records[0].SpanId, 8)
+}
+
+func TestRun_invalidMode(t *testing.T) {
+	assert.EqualError(t, Run(&Config{NumTraces: 1, Mode: "profiles"}, zap.NewNop()),
+		`unknown mode "profiles", must be "traces", "metrics" or "logs"`)
+	assert.EqualError(t, Run(&Config{NumTraces: 1, Mode: ModeLogs}, zap.NewNop()),
+		"an exporter is required to generate logs")
+}
+
+var _ Exporter = (*mockExporter)(nil)
+
+type mockExporter struct {
+	mu      sync.Mutex
+	metrics []*colmetricspb.ExportMetricsServiceRequest
+	logs    []*collogspb.ExportLogsServiceRequest
+}
+
+func (m *mockExporter) ExportMetrics(_ context.Context, req *colmetricspb.ExportMetricsServiceRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.metrics = append(m.metrics, req)
+	return nil
+}
+
+func (m *mockExporter) ExportLogs(_ context.Context, req *collogspb.ExportLogsServiceRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs = append(m.logs, req)
+	return nil
+}
+
 var _ sdktrace.SpanExporter = (*mockSyncer)(nil)
 
 type mockSyncer struct {
@@ -19,14 +19,19 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"sync"
 	"time"
 
 	grpcZap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
@@ -47,18 +52,12 @@ func main() {
 		zap.AddCallerSkip(3),
 	))
 
-	expOptions := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(cfg.Endpoint),
-		otlptracegrpc.WithDialOption(
-			grpc.WithBlock(),
-		),
-	}
-
-	if cfg.Insecure {
-		expOptions = append(expOptions, otlptracegrpc.WithInsecure())
+	if cfg.Mode == tracegen.ModeMetrics || cfg.Mode == tracegen.ModeLogs {
+		runMetricsOrLogs(cfg, logger)
+		return
 	}
 
-	exp, err := otlptracegrpc.New(context.Background(), expOptions...)
+	exp, err := newTraceExporter(cfg)
 	if err != nil {
 		logger.Error("failed to obtain OTLP exporter", zap.Error(err))
 		return
@@ -81,7 +80,81 @@ func main() {
 	tracerProvider.RegisterSpanProcessor(ssp)
 	otel.SetTracerProvider(tracerProvider)
 
+	// each service of a scenario gets its own tracer provider, so that its spans have its own resource
+	providers := &serviceTracerProviders{processor: ssp, providers: map[string]trace.TracerProvider{}}
+	cfg.TracerProvider = providers.get
+
 	if err := tracegen.Run(cfg, logger); err != nil {
 		logger.Error("failed to stop the exporter", zap.Error(err))
 	}
 }
+
+func newTraceExporter(cfg *tracegen.Config) (*otlptrace.Exporter, error) {
+	if cfg.UseHTTP {
+		expOptions := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.Endpoint),
+		}
+		if cfg.Insecure {
+			expOptions = append(expOptions, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), expOptions...)
+	}
+
+	expOptions := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		otlptracegrpc.WithDialOption(
+			grpc.WithBlock(),
+		),
+	}
+
+	if cfg.Insecure {
+		expOptions = append(expOptions, otlptracegrpc.WithInsecure())
+	}
+
+	return otlptracegrpc.New(context.Background(), expOptions...)
+}
+
+func runMetricsOrLogs(cfg *tracegen.Config, logger *zap.Logger) {
+	if cfg.UseHTTP {
+		cfg.Exporter = tracegen.NewHTTPExporter(cfg.Endpoint, cfg.Insecure)
+	} else {
+		dialOptions := []grpc.DialOption{grpc.WithBlock()}
+		if cfg.Insecure {
+			dialOptions = append(dialOptions, grpc.WithInsecure())
+		}
+		conn, err := grpc.Dial(cfg.Endpoint, dialOptions...)
+		if err != nil {
+			logger.Error("failed to connect to the OTLP endpoint", zap.Error(err))
+			return
+		}
+		defer conn.Close()
+		cfg.Exporter = tracegen.NewGRPCExporter(conn)
+	}
+
+	if err := tracegen.Run(cfg, logger); err != nil {
+		logger.Error("failed to generate "+cfg.Mode, zap.Error(err))
+	}
+}
+
+// serviceTracerProviders creates a tracer provider per service, all sharing the same span processor.
+type serviceTracerProviders struct {
+	processor sdktrace.SpanProcessor
+
+	mu        sync.Mutex
+	providers map[string]trace.TracerProvider
+}
+
+func (s *serviceTracerProviders) get(serviceName string, attrs []attribute.KeyValue) trace.TracerProvider {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if tp, ok := s.providers[serviceName]; ok {
+		return tp
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			append([]attribute.KeyValue{semconv.ServiceNameKey.String(serviceName)}, attrs...)...)),
+		sdktrace.WithSpanProcessor(s.processor),
+	)
+	s.providers[serviceName] = tp
+	return tp
+}
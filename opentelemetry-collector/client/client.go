@@ -19,15 +19,27 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"strings"
 
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
 
 type ctxKey struct{}
 
+type principalCtxKey struct{}
+
 // Client represents a generic client that sends data to any receiver supported by the OT receiver
 type Client struct {
 	IP string
+
+	// Principal is the authenticated subject of the request, as determined by a configauth.ServerAuthenticator.
+	// It is empty when the request wasn't authenticated.
+	Principal string
+
+	// Metadata holds the subset of the request headers (HTTP) or metadata (gRPC) selected by the receiver's
+	// configuration. Keys are always lowercase.
+	Metadata map[string][]string
 }
 
 // NewContext takes an existing context and derives a new context with the client value stored on it
@@ -41,24 +53,63 @@ func FromContext(ctx context.Context) (*Client, bool) {
 	return c, ok
 }
 
-// FromGRPC takes a GRPC context and tries to extract client information from it
-func FromGRPC(ctx context.Context) (*Client, bool) {
+// NewContextWithPrincipal derives a new context with the given authenticated principal stored on it.
+// Server authenticators are expected to call this on successful authentication, so that FromGRPC and FromHTTP
+// can record the principal in the resulting Client.
+func NewContextWithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal stored in the context, if present.
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(string)
+	return p, ok
+}
+
+// FromGRPC takes a GRPC context and tries to extract client information from it.
+// Only the incoming metadata entries listed in metadataKeys are recorded in the resulting Client.
+func FromGRPC(ctx context.Context, metadataKeys ...string) (*Client, bool) {
+	c := &Client{}
 	if p, ok := peer.FromContext(ctx); ok {
-		ip := parseIP(p.Addr.String())
-		if ip != "" {
-			return &Client{ip}, true
-		}
+		c.IP = parseIP(p.Addr.String())
 	}
-	return nil, false
+	c.Principal, _ = PrincipalFromContext(ctx)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		c.Metadata = selectMetadata(metadataKeys, md.Get)
+	}
+	if c.IP == "" && c.Principal == "" && len(c.Metadata) == 0 {
+		return nil, false
+	}
+	return c, true
 }
 
-// FromHTTP takes a net/http Request object and tries to extract client information from it
-func FromHTTP(r *http.Request) (*Client, bool) {
-	ip := parseIP(r.RemoteAddr)
-	if ip == "" {
+// FromHTTP takes a net/http Request object and tries to extract client information from it.
+// Only the request headers listed in metadataKeys are recorded in the resulting Client.
+func FromHTTP(r *http.Request, metadataKeys ...string) (*Client, bool) {
+	c := &Client{IP: parseIP(r.RemoteAddr)}
+	c.Principal, _ = PrincipalFromContext(r.Context())
+	c.Metadata = selectMetadata(metadataKeys, r.Header.Values)
+	if c.IP == "" && c.Principal == "" && len(c.Metadata) == 0 {
 		return nil, false
 	}
-	return &Client{ip}, true
+	return c, true
+}
+
+// selectMetadata returns the values for the given keys, keyed by their lowercase form.
+// Keys without values are omitted. It returns nil when no values are found.
+func selectMetadata(keys []string, get func(key string) []string) map[string][]string {
+	var selected map[string][]string
+	for _, k := range keys {
+		vals := get(k)
+		if len(vals) == 0 {
+			continue
+		}
+		if selected == nil {
+			selected = make(map[string][]string, len(keys))
+		}
+		selected[strings.ToLower(k)] = append([]string(nil), vals...)
+	}
+	return selected
 }
 
 func parseIP(source string) string {
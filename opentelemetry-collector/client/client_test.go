@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 )
 
@@ -30,7 +31,7 @@ func TestClientContext(t *testing.T) {
 		"1.1.1.1", "127.0.0.1", "1111", "ip",
 	}
 	for _, ip := range ips {
-		ctx := NewContext(context.Background(), &Client{ip})
+		ctx := NewContext(context.Background(), &Client{IP: ip})
 		c, ok := FromContext(ctx)
 		assert.True(t, ok)
 		assert.NotNil(t, c)
@@ -58,3 +59,59 @@ func TestParsingHTTP(t *testing.T) {
 	assert.NotNil(t, client)
 	assert.Equal(t, client.IP, "192.168.1.2")
 }
+
+func TestParsingGRPCMetadata(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{
+			IP:   net.ParseIP("192.168.1.1"),
+			Port: 80,
+		},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"x-scope-orgid", "acme",
+		"authorization", "secret",
+	))
+	ctx = NewContextWithPrincipal(ctx, "user-1")
+
+	client, ok := FromGRPC(ctx, "X-Scope-OrgID", "missing")
+	assert.True(t, ok)
+	assert.Equal(t, "192.168.1.1", client.IP)
+	assert.Equal(t, "user-1", client.Principal)
+	assert.Equal(t, map[string][]string{"x-scope-orgid": {"acme"}}, client.Metadata)
+}
+
+func TestParsingHTTPMetadata(t *testing.T) {
+	r := &http.Request{
+		RemoteAddr: "192.168.1.2",
+		Header: http.Header{
+			"X-Scope-Orgid": {"acme", "other"},
+			"Authorization": {"secret"},
+		},
+	}
+	r = r.WithContext(NewContextWithPrincipal(context.Background(), "user-1"))
+
+	client, ok := FromHTTP(r, "x-scope-orgid")
+	assert.True(t, ok)
+	assert.Equal(t, "192.168.1.2", client.IP)
+	assert.Equal(t, "user-1", client.Principal)
+	assert.Equal(t, map[string][]string{"x-scope-orgid": {"acme", "other"}}, client.Metadata)
+}
+
+func TestParsingNoClientInfo(t *testing.T) {
+	client, ok := FromGRPC(context.Background(), "x-scope-orgid")
+	assert.False(t, ok)
+	assert.Nil(t, client)
+
+	client, ok = FromHTTP(&http.Request{}, "x-scope-orgid")
+	assert.False(t, ok)
+	assert.Nil(t, client)
+}
+
+func TestPrincipalFromContext(t *testing.T) {
+	_, ok := PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+
+	p, ok := PrincipalFromContext(NewContextWithPrincipal(context.Background(), "user-1"))
+	assert.True(t, ok)
+	assert.Equal(t, "user-1", p)
+}
@@ -20,7 +20,7 @@ import (
 	"go.opentelemetry.io/collector/config"
 )
 
-// Component is either a receiver, exporter, processor, or an extension.
+// Component is either a receiver, exporter, processor, connector, or an extension.
 //
 // A component's lifecycle has the following phases:
 //
@@ -69,6 +69,7 @@ const (
 	KindProcessor
 	KindExporter
 	KindExtension
+	KindConnector
 )
 
 // Factory is implemented by all component factories.
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package componenttest
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+// NewNopConnectorCreateSettings returns a new nop settings for Create*Connector functions.
+func NewNopConnectorCreateSettings() component.ConnectorCreateSettings {
+	return component.ConnectorCreateSettings{
+		TelemetryCreateSettings: NewNopTelemetryCreateSettings(),
+		BuildInfo:               component.DefaultBuildInfo(),
+	}
+}
+
+type nopConnectorConfig struct {
+	config.ConnectorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+}
+
+// nopConnectorFactory is factory for nopConnector.
+type nopConnectorFactory struct{}
+
+var nopConnectorFactoryInstance = &nopConnectorFactory{}
+
+// NewNopConnectorFactory returns a component.ConnectorFactory that constructs nop connectors.
+func NewNopConnectorFactory() component.ConnectorFactory {
+	return nopConnectorFactoryInstance
+}
+
+// Type gets the type of the Connector config created by this factory.
+func (f *nopConnectorFactory) Type() config.Type {
+	return "nop"
+}
+
+// CreateDefaultConfig creates the default configuration for the Connector.
+func (f *nopConnectorFactory) CreateDefaultConfig() config.Connector {
+	return &nopConnectorConfig{
+		ConnectorSettings: config.NewConnectorSettings(config.NewID("nop")),
+	}
+}
+
+// CreateTracesToTracesConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateTracesToTracesConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Traces,
+) (component.TracesConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateTracesToMetricsConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateTracesToMetricsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Metrics,
+) (component.TracesConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateTracesToLogsConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateTracesToLogsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Logs,
+) (component.TracesConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateMetricsToTracesConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateMetricsToTracesConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Traces,
+) (component.MetricsConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateMetricsToMetricsConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateMetricsToMetricsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Metrics,
+) (component.MetricsConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateMetricsToLogsConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateMetricsToLogsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Logs,
+) (component.MetricsConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateLogsToTracesConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateLogsToTracesConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Traces,
+) (component.LogsConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateLogsToMetricsConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateLogsToMetricsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Metrics,
+) (component.LogsConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+// CreateLogsToLogsConnector implements component.ConnectorFactory interface.
+func (f *nopConnectorFactory) CreateLogsToLogsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	_ consumer.Logs,
+) (component.LogsConnector, error) {
+	return nopConnectorInstance, nil
+}
+
+var nopConnectorInstance = &nopConnector{
+	Component: componenthelper.New(),
+	Consumer:  consumertest.NewNop(),
+}
+
+// nopConnector drops all the data it consumes, nothing is emitted to the next pipelines.
+type nopConnector struct {
+	component.Component
+	consumertest.Consumer
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package componenttest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestNewNopConnectorFactory(t *testing.T) {
+	factory := NewNopConnectorFactory()
+	require.NotNil(t, factory)
+	assert.Equal(t, config.Type("nop"), factory.Type())
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &nopConnectorConfig{ConnectorSettings: config.NewConnectorSettings(config.NewID("nop"))}, cfg)
+
+	traces, err := factory.CreateTracesToTracesConnector(context.Background(), NewNopConnectorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NoError(t, traces.Start(context.Background(), NewNopHost()))
+	assert.NoError(t, traces.ConsumeTraces(context.Background(), pdata.NewTraces()))
+	assert.NoError(t, traces.Shutdown(context.Background()))
+
+	metrics, err := factory.CreateMetricsToMetricsConnector(context.Background(), NewNopConnectorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NoError(t, metrics.Start(context.Background(), NewNopHost()))
+	assert.NoError(t, metrics.ConsumeMetrics(context.Background(), pdata.NewMetrics()))
+	assert.NoError(t, metrics.Shutdown(context.Background()))
+
+	logs, err := factory.CreateLogsToLogsConnector(context.Background(), NewNopConnectorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NoError(t, logs.Start(context.Background(), NewNopHost()))
+	assert.NoError(t, logs.ConsumeLogs(context.Background(), pdata.NewLogs()))
+	assert.NoError(t, logs.Shutdown(context.Background()))
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package component
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+// Connector links pipelines together. It acts as an exporter at the end of one pipeline
+// and as a receiver at the beginning of another, possibly of a different data type.
+type Connector interface {
+	Component
+}
+
+// TracesConnector is a Connector that can consume traces from the pipelines where it is
+// used as an exporter.
+type TracesConnector interface {
+	Connector
+	consumer.Traces
+}
+
+// MetricsConnector is a Connector that can consume metrics from the pipelines where it is
+// used as an exporter.
+type MetricsConnector interface {
+	Connector
+	consumer.Metrics
+}
+
+// LogsConnector is a Connector that can consume logs from the pipelines where it is
+// used as an exporter.
+type LogsConnector interface {
+	Connector
+	consumer.Logs
+}
+
+// ConnectorCreateSettings configures Connector creators.
+type ConnectorCreateSettings struct {
+	TelemetryCreateSettings
+
+	// BuildInfo can be used by components for informational purposes
+	BuildInfo BuildInfo
+}
+
+// ConnectorFactory can create a Connector for every combination of the data type it
+// consumes and the data type it emits to the next pipelines.
+type ConnectorFactory interface {
+	Factory
+
+	// CreateDefaultConfig creates the default configuration for the Connector.
+	// This method can be called multiple times depending on the pipeline
+	// configuration and should not cause side-effects that prevent the creation
+	// of multiple instances of the Connector.
+	// The object returned by this method needs to pass the checks implemented by
+	// 'configcheck.ValidateConfig'. It is recommended to have these checks in the
+	// tests of any implementation of the Factory interface.
+	CreateDefaultConfig() config.Connector
+
+	// CreateTracesToTracesConnector creates a connector that consumes traces and emits traces.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateTracesToTracesConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Traces) (TracesConnector, error)
+
+	// CreateTracesToMetricsConnector creates a connector that consumes traces and emits metrics.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateTracesToMetricsConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Metrics) (TracesConnector, error)
+
+	// CreateTracesToLogsConnector creates a connector that consumes traces and emits logs.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateTracesToLogsConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Logs) (TracesConnector, error)
+
+	// CreateMetricsToTracesConnector creates a connector that consumes metrics and emits traces.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateMetricsToTracesConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Traces) (MetricsConnector, error)
+
+	// CreateMetricsToMetricsConnector creates a connector that consumes metrics and emits metrics.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateMetricsToMetricsConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Metrics) (MetricsConnector, error)
+
+	// CreateMetricsToLogsConnector creates a connector that consumes metrics and emits logs.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateMetricsToLogsConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Logs) (MetricsConnector, error)
+
+	// CreateLogsToTracesConnector creates a connector that consumes logs and emits traces.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateLogsToTracesConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Traces) (LogsConnector, error)
+
+	// CreateLogsToMetricsConnector creates a connector that consumes logs and emits metrics.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateLogsToMetricsConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Metrics) (LogsConnector, error)
+
+	// CreateLogsToLogsConnector creates a connector that consumes logs and emits logs.
+	// If the connector does not support this combination of data types or if the config is
+	// not valid, an error will be returned instead.
+	CreateLogsToLogsConnector(ctx context.Context, set ConnectorCreateSettings,
+		cfg config.Connector, nextConsumer consumer.Logs) (LogsConnector, error)
+}
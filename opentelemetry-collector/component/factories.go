@@ -32,6 +32,9 @@ type Factories struct {
 	// Exporters maps exporter type names in the config to the respective factory.
 	Exporters map[config.Type]ExporterFactory
 
+	// Connectors maps connector type names in the config to the respective factory.
+	Connectors map[config.Type]ConnectorFactory
+
 	// Extensions maps extension type names in the config to the respective factory.
 	Extensions map[config.Type]ExtensionFactory
 }
@@ -78,6 +81,20 @@ func MakeExporterFactoryMap(factories ...ExporterFactory) (map[config.Type]Expor
 	return fMap, nil
 }
 
+// MakeConnectorFactoryMap takes a list of connector factories and returns a map
+// with factory type as keys. It returns a non-nil error when more than one factories
+// have the same type.
+func MakeConnectorFactoryMap(factories ...ConnectorFactory) (map[config.Type]ConnectorFactory, error) {
+	fMap := map[config.Type]ConnectorFactory{}
+	for _, f := range factories {
+		if _, ok := fMap[f.Type()]; ok {
+			return fMap, fmt.Errorf("duplicate connector factory %q", f.Type())
+		}
+		fMap[f.Type()] = f
+	}
+	return fMap, nil
+}
+
 // MakeExtensionFactoryMap takes a list of extension factories and returns a map
 // with factory type as keys. It returns a non-nil error when more than one factories
 // have the same type.
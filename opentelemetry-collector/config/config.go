@@ -17,6 +17,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"go.opentelemetry.io/collector/config/configparser"
 )
@@ -32,6 +33,7 @@ type Config struct {
 	Receivers
 	Exporters
 	Processors
+	Connectors
 	Extensions
 	Service
 }
@@ -77,6 +79,21 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	// Validate the connector configuration.
+	for conn, connCfg := range cfg.Connectors {
+		if err := connCfg.Validate(); err != nil {
+			return fmt.Errorf("connector \"%s\" has invalid configuration: %w", conn, err)
+		}
+		// A connector is referenced in the pipelines in the same places as receivers and
+		// exporters, so its ID must not be ambiguous.
+		if cfg.Receivers[conn] != nil {
+			return fmt.Errorf("connector %q has the same ID as a receiver", conn)
+		}
+		if cfg.Exporters[conn] != nil {
+			return fmt.Errorf("connector %q has the same ID as an exporter", conn)
+		}
+	}
+
 	// Validate the extension configuration.
 	for ext, extCfg := range cfg.Extensions {
 		if err := extCfg.Validate(); err != nil {
@@ -91,7 +108,12 @@ func (cfg *Config) Validate() error {
 
 	// Check that all pipelines have at least one receiver and one exporter, and they reference
 	// only configured components.
-	return cfg.validateServicePipelines()
+	if err := cfg.validateServicePipelines(); err != nil {
+		return err
+	}
+
+	// Check that all connectors link pipelines together without creating cycles.
+	return cfg.validateServiceConnectors()
 }
 
 func (cfg *Config) validateServiceExtensions() error {
@@ -121,8 +143,9 @@ func (cfg *Config) validateServicePipelines() error {
 
 		// Validate pipeline receiver name references.
 		for _, ref := range pipeline.Receivers {
-			// Check that the name referenced in the pipeline's receivers exists in the top-level receivers.
-			if cfg.Receivers[ref] == nil {
+			// Check that the name referenced in the pipeline's receivers exists in the top-level receivers
+			// or connectors.
+			if cfg.Receivers[ref] == nil && cfg.Connectors[ref] == nil {
 				return fmt.Errorf("pipeline %q references receiver %q which does not exist", pipeline.Name, ref)
 			}
 		}
@@ -142,8 +165,9 @@ func (cfg *Config) validateServicePipelines() error {
 
 		// Validate pipeline exporter name references.
 		for _, ref := range pipeline.Exporters {
-			// Check that the name referenced in the pipeline's Exporters exists in the top-level Exporters.
-			if cfg.Exporters[ref] == nil {
+			// Check that the name referenced in the pipeline's Exporters exists in the top-level Exporters
+			// or Connectors.
+			if cfg.Exporters[ref] == nil && cfg.Connectors[ref] == nil {
 				return fmt.Errorf("pipeline %q references exporter %q which does not exist", pipeline.Name, ref)
 			}
 		}
@@ -151,6 +175,83 @@ func (cfg *Config) validateServicePipelines() error {
 	return nil
 }
 
+func (cfg *Config) validateServiceConnectors() error {
+	if len(cfg.Connectors) == 0 {
+		return nil
+	}
+
+	// For every connector find the pipelines that export to it and the pipelines that receive from it.
+	exportedBy := make(map[ComponentID][]string)
+	receivedBy := make(map[ComponentID][]string)
+	for _, pipeline := range cfg.Service.Pipelines {
+		for _, ref := range pipeline.Exporters {
+			if cfg.Connectors[ref] != nil {
+				exportedBy[ref] = append(exportedBy[ref], pipeline.Name)
+			}
+		}
+		for _, ref := range pipeline.Receivers {
+			if cfg.Connectors[ref] != nil {
+				receivedBy[ref] = append(receivedBy[ref], pipeline.Name)
+			}
+		}
+	}
+
+	// Check that every connector used in the pipelines is used both as an exporter and as a receiver.
+	for conn := range cfg.Connectors {
+		if len(exportedBy[conn]) == 0 && len(receivedBy[conn]) == 0 {
+			continue
+		}
+		if len(exportedBy[conn]) == 0 {
+			return fmt.Errorf("connector %q is used as a receiver but not as an exporter in any pipeline", conn)
+		}
+		if len(receivedBy[conn]) == 0 {
+			return fmt.Errorf("connector %q is used as an exporter but not as a receiver in any pipeline", conn)
+		}
+	}
+
+	// Build the graph of pipelines linked by connectors and check that it has no cycles.
+	next := make(map[string][]string)
+	for conn, from := range exportedBy {
+		for _, f := range from {
+			next[f] = append(next[f], receivedBy[conn]...)
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int)
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("pipeline %q is part of a cycle created by connectors", name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, n := range next[name] {
+			if err := visit(n); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Service.Pipelines))
+	for name := range cfg.Service.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Service defines the configurable components of the service.
 type Service struct {
 	// Extensions are the ordered list of extensions configured for the service.
@@ -26,6 +26,7 @@ var errInvalidRecvConfig = errors.New("invalid receiver config")
 var errInvalidExpConfig = errors.New("invalid exporter config")
 var errInvalidProcConfig = errors.New("invalid processor config")
 var errInvalidExtConfig = errors.New("invalid extension config")
+var errInvalidConnConfig = errors.New("invalid connector config")
 
 type nopRecvConfig struct {
 	ReceiverSettings
@@ -60,6 +61,17 @@ func (nc *nopProcConfig) Validate() error {
 	return nil
 }
 
+type nopConnConfig struct {
+	ConnectorSettings
+}
+
+func (nc *nopConnConfig) Validate() error {
+	if nc.ID().Type() != "conn" {
+		return errInvalidConnConfig
+	}
+	return nil
+}
+
 type nopExtConfig struct {
 	ExtensionSettings
 }
@@ -212,6 +224,75 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expected: fmt.Errorf(`extension "nop" has invalid configuration: %w`, errInvalidExtConfig),
 		},
+		{
+			name:     "valid-connector",
+			cfgFn:    generateConfigWithConnector,
+			expected: nil,
+		},
+		{
+			name: "invalid-connector-config",
+			cfgFn: func() *Config {
+				cfg := generateConfigWithConnector()
+				cfg.Connectors[NewID("conn")] = &nopConnConfig{
+					ConnectorSettings: NewConnectorSettings(NewID("invalid_conn_type")),
+				}
+				return cfg
+			},
+			expected: fmt.Errorf(`connector "conn" has invalid configuration: %w`, errInvalidConnConfig),
+		},
+		{
+			name: "connector-same-id-as-receiver",
+			cfgFn: func() *Config {
+				cfg := generateConfigWithConnector()
+				cfg.Receivers[NewID("conn")] = &nopRecvConfig{
+					ReceiverSettings: NewReceiverSettings(NewID("nop")),
+				}
+				return cfg
+			},
+			expected: errors.New(`connector "conn" has the same ID as a receiver`),
+		},
+		{
+			name: "connector-same-id-as-exporter",
+			cfgFn: func() *Config {
+				cfg := generateConfigWithConnector()
+				cfg.Exporters[NewID("conn")] = &nopExpConfig{
+					ExporterSettings: NewExporterSettings(NewID("nop")),
+				}
+				return cfg
+			},
+			expected: errors.New(`connector "conn" has the same ID as an exporter`),
+		},
+		{
+			name: "connector-not-used-as-receiver",
+			cfgFn: func() *Config {
+				cfg := generateConfigWithConnector()
+				cfg.Service.Pipelines["metrics"].Receivers = []ComponentID{NewID("nop")}
+				return cfg
+			},
+			expected: errors.New(`connector "conn" is used as an exporter but not as a receiver in any pipeline`),
+		},
+		{
+			name: "connector-not-used-as-exporter",
+			cfgFn: func() *Config {
+				cfg := generateConfigWithConnector()
+				cfg.Service.Pipelines["traces"].Exporters = []ComponentID{NewID("nop")}
+				return cfg
+			},
+			expected: errors.New(`connector "conn" is used as a receiver but not as an exporter in any pipeline`),
+		},
+		{
+			name: "connector-cycle",
+			cfgFn: func() *Config {
+				cfg := generateConfigWithConnector()
+				cfg.Connectors[NewIDWithName("conn", "back")] = &nopConnConfig{
+					ConnectorSettings: NewConnectorSettings(NewIDWithName("conn", "back")),
+				}
+				cfg.Service.Pipelines["metrics"].Exporters = append(cfg.Service.Pipelines["metrics"].Exporters, NewIDWithName("conn", "back"))
+				cfg.Service.Pipelines["traces"].Receivers = append(cfg.Service.Pipelines["traces"].Receivers, NewIDWithName("conn", "back"))
+				return cfg
+			},
+			expected: errors.New(`pipeline "metrics" is part of a cycle created by connectors`),
+		},
 	}
 
 	for _, test := range testCases {
@@ -258,3 +339,22 @@ func generateConfig() *Config {
 		},
 	}
 }
+
+// generateConfigWithConnector returns a config where the "traces" pipeline exports to the
+// "metrics" pipeline through a connector.
+func generateConfigWithConnector() *Config {
+	cfg := generateConfig()
+	cfg.Connectors = map[ComponentID]Connector{
+		NewID("conn"): &nopConnConfig{
+			ConnectorSettings: NewConnectorSettings(NewID("conn")),
+		},
+	}
+	cfg.Service.Pipelines["traces"].Exporters = append(cfg.Service.Pipelines["traces"].Exporters, NewID("conn"))
+	cfg.Service.Pipelines["metrics"] = &Pipeline{
+		Name:      "metrics",
+		InputType: MetricsDataType,
+		Receivers: []ComponentID{NewID("conn")},
+		Exporters: []ComponentID{NewID("nop")},
+	}
+	return cfg
+}
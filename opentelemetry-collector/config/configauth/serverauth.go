@@ -43,7 +43,8 @@ type ServerAuthenticator interface {
 	// The resulting context should contain the authentication data, such as the principal/username, group membership (if available), and the raw
 	// authentication data (if possible). This will allow other components in the pipeline to make decisions based on that data, such as routing based
 	// on tenancy as determined by the group membership, or passing through the authentication data to the next collector/backend.
-	// The context keys to be used are not defined yet.
+	// The principal should be stored using client.NewContextWithPrincipal, or be returned by PrincipalProvider.Principal,
+	// so that receivers can expose it via client.Client.
 	Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error)
 
 	// GRPCUnaryServerInterceptor is a helper method to provide a gRPC-compatible UnaryServerInterceptor, typically calling the authenticator's Authenticate method.
@@ -61,6 +62,13 @@ type ServerAuthenticator interface {
 	GRPCStreamServerInterceptor(srv interface{}, stream grpc.ServerStream, srvInfo *grpc.StreamServerInfo, handler grpc.StreamHandler) error
 }
 
+// PrincipalProvider is implemented by the ServerAuthenticators telling the principal of the authenticated requests,
+// as an alternative to storing it with client.NewContextWithPrincipal.
+type PrincipalProvider interface {
+	// Principal returns the principal stored by Authenticate in the resulting context, if any.
+	Principal(ctx context.Context) (string, bool)
+}
+
 // AuthenticateFunc defines the signature for the function responsible for performing the authentication based on the given headers map.
 // See ServerAuthenticator.Authenticate.
 type AuthenticateFunc func(ctx context.Context, headers map[string][]string) (context.Context, error)
@@ -100,11 +108,20 @@ func DefaultGRPCStreamServerInterceptor(srv interface{}, stream grpc.ServerStrea
 		return errMetadataNotFound
 	}
 
-	// TODO: propagate the context down the stream
-	_, err := authenticate(ctx, headers)
+	ctx, err := authenticate(ctx, headers)
 	if err != nil {
 		return err
 	}
 
-	return handler(srv, stream)
+	return handler(srv, &authServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// authServerStream wraps a grpc.ServerStream, overriding its context with the one resulting from the authentication.
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
 }
@@ -22,6 +22,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/client"
 )
 
 func TestDefaultUnaryInterceptorAuthSucceeded(t *testing.T) {
@@ -116,6 +118,29 @@ func TestDefaultStreamInterceptorAuthSucceeded(t *testing.T) {
 	assert.True(t, handlerCalled)
 }
 
+func TestDefaultStreamInterceptorPropagatesContext(t *testing.T) {
+	// prepare
+	authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+		return client.NewContextWithPrincipal(ctx, "user-1"), nil
+	}
+	var principal string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		principal, _ = client.PrincipalFromContext(stream.Context())
+		return nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data"))
+	streamServer := &mockServerStream{
+		ctx: ctx,
+	}
+
+	// test
+	err := DefaultGRPCStreamServerInterceptor(nil, streamServer, &grpc.StreamServerInfo{}, handler, authFunc)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", principal)
+}
+
 func TestDefaultStreamInterceptorAuthFailure(t *testing.T) {
 	// prepare
 	authCalled := false
@@ -0,0 +1,25 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configcompression
+
+// Compression types supported by the confighttp and configgrpc settings.
+// Values are case-insensitive when used in the configuration.
+const (
+	Gzip    = "gzip"
+	Zlib    = "zlib"
+	Deflate = "deflate"
+	Snappy  = "snappy"
+	Zstd    = "zstd"
+)
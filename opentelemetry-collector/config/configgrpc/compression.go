@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"io"
+
+	"google.golang.org/grpc/encoding"
+
+	"go.opentelemetry.io/collector/internal/middleware"
+)
+
+func init() {
+	// gzip is registered by the grpc/encoding/gzip package, the remaining
+	// compression types are registered here so that both clients and servers
+	// built from these settings support them.
+	encoding.RegisterCompressor(&compressor{name: CompressionDeflate})
+	encoding.RegisterCompressor(&compressor{name: CompressionSnappy})
+	encoding.RegisterCompressor(&compressor{name: CompressionZstd})
+}
+
+// compressor implements encoding.Compressor on top of the shared compression registry.
+type compressor struct {
+	name string
+}
+
+var _ encoding.Compressor = (*compressor)(nil)
+
+func (c *compressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return middleware.NewCompressionWriter(c.name, w)
+}
+
+func (c *compressor) Decompress(r io.Reader) (io.Reader, error) {
+	return middleware.NewDecompressionReader(c.name, r)
+}
+
+func (c *compressor) Name() string {
+	return c.name
+}
@@ -15,6 +15,7 @@
 package configgrpc
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
@@ -29,9 +30,11 @@ import (
 	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/keepalive"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
 )
@@ -39,13 +42,21 @@ import (
 // Compression gRPC keys for supported compression types within collector.
 const (
 	CompressionUnsupported = ""
-	CompressionGzip        = "gzip"
+	CompressionGzip        = configcompression.Gzip
+	CompressionZlib        = configcompression.Zlib
+	CompressionDeflate     = configcompression.Deflate
+	CompressionSnappy      = configcompression.Snappy
+	CompressionZstd        = configcompression.Zstd
 )
 
 var (
 	// Map of opentelemetry compression types to grpc registered compression types.
 	gRPCCompressionKeyMap = map[string]string{
-		CompressionGzip: gzip.Name,
+		CompressionGzip:    gzip.Name,
+		CompressionZlib:    CompressionDeflate,
+		CompressionDeflate: CompressionDeflate,
+		CompressionSnappy:  CompressionSnappy,
+		CompressionZstd:    CompressionZstd,
 	}
 )
 
@@ -69,7 +80,7 @@ type GRPCClientSettings struct {
 	Endpoint string `mapstructure:"endpoint"`
 
 	// The compression key for supported compression types within
-	// collector. Currently the only supported mode is `gzip`.
+	// collector. See configcompression for the supported types.
 	Compression string `mapstructure:"compression"`
 
 	// TLSSetting struct exposes TLS client configuration.
@@ -156,6 +167,10 @@ type GRPCServerSettings struct {
 
 	// Auth for this receiver
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// MetadataKeys lists the incoming gRPC metadata keys to be recorded in the client.Client
+	// associated with each request, making them available to the components in the pipeline.
+	MetadataKeys []string `mapstructure:"metadata_keys,omitempty"`
 }
 
 // SanitizedEndpoint strips the prefix of either http:// or https:// from configgrpc.GRPCClientSettings.Endpoint.
@@ -170,6 +185,17 @@ func (gcs *GRPCClientSettings) SanitizedEndpoint() string {
 	}
 }
 
+// endpointHost returns the host of a gRPC target, without the resolver scheme and the port.
+func endpointHost(endpoint string) string {
+	if i := strings.Index(endpoint, ":///"); i >= 0 {
+		endpoint = endpoint[i+len(":///"):]
+	}
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return endpoint
+}
+
 func (gcs *GRPCClientSettings) isSchemeHTTP() bool {
 	return strings.HasPrefix(gcs.Endpoint, "http://")
 }
@@ -189,7 +215,12 @@ func (gcs *GRPCClientSettings) ToDialOptions(ext map[config.ComponentID]componen
 		}
 	}
 
-	tlsCfg, err := gcs.TLSSetting.LoadTLSConfig()
+	tlsSetting := gcs.TLSSetting
+	if tlsSetting.ReloadInterval > 0 && tlsSetting.ServerName == "" {
+		// The server certificate is verified against the configured server name when reloaded.
+		tlsSetting.ServerName = endpointHost(gcs.SanitizedEndpoint())
+	}
+	tlsCfg, err := tlsSetting.LoadTLSConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -327,6 +358,7 @@ func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]compone
 
 	uInterceptors := []grpc.UnaryServerInterceptor{}
 	sInterceptors := []grpc.StreamServerInterceptor{}
+	clientInfo := &clientInfoInterceptor{metadataKeys: gss.MetadataKeys}
 
 	if gss.Auth != nil {
 		componentID, cperr := config.NewIDFromString(gss.Auth.AuthenticatorName)
@@ -341,8 +373,13 @@ func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]compone
 
 		uInterceptors = append(uInterceptors, authenticator.GRPCUnaryServerInterceptor)
 		sInterceptors = append(sInterceptors, authenticator.GRPCStreamServerInterceptor)
+		clientInfo.principals, _ = authenticator.(configauth.PrincipalProvider)
 	}
 
+	// Record the client information after authentication, so that the principal is available.
+	uInterceptors = append(uInterceptors, clientInfo.unary)
+	sInterceptors = append(sInterceptors, clientInfo.stream)
+
 	// Enable OpenTelemetry observability plugin.
 	// TODO: Pass construct settings to have access to Tracer.
 	uInterceptors = append(uInterceptors, otelgrpc.UnaryServerInterceptor(
@@ -359,6 +396,43 @@ func (gss *GRPCServerSettings) ToServerOption(ext map[config.ComponentID]compone
 	return opts, nil
 }
 
+// clientInfoInterceptor stores the client.Client of the requests in their context.
+type clientInfoInterceptor struct {
+	metadataKeys []string
+	// principals is the authenticator of the requests, when it tells their principal.
+	principals configauth.PrincipalProvider
+}
+
+func (ci *clientInfoInterceptor) unary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(ci.contextWithClient(ctx), req)
+}
+
+func (ci *clientInfoInterceptor) stream(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &clientInfoServerStream{ServerStream: stream, ctx: ci.contextWithClient(stream.Context())})
+}
+
+func (ci *clientInfoInterceptor) contextWithClient(ctx context.Context) context.Context {
+	if ci.principals != nil {
+		if principal, ok := ci.principals.Principal(ctx); ok {
+			ctx = client.NewContextWithPrincipal(ctx, principal)
+		}
+	}
+	if c, ok := client.FromGRPC(ctx, ci.metadataKeys...); ok {
+		return client.NewContext(ctx, c)
+	}
+	return ctx
+}
+
+// clientInfoServerStream wraps a grpc.ServerStream, overriding its context with one carrying the client.Client.
+type clientInfoServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clientInfoServerStream) Context() context.Context {
+	return s.ctx
+}
+
 // GetGRPCCompressionKey returns the grpc registered compression key if the
 // passed in compression key is supported, and CompressionUnsupported otherwise.
 func GetGRPCCompressionKey(compressionType string) string {
@@ -16,6 +16,7 @@ package configgrpc
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"os"
 	"path"
@@ -26,12 +27,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/model/otlpgrpc"
 	"go.opentelemetry.io/collector/model/pdata"
 )
@@ -254,6 +258,24 @@ func TestUseSecure(t *testing.T) {
 	assert.Len(t, dialOpts, 3)
 }
 
+func TestEndpointHost(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{endpoint: "localhost:4317", want: "localhost"},
+		{endpoint: "127.0.0.1:4317", want: "127.0.0.1"},
+		{endpoint: "[::1]:4317", want: "::1"},
+		{endpoint: "dns:///collector.example.com:4317", want: "collector.example.com"},
+		{endpoint: "collector.example.com", want: "collector.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			assert.Equal(t, tt.want, endpointHost(tt.endpoint))
+		})
+	}
+}
+
 func TestGRPCServerSettingsError(t *testing.T) {
 	tests := []struct {
 		settings GRPCServerSettings
@@ -339,6 +361,53 @@ func TestGetGRPCCompressionKey(t *testing.T) {
 	if GetGRPCCompressionKey("badType") != CompressionUnsupported {
 		t.Error("badType is not supported but was returned as supported")
 	}
+
+	assert.Equal(t, CompressionDeflate, GetGRPCCompressionKey("zlib"))
+	assert.Equal(t, CompressionDeflate, GetGRPCCompressionKey("deflate"))
+	assert.Equal(t, CompressionSnappy, GetGRPCCompressionKey("snappy"))
+	assert.Equal(t, CompressionZstd, GetGRPCCompressionKey("ZSTD"))
+}
+
+func TestGRPCCompression(t *testing.T) {
+	for _, compression := range []string{CompressionGzip, CompressionZlib, CompressionDeflate, CompressionSnappy, CompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			gss := &GRPCServerSettings{
+				NetAddr: confignet.NetAddr{
+					Endpoint:  "localhost:0",
+					Transport: "tcp",
+				},
+			}
+			ln, err := gss.ToListener()
+			require.NoError(t, err)
+			opts, err := gss.ToServerOption(map[config.ComponentID]component.Extension{})
+			require.NoError(t, err)
+			s := grpc.NewServer(opts...)
+			otlpgrpc.RegisterTracesServer(s, &grpcTraceServer{})
+
+			go func() {
+				_ = s.Serve(ln)
+			}()
+			defer s.Stop()
+
+			gcs := &GRPCClientSettings{
+				Endpoint:    ln.Addr().String(),
+				Compression: compression,
+				TLSSetting: configtls.TLSClientSetting{
+					Insecure: true,
+				},
+			}
+			clientOpts, err := gcs.ToDialOptions(map[config.ComponentID]component.Extension{})
+			require.NoError(t, err)
+			grpcClientConn, err := grpc.Dial(gcs.Endpoint, clientOpts...)
+			require.NoError(t, err)
+			defer grpcClientConn.Close()
+
+			ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancelFunc()
+			_, err = otlpgrpc.NewTracesClient(grpcClientConn).Export(ctx, testdata.GenerateTracesManySpansSameResource(100), grpc.WaitForReady(true))
+			assert.NoError(t, err)
+		})
+	}
 }
 
 func TestHttpReception(t *testing.T) {
@@ -530,9 +599,122 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 	s.Stop()
 }
 
-type grpcTraceServer struct{}
+func TestClientInfoMetadataKeys(t *testing.T) {
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  "localhost:0",
+			Transport: "tcp",
+		},
+		MetadataKeys: []string{"X-Scope-OrgID"},
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	opts, err := gss.ToServerOption(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	s := grpc.NewServer(opts...)
+	srv := &grpcTraceServer{}
+	otlpgrpc.RegisterTracesServer(s, srv)
+
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer s.Stop()
+
+	gcs := &GRPCClientSettings{
+		Endpoint: ln.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	clientOpts, err := gcs.ToDialOptions(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	grpcClientConn, err := grpc.Dial(gcs.Endpoint, clientOpts...)
+	require.NoError(t, err)
+	defer grpcClientConn.Close()
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+	_, err = otlpgrpc.NewTracesClient(grpcClientConn).Export(metadata.AppendToOutgoingContext(ctx, "x-scope-orgid", "acme", "other", "value"), pdata.NewTraces(), grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	require.NotNil(t, srv.client)
+	assert.Equal(t, "127.0.0.1", srv.client.IP)
+	assert.Equal(t, map[string][]string{"x-scope-orgid": {"acme"}}, srv.client.Metadata)
+}
+
+func TestClientInfoPrincipal(t *testing.T) {
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  "localhost:0",
+			Transport: "tcp",
+		},
+		Auth: &configauth.Authentication{AuthenticatorName: "principal"},
+	}
+	ln, err := gss.ToListener()
+	require.NoError(t, err)
+	opts, err := gss.ToServerOption(map[config.ComponentID]component.Extension{
+		config.NewID("principal"): &principalAuthenticator{},
+	})
+	require.NoError(t, err)
+	s := grpc.NewServer(opts...)
+	srv := &grpcTraceServer{}
+	otlpgrpc.RegisterTracesServer(s, srv)
+
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer s.Stop()
+
+	gcs := &GRPCClientSettings{
+		Endpoint: ln.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	clientOpts, err := gcs.ToDialOptions(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	grpcClientConn, err := grpc.Dial(gcs.Endpoint, clientOpts...)
+	require.NoError(t, err)
+	defer grpcClientConn.Close()
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+	_, err = otlpgrpc.NewTracesClient(grpcClientConn).Export(metadata.AppendToOutgoingContext(ctx, "user", "alice"), pdata.NewTraces(), grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	require.NotNil(t, srv.client)
+	assert.Equal(t, "alice", srv.client.Principal)
+}
+
+// principalAuthenticator authenticates the requests as the user named by their "user" metadata.
+type principalAuthenticator struct {
+	configauth.MockAuthenticator
+}
+
+type principalCtxKey struct{}
+
+func (pa *principalAuthenticator) Authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	if len(headers["user"]) == 0 {
+		return ctx, errors.New("no user")
+	}
+	return context.WithValue(ctx, principalCtxKey{}, headers["user"][0]), nil
+}
+
+func (pa *principalAuthenticator) GRPCUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return configauth.DefaultGRPCUnaryServerInterceptor(ctx, req, info, handler, pa.Authenticate)
+}
+
+func (pa *principalAuthenticator) Principal(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalCtxKey{}).(string)
+	return principal, ok
+}
+
+type grpcTraceServer struct {
+	client *client.Client
+}
 
-func (gts *grpcTraceServer) Export(context.Context, pdata.Traces) (otlpgrpc.TracesResponse, error) {
+func (gts *grpcTraceServer) Export(ctx context.Context, _ pdata.Traces) (otlpgrpc.TracesResponse, error) {
+	gts.client, _ = client.FromContext(ctx)
 	return otlpgrpc.NewTracesResponse(), nil
 }
 
@@ -19,12 +19,14 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/rs/cors"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configauth"
@@ -58,11 +60,22 @@ type HTTPClientSettings struct {
 
 	// Auth configuration for outgoing HTTP calls.
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// The compression key for supported compression types within collector.
+	// See configcompression for the supported types. No compression is used when empty.
+	Compression string `mapstructure:"compression"`
 }
 
 // ToClient creates an HTTP client.
 func (hcs *HTTPClientSettings) ToClient(ext map[config.ComponentID]component.Extension) (*http.Client, error) {
-	tlsCfg, err := hcs.TLSSetting.LoadTLSConfig()
+	tlsSetting := hcs.TLSSetting
+	if tlsSetting.ReloadInterval > 0 && tlsSetting.ServerName == "" {
+		// The server certificate is verified against the configured server name when reloaded.
+		if u, err := url.Parse(hcs.Endpoint); err == nil {
+			tlsSetting.ServerName = u.Hostname()
+		}
+	}
+	tlsCfg, err := tlsSetting.LoadTLSConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -106,6 +119,13 @@ func (hcs *HTTPClientSettings) ToClient(ext map[config.ComponentID]component.Ext
 		}
 	}
 
+	if hcs.Compression != "" {
+		clientTransport, err = middleware.NewCompressRoundTripper(clientTransport, hcs.Compression)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if hcs.CustomRoundTripper != nil {
 		clientTransport, err = hcs.CustomRoundTripper(clientTransport)
 		if err != nil {
@@ -153,6 +173,10 @@ type HTTPServerSettings struct {
 	// CORS needs to be enabled first by providing a non-empty list in CorsOrigins
 	// A wildcard (*) can be used to match any header.
 	CorsHeaders []string `mapstructure:"cors_allowed_headers"`
+
+	// MetadataKeys lists the request headers to be recorded in the client.Client
+	// associated with each request, making them available to the components in the pipeline.
+	MetadataKeys []string `mapstructure:"metadata_keys,omitempty"`
 }
 
 // ToListener creates a net.Listener.
@@ -198,6 +222,11 @@ func (hss *HTTPServerSettings) ToServer(handler http.Handler, opts ...ToServerOp
 		o(serverOpts)
 	}
 
+	handler = &clientInfoHandler{
+		next:         handler,
+		metadataKeys: hss.MetadataKeys,
+	}
+
 	handler = middleware.HTTPContentDecompressor(
 		handler,
 		middleware.WithErrorHandler(serverOpts.errorHandler),
@@ -229,3 +258,16 @@ func (hss *HTTPServerSettings) ToServer(handler http.Handler, opts ...ToServerOp
 		Handler: handler,
 	}
 }
+
+// clientInfoHandler is an http.Handler that records the client.Client in the request context.
+type clientInfoHandler struct {
+	next         http.Handler
+	metadataKeys []string
+}
+
+func (h *clientInfoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c, ok := client.FromHTTP(r, h.metadataKeys...); ok {
+		r = r.WithContext(client.NewContext(r.Context(), c))
+	}
+	h.next.ServeHTTP(w, r)
+}
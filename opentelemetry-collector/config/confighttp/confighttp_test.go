@@ -15,6 +15,7 @@
 package confighttp
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -28,6 +29,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configauth"
@@ -95,6 +97,23 @@ func TestAllHTTPClientSettings(t *testing.T) {
 	}
 }
 
+func TestHTTPClientSettingsReloadServerName(t *testing.T) {
+	hcs := HTTPClientSettings{
+		Endpoint: "https://127.0.0.1:4318",
+		TLSSetting: configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{
+				CAFile:         path.Join("testdata", "ca.crt"),
+				ReloadInterval: time.Minute,
+			},
+		},
+	}
+	client, err := hcs.ToClient(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+	transport := client.Transport.(*http.Transport)
+	// The server certificate is verified against the endpoint host when no server name is configured.
+	assert.Equal(t, "127.0.0.1", transport.TLSClientConfig.ServerName)
+}
+
 func TestHTTPClientSettingsError(t *testing.T) {
 	tests := []struct {
 		settings HTTPClientSettings
@@ -510,6 +529,68 @@ func TestHttpCorsInvalidSettings(t *testing.T) {
 	require.NoError(t, s.Close())
 }
 
+func TestHTTPServerClientInfo(t *testing.T) {
+	hss := &HTTPServerSettings{
+		Endpoint:     "localhost:0",
+		MetadataKeys: []string{"X-Scope-OrgID"},
+	}
+
+	var got *client.Client
+	s := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = client.FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest("POST", "/v1/traces", nil)
+	req.RemoteAddr = "192.168.1.2:1234"
+	req.Header.Set("X-Scope-OrgID", "acme")
+	req.Header.Set("Other", "value")
+	s.Handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotNil(t, got)
+	assert.Equal(t, "192.168.1.2", got.IP)
+	assert.Equal(t, map[string][]string{"x-scope-orgid": {"acme"}}, got.Metadata)
+}
+
+func TestHTTPCompression(t *testing.T) {
+	testBody := []byte("uncompressed_text")
+	for _, compression := range []string{"", "gzip", "zlib", "deflate", "snappy", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			hss := &HTTPServerSettings{Endpoint: "localhost:0"}
+			s := hss.ToServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, testBody, body)
+				w.WriteHeader(http.StatusOK)
+			}))
+			server := httptest.NewServer(s.Handler)
+			defer server.Close()
+
+			hcs := &HTTPClientSettings{
+				Endpoint:    server.URL,
+				Compression: compression,
+			}
+			c, err := hcs.ToClient(map[config.ComponentID]component.Extension{})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("POST", hcs.Endpoint, bytes.NewReader(testBody))
+			require.NoError(t, err)
+			resp, err := c.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestHTTPClientUnsupportedCompression(t *testing.T) {
+	hcs := &HTTPClientSettings{
+		Endpoint:    "localhost:1234",
+		Compression: "lz4",
+	}
+	_, err := hcs.ToClient(map[config.ComponentID]component.Extension{})
+	assert.EqualError(t, err, `unsupported compression type "lz4"`)
+}
+
 func verifyCorsResp(t *testing.T, url string, origin string, extraHeader bool, wantStatus int, wantAllowed bool) {
 	req, err := http.NewRequest("OPTIONS", url, nil)
 	require.NoError(t, err, "Error creating trace OPTIONS request: %v", err)
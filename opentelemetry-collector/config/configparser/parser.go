@@ -171,6 +171,7 @@ func decoderConfig(result interface{}) *mapstructure.DecoderConfig {
 			expandNilStructPointers(),
 			mapstructure.StringToTimeDurationHookFunc(),
 			mapstructure.StringToSliceHookFunc(","),
+			mapstructure.TextUnmarshallerHookFunc(),
 		),
 	}
 }
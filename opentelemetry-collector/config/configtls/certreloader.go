@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"time"
+)
+
+// timeNow is used to determine when the certificates are due for reload, it is replaced in tests.
+var timeNow = time.Now
+
+// loadedCerts holds the TLS material read from the configured files.
+type loadedCerts struct {
+	cert         *tls.Certificate
+	caPool       *x509.CertPool
+	clientCAPool *x509.CertPool
+}
+
+// certReloader holds the TLS material and reloads it from the files once the reload interval
+// has elapsed. Reloading is lazy, it happens during the first handshake that follows the
+// expiration of the interval. When reloading fails, the previously loaded material is kept.
+type certReloader struct {
+	interval time.Duration
+	load     func() (*loadedCerts, error)
+	// serverName is the name the server certificate is verified against by clients.
+	serverName string
+
+	lock       sync.Mutex
+	current    *loadedCerts
+	nextReload time.Time
+}
+
+func newCertReloader(interval time.Duration, initial *loadedCerts, load func() (*loadedCerts, error)) *certReloader {
+	return &certReloader{
+		interval:   interval,
+		load:       load,
+		current:    initial,
+		nextReload: timeNow().Add(interval),
+	}
+}
+
+// get returns the current TLS material, reloading it first if it is due.
+func (r *certReloader) get() *loadedCerts {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := timeNow()
+	if now.Before(r.nextReload) {
+		return r.current
+	}
+	r.nextReload = now.Add(r.interval)
+	if loaded, err := r.load(); err == nil {
+		r.current = loaded
+	}
+	return r.current
+}
+
+func (r *certReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if cert := r.get().cert; cert != nil {
+		return cert, nil
+	}
+	// No certificate is configured, an empty one means that none is sent to the server.
+	return &tls.Certificate{}, nil
+}
+
+// getConfigForClient returns a copy of the server tls.Config using the current certificate and client CA pool.
+func (r *certReloader) getConfigForClient(base *tls.Config) func(*tls.ClientHelloInfo) (*tls.Config, error) {
+	return func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		current := r.get()
+		cfg := base.Clone()
+		if current.cert != nil {
+			cfg.Certificates = []tls.Certificate{*current.cert}
+		}
+		cfg.ClientCAs = current.clientCAPool
+		return cfg, nil
+	}
+}
+
+// verifyConnection verifies the server certificate chain against the current CA pool and the
+// configured server name. It replaces the default verification, which can only use the CA pool
+// loaded at startup. The name sent in the SNI can't be used since it is empty for IP addresses.
+func (r *certReloader) verifyConnection(cs tls.ConnectionState) error {
+	if r.serverName == "" {
+		return errors.New("tls: no server name to verify the server certificate against")
+	}
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server did not provide a certificate")
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       r.serverName,
+		Roots:         r.get().caPool,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
@@ -0,0 +1,304 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configtls
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert creates a certificate for localhost, signed by parent or self-signed when parent is nil.
+func newTestCert(t *testing.T, serial int64, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	signerCert, signerKey := tmpl, key
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func (tc *testCert) write(t *testing.T, certFile, keyFile string) {
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tc.cert.Raw}), 0600))
+	if keyFile == "" {
+		return
+	}
+	keyDER, err := x509.MarshalECPrivateKey(tc.key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+}
+
+// advanceTime moves the clock used to schedule reloads by d.
+func advanceTime(t *testing.T, d time.Duration) {
+	prev := timeNow
+	timeNow = func() time.Time { return prev().Add(d) }
+	t.Cleanup(func() { timeNow = prev })
+}
+
+func TestServerCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certA := newTestCert(t, 1, nil)
+	certA.write(t, certFile, keyFile)
+
+	tlsSetting := TLSServerSetting{
+		TLSSetting: TLSSetting{
+			CertFile:       certFile,
+			KeyFile:        keyFile,
+			ReloadInterval: time.Minute,
+		},
+	}
+	tlsCfg, err := tlsSetting.LoadTLSConfig()
+	require.NoError(t, err)
+	require.NotNil(t, tlsCfg.GetConfigForClient)
+
+	certB := newTestCert(t, 2, nil)
+	certB.write(t, certFile, keyFile)
+
+	cfg, err := tlsCfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, certA.cert.Raw, cfg.Certificates[0].Certificate[0], "certificate reloaded before the interval elapsed")
+
+	advanceTime(t, 2*time.Minute)
+	cfg, err = tlsCfg.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, certB.cert.Raw, cfg.Certificates[0].Certificate[0])
+}
+
+func TestClientCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certA := newTestCert(t, 1, nil)
+	certA.write(t, certFile, keyFile)
+
+	tlsSetting := TLSClientSetting{
+		TLSSetting: TLSSetting{
+			CertFile:       certFile,
+			KeyFile:        keyFile,
+			ReloadInterval: time.Minute,
+		},
+	}
+	tlsCfg, err := tlsSetting.LoadTLSConfig()
+	require.NoError(t, err)
+	assert.Empty(t, tlsCfg.Certificates)
+
+	cert, err := tlsCfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, certA.cert.Raw, cert.Certificate[0])
+
+	certB := newTestCert(t, 2, nil)
+	certB.write(t, certFile, keyFile)
+	advanceTime(t, 2*time.Minute)
+	cert, err = tlsCfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, certB.cert.Raw, cert.Certificate[0])
+}
+
+func TestCertificateReloadKeepsPreviousOnError(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certA := newTestCert(t, 1, nil)
+	certA.write(t, certFile, keyFile)
+
+	tlsSetting := TLSClientSetting{
+		TLSSetting: TLSSetting{
+			CertFile:       certFile,
+			KeyFile:        keyFile,
+			ReloadInterval: time.Minute,
+		},
+	}
+	tlsCfg, err := tlsSetting.LoadTLSConfig()
+	require.NoError(t, err)
+
+	require.NoError(t, ioutil.WriteFile(certFile, []byte("not a certificate"), 0600))
+	advanceTime(t, 2*time.Minute)
+	cert, err := tlsCfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, certA.cert.Raw, cert.Certificate[0])
+}
+
+func TestClientCAReload(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	caA := newTestCert(t, 1, nil)
+	caA.write(t, caFile, "")
+	caB := newTestCert(t, 2, nil)
+	serverCert := newTestCert(t, 3, caB)
+
+	serverCfg := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.cert.Raw}, PrivateKey: serverCert.key}},
+	}
+	tlsSetting := TLSClientSetting{
+		TLSSetting: TLSSetting{
+			CAFile:         caFile,
+			ReloadInterval: time.Minute,
+		},
+		ServerName: "localhost",
+	}
+	clientCfg, err := tlsSetting.LoadTLSConfig()
+	require.NoError(t, err)
+
+	// The server certificate isn't signed by the CA loaded at startup.
+	assert.Error(t, handshake(serverCfg, clientCfg))
+
+	caB.write(t, caFile, "")
+	advanceTime(t, 2*time.Minute)
+	assert.NoError(t, handshake(serverCfg, clientCfg))
+}
+
+func TestClientCAReloadVerifiesServerName(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	ca := newTestCert(t, 1, nil)
+	ca.write(t, caFile, "")
+	// The server certificate is only valid for localhost, not for the IP address dialed.
+	serverCert := newTestCert(t, 2, ca)
+	serverCfg := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.cert.Raw}, PrivateKey: serverCert.key}},
+	}
+
+	for _, tt := range []struct {
+		name       string
+		serverName string
+		wantErr    bool
+	}{
+		{name: "matching", serverName: "localhost"},
+		{name: "mismatching IP", serverName: "127.0.0.1", wantErr: true},
+		// The IP address dialed isn't sent in the SNI, verification fails instead of skipping the name.
+		{name: "empty", wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsSetting := TLSClientSetting{
+				TLSSetting: TLSSetting{
+					CAFile:         caFile,
+					ReloadInterval: time.Minute,
+				},
+				ServerName: tt.serverName,
+			}
+			clientCfg, err := tlsSetting.LoadTLSConfig()
+			require.NoError(t, err)
+
+			if tt.wantErr {
+				assert.Error(t, handshake(serverCfg, clientCfg))
+			} else {
+				assert.NoError(t, handshake(serverCfg, clientCfg))
+			}
+		})
+	}
+}
+
+func TestServerClientCAReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCAFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "client-ca.pem")
+	newTestCert(t, 1, nil).write(t, certFile, keyFile)
+	caA := newTestCert(t, 2, nil)
+	caA.write(t, clientCAFile, "")
+	caB := newTestCert(t, 3, nil)
+	clientCert := newTestCert(t, 4, caB)
+
+	tlsSetting := TLSServerSetting{
+		TLSSetting: TLSSetting{
+			CertFile:       certFile,
+			KeyFile:        keyFile,
+			ReloadInterval: time.Minute,
+		},
+		ClientCAFile: clientCAFile,
+	}
+	serverCfg, err := tlsSetting.LoadTLSConfig()
+	require.NoError(t, err)
+	clientCfg := &tls.Config{
+		InsecureSkipVerify: true, // #nosec
+		Certificates:       []tls.Certificate{{Certificate: [][]byte{clientCert.cert.Raw}, PrivateKey: clientCert.key}},
+	}
+
+	// The client certificate isn't signed by the client CA loaded at startup.
+	assert.Error(t, handshake(serverCfg, clientCfg))
+
+	caB.write(t, clientCAFile, "")
+	advanceTime(t, 2*time.Minute)
+	assert.NoError(t, handshake(serverCfg, clientCfg))
+}
+
+// handshake performs a TLS handshake between a server and a client using the given configurations.
+func handshake(serverCfg, clientCfg *tls.Config) error {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		server := tls.Server(conn, serverCfg)
+		err = server.Handshake()
+		if err == nil {
+			// In TLS 1.3 the client certificate is verified by the server after the client
+			// completes its handshake, reading surfaces a failure on the client side.
+			_, err = server.Write([]byte{0})
+		}
+		serverErr <- err
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientCfg)
+	if err == nil {
+		// Read the byte written by the server, or the alert sent on failure.
+		_, err = conn.Read(make([]byte, 1))
+		conn.Close()
+	}
+	if sErr := <-serverErr; sErr != nil {
+		return sErr
+	}
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"time"
 )
 
 // TLSSetting exposes the common client and server TLS configurations.
@@ -44,6 +45,12 @@ type TLSSetting struct {
 	// MaxVersion sets the maximum TLS version that is acceptable.
 	// If not set, TLS 1.3 is used. (optional)
 	MaxVersion string `mapstructure:"max_version"`
+
+	// ReloadInterval specifies the duration after which the certificate, key and CA files
+	// are reloaded, allowing them to be rotated without a restart. The files are reloaded
+	// on the first handshake following the interval expiration.
+	// If not set, the files are only loaded once. (optional)
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
 }
 
 // TLSClientSetting contains TLS configurations that are specific to client
@@ -87,14 +94,43 @@ type TLSServerSetting struct {
 
 // LoadTLSConfig loads TLS certificates and returns a tls.Config.
 // This will set the RootCAs and Certificates of a tls.Config.
-func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
+func (c TLSSetting) loadTLSConfig() (*tls.Config, *loadedCerts, error) {
+	certs, err := c.loadCerts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	minTLS, err := convertVersion(c.MinVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid TLS min_version: %w", err)
+	}
+	maxTLS, err := convertVersion(c.MaxVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid TLS max_version: %w", err)
+	}
+
+	var certificates []tls.Certificate
+	if certs.cert != nil {
+		certificates = append(certificates, *certs.cert)
+	}
+
+	return &tls.Config{
+		RootCAs:      certs.caPool,
+		Certificates: certificates,
+		MinVersion:   minTLS,
+		MaxVersion:   maxTLS,
+	}, certs, nil
+}
+
+// loadCerts reads the CA, certificate and key files.
+func (c TLSSetting) loadCerts() (*loadedCerts, error) {
 	// There is no need to load the System Certs for RootCAs because
 	// if the value is nil, it will default to checking against th System Certs.
+	certs := &loadedCerts{}
 	var err error
-	var certPool *x509.CertPool
 	if len(c.CAFile) != 0 {
 		// Set up user specified truststore.
-		certPool, err = c.loadCert(c.CAFile)
+		certs.caPool, err = c.loadCert(c.CAFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load CA CertPool: %w", err)
 		}
@@ -104,31 +140,15 @@ func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
 		return nil, fmt.Errorf("for auth via TLS, either both certificate and key must be supplied, or neither")
 	}
 
-	var certificates []tls.Certificate
 	if c.CertFile != "" && c.KeyFile != "" {
 		var tlsCert tls.Certificate
 		tlsCert, err = tls.LoadX509KeyPair(filepath.Clean(c.CertFile), filepath.Clean(c.KeyFile))
 		if err != nil {
 			return nil, fmt.Errorf("failed to load TLS cert and key: %w", err)
 		}
-		certificates = append(certificates, tlsCert)
-	}
-
-	minTLS, err := convertVersion(c.MinVersion)
-	if err != nil {
-		return nil, fmt.Errorf("invalid TLS min_version: %w", err)
-	}
-	maxTLS, err := convertVersion(c.MaxVersion)
-	if err != nil {
-		return nil, fmt.Errorf("invalid TLS max_version: %w", err)
+		certs.cert = &tlsCert
 	}
-
-	return &tls.Config{
-		RootCAs:      certPool,
-		Certificates: certificates,
-		MinVersion:   minTLS,
-		MaxVersion:   maxTLS,
-	}, nil
+	return certs, nil
 }
 
 func (c TLSSetting) loadCert(caPath string) (*x509.CertPool, error) {
@@ -150,32 +170,65 @@ func (c TLSClientSetting) LoadTLSConfig() (*tls.Config, error) {
 		return nil, nil
 	}
 
-	tlsCfg, err := c.TLSSetting.loadTLSConfig()
+	tlsCfg, certs, err := c.TLSSetting.loadTLSConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
 	tlsCfg.ServerName = c.ServerName
 	tlsCfg.InsecureSkipVerify = c.InsecureSkipVerify
+
+	if c.ReloadInterval > 0 {
+		reloader := newCertReloader(c.ReloadInterval, certs, c.TLSSetting.loadCerts)
+		reloader.serverName = c.ServerName
+		tlsCfg.Certificates = nil
+		tlsCfg.GetClientCertificate = reloader.getClientCertificate
+		if c.CAFile != "" && !c.InsecureSkipVerify {
+			// The default verification only uses the CA pool loaded at startup,
+			// so it is replaced by one using the reloaded CA pool.
+			tlsCfg.InsecureSkipVerify = true
+			tlsCfg.VerifyConnection = reloader.verifyConnection
+		}
+	}
 	return tlsCfg, nil
 }
 
 // LoadTLSConfig loads the TLS configuration.
 func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
-	tlsCfg, err := c.loadTLSConfig()
+	tlsCfg, certs, err := c.loadTLSConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
 	if c.ClientCAFile != "" {
-		certPool, err := c.loadCert(c.ClientCAFile)
+		certs.clientCAPool, err = c.loadCert(c.ClientCAFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load TLS config: failed to load client CA CertPool: %w", err)
 		}
-		tlsCfg.ClientCAs = certPool
+		tlsCfg.ClientCAs = certs.clientCAPool
 		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
 	}
+
+	if c.ReloadInterval > 0 {
+		reloader := newCertReloader(c.ReloadInterval, certs, c.loadServerCerts)
+		tlsCfg.GetConfigForClient = reloader.getConfigForClient(tlsCfg.Clone())
+	}
 	return tlsCfg, nil
 }
 
+// loadServerCerts reads the CA, certificate, key and client CA files.
+func (c TLSServerSetting) loadServerCerts() (*loadedCerts, error) {
+	certs, err := c.loadCerts()
+	if err != nil {
+		return nil, err
+	}
+	if c.ClientCAFile != "" {
+		certs.clientCAPool, err = c.loadCert(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return certs, nil
+}
+
 func convertVersion(v string) (uint16, error) {
 	if v == "" {
 		return 0, nil // default
@@ -64,6 +64,9 @@ const (
 	// processorsKeyName is the configuration key name for processors section.
 	processorsKeyName = "processors"
 
+	// connectorsKeyName is the configuration key name for connectors section.
+	connectorsKeyName = "connectors"
+
 	// pipelinesKeyName is the configuration key name for pipelines section.
 	pipelinesKeyName = "pipelines"
 )
@@ -72,6 +75,7 @@ type configSettings struct {
 	Receivers  map[string]map[string]interface{} `mapstructure:"receivers"`
 	Processors map[string]map[string]interface{} `mapstructure:"processors"`
 	Exporters  map[string]map[string]interface{} `mapstructure:"exporters"`
+	Connectors map[string]map[string]interface{} `mapstructure:"connectors"`
 	Extensions map[string]map[string]interface{} `mapstructure:"extensions"`
 	Service    serviceSettings                   `mapstructure:"service"`
 }
@@ -119,7 +123,7 @@ func (*defaultUnmarshaler) Unmarshal(v *configparser.ConfigMap, factories compon
 	}
 	cfg.Extensions = extensions
 
-	// Unmarshal data components (receivers, exporters, and processors).
+	// Unmarshal data components (receivers, exporters, processors, and connectors).
 
 	receivers, err := unmarshalReceivers(rawCfg.Receivers, factories.Receivers)
 	if err != nil {
@@ -139,6 +143,12 @@ func (*defaultUnmarshaler) Unmarshal(v *configparser.ConfigMap, factories compon
 	}
 	cfg.Processors = processors
 
+	connectors, err := unmarshalConnectors(rawCfg.Connectors, factories.Connectors)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Connectors = connectors
+
 	// Unmarshal the service and its data pipelines.
 	service, err := unmarshalService(rawCfg.Service)
 	if err != nil {
@@ -375,6 +385,48 @@ func unmarshalProcessors(procs map[string]map[string]interface{}, factories map[
 	return processors, nil
 }
 
+func unmarshalConnectors(conns map[string]map[string]interface{}, factories map[config.Type]component.ConnectorFactory) (config.Connectors, error) {
+	// Prepare resulting map.
+	connectors := make(config.Connectors)
+
+	// Iterate over connectors and create a config for each.
+	for key, value := range conns {
+		componentConfig := configparser.NewParserFromStringMap(value)
+		expandEnvConfig(componentConfig)
+
+		// Decode the key into type and fullName components.
+		id, err := config.NewIDFromString(key)
+		if err != nil {
+			return nil, errorInvalidTypeAndNameKey(connectorsKeyName, key, err)
+		}
+
+		// Find connector factory based on "type" that we read from config source.
+		factory := factories[id.Type()]
+		if factory == nil {
+			return nil, errorUnknownType(connectorsKeyName, id)
+		}
+
+		// Create the default config for this connector.
+		connectorCfg := factory.CreateDefaultConfig()
+		connectorCfg.SetIDName(id.Name())
+		expandEnvLoadedConfig(connectorCfg)
+
+		// Now that the default config struct is created we can Unmarshal into it,
+		// and it will apply user-defined config on top of the default.
+		if err = unmarshal(componentConfig, connectorCfg); err != nil {
+			return nil, errorUnmarshalError(connectorsKeyName, id, err)
+		}
+
+		if connectors[id] != nil {
+			return nil, errorDuplicateName(connectorsKeyName, id)
+		}
+
+		connectors[id] = connectorCfg
+	}
+
+	return connectors, nil
+}
+
 func unmarshalPipelines(pipelinesConfig map[string]pipelineSettings) (config.Pipelines, error) {
 	// Prepare resulting map.
 	pipelines := make(config.Pipelines)
@@ -116,6 +116,51 @@ func TestDecodeConfig(t *testing.T) {
 		"Did not load pipeline config correctly")
 }
 
+func TestDecodeConfigWithConnector(t *testing.T) {
+	factories, err := testcomponents.ExampleComponents()
+	assert.NoError(t, err)
+
+	// Unmarshal the config
+	cfg, err := loadConfigFile(t, path.Join(".", "testdata", "valid-config-with-connector.yaml"), factories)
+	require.NoError(t, err, "Unable to load config")
+
+	// Verify connectors
+	assert.Equal(t, 1, len(cfg.Connectors), "Incorrect connectors count")
+
+	assert.Equal(t,
+		&testcomponents.ExampleConnectorCfg{
+			ConnectorSettings: config.NewConnectorSettings(config.NewID("exampleconnector")),
+			ExtraSetting:      "some connector string 2",
+		},
+		cfg.Connectors[config.NewID("exampleconnector")],
+		"Did not load connector config correctly")
+
+	// Verify Pipelines
+	assert.Equal(t, 2, len(cfg.Service.Pipelines), "Incorrect pipelines count")
+
+	assert.Equal(t,
+		&config.Pipeline{
+			Name:      "traces",
+			InputType: config.TracesDataType,
+			Receivers: []config.ComponentID{config.NewID("examplereceiver")},
+			Exporters: []config.ComponentID{config.NewID("exampleconnector")},
+		},
+		cfg.Service.Pipelines["traces"],
+		"Did not load pipeline config correctly")
+
+	assert.Equal(t,
+		&config.Pipeline{
+			Name:      "metrics",
+			InputType: config.MetricsDataType,
+			Receivers: []config.ComponentID{config.NewID("exampleconnector")},
+			Exporters: []config.ComponentID{config.NewID("exampleexporter")},
+		},
+		cfg.Service.Pipelines["metrics"],
+		"Did not load pipeline config correctly")
+
+	assert.NoError(t, cfg.Validate())
+}
+
 func TestSimpleConfig(t *testing.T) {
 	var testCases = []struct {
 		name string // test case name (also file name containing config yaml)
@@ -379,6 +424,7 @@ func TestDecodeConfig_Invalid(t *testing.T) {
 		{name: "invalid-receiver-type", expected: errInvalidTypeAndNameKey},
 		{name: "invalid-exporter-type", expected: errInvalidTypeAndNameKey},
 		{name: "invalid-processor-type", expected: errInvalidTypeAndNameKey},
+		{name: "invalid-connector-type", expected: errInvalidTypeAndNameKey},
 		{name: "invalid-pipeline-type", expected: errInvalidTypeAndNameKey},
 
 		{name: "invalid-extension-name-after-slash", expected: errInvalidTypeAndNameKey},
@@ -391,12 +437,14 @@ func TestDecodeConfig_Invalid(t *testing.T) {
 		{name: "unknown-receiver-type", expected: errUnknownType, expectedMessage: "receivers"},
 		{name: "unknown-exporter-type", expected: errUnknownType, expectedMessage: "exporters"},
 		{name: "unknown-processor-type", expected: errUnknownType, expectedMessage: "processors"},
+		{name: "unknown-connector-type", expected: errUnknownType, expectedMessage: "connectors"},
 		{name: "unknown-pipeline-type", expected: errUnknownType, expectedMessage: "pipelines"},
 
 		{name: "duplicate-extension", expected: errDuplicateName, expectedMessage: "extensions"},
 		{name: "duplicate-receiver", expected: errDuplicateName, expectedMessage: "receivers"},
 		{name: "duplicate-exporter", expected: errDuplicateName, expectedMessage: "exporters"},
 		{name: "duplicate-processor", expected: errDuplicateName, expectedMessage: "processors"},
+		{name: "duplicate-connector", expected: errDuplicateName, expectedMessage: "connectors"},
 		{name: "duplicate-pipeline", expected: errDuplicateName, expectedMessage: "pipelines"},
 
 		{name: "invalid-top-level-section", expected: errUnmarshalTopLevelStructureError, expectedMessage: "top level"},
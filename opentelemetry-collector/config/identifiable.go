@@ -94,3 +94,14 @@ func (id ComponentID) String() string {
 
 	return string(id.typeVal) + typeAndNameSeparator + id.nameVal
 }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface,
+// so that a ComponentID can be referenced from another component's configuration.
+func (id *ComponentID) UnmarshalText(text []byte) error {
+	parsed, err := NewIDFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
@@ -76,3 +76,11 @@ func TestIDFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestIDUnmarshalText(t *testing.T) {
+	id := ComponentID{}
+	assert.NoError(t, id.UnmarshalText([]byte("valid_type/valid_name")))
+	assert.Equal(t, NewIDWithName("valid_type", "valid_name"), id)
+
+	assert.Error(t, id.UnmarshalText([]byte("/valid_name")))
+}
@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectorhelper
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+// FactoryOption apply changes to ConnectorOptions.
+type FactoryOption func(o *factory)
+
+// WithTracesToTraces overrides the default "error not supported" implementation for CreateTracesToTracesConnector.
+func WithTracesToTraces(createTracesToTracesConnector CreateTracesToTracesConnector) FactoryOption {
+	return func(o *factory) {
+		o.createTracesToTracesConnector = createTracesToTracesConnector
+	}
+}
+
+// WithTracesToMetrics overrides the default "error not supported" implementation for CreateTracesToMetricsConnector.
+func WithTracesToMetrics(createTracesToMetricsConnector CreateTracesToMetricsConnector) FactoryOption {
+	return func(o *factory) {
+		o.createTracesToMetricsConnector = createTracesToMetricsConnector
+	}
+}
+
+// WithTracesToLogs overrides the default "error not supported" implementation for CreateTracesToLogsConnector.
+func WithTracesToLogs(createTracesToLogsConnector CreateTracesToLogsConnector) FactoryOption {
+	return func(o *factory) {
+		o.createTracesToLogsConnector = createTracesToLogsConnector
+	}
+}
+
+// WithMetricsToTraces overrides the default "error not supported" implementation for CreateMetricsToTracesConnector.
+func WithMetricsToTraces(createMetricsToTracesConnector CreateMetricsToTracesConnector) FactoryOption {
+	return func(o *factory) {
+		o.createMetricsToTracesConnector = createMetricsToTracesConnector
+	}
+}
+
+// WithMetricsToMetrics overrides the default "error not supported" implementation for CreateMetricsToMetricsConnector.
+func WithMetricsToMetrics(createMetricsToMetricsConnector CreateMetricsToMetricsConnector) FactoryOption {
+	return func(o *factory) {
+		o.createMetricsToMetricsConnector = createMetricsToMetricsConnector
+	}
+}
+
+// WithMetricsToLogs overrides the default "error not supported" implementation for CreateMetricsToLogsConnector.
+func WithMetricsToLogs(createMetricsToLogsConnector CreateMetricsToLogsConnector) FactoryOption {
+	return func(o *factory) {
+		o.createMetricsToLogsConnector = createMetricsToLogsConnector
+	}
+}
+
+// WithLogsToTraces overrides the default "error not supported" implementation for CreateLogsToTracesConnector.
+func WithLogsToTraces(createLogsToTracesConnector CreateLogsToTracesConnector) FactoryOption {
+	return func(o *factory) {
+		o.createLogsToTracesConnector = createLogsToTracesConnector
+	}
+}
+
+// WithLogsToMetrics overrides the default "error not supported" implementation for CreateLogsToMetricsConnector.
+func WithLogsToMetrics(createLogsToMetricsConnector CreateLogsToMetricsConnector) FactoryOption {
+	return func(o *factory) {
+		o.createLogsToMetricsConnector = createLogsToMetricsConnector
+	}
+}
+
+// WithLogsToLogs overrides the default "error not supported" implementation for CreateLogsToLogsConnector.
+func WithLogsToLogs(createLogsToLogsConnector CreateLogsToLogsConnector) FactoryOption {
+	return func(o *factory) {
+		o.createLogsToLogsConnector = createLogsToLogsConnector
+	}
+}
+
+// CreateDefaultConfig is the equivalent of component.ConnectorFactory.CreateDefaultConfig()
+type CreateDefaultConfig func() config.Connector
+
+// CreateTracesToTracesConnector is the equivalent of component.ConnectorFactory.CreateTracesToTracesConnector()
+type CreateTracesToTracesConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Traces) (component.TracesConnector, error)
+
+// CreateTracesToMetricsConnector is the equivalent of component.ConnectorFactory.CreateTracesToMetricsConnector()
+type CreateTracesToMetricsConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Metrics) (component.TracesConnector, error)
+
+// CreateTracesToLogsConnector is the equivalent of component.ConnectorFactory.CreateTracesToLogsConnector()
+type CreateTracesToLogsConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Logs) (component.TracesConnector, error)
+
+// CreateMetricsToTracesConnector is the equivalent of component.ConnectorFactory.CreateMetricsToTracesConnector()
+type CreateMetricsToTracesConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Traces) (component.MetricsConnector, error)
+
+// CreateMetricsToMetricsConnector is the equivalent of component.ConnectorFactory.CreateMetricsToMetricsConnector()
+type CreateMetricsToMetricsConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Metrics) (component.MetricsConnector, error)
+
+// CreateMetricsToLogsConnector is the equivalent of component.ConnectorFactory.CreateMetricsToLogsConnector()
+type CreateMetricsToLogsConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Logs) (component.MetricsConnector, error)
+
+// CreateLogsToTracesConnector is the equivalent of component.ConnectorFactory.CreateLogsToTracesConnector()
+type CreateLogsToTracesConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Traces) (component.LogsConnector, error)
+
+// CreateLogsToMetricsConnector is the equivalent of component.ConnectorFactory.CreateLogsToMetricsConnector()
+type CreateLogsToMetricsConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Metrics) (component.LogsConnector, error)
+
+// CreateLogsToLogsConnector is the equivalent of component.ConnectorFactory.CreateLogsToLogsConnector()
+type CreateLogsToLogsConnector func(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Logs) (component.LogsConnector, error)
+
+type factory struct {
+	cfgType                         config.Type
+	createDefaultConfig             CreateDefaultConfig
+	createTracesToTracesConnector   CreateTracesToTracesConnector
+	createTracesToMetricsConnector  CreateTracesToMetricsConnector
+	createTracesToLogsConnector     CreateTracesToLogsConnector
+	createMetricsToTracesConnector  CreateMetricsToTracesConnector
+	createMetricsToMetricsConnector CreateMetricsToMetricsConnector
+	createMetricsToLogsConnector    CreateMetricsToLogsConnector
+	createLogsToTracesConnector     CreateLogsToTracesConnector
+	createLogsToMetricsConnector    CreateLogsToMetricsConnector
+	createLogsToLogsConnector       CreateLogsToLogsConnector
+}
+
+// NewFactory returns a component.ConnectorFactory.
+func NewFactory(
+	cfgType config.Type,
+	createDefaultConfig CreateDefaultConfig,
+	options ...FactoryOption) component.ConnectorFactory {
+	f := &factory{
+		cfgType:             cfgType,
+		createDefaultConfig: createDefaultConfig,
+	}
+	for _, opt := range options {
+		opt(f)
+	}
+	return f
+}
+
+// Type gets the type of the Connector config created by this factory.
+func (f *factory) Type() config.Type {
+	return f.cfgType
+}
+
+// CreateDefaultConfig creates the default configuration for connector.
+func (f *factory) CreateDefaultConfig() config.Connector {
+	return f.createDefaultConfig()
+}
+
+// CreateTracesToTracesConnector creates a component.TracesConnector that emits traces based on this config.
+func (f *factory) CreateTracesToTracesConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Traces) (component.TracesConnector, error) {
+	if f.createTracesToTracesConnector != nil {
+		return f.createTracesToTracesConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateTracesToMetricsConnector creates a component.TracesConnector that emits metrics based on this config.
+func (f *factory) CreateTracesToMetricsConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Metrics) (component.TracesConnector, error) {
+	if f.createTracesToMetricsConnector != nil {
+		return f.createTracesToMetricsConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateTracesToLogsConnector creates a component.TracesConnector that emits logs based on this config.
+func (f *factory) CreateTracesToLogsConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Logs) (component.TracesConnector, error) {
+	if f.createTracesToLogsConnector != nil {
+		return f.createTracesToLogsConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateMetricsToTracesConnector creates a component.MetricsConnector that emits traces based on this config.
+func (f *factory) CreateMetricsToTracesConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Traces) (component.MetricsConnector, error) {
+	if f.createMetricsToTracesConnector != nil {
+		return f.createMetricsToTracesConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateMetricsToMetricsConnector creates a component.MetricsConnector that emits metrics based on this config.
+func (f *factory) CreateMetricsToMetricsConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Metrics) (component.MetricsConnector, error) {
+	if f.createMetricsToMetricsConnector != nil {
+		return f.createMetricsToMetricsConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateMetricsToLogsConnector creates a component.MetricsConnector that emits logs based on this config.
+func (f *factory) CreateMetricsToLogsConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Logs) (component.MetricsConnector, error) {
+	if f.createMetricsToLogsConnector != nil {
+		return f.createMetricsToLogsConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateLogsToTracesConnector creates a component.LogsConnector that emits traces based on this config.
+func (f *factory) CreateLogsToTracesConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Traces) (component.LogsConnector, error) {
+	if f.createLogsToTracesConnector != nil {
+		return f.createLogsToTracesConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateLogsToMetricsConnector creates a component.LogsConnector that emits metrics based on this config.
+func (f *factory) CreateLogsToMetricsConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Metrics) (component.LogsConnector, error) {
+	if f.createLogsToMetricsConnector != nil {
+		return f.createLogsToMetricsConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
+
+// CreateLogsToLogsConnector creates a component.LogsConnector that emits logs based on this config.
+func (f *factory) CreateLogsToLogsConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	cfg config.Connector,
+	nextConsumer consumer.Logs) (component.LogsConnector, error) {
+	if f.createLogsToLogsConnector != nil {
+		return f.createLogsToLogsConnector(ctx, set, cfg, nextConsumer)
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
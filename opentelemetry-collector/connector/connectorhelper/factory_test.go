@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connectorhelper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const typeStr = "test"
+
+var defaultCfg = config.NewConnectorSettings(config.NewID(typeStr))
+
+func TestNewFactory(t *testing.T) {
+	factory := NewFactory(
+		typeStr,
+		defaultConfig)
+	assert.EqualValues(t, typeStr, factory.Type())
+	assert.EqualValues(t, &defaultCfg, factory.CreateDefaultConfig())
+	_, err := factory.CreateTracesToTracesConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateTracesToMetricsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateTracesToLogsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateMetricsToTracesConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateMetricsToMetricsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateMetricsToLogsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateLogsToTracesConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateLogsToMetricsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	_, err = factory.CreateLogsToLogsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+}
+
+func TestNewFactory_WithConstructors(t *testing.T) {
+	factory := NewFactory(
+		typeStr,
+		defaultConfig,
+		WithTracesToTraces(createTracesToTracesConnector),
+		WithTracesToMetrics(createTracesToMetricsConnector),
+		WithTracesToLogs(createTracesToLogsConnector),
+		WithMetricsToTraces(createMetricsToTracesConnector),
+		WithMetricsToMetrics(createMetricsToMetricsConnector),
+		WithMetricsToLogs(createMetricsToLogsConnector),
+		WithLogsToTraces(createLogsToTracesConnector),
+		WithLogsToMetrics(createLogsToMetricsConnector),
+		WithLogsToLogs(createLogsToLogsConnector),
+	)
+	assert.EqualValues(t, typeStr, factory.Type())
+	assert.EqualValues(t, &defaultCfg, factory.CreateDefaultConfig())
+
+	_, err := factory.CreateTracesToTracesConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateTracesToMetricsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateTracesToLogsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateMetricsToTracesConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateMetricsToMetricsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateMetricsToLogsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateLogsToTracesConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateLogsToMetricsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+
+	_, err = factory.CreateLogsToLogsConnector(context.Background(), componenttest.NewNopConnectorCreateSettings(), factory.CreateDefaultConfig(), nil)
+	assert.NoError(t, err)
+}
+
+func defaultConfig() config.Connector {
+	return &defaultCfg
+}
+
+func createTracesToTracesConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Traces) (component.TracesConnector, error) {
+	return nil, nil
+}
+
+func createTracesToMetricsConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Metrics) (component.TracesConnector, error) {
+	return nil, nil
+}
+
+func createTracesToLogsConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Logs) (component.TracesConnector, error) {
+	return nil, nil
+}
+
+func createMetricsToTracesConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Traces) (component.MetricsConnector, error) {
+	return nil, nil
+}
+
+func createMetricsToMetricsConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Metrics) (component.MetricsConnector, error) {
+	return nil, nil
+}
+
+func createMetricsToLogsConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Logs) (component.MetricsConnector, error) {
+	return nil, nil
+}
+
+func createLogsToTracesConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Traces) (component.LogsConnector, error) {
+	return nil, nil
+}
+
+func createLogsToMetricsConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Metrics) (component.LogsConnector, error) {
+	return nil, nil
+}
+
+func createLogsToLogsConnector(context.Context, component.ConnectorCreateSettings, config.Connector, consumer.Logs) (component.LogsConnector, error) {
+	return nil, nil
+}
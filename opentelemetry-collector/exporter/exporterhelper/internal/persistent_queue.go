@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+const (
+	zapKey           = "key"
+	zapQueueNameKey  = "queueName"
+	zapErrorCount    = "errorCount"
+	zapNumberOfItems = "numberOfItems"
+)
+
+// ProducerConsumerQueue is an interface for a queue of items that are produced
+// by the exporter and consumed by a fixed number of workers.
+type ProducerConsumerQueue interface {
+	// StartConsumers starts a given number of goroutines consuming items from the queue
+	// and passing them into the consumer callback.
+	StartConsumers(num int, callback func(item interface{}))
+	// Produce is used by the producer to submit new item to the queue. Returns false if the item wasn't added
+	// to the queue due to queue overflow.
+	Produce(item interface{}) bool
+	// Size returns the current Size of the queue
+	Size() int
+	// Stop stops all consumers, as well as the length reporter if started,
+	// and releases the items channel. It blocks until all consumers have stopped.
+	Stop()
+}
+
+// PersistentRequest is a request which can be stored in a persistent queue.
+type PersistentRequest interface {
+	// Marshal serializes the request so it can be written to the storage.
+	Marshal() ([]byte, error)
+	// OnProcessingFinished must be called when the request was dispatched, successfully or not,
+	// so that the queue can release it.
+	OnProcessingFinished()
+	// SetOnProcessingFinished sets the callback invoked by OnProcessingFinished.
+	SetOnProcessingFinished(callback func())
+}
+
+// RequestUnmarshaler defines a function which takes a byte slice and unmarshals it into a PersistentRequest.
+type RequestUnmarshaler func([]byte) (PersistentRequest, error)
+
+// persistentQueue holds the queue backed by file storage
+type persistentQueue struct {
+	logger     *zap.Logger
+	stopWG     sync.WaitGroup
+	stopOnce   sync.Once
+	stopChan   chan struct{}
+	numWorkers int
+	storage    *persistentContiguousStorage
+}
+
+// NewPersistentQueue creates a new queue backed by file storage; name parameter must be a unique value
+// that identifies the queue. Items which were left in the storage by a previous run are replayed.
+func NewPersistentQueue(ctx context.Context, name string, capacity int, logger *zap.Logger, client storage.Client, unmarshaler RequestUnmarshaler) ProducerConsumerQueue {
+	return &persistentQueue{
+		logger:   logger,
+		stopChan: make(chan struct{}),
+		storage:  newPersistentContiguousStorage(ctx, name, uint64(capacity), logger, client, unmarshaler),
+	}
+}
+
+// StartConsumers starts the given number of consumers which will be consuming items
+func (pq *persistentQueue) StartConsumers(num int, callback func(item interface{})) {
+	pq.numWorkers = num
+
+	for i := 0; i < pq.numWorkers; i++ {
+		pq.stopWG.Add(1)
+		go func() {
+			defer pq.stopWG.Done()
+			for {
+				select {
+				case req := <-pq.storage.get():
+					callback(req)
+				case <-pq.stopChan:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Produce adds an item to the queue and returns true if it was accepted
+func (pq *persistentQueue) Produce(item interface{}) bool {
+	req, ok := item.(PersistentRequest)
+	if !ok {
+		return false
+	}
+	err := pq.storage.put(req)
+	return err == nil
+}
+
+// Stop stops accepting items, shuts down the queue and closes the persistent queue
+func (pq *persistentQueue) Stop() {
+	pq.stopOnce.Do(func() {
+		close(pq.stopChan)
+		pq.stopWG.Wait()
+		pq.storage.stop()
+	})
+}
+
+// Size returns the current depth of the queue, excluding the item already in the storage channel (if any)
+func (pq *persistentQueue) Size() int {
+	return int(pq.storage.size())
+}
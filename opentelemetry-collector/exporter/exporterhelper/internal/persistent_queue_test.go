@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func createTestQueue(client *mockStorageClient, capacity int) ProducerConsumerQueue {
+	return NewPersistentQueue(context.Background(), "foo", capacity, zap.NewNop(), client, fakeRequestUnmarshaler)
+}
+
+func TestPersistentQueue_Capacity(t *testing.T) {
+	pq := createTestQueue(newMockStorageClient(), 5)
+	defer pq.Stop()
+
+	for i := 0; i < 10; i++ {
+		result := pq.Produce(newFakeRequest(strconv.Itoa(i)))
+		if i < 5 {
+			assert.True(t, result)
+		}
+		// The last few items might be accepted, since the loop may pick up
+		// the first item while the others are being produced.
+	}
+	assert.LessOrEqual(t, pq.Size(), 5)
+}
+
+func TestPersistentQueue_ConsumersProducers(t *testing.T) {
+	for _, numConsumers := range []int{1, 3, 10} {
+		t.Run("consumers_"+strconv.Itoa(numConsumers), func(t *testing.T) {
+			pq := createTestQueue(newMockStorageClient(), 1000)
+
+			var mu sync.Mutex
+			consumed := map[string]bool{}
+			pq.StartConsumers(numConsumers, func(item interface{}) {
+				req := item.(*fakeRequest)
+				mu.Lock()
+				consumed[req.payload] = true
+				mu.Unlock()
+				req.OnProcessingFinished()
+			})
+
+			for i := 0; i < 100; i++ {
+				require.True(t, pq.Produce(newFakeRequest(strconv.Itoa(i))))
+			}
+
+			assert.Eventually(t, func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return len(consumed) == 100
+			}, 5*time.Second, 10*time.Millisecond)
+			pq.Stop()
+		})
+	}
+}
+
+func TestPersistentQueue_RejectsNonPersistentItems(t *testing.T) {
+	pq := createTestQueue(newMockStorageClient(), 5)
+	defer pq.Stop()
+
+	assert.False(t, pq.Produce("not a request"))
+	assert.Equal(t, 0, pq.Size())
+}
@@ -0,0 +1,425 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"sync"
+
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+// persistentContiguousStorage provides a persistent queue implementation backed by a storage.Client.
+//
+// Write index describes the position at which next item is going to be stored.
+// Read index describes which item needs to be read next.
+// When Write index = Read index, no elements are in the queue.
+//
+// The items currently being dispatched are also tracked, so that they can be
+// re-enqueued when the collector restarts before they were fully processed.
+//
+//   ┌─Read index──┐         ┌─Write index─┐
+//   │             │         │             │
+//   │             ▼         ▼             │
+//   │ ┌───┬───┬───┬───┬───┬───┬───┬───┐   │
+//   │ │ x │ x │ x │ 3 │ 4 │ 5 │   │   │   │
+//   │ └───┴───┴───┴───┴───┴───┴───┴───┘   │
+//   └─────────────────────────────────────┘
+type persistentContiguousStorage struct {
+	logger      *zap.Logger
+	queueName   string
+	client      storage.Client
+	unmarshaler RequestUnmarshaler
+
+	putChan  chan struct{}
+	stopChan chan struct{}
+	stopOnce sync.Once
+	capacity uint64
+
+	reqChan chan PersistentRequest
+
+	mu                       sync.Mutex
+	readIndex                itemIndex
+	writeIndex               itemIndex
+	currentlyDispatchedItems []itemIndex
+
+	itemsCount *atomic.Uint64
+}
+
+type itemIndex uint64
+
+const (
+	zeroIndex      = itemIndex(0)
+	readIndexKey   = "ri"
+	writeIndexKey  = "wi"
+	dispatchedKey  = "di"
+	indexKeyPrefix = "i"
+)
+
+var (
+	errMaxCapacityReached   = errors.New("max capacity reached")
+	errValueNotSet          = errors.New("value not set")
+	errInvalidEncodedLength = errors.New("invalid length of encoded value")
+)
+
+// newPersistentContiguousStorage creates a new storage extension backed queue;
+// queueName parameter must be a unique value that identifies the queue.
+// Items left from a previous run are loaded and the ones which were being dispatched
+// when the collector stopped are moved back to the queue.
+func newPersistentContiguousStorage(ctx context.Context, queueName string, capacity uint64, logger *zap.Logger, client storage.Client, unmarshaler RequestUnmarshaler) *persistentContiguousStorage {
+	pcs := &persistentContiguousStorage{
+		logger:      logger,
+		client:      client,
+		queueName:   queueName,
+		unmarshaler: unmarshaler,
+		capacity:    capacity,
+		putChan:     make(chan struct{}, 1),
+		reqChan:     make(chan PersistentRequest),
+		stopChan:    make(chan struct{}),
+		itemsCount:  atomic.NewUint64(0),
+	}
+
+	initPersistentContiguousStorage(ctx, pcs)
+	notDispatchedReqs := pcs.retrieveNotDispatchedReqs(ctx)
+
+	// Make sure the leftover requests are handled
+	pcs.enqueueNotDispatchedReqs(notDispatchedReqs)
+	// Make sure the communication channel is loaded up
+	if pcs.size() > 0 {
+		select {
+		case pcs.putChan <- struct{}{}:
+		default:
+		}
+	}
+
+	// Start the loop which moves items from storage to the outbound channel
+	go pcs.loop()
+
+	return pcs
+}
+
+func initPersistentContiguousStorage(ctx context.Context, pcs *persistentContiguousStorage) {
+	var writeIndex itemIndex
+	var readIndex itemIndex
+	batch := []storage.Operation{
+		storage.GetOperation(readIndexKey),
+		storage.GetOperation(writeIndexKey),
+	}
+	err := pcs.client.Batch(ctx, batch...)
+
+	if err == nil {
+		readIndex, err = bytesToItemIndex(batch[0].Value)
+	}
+
+	if err == nil {
+		writeIndex, err = bytesToItemIndex(batch[1].Value)
+	}
+
+	if err != nil {
+		pcs.logger.Error("Failed getting read/write index, starting with new ones",
+			zap.String(zapQueueNameKey, pcs.queueName),
+			zap.Error(err))
+		pcs.readIndex = zeroIndex
+		pcs.writeIndex = zeroIndex
+	} else {
+		if batch[1].Value == nil {
+			pcs.logger.Info("Initializing new persistent queue", zap.String(zapQueueNameKey, pcs.queueName))
+		}
+		pcs.readIndex = readIndex
+		pcs.writeIndex = writeIndex
+	}
+
+	pcs.itemsCount.Store(uint64(pcs.writeIndex - pcs.readIndex))
+}
+
+func (pcs *persistentContiguousStorage) enqueueNotDispatchedReqs(reqs []PersistentRequest) {
+	if len(reqs) > 0 {
+		errCount := 0
+		for _, req := range reqs {
+			if req == nil || pcs.put(req) != nil {
+				errCount++
+			}
+		}
+		if errCount > 0 {
+			pcs.logger.Error("Errors occurred while moving items for dispatching back to queue",
+				zap.String(zapQueueNameKey, pcs.queueName),
+				zap.Int(zapNumberOfItems, len(reqs)), zap.Int(zapErrorCount, errCount))
+		} else {
+			pcs.logger.Info("Moved items for dispatching back to queue",
+				zap.String(zapQueueNameKey, pcs.queueName),
+				zap.Int(zapNumberOfItems, len(reqs)))
+		}
+	}
+}
+
+// loop is the main loop that handles fetching items from the persistent buffer
+func (pcs *persistentContiguousStorage) loop() {
+	for {
+		select {
+		case <-pcs.stopChan:
+			return
+		case <-pcs.putChan:
+			for {
+				req, found := pcs.getNextItem(context.Background())
+				if !found {
+					break
+				}
+				if req == nil {
+					// The item could not be read back; it was already logged and removed.
+					continue
+				}
+				select {
+				case pcs.reqChan <- req:
+				case <-pcs.stopChan:
+					return
+				}
+			}
+		}
+	}
+}
+
+// get returns the request channel that all the requests will be send on
+func (pcs *persistentContiguousStorage) get() <-chan PersistentRequest {
+	return pcs.reqChan
+}
+
+// size returns the number of currently available items, which were not picked by consumers yet
+func (pcs *persistentContiguousStorage) size() uint64 {
+	return pcs.itemsCount.Load()
+}
+
+func (pcs *persistentContiguousStorage) stop() {
+	pcs.logger.Debug("Stopping persistentContiguousStorage", zap.String(zapQueueNameKey, pcs.queueName))
+	pcs.stopOnce.Do(func() {
+		close(pcs.stopChan)
+		if err := pcs.client.Close(context.Background()); err != nil {
+			pcs.logger.Error("Error closing storage client", zap.String(zapQueueNameKey, pcs.queueName), zap.Error(err))
+		}
+	})
+}
+
+// put marshals the request and puts it into the persistent queue
+func (pcs *persistentContiguousStorage) put(req PersistentRequest) error {
+	buf, err := req.Marshal()
+	if err != nil {
+		return err
+	}
+
+	pcs.mu.Lock()
+	defer pcs.mu.Unlock()
+
+	if pcs.size() >= pcs.capacity {
+		pcs.logger.Warn("Maximum queue capacity reached", zap.String(zapQueueNameKey, pcs.queueName))
+		return errMaxCapacityReached
+	}
+
+	itemKey := pcs.itemKey(pcs.writeIndex)
+	newIndex := pcs.writeIndex + 1
+
+	ctx := context.Background()
+	err = pcs.client.Batch(ctx,
+		storage.SetOperation(writeIndexKey, itemIndexToBytes(newIndex)),
+		storage.SetOperation(itemKey, buf))
+	if err != nil {
+		return err
+	}
+
+	pcs.writeIndex = newIndex
+	pcs.itemsCount.Store(uint64(pcs.writeIndex - pcs.readIndex))
+
+	// Inform the loop that there's some data to process
+	select {
+	case pcs.putChan <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// getNextItem pulls the next available item from the persistent storage; if none is found, returns (nil, false)
+func (pcs *persistentContiguousStorage) getNextItem(ctx context.Context) (PersistentRequest, bool) {
+	pcs.mu.Lock()
+	defer pcs.mu.Unlock()
+
+	if pcs.readIndex == pcs.writeIndex {
+		return nil, false
+	}
+
+	index := pcs.readIndex
+	// Increase here, so even if errors happen below, it always iterates
+	pcs.readIndex++
+	pcs.itemsCount.Store(uint64(pcs.writeIndex - pcs.readIndex))
+	pcs.currentlyDispatchedItems = append(pcs.currentlyDispatchedItems, index)
+
+	getOp := storage.GetOperation(pcs.itemKey(index))
+	err := pcs.client.Batch(ctx,
+		storage.SetOperation(readIndexKey, itemIndexToBytes(pcs.readIndex)),
+		storage.SetOperation(dispatchedKey, itemIndexArrayToBytes(pcs.currentlyDispatchedItems)),
+		getOp)
+
+	var req PersistentRequest
+	if err == nil {
+		req, err = pcs.unmarshaler(getOp.Value)
+	}
+
+	if err != nil || req == nil {
+		pcs.logger.Debug("Failed to dispatch item", zap.String(zapQueueNameKey, pcs.queueName), zap.Error(err))
+		// Do not leave a broken item behind, it would be replayed forever.
+		pcs.itemDispatchingFinishLocked(ctx, index)
+		return nil, true
+	}
+
+	req.SetOnProcessingFinished(func() {
+		pcs.mu.Lock()
+		defer pcs.mu.Unlock()
+		pcs.itemDispatchingFinishLocked(context.Background(), index)
+	})
+	return req, true
+}
+
+// retrieveNotDispatchedReqs gets the items for which sending was not finished, cleans the storage
+// and moves the items back to the queue
+func (pcs *persistentContiguousStorage) retrieveNotDispatchedReqs(ctx context.Context) []PersistentRequest {
+	var dispatchedItems []itemIndex
+
+	pcs.mu.Lock()
+	defer pcs.mu.Unlock()
+
+	pcs.logger.Debug("Checking if there are items left for dispatch by consumers", zap.String(zapQueueNameKey, pcs.queueName))
+	itemKeysBuf, err := pcs.client.Get(ctx, dispatchedKey)
+	if err == nil {
+		dispatchedItems, err = bytesToItemIndexArray(itemKeysBuf)
+	}
+	if err != nil {
+		pcs.logger.Error("Could not fetch items left for dispatch by consumers", zap.String(zapQueueNameKey, pcs.queueName), zap.Error(err))
+		return nil
+	}
+
+	if len(dispatchedItems) == 0 {
+		pcs.logger.Debug("No items left for dispatch by consumers", zap.String(zapQueueNameKey, pcs.queueName))
+		return nil
+	}
+
+	pcs.logger.Info("Fetching items left for dispatch by consumers",
+		zap.String(zapQueueNameKey, pcs.queueName), zap.Int(zapNumberOfItems, len(dispatchedItems)))
+	retrieveBatch := make([]storage.Operation, len(dispatchedItems))
+	cleanupBatch := make([]storage.Operation, len(dispatchedItems)+1)
+	for i, it := range dispatchedItems {
+		key := pcs.itemKey(it)
+		retrieveBatch[i] = storage.GetOperation(key)
+		cleanupBatch[i] = storage.DeleteOperation(key)
+	}
+	cleanupBatch[len(dispatchedItems)] = storage.SetOperation(dispatchedKey, itemIndexArrayToBytes(nil))
+
+	retrieveErr := pcs.client.Batch(ctx, retrieveBatch...)
+	cleanupErr := pcs.client.Batch(ctx, cleanupBatch...)
+
+	if cleanupErr != nil {
+		pcs.logger.Debug("Failed cleaning items left by consumers", zap.String(zapQueueNameKey, pcs.queueName), zap.Error(cleanupErr))
+	}
+
+	if retrieveErr != nil {
+		pcs.logger.Warn("Failed retrieving items left by consumers", zap.String(zapQueueNameKey, pcs.queueName), zap.Error(retrieveErr))
+		return nil
+	}
+
+	reqs := make([]PersistentRequest, 0, len(retrieveBatch))
+	for _, op := range retrieveBatch {
+		if op.Value == nil {
+			pcs.logger.Warn("Failed retrieving item", zap.String(zapKey, op.Key), zap.Error(errValueNotSet))
+			continue
+		}
+		req, err := pcs.unmarshaler(op.Value)
+		if err != nil || req == nil {
+			pcs.logger.Warn("Failed unmarshalling item", zap.String(zapKey, op.Key), zap.Error(err))
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+
+	return reqs
+}
+
+// itemDispatchingFinishLocked removes the item from the list of currently dispatched items
+// and deletes it from the storage. The caller must hold pcs.mu.
+func (pcs *persistentContiguousStorage) itemDispatchingFinishLocked(ctx context.Context, index itemIndex) {
+	for i, it := range pcs.currentlyDispatchedItems {
+		if it == index {
+			pcs.currentlyDispatchedItems = append(pcs.currentlyDispatchedItems[:i], pcs.currentlyDispatchedItems[i+1:]...)
+			break
+		}
+	}
+
+	err := pcs.client.Batch(ctx,
+		storage.SetOperation(dispatchedKey, itemIndexArrayToBytes(pcs.currentlyDispatchedItems)),
+		storage.DeleteOperation(pcs.itemKey(index)))
+	if err != nil {
+		pcs.logger.Debug("Failed updating currently dispatched items", zap.String(zapQueueNameKey, pcs.queueName), zap.Error(err))
+	}
+}
+
+func (pcs *persistentContiguousStorage) itemKey(index itemIndex) string {
+	return indexKeyPrefix + strconv.FormatUint(uint64(index), 10)
+}
+
+func itemIndexToBytes(val itemIndex) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(val))
+	return buf
+}
+
+// bytesToItemIndex decodes an index, a missing value is treated as the zero index.
+func bytesToItemIndex(buf []byte) (itemIndex, error) {
+	if buf == nil {
+		return zeroIndex, nil
+	}
+	if len(buf) != 8 {
+		return zeroIndex, errInvalidEncodedLength
+	}
+	return itemIndex(binary.LittleEndian.Uint64(buf)), nil
+}
+
+func itemIndexArrayToBytes(arr []itemIndex) []byte {
+	buf := make([]byte, 4+8*len(arr))
+	binary.LittleEndian.PutUint32(buf, uint32(len(arr)))
+	for i, item := range arr {
+		binary.LittleEndian.PutUint64(buf[4+8*i:], uint64(item))
+	}
+	return buf
+}
+
+func bytesToItemIndexArray(buf []byte) ([]itemIndex, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	if len(buf) < 4 {
+		return nil, errInvalidEncodedLength
+	}
+	size := int(binary.LittleEndian.Uint32(buf))
+	if len(buf) != 4+8*size {
+		return nil, errInvalidEncodedLength
+	}
+	arr := make([]itemIndex, size)
+	for i := 0; i < size; i++ {
+		arr[i] = itemIndex(binary.LittleEndian.Uint64(buf[4+8*i:]))
+	}
+	return arr, nil
+}
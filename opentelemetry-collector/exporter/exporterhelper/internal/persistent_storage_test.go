@@ -0,0 +1,213 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/extension/storage"
+)
+
+type fakeRequest struct {
+	payload            string
+	processingFinished func()
+}
+
+func newFakeRequest(payload string) *fakeRequest {
+	return &fakeRequest{payload: payload}
+}
+
+func (fr *fakeRequest) Marshal() ([]byte, error) {
+	return []byte(fr.payload), nil
+}
+
+func (fr *fakeRequest) OnProcessingFinished() {
+	if fr.processingFinished != nil {
+		fr.processingFinished()
+	}
+}
+
+func (fr *fakeRequest) SetOnProcessingFinished(callback func()) {
+	fr.processingFinished = callback
+}
+
+func fakeRequestUnmarshaler(buf []byte) (PersistentRequest, error) {
+	return newFakeRequest(string(buf)), nil
+}
+
+type mockStorageClient struct {
+	st sync.Map
+}
+
+func newMockStorageClient() *mockStorageClient {
+	return &mockStorageClient{}
+}
+
+func (m *mockStorageClient) Get(ctx context.Context, s string) ([]byte, error) {
+	getOp := storage.GetOperation(s)
+	err := m.Batch(ctx, getOp)
+	return getOp.Value, err
+}
+
+func (m *mockStorageClient) Set(ctx context.Context, s string, bytes []byte) error {
+	return m.Batch(ctx, storage.SetOperation(s, bytes))
+}
+
+func (m *mockStorageClient) Delete(ctx context.Context, s string) error {
+	return m.Batch(ctx, storage.DeleteOperation(s))
+}
+
+func (m *mockStorageClient) Close(context.Context) error {
+	return nil
+}
+
+func (m *mockStorageClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			val, found := m.st.Load(op.Key)
+			if !found {
+				op.Value = nil
+				continue
+			}
+			op.Value = val.([]byte)
+		case storage.Set:
+			m.st.Store(op.Key, op.Value)
+		case storage.Delete:
+			m.st.Delete(op.Key)
+		default:
+			return errors.New("wrong operation type")
+		}
+	}
+
+	return nil
+}
+
+func (m *mockStorageClient) keys() int {
+	cnt := 0
+	m.st.Range(func(key, value interface{}) bool {
+		cnt++
+		return true
+	})
+	return cnt
+}
+
+func newTestStorage(client storage.Client, capacity uint64) *persistentContiguousStorage {
+	return newPersistentContiguousStorage(context.Background(), "foo", capacity, zap.NewNop(), client, fakeRequestUnmarshaler)
+}
+
+func TestPersistentStorage_PutGet(t *testing.T) {
+	ps := newTestStorage(newMockStorageClient(), 100)
+	defer ps.stop()
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, ps.put(newFakeRequest(strconv.Itoa(i))))
+	}
+
+	for i := 0; i < 10; i++ {
+		req := getItemFromChannel(t, ps)
+		assert.Equal(t, strconv.Itoa(i), req.(*fakeRequest).payload)
+		req.OnProcessingFinished()
+	}
+	assert.EqualValues(t, 0, ps.size())
+}
+
+func TestPersistentStorage_CapacityReached(t *testing.T) {
+	client := newMockStorageClient()
+	ps := newTestStorage(client, 5)
+	defer ps.stop()
+
+	// The loop may pick up one item and wait on the channel, so add more than capacity.
+	var errs int
+	for i := 0; i < 10; i++ {
+		if err := ps.put(newFakeRequest(strconv.Itoa(i))); err != nil {
+			assert.Equal(t, errMaxCapacityReached, err)
+			errs++
+		}
+	}
+	assert.GreaterOrEqual(t, errs, 4)
+	assert.LessOrEqual(t, ps.size(), uint64(5))
+}
+
+func TestPersistentStorage_RestoresStateAfterRestart(t *testing.T) {
+	client := newMockStorageClient()
+	ps := newTestStorage(client, 100)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, ps.put(newFakeRequest(strconv.Itoa(i))))
+	}
+
+	// Take one item and finish it, take another one but do not finish it.
+	getItemFromChannel(t, ps).OnProcessingFinished()
+	notFinished := getItemFromChannel(t, ps)
+	assert.Equal(t, "1", notFinished.(*fakeRequest).payload)
+	ps.stop()
+
+	ps = newTestStorage(client, 100)
+	defer ps.stop()
+
+	var payloads []string
+	for i := 0; i < 4; i++ {
+		req := getItemFromChannel(t, ps)
+		payloads = append(payloads, req.(*fakeRequest).payload)
+		req.OnProcessingFinished()
+	}
+	// Items left in flight by the previous run are moved to the back of the queue.
+	assert.ElementsMatch(t, []string{"1", "2", "3", "4"}, payloads)
+
+	assert.Eventually(t, func() bool {
+		// only the read index, write index and dispatched items are left
+		return client.keys() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPersistentStorage_ItemIndexArrayMarshaling(t *testing.T) {
+	for _, arr := range [][]itemIndex{nil, {}, {0}, {1, 2, 3, 1 << 40}} {
+		buf := itemIndexArrayToBytes(arr)
+		got, err := bytesToItemIndexArray(buf)
+		require.NoError(t, err)
+		assert.Equal(t, len(arr), len(got))
+		for i := range arr {
+			assert.Equal(t, arr[i], got[i])
+		}
+	}
+
+	_, err := bytesToItemIndexArray([]byte{1, 2})
+	assert.Equal(t, errInvalidEncodedLength, err)
+	_, err = bytesToItemIndex([]byte{1, 2})
+	assert.Equal(t, errInvalidEncodedLength, err)
+}
+
+func getItemFromChannel(t *testing.T, pcs *persistentContiguousStorage) PersistentRequest {
+	var readReq PersistentRequest
+	require.Eventually(t, func() bool {
+		select {
+		case readReq = <-pcs.get():
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	return readReq
+}
@@ -23,9 +23,14 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumerhelper"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
+var logsMarshaler = otlp.NewProtobufLogsMarshaler()
+var logsUnmarshaler = otlp.NewProtobufLogsUnmarshaler()
+
 type logsRequest struct {
 	baseRequest
 	ld     pdata.Logs
@@ -40,6 +45,16 @@ func newLogsRequest(ctx context.Context, ld pdata.Logs, pusher consumerhelper.Co
 	}
 }
 
+func newLogsRequestUnmarshalerFunc(pusher consumerhelper.ConsumeLogsFunc) internal.RequestUnmarshaler {
+	return func(bytes []byte) (internal.PersistentRequest, error) {
+		ld, err := logsUnmarshaler.UnmarshalLogs(bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newLogsRequest(context.Background(), ld, pusher), nil
+	}
+}
+
 func (req *logsRequest) onError(err error) request {
 	var logError consumererror.Logs
 	if consumererror.AsLogs(err, &logError) {
@@ -48,6 +63,11 @@ func (req *logsRequest) onError(err error) request {
 	return req
 }
 
+// Marshal provides serialization capabilities required by persistent queue
+func (req *logsRequest) Marshal() ([]byte, error) {
+	return logsMarshaler.MarshalLogs(req.ld)
+}
+
 func (req *logsRequest) export(ctx context.Context) error {
 	return req.pusher(ctx, req.ld)
 }
@@ -81,7 +101,7 @@ func NewLogsExporter(
 	}
 
 	bs := fromOptions(options...)
-	be := newBaseExporter(cfg, set, bs)
+	be := newBaseExporter(cfg, set, bs, config.LogsDataType, newLogsRequestUnmarshalerFunc(pusher))
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &logsExporterWithObservability{
 			obsrep:     be.obsrep,
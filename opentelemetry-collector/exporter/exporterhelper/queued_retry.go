@@ -29,9 +29,13 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/extension/storage"
 	"go.opentelemetry.io/collector/internal/obsreportconfig/obsmetrics"
+	"go.opentelemetry.io/collector/internal/retention"
 )
 
 var (
@@ -58,6 +62,9 @@ type QueueSettings struct {
 	NumConsumers int `mapstructure:"num_consumers"`
 	// QueueSize is the maximum number of batches allowed in queue at a given time.
 	QueueSize int `mapstructure:"queue_size"`
+	// StorageID if not empty, enables the persistent storage and uses the component specified
+	// as a storage extension for the persistent queue
+	StorageID *config.ComponentID `mapstructure:"storage"`
 }
 
 // DefaultQueueSettings returns the default settings for QueueSettings.
@@ -99,13 +106,16 @@ func DefaultRetrySettings() RetrySettings {
 }
 
 type queuedRetrySender struct {
-	fullName        string
-	cfg             QueueSettings
-	consumerSender  requestSender
-	queue           *internal.BoundedQueue
-	retryStopCh     chan struct{}
-	traceAttributes []attribute.KeyValue
-	logger          *zap.Logger
+	id                 config.ComponentID
+	fullName           string
+	signal             config.DataType
+	cfg                QueueSettings
+	consumerSender     requestSender
+	queue              internal.ProducerConsumerQueue
+	retryStopCh        chan struct{}
+	traceAttributes    []attribute.KeyValue
+	logger             *zap.Logger
+	requestUnmarshaler internal.RequestUnmarshaler
 }
 
 func createSampledLogger(logger *zap.Logger) *zap.Logger {
@@ -127,12 +137,14 @@ func createSampledLogger(logger *zap.Logger) *zap.Logger {
 	return logger.WithOptions(opts)
 }
 
-func newQueuedRetrySender(fullName string, qCfg QueueSettings, rCfg RetrySettings, nextSender requestSender, logger *zap.Logger) *queuedRetrySender {
+func newQueuedRetrySender(id config.ComponentID, signal config.DataType, qCfg QueueSettings, rCfg RetrySettings, reqUnmarshaler internal.RequestUnmarshaler, nextSender requestSender, logger *zap.Logger) *queuedRetrySender {
 	retryStopCh := make(chan struct{})
 	sampledLogger := createSampledLogger(logger)
-	traceAttr := attribute.String(obsmetrics.ExporterKey, fullName)
+	traceAttr := attribute.String(obsmetrics.ExporterKey, id.String())
 	return &queuedRetrySender{
-		fullName: fullName,
+		id:       id,
+		fullName: id.String(),
+		signal:   signal,
 		cfg:      qCfg,
 		consumerSender: &retrySender{
 			traceAttribute: traceAttr,
@@ -141,18 +153,45 @@ func newQueuedRetrySender(fullName string, qCfg QueueSettings, rCfg RetrySetting
 			stopCh:         retryStopCh,
 			logger:         sampledLogger,
 		},
-		queue:           internal.NewBoundedQueue(qCfg.QueueSize, func(item interface{}) {}),
-		retryStopCh:     retryStopCh,
-		traceAttributes: []attribute.KeyValue{traceAttr},
-		logger:          sampledLogger,
+		queue:              internal.NewBoundedQueue(qCfg.QueueSize, func(item interface{}) {}),
+		retryStopCh:        retryStopCh,
+		traceAttributes:    []attribute.KeyValue{traceAttr},
+		logger:             sampledLogger,
+		requestUnmarshaler: reqUnmarshaler,
 	}
 }
 
+// initializePersistentQueue replaces the in-memory queue with one backed by the configured storage extension.
+func (qrs *queuedRetrySender) initializePersistentQueue(ctx context.Context, host component.Host) error {
+	if qrs.cfg.StorageID == nil {
+		return nil
+	}
+
+	storageClient, err := storage.GetClient(ctx, host, *qrs.cfg.StorageID, component.KindExporter, qrs.id, string(qrs.signal))
+	if err != nil {
+		return err
+	}
+
+	qrs.queue = internal.NewPersistentQueue(ctx, qrs.fullName, qrs.cfg.QueueSize, qrs.logger, storageClient, qrs.requestUnmarshaler)
+	return nil
+}
+
 // start is invoked during service startup.
-func (qrs *queuedRetrySender) start() error {
+func (qrs *queuedRetrySender) start(ctx context.Context, host component.Host) error {
+	if qrs.cfg.Enabled {
+		if err := qrs.initializePersistentQueue(ctx, host); err != nil {
+			return err
+		}
+	}
+
 	qrs.queue.StartConsumers(qrs.cfg.NumConsumers, func(item interface{}) {
 		req := item.(request)
-		_ = qrs.consumerSender.send(req)
+		err := qrs.consumerSender.send(req)
+		if err != nil && qrs.cfg.StorageID != nil && qrs.isStopped() {
+			// Keep the request in the persistent storage so it is replayed after restart.
+			return
+		}
+		req.OnProcessingFinished()
 	})
 
 	// Start reporting queue length metric
@@ -185,8 +224,15 @@ func (qrs *queuedRetrySender) send(req request) error {
 	// The grpc/http based receivers will cancel the request context after this function returns.
 	req.setContext(noCancellationContext{Context: req.context()})
 
+	// The in-memory queue keeps the data until it is dispatched, the persistent one stores a copy of it.
+	release := retention.Retain(req.context())
+	if qrs.cfg.StorageID == nil {
+		req.SetOnProcessingFinished(release)
+	}
+
 	span := trace.SpanFromContext(req.context())
 	if !qrs.queue.Produce(req) {
+		release()
 		qrs.logger.Error(
 			"Dropping data because sending_queue is full. Try increasing queue_size.",
 			zap.Int("dropped_items", req.count()),
@@ -194,11 +240,24 @@ func (qrs *queuedRetrySender) send(req request) error {
 		span.AddEvent("Dropped item, sending_queue is full.", trace.WithAttributes(qrs.traceAttributes...))
 		return errSendingQueueIsFull
 	}
+	if qrs.cfg.StorageID != nil {
+		release()
+	}
 
 	span.AddEvent("Enqueued item.", trace.WithAttributes(qrs.traceAttributes...))
 	return nil
 }
 
+// isStopped returns true if the shutdown of the sender already started.
+func (qrs *queuedRetrySender) isStopped() bool {
+	select {
+	case <-qrs.retryStopCh:
+		return true
+	default:
+		return false
+	}
+}
+
 // shutdown is invoked during service shutdown.
 func (qrs *queuedRetrySender) shutdown() {
 	// Cleanup queue metrics reporting
@@ -29,16 +29,25 @@ import (
 	"go.opencensus.io/metric/metricproducer"
 	"go.opencensus.io/tag"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenthelper"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configparser"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/extension/storage"
+	"go.opentelemetry.io/collector/internal/retention"
+	"go.opentelemetry.io/collector/internal/retention/retentiontest"
 	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 )
 
 func TestQueuedRetry_DropOnPermanentError(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -62,7 +71,7 @@ func TestQueuedRetry_DropOnNoRetry(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	rCfg := DefaultRetrySettings()
 	rCfg.Enabled = false
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -87,7 +96,7 @@ func TestQueuedRetry_OnError(t *testing.T) {
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = 0
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -113,7 +122,7 @@ func TestQueuedRetry_StopWhileWaiting(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -146,7 +155,7 @@ func TestQueuedRetry_DoNotPreserveCancellation(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -175,7 +184,7 @@ func TestQueuedRetry_MaxElapsedTime(t *testing.T) {
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = time.Millisecond
 	rCfg.MaxElapsedTime = 100 * time.Millisecond
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -221,7 +230,7 @@ func TestQueuedRetry_ThrottleError(t *testing.T) {
 	qCfg.NumConsumers = 1
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = 10 * time.Millisecond
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -253,7 +262,7 @@ func TestQueuedRetry_RetryOnError(t *testing.T) {
 	qCfg.QueueSize = 1
 	rCfg := DefaultRetrySettings()
 	rCfg.InitialInterval = 0
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -279,7 +288,7 @@ func TestQueuedRetry_DropOnFull(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.QueueSize = 0
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -290,6 +299,70 @@ func TestQueuedRetry_DropOnFull(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestQueuedRetry_RetainUntilSent(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
+	unblock := make(chan struct{})
+	be.qrSender.consumerSender = &blockingSender{nextSender: be.qrSender.consumerSender, unblock: unblock}
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	holder := &retentiontest.CountingHolder{}
+	ctx := retention.NewContext(context.Background(), holder)
+	require.NoError(t, be.sender.send(newMockRequest(ctx, 2, nil)))
+	assert.EqualValues(t, 1, holder.Held())
+
+	close(unblock)
+	assert.Eventually(t, func() bool { return holder.Held() == 0 }, time.Second, time.Millisecond)
+	assert.EqualValues(t, 1, holder.Retained())
+}
+
+func TestQueuedRetry_ReleaseOnFull(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.QueueSize = 0
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	holder := &retentiontest.CountingHolder{}
+	ctx := retention.NewContext(context.Background(), holder)
+	require.Error(t, be.sender.send(newMockRequest(ctx, 2, nil)))
+	assert.EqualValues(t, 1, holder.Retained())
+	assert.EqualValues(t, 0, holder.Held())
+}
+
+func TestQueuedRetry_ReleaseWhenStopped(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
+	unblock := make(chan struct{})
+	be.qrSender.consumerSender = &blockingSender{nextSender: be.qrSender.consumerSender, unblock: unblock}
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	holder := &retentiontest.CountingHolder{}
+	ctx := retention.NewContext(context.Background(), holder)
+	require.NoError(t, be.sender.send(newMockRequest(ctx, 2, errors.New("transient error"))))
+	assert.EqualValues(t, 1, holder.Held())
+
+	// The request fails once the exporter is stopped, the in-memory queue drops it.
+	go func() {
+		for !be.qrSender.isStopped() {
+			time.Sleep(time.Millisecond)
+		}
+		close(unblock)
+	}()
+	assert.NoError(t, be.Shutdown(context.Background()))
+	assert.EqualValues(t, 0, holder.Held())
+}
+
 func TestQueuedRetryHappyPath(t *testing.T) {
 	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
 	require.NoError(t, err)
@@ -297,7 +370,7 @@ func TestQueuedRetryHappyPath(t *testing.T) {
 
 	qCfg := DefaultQueueSettings()
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	ocs := newObservabilityConsumerSender(be.qrSender.consumerSender)
 	be.qrSender.consumerSender = ocs
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
@@ -331,7 +404,7 @@ func TestQueuedRetry_QueueMetricsReported(t *testing.T) {
 	qCfg := DefaultQueueSettings()
 	qCfg.NumConsumers = 0 // to make every request go straight to the queue
 	rCfg := DefaultRetrySettings()
-	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)))
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
 	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
 
 	for i := 0; i < 7; i++ {
@@ -343,6 +416,108 @@ func TestQueuedRetry_QueueMetricsReported(t *testing.T) {
 	checkValueForProducer(t, defaultExporterTags, int64(0), "exporter/queue_size")
 }
 
+func TestQueueSettingsUnmarshalStorage(t *testing.T) {
+	cp := configparser.NewParserFromStringMap(map[string]interface{}{
+		"enabled":    true,
+		"queue_size": 10,
+		"storage":    "file_storage/otc",
+	})
+	qCfg := QueueSettings{}
+	require.NoError(t, cp.UnmarshalExact(&qCfg))
+	require.NotNil(t, qCfg.StorageID)
+	assert.Equal(t, config.NewIDWithName("file_storage", "otc"), *qCfg.StorageID)
+}
+
+func TestQueuedRetryPersistenceEnabled(t *testing.T) {
+	qCfg := DefaultQueueSettings()
+	storageID := config.NewIDWithName("file_storage", "storage")
+	qCfg.StorageID = &storageID // enable persistence
+	rCfg := DefaultRetrySettings()
+	be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
+
+	host := &mockHost{ext: map[config.ComponentID]component.Extension{
+		storageID: newMockStorageExtension(),
+	}}
+
+	// we start correctly with a file storage extension
+	require.NoError(t, be.Start(context.Background(), host))
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestQueuedRetryPersistenceEnabledStorageError(t *testing.T) {
+	storageID := config.NewIDWithName("file_storage", "storage")
+	for _, tt := range []struct {
+		name    string
+		ext     map[config.ComponentID]component.Extension
+		wantErr error
+	}{
+		{
+			name:    "missing extension",
+			ext:     map[config.ComponentID]component.Extension{},
+			wantErr: storage.ErrNoStorageClient,
+		},
+		{
+			name: "not a storage extension",
+			ext: map[config.ComponentID]component.Extension{
+				storageID: componenthelper.New(),
+			},
+			wantErr: storage.ErrWrongExtensionType,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			qCfg := DefaultQueueSettings()
+			qCfg.StorageID = &storageID
+			rCfg := DefaultRetrySettings()
+			be := newBaseExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(), fromOptions(WithRetry(rCfg), WithQueue(qCfg)), "", nopRequestUnmarshaler())
+
+			require.ErrorIs(t, be.Start(context.Background(), &mockHost{ext: tt.ext}), tt.wantErr)
+		})
+	}
+}
+
+func TestQueuedRetryPersistentQueueReplayedAfterRestart(t *testing.T) {
+	storageID := config.NewIDWithName("file_storage", "storage")
+	host := &mockHost{ext: map[config.ComponentID]component.Extension{
+		storageID: newMockStorageExtension(),
+	}}
+	qCfg := DefaultQueueSettings()
+	qCfg.StorageID = &storageID
+	rCfg := DefaultRetrySettings()
+	td := testdata.GenerateTracesOneSpan()
+
+	// The first exporter never consumes, so the data stays in the storage.
+	qCfg.NumConsumers = 0
+	te, err := NewTracesExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(),
+		func(context.Context, pdata.Traces) error { return errors.New("should not be called") },
+		WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), host))
+	require.NoError(t, te.ConsumeTraces(context.Background(), td))
+	require.NoError(t, te.Shutdown(context.Background()))
+
+	// The second exporter replays what was left by the first one.
+	qCfg.NumConsumers = 1
+	received := make(chan pdata.Traces, 1)
+	te, err = NewTracesExporter(&defaultExporterCfg, componenttest.NewNopExporterCreateSettings(),
+		func(_ context.Context, td pdata.Traces) error {
+			received <- td
+			return nil
+		},
+		WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, te.Shutdown(context.Background()))
+	})
+
+	select {
+	case got := <-received:
+		assert.Equal(t, td, got)
+	case <-time.After(5 * time.Second):
+		t.Fatal("persisted traces were not replayed")
+	}
+}
+
 func TestNoCancellationContext(t *testing.T) {
 	deadline := time.Now().Add(1 * time.Second)
 	ctx, cancelFunc := context.WithDeadline(context.Background(), deadline)
@@ -371,6 +546,10 @@ func (mer *mockErrorRequest) onError(error) request {
 	return mer
 }
 
+func (mer *mockErrorRequest) Marshal() ([]byte, error) {
+	return nil, nil
+}
+
 func (mer *mockErrorRequest) count() int {
 	return 7
 }
@@ -417,6 +596,10 @@ func (m *mockRequest) checkNumRequests(t *testing.T, want int) {
 	}, time.Second, 1*time.Millisecond)
 }
 
+func (m *mockRequest) Marshal() ([]byte, error) {
+	return nil, nil
+}
+
 func (m *mockRequest) count() int {
 	return m.cnt
 }
@@ -430,6 +613,17 @@ func newMockRequest(ctx context.Context, cnt int, consumeError error) *mockReque
 	}
 }
 
+// blockingSender waits for unblock to be closed before sending the requests.
+type blockingSender struct {
+	nextSender requestSender
+	unblock    chan struct{}
+}
+
+func (bs *blockingSender) send(req request) error {
+	<-bs.unblock
+	return bs.nextSender.send(req)
+}
+
 type observabilityConsumerSender struct {
 	waitGroup         *sync.WaitGroup
 	sentItemsCount    int64
@@ -505,3 +699,78 @@ func tagsMatchLabelKeys(tags []tag.Tag, keys []metricdata.LabelKey, labels []met
 	}
 	return true
 }
+
+func nopRequestUnmarshaler() internal.RequestUnmarshaler {
+	return func([]byte) (internal.PersistentRequest, error) {
+		return nil, nil
+	}
+}
+
+type mockHost struct {
+	component.Host
+	ext map[config.ComponentID]component.Extension
+}
+
+func (nh *mockHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return nh.ext
+}
+
+type mockStorageExtension struct {
+	component.Component
+	// data is shared by all clients, so it outlives the exporters like a real storage.
+	data map[string][]byte
+	mu   sync.Mutex
+}
+
+func newMockStorageExtension() *mockStorageExtension {
+	return &mockStorageExtension{
+		Component: componenthelper.New(),
+		data:      map[string][]byte{},
+	}
+}
+
+func (mse *mockStorageExtension) GetClient(_ context.Context, _ component.Kind, _ config.ComponentID, _ string) (storage.Client, error) {
+	return &mockStorageClient{ext: mse}, nil
+}
+
+type mockStorageClient struct {
+	ext *mockStorageExtension
+}
+
+func (m *mockStorageClient) Get(ctx context.Context, s string) ([]byte, error) {
+	getOp := storage.GetOperation(s)
+	err := m.Batch(ctx, getOp)
+	return getOp.Value, err
+}
+
+func (m *mockStorageClient) Set(ctx context.Context, s string, bytes []byte) error {
+	return m.Batch(ctx, storage.SetOperation(s, bytes))
+}
+
+func (m *mockStorageClient) Delete(ctx context.Context, s string) error {
+	return m.Batch(ctx, storage.DeleteOperation(s))
+}
+
+func (m *mockStorageClient) Close(context.Context) error {
+	return nil
+}
+
+func (m *mockStorageClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	m.ext.mu.Lock()
+	defer m.ext.mu.Unlock()
+
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			op.Value = m.ext.data[op.Key]
+		case storage.Set:
+			m.ext.data[op.Key] = op.Value
+		case storage.Delete:
+			delete(m.ext.data, op.Key)
+		default:
+			return errors.New("wrong operation type")
+		}
+	}
+
+	return nil
+}
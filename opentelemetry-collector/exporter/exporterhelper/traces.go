@@ -23,9 +23,14 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumerhelper"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
+var tracesMarshaler = otlp.NewProtobufTracesMarshaler()
+var tracesUnmarshaler = otlp.NewProtobufTracesUnmarshaler()
+
 type tracesRequest struct {
 	baseRequest
 	td     pdata.Traces
@@ -40,6 +45,16 @@ func newTracesRequest(ctx context.Context, td pdata.Traces, pusher consumerhelpe
 	}
 }
 
+func newTracesRequestUnmarshalerFunc(pusher consumerhelper.ConsumeTracesFunc) internal.RequestUnmarshaler {
+	return func(bytes []byte) (internal.PersistentRequest, error) {
+		td, err := tracesUnmarshaler.UnmarshalTraces(bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newTracesRequest(context.Background(), td, pusher), nil
+	}
+}
+
 func (req *tracesRequest) onError(err error) request {
 	var traceError consumererror.Traces
 	if consumererror.AsTraces(err, &traceError) {
@@ -48,6 +63,11 @@ func (req *tracesRequest) onError(err error) request {
 	return req
 }
 
+// Marshal provides serialization capabilities required by persistent queue
+func (req *tracesRequest) Marshal() ([]byte, error) {
+	return tracesMarshaler.MarshalTraces(req.td)
+}
+
 func (req *tracesRequest) export(ctx context.Context) error {
 	return req.pusher(ctx, req.td)
 }
@@ -82,7 +102,7 @@ func NewTracesExporter(
 	}
 
 	bs := fromOptions(options...)
-	be := newBaseExporter(cfg, set, bs)
+	be := newBaseExporter(cfg, set, bs, config.TracesDataType, newTracesRequestUnmarshalerFunc(pusher))
 	be.wrapConsumerSender(func(nextSender requestSender) requestSender {
 		return &tracesExporterWithObservability{
 			obsrep:     be.obsrep,
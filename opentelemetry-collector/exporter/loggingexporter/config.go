@@ -15,9 +15,23 @@
 package loggingexporter
 
 import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+
 	"go.opentelemetry.io/collector/config"
 )
 
+const (
+	verbosityBasic    = "basic"
+	verbosityNormal   = "normal"
+	verbosityDetailed = "detailed"
+
+	encodingText = "text"
+	encodingJSON = "json"
+)
+
 // Config defines configuration for logging exporter.
 type Config struct {
 	config.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -30,11 +44,54 @@ type Config struct {
 
 	// SamplingThereafter defines the sampling rate after the initial samples are logged.
 	SamplingThereafter int `mapstructure:"sampling_thereafter"`
+
+	// Verbosity defines how much of the pipeline data is logged; options are basic, normal, detailed.
+	// When empty, it is detailed if LogLevel is debug and basic otherwise.
+	Verbosity string `mapstructure:"verbosity"`
+
+	// Encoding defines how data is logged at detailed verbosity; options are text, json.
+	// When empty, it is text.
+	Encoding string `mapstructure:"encoding"`
 }
 
 var _ config.Exporter = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
+	switch strings.ToLower(cfg.Verbosity) {
+	case "", verbosityBasic, verbosityNormal, verbosityDetailed:
+	default:
+		return fmt.Errorf("verbosity must be one of %s, %s or %s, got %q", verbosityBasic, verbosityNormal, verbosityDetailed, cfg.Verbosity)
+	}
+
+	switch strings.ToLower(cfg.Encoding) {
+	case "", encodingText:
+	case encodingJSON:
+		if cfg.verbosity() != verbosityDetailed {
+			return fmt.Errorf("encoding %s requires verbosity %s", encodingJSON, verbosityDetailed)
+		}
+	default:
+		return fmt.Errorf("encoding must be one of %s or %s, got %q", encodingText, encodingJSON, cfg.Encoding)
+	}
 	return nil
 }
+
+// verbosity returns the configured verbosity, falling back to the one implied by LogLevel.
+func (cfg *Config) verbosity() string {
+	if cfg.Verbosity != "" {
+		return strings.ToLower(cfg.Verbosity)
+	}
+	if strings.ToLower(cfg.LogLevel) == "debug" {
+		return verbosityDetailed
+	}
+	return verbosityBasic
+}
+
+// dataLevel returns the level the pipeline data is logged at: info when the verbosity is set,
+// debug when it is implied by LogLevel.
+func (cfg *Config) dataLevel() zapcore.Level {
+	if cfg.Verbosity != "" {
+		return zapcore.InfoLevel
+	}
+	return zapcore.DebugLevel
+}
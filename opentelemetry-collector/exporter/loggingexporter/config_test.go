@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
@@ -47,5 +48,59 @@ func TestLoadConfig(t *testing.T) {
 			LogLevel:           "debug",
 			SamplingInitial:    10,
 			SamplingThereafter: 50,
+			Encoding:           encodingText,
 		})
+
+	e2 := cfg.Exporters[config.NewIDWithName(typeStr, "3")]
+	assert.Equal(t, e2,
+		&Config{
+			ExporterSettings:   config.NewExporterSettings(config.NewIDWithName(typeStr, "3")),
+			LogLevel:           "info",
+			SamplingInitial:    defaultSamplingInitial,
+			SamplingThereafter: defaultSamplingThereafter,
+			Verbosity:          verbosityDetailed,
+			Encoding:           encodingJSON,
+		})
+}
+
+func TestConfigVerbosity(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, verbosityBasic, cfg.verbosity())
+
+	cfg.LogLevel = "DEBUG"
+	assert.Equal(t, verbosityDetailed, cfg.verbosity())
+	assert.Equal(t, zapcore.DebugLevel, cfg.dataLevel())
+
+	cfg.Verbosity = "Normal"
+	assert.Equal(t, verbosityNormal, cfg.verbosity())
+	assert.Equal(t, zapcore.InfoLevel, cfg.dataLevel())
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		verbosity string
+		encoding  string
+		wantErr   bool
+	}{
+		{name: "default", encoding: encodingText},
+		{name: "empty encoding", verbosity: verbosityDetailed},
+		{name: "normal", verbosity: verbosityNormal, encoding: encodingText},
+		{name: "detailed json", verbosity: verbosityDetailed, encoding: encodingJSON},
+		{name: "unknown verbosity", verbosity: "verbose", encoding: encodingText, wantErr: true},
+		{name: "unknown encoding", verbosity: verbosityDetailed, encoding: "yaml", wantErr: true},
+		{name: "json without detailed", verbosity: verbosityNormal, encoding: encodingJSON, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Verbosity = tt.verbosity
+			cfg.Encoding = tt.encoding
+			if tt.wantErr {
+				assert.Error(t, cfg.Validate())
+			} else {
+				assert.NoError(t, cfg.Validate())
+			}
+		})
+	}
 }
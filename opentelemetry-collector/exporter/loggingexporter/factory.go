@@ -16,6 +16,7 @@ package loggingexporter
 
 import (
 	"context"
+	"strings"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -48,6 +49,7 @@ func createDefaultConfig() config.Exporter {
 		LogLevel:           "info",
 		SamplingInitial:    defaultSamplingInitial,
 		SamplingThereafter: defaultSamplingThereafter,
+		Encoding:           encodingText,
 	}
 }
 
@@ -59,7 +61,7 @@ func createTracesExporter(_ context.Context, set component.ExporterCreateSetting
 		return nil, err
 	}
 
-	return newTracesExporter(config, cfg.LogLevel, exporterLogger, set)
+	return newTracesExporter(config, cfg.verbosity(), strings.ToLower(cfg.Encoding), cfg.dataLevel(), exporterLogger, set)
 }
 
 func createMetricsExporter(_ context.Context, set component.ExporterCreateSettings, config config.Exporter) (component.MetricsExporter, error) {
@@ -70,7 +72,7 @@ func createMetricsExporter(_ context.Context, set component.ExporterCreateSettin
 		return nil, err
 	}
 
-	return newMetricsExporter(config, cfg.LogLevel, exporterLogger, set)
+	return newMetricsExporter(config, cfg.verbosity(), strings.ToLower(cfg.Encoding), cfg.dataLevel(), exporterLogger, set)
 }
 
 func createLogsExporter(_ context.Context, set component.ExporterCreateSettings, config config.Exporter) (component.LogsExporter, error) {
@@ -81,7 +83,7 @@ func createLogsExporter(_ context.Context, set component.ExporterCreateSettings,
 		return nil, err
 	}
 
-	return newLogsExporter(config, cfg.LogLevel, exporterLogger, set)
+	return newLogsExporter(config, cfg.verbosity(), strings.ToLower(cfg.Encoding), cfg.dataLevel(), exporterLogger, set)
 }
 
 func createLogger(cfg *Config) (*zap.Logger, error) {
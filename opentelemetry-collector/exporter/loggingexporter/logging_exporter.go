@@ -17,21 +17,23 @@ package loggingexporter
 import (
 	"context"
 	"os"
-	"strings"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/internal/otlptext"
+	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
 type loggingExporter struct {
 	logger           *zap.Logger
-	debug            bool
+	verbosity        string
+	dataLevel        zapcore.Level
 	logsMarshaler    pdata.LogsMarshaler
 	metricsMarshaler pdata.MetricsMarshaler
 	tracesMarshaler  pdata.TracesMarshaler
@@ -40,7 +42,7 @@ type loggingExporter struct {
 func (s *loggingExporter) pushTraces(_ context.Context, td pdata.Traces) error {
 	s.logger.Info("TracesExporter", zap.Int("#spans", td.SpanCount()))
 
-	if !s.debug {
+	if !s.logsData() {
 		return nil
 	}
 
@@ -48,14 +50,14 @@ func (s *loggingExporter) pushTraces(_ context.Context, td pdata.Traces) error {
 	if err != nil {
 		return err
 	}
-	s.logger.Debug(string(buf))
+	s.logger.Check(s.dataLevel, string(buf)).Write()
 	return nil
 }
 
 func (s *loggingExporter) pushMetrics(_ context.Context, md pdata.Metrics) error {
 	s.logger.Info("MetricsExporter", zap.Int("#metrics", md.MetricCount()))
 
-	if !s.debug {
+	if !s.logsData() {
 		return nil
 	}
 
@@ -63,14 +65,14 @@ func (s *loggingExporter) pushMetrics(_ context.Context, md pdata.Metrics) error
 	if err != nil {
 		return err
 	}
-	s.logger.Debug(string(buf))
+	s.logger.Check(s.dataLevel, string(buf)).Write()
 	return nil
 }
 
 func (s *loggingExporter) pushLogs(_ context.Context, ld pdata.Logs) error {
 	s.logger.Info("LogsExporter", zap.Int("#logs", ld.LogRecordCount()))
 
-	if !s.debug {
+	if !s.logsData() {
 		return nil
 	}
 
@@ -78,24 +80,41 @@ func (s *loggingExporter) pushLogs(_ context.Context, ld pdata.Logs) error {
 	if err != nil {
 		return err
 	}
-	s.logger.Debug(string(buf))
+	s.logger.Check(s.dataLevel, string(buf)).Write()
 	return nil
 }
 
-func newLoggingExporter(level string, logger *zap.Logger) *loggingExporter {
-	return &loggingExporter{
-		debug:            strings.ToLower(level) == "debug",
+// logsData indicates whether the pipeline data is logged, so that it is not marshaled for nothing.
+func (s *loggingExporter) logsData() bool {
+	return s.verbosity != verbosityBasic && s.logger.Core().Enabled(s.dataLevel)
+}
+
+func newLoggingExporter(verbosity, encoding string, dataLevel zapcore.Level, logger *zap.Logger) *loggingExporter {
+	s := &loggingExporter{
+		verbosity:        verbosity,
+		dataLevel:        dataLevel,
 		logger:           logger,
 		logsMarshaler:    otlptext.NewTextLogsMarshaler(),
 		metricsMarshaler: otlptext.NewTextMetricsMarshaler(),
 		tracesMarshaler:  otlptext.NewTextTracesMarshaler(),
 	}
+	switch {
+	case verbosity == verbosityNormal:
+		s.logsMarshaler = otlptext.NewCompactLogsMarshaler()
+		s.metricsMarshaler = otlptext.NewCompactMetricsMarshaler()
+		s.tracesMarshaler = otlptext.NewCompactTracesMarshaler()
+	case encoding == encodingJSON:
+		s.logsMarshaler = otlp.NewJSONLogsMarshaler()
+		s.metricsMarshaler = otlp.NewJSONMetricsMarshaler()
+		s.tracesMarshaler = otlp.NewJSONTracesMarshaler()
+	}
+	return s
 }
 
 // newTracesExporter creates an exporter.TracesExporter that just drops the
 // received data and logs debugging messages.
-func newTracesExporter(config config.Exporter, level string, logger *zap.Logger, set component.ExporterCreateSettings) (component.TracesExporter, error) {
-	s := newLoggingExporter(level, logger)
+func newTracesExporter(config config.Exporter, verbosity, encoding string, dataLevel zapcore.Level, logger *zap.Logger, set component.ExporterCreateSettings) (component.TracesExporter, error) {
+	s := newLoggingExporter(verbosity, encoding, dataLevel, logger)
 	return exporterhelper.NewTracesExporter(
 		config,
 		set,
@@ -111,8 +130,8 @@ func newTracesExporter(config config.Exporter, level string, logger *zap.Logger,
 
 // newMetricsExporter creates an exporter.MetricsExporter that just drops the
 // received data and logs debugging messages.
-func newMetricsExporter(config config.Exporter, level string, logger *zap.Logger, set component.ExporterCreateSettings) (component.MetricsExporter, error) {
-	s := newLoggingExporter(level, logger)
+func newMetricsExporter(config config.Exporter, verbosity, encoding string, dataLevel zapcore.Level, logger *zap.Logger, set component.ExporterCreateSettings) (component.MetricsExporter, error) {
+	s := newLoggingExporter(verbosity, encoding, dataLevel, logger)
 	return exporterhelper.NewMetricsExporter(
 		config,
 		set,
@@ -128,8 +147,8 @@ func newMetricsExporter(config config.Exporter, level string, logger *zap.Logger
 
 // newLogsExporter creates an exporter.LogsExporter that just drops the
 // received data and logs debugging messages.
-func newLogsExporter(config config.Exporter, level string, logger *zap.Logger, set component.ExporterCreateSettings) (component.LogsExporter, error) {
-	s := newLoggingExporter(level, logger)
+func newLogsExporter(config config.Exporter, verbosity, encoding string, dataLevel zapcore.Level, logger *zap.Logger, set component.ExporterCreateSettings) (component.LogsExporter, error) {
+	s := newLoggingExporter(verbosity, encoding, dataLevel, logger)
 	return exporterhelper.NewLogsExporter(
 		config,
 		set,
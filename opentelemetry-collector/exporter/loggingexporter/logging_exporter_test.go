@@ -21,6 +21,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
@@ -29,7 +32,7 @@ import (
 )
 
 func TestLoggingTracesExporterNoErrors(t *testing.T) {
-	lte, err := newTracesExporter(&config.ExporterSettings{}, "Debug", zap.NewNop(), componenttest.NewNopExporterCreateSettings())
+	lte, err := newTracesExporter(&config.ExporterSettings{}, verbosityDetailed, encodingText, zapcore.DebugLevel, zap.NewNop(), componenttest.NewNopExporterCreateSettings())
 	require.NotNil(t, lte)
 	assert.NoError(t, err)
 
@@ -40,7 +43,7 @@ func TestLoggingTracesExporterNoErrors(t *testing.T) {
 }
 
 func TestLoggingMetricsExporterNoErrors(t *testing.T) {
-	lme, err := newMetricsExporter(&config.ExporterSettings{}, "DEBUG", zap.NewNop(), componenttest.NewNopExporterCreateSettings())
+	lme, err := newMetricsExporter(&config.ExporterSettings{}, verbosityDetailed, encodingText, zapcore.DebugLevel, zap.NewNop(), componenttest.NewNopExporterCreateSettings())
 	require.NotNil(t, lme)
 	assert.NoError(t, err)
 
@@ -53,7 +56,7 @@ func TestLoggingMetricsExporterNoErrors(t *testing.T) {
 }
 
 func TestLoggingLogsExporterNoErrors(t *testing.T) {
-	lle, err := newLogsExporter(&config.ExporterSettings{}, "debug", zap.NewNop(), componenttest.NewNopExporterCreateSettings())
+	lle, err := newLogsExporter(&config.ExporterSettings{}, verbosityDetailed, encodingText, zapcore.DebugLevel, zap.NewNop(), componenttest.NewNopExporterCreateSettings())
 	require.NotNil(t, lle)
 	assert.NoError(t, err)
 
@@ -66,7 +69,7 @@ func TestLoggingLogsExporterNoErrors(t *testing.T) {
 }
 
 func TestLoggingExporterErrors(t *testing.T) {
-	le := newLoggingExporter("Debug", zap.NewNop())
+	le := newLoggingExporter(verbosityDetailed, encodingText, zapcore.DebugLevel, zaptest.NewLogger(t))
 	require.NotNil(t, le)
 
 	errWant := errors.New("my error")
@@ -93,3 +96,43 @@ func (e errMarshaler) MarshalMetrics(pdata.Metrics) ([]byte, error) {
 func (e errMarshaler) MarshalTraces(pdata.Traces) ([]byte, error) {
 	return nil, e.err
 }
+
+func TestLoggingExporterVerbosity(t *testing.T) {
+	tests := []struct {
+		name        string
+		verbosity   string
+		encoding    string
+		dataLevel   zapcore.Level
+		loggerLevel zapcore.Level
+		contains    string
+	}{
+		{name: "basic", verbosity: verbosityBasic, encoding: encodingText, dataLevel: zapcore.InfoLevel, loggerLevel: zapcore.DebugLevel},
+		{name: "normal", verbosity: verbosityNormal, encoding: encodingText, dataLevel: zapcore.InfoLevel, loggerLevel: zapcore.InfoLevel, contains: "Trace "},
+		{name: "detailed", verbosity: verbosityDetailed, encoding: encodingText, dataLevel: zapcore.InfoLevel, loggerLevel: zapcore.InfoLevel, contains: "ResourceSpans #0"},
+		{name: "detailed json", verbosity: verbosityDetailed, encoding: encodingJSON, dataLevel: zapcore.InfoLevel, loggerLevel: zapcore.InfoLevel, contains: `{"resourceSpans":`},
+		{name: "detailed debug", verbosity: verbosityDetailed, encoding: encodingText, dataLevel: zapcore.DebugLevel, loggerLevel: zapcore.DebugLevel, contains: "ResourceSpans #0"},
+		{name: "detailed debug disabled", verbosity: verbosityDetailed, encoding: encodingText, dataLevel: zapcore.DebugLevel, loggerLevel: zapcore.InfoLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(tt.loggerLevel)
+			le := newLoggingExporter(tt.verbosity, tt.encoding, tt.dataLevel, zap.New(core))
+			if tt.contains == "" {
+				// The data which is not logged must not be marshaled.
+				le.tracesMarshaler = &errMarshaler{err: errors.New("marshaled")}
+			}
+
+			require.NoError(t, le.pushTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+			entries := logs.All()
+			require.NotEmpty(t, entries)
+			assert.Equal(t, "TracesExporter", entries[0].Message)
+			if tt.contains == "" {
+				assert.Len(t, entries, 1)
+				return
+			}
+			require.Len(t, entries, 2)
+			assert.Equal(t, tt.dataLevel, entries[1].Level)
+			assert.Contains(t, entries[1].Message, tt.contains)
+		})
+	}
+}
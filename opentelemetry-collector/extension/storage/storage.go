@@ -16,11 +16,19 @@ package storage
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 )
 
+var (
+	// ErrNoStorageClient is returned by GetClient when the host has no extension with the requested ID.
+	ErrNoStorageClient = errors.New("no storage client extension found")
+	// ErrWrongExtensionType is returned by GetClient when the requested extension is not a storage Extension.
+	ErrWrongExtensionType = errors.New("requested extension is not a storage extension")
+)
+
 // Extension is the interface that storage extensions must implement
 type Extension interface {
 	component.Extension
@@ -32,6 +40,22 @@ type Extension interface {
 	GetClient(ctx context.Context, kind component.Kind, id config.ComponentID, storageName string) (Client, error)
 }
 
+// GetClient returns the client of the storage extension with the given ID of the host,
+// for use by the component of the given kind and ID.
+func GetClient(ctx context.Context, host component.Host, storageID config.ComponentID, kind component.Kind, id config.ComponentID, storageName string) (Client, error) {
+	ext, found := host.GetExtensions()[storageID]
+	if !found {
+		return nil, ErrNoStorageClient
+	}
+
+	storageExt, ok := ext.(Extension)
+	if !ok {
+		return nil, ErrWrongExtensionType
+	}
+
+	return storageExt.GetClient(ctx, kind, id, storageName)
+}
+
 // Client is the interface that storage clients must implement
 // All methods should return error only if a problem occurred.
 // This mirrors the behavior of a golang map:
@@ -18,23 +18,120 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"go.opentelemetry.io/collector/config/configcompression"
 )
 
 const (
 	headerContentEncoding = "Content-Encoding"
-	headerValueGZIP       = "gzip"
 )
 
+// codec creates the writers and readers for a compression type.
+type codec struct {
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+	newReader func(r io.Reader) (io.ReadCloser, error)
+}
+
+// codecs is the registry of the supported compression types, shared by the HTTP
+// client round tripper, the HTTP server decompressor and the gRPC compressors.
+var codecs = map[string]codec{
+	configcompression.Gzip: {
+		newWriter: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+		newReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	},
+	configcompression.Zlib: {
+		newWriter: newZlibWriter,
+		newReader: zlib.NewReader,
+	},
+	configcompression.Deflate: {
+		newWriter: newZlibWriter,
+		newReader: zlib.NewReader,
+	},
+	configcompression.Snappy: {
+		newWriter: func(w io.Writer) (io.WriteCloser, error) { return snappy.NewBufferedWriter(w), nil },
+		newReader: func(r io.Reader) (io.ReadCloser, error) { return ioutil.NopCloser(snappy.NewReader(r)), nil },
+	},
+	configcompression.Zstd: {
+		newWriter: func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1)) },
+		newReader: func(r io.Reader) (io.ReadCloser, error) {
+			dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			return &zstdReadCloser{Decoder: dec}, nil
+		},
+	},
+}
+
+func newZlibWriter(w io.Writer) (io.WriteCloser, error) {
+	return zlib.NewWriter(w), nil
+}
+
+// zstdReadCloser releases the resources of the decoder when closed or when the stream ends,
+// so that callers that only read until io.EOF do not leak the decoder goroutines.
+type zstdReadCloser struct {
+	*zstd.Decoder
+}
+
+func (z *zstdReadCloser) Read(p []byte) (int, error) {
+	n, err := z.Decoder.Read(p)
+	if err == io.EOF {
+		z.Decoder.Close()
+	}
+	return n, err
+}
+
+func (z *zstdReadCloser) Close() error {
+	z.Decoder.Close()
+	return nil
+}
+
+// IsSupportedCompression returns true if the compression type, case-insensitive, is supported.
+func IsSupportedCompression(compressionType string) bool {
+	_, ok := codecs[strings.ToLower(compressionType)]
+	return ok
+}
+
+// NewCompressionWriter returns a writer compressing to w using the given compression type.
+func NewCompressionWriter(compressionType string, w io.Writer) (io.WriteCloser, error) {
+	c, ok := codecs[strings.ToLower(compressionType)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression type %q", compressionType)
+	}
+	return c.newWriter(w)
+}
+
+// NewDecompressionReader returns a reader decompressing r using the given compression type.
+func NewDecompressionReader(compressionType string, r io.Reader) (io.ReadCloser, error) {
+	c, ok := codecs[strings.ToLower(compressionType)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression type %q", compressionType)
+	}
+	return c.newReader(r)
+}
+
 type CompressRoundTripper struct {
 	http.RoundTripper
+	compressionType string
 }
 
-func NewCompressRoundTripper(rt http.RoundTripper) *CompressRoundTripper {
-	return &CompressRoundTripper{
-		RoundTripper: rt,
+// NewCompressRoundTripper returns a round tripper compressing the request bodies with the given compression type.
+func NewCompressRoundTripper(rt http.RoundTripper, compressionType string) (*CompressRoundTripper, error) {
+	if !IsSupportedCompression(compressionType) {
+		return nil, fmt.Errorf("unsupported compression type %q", compressionType)
 	}
+	return &CompressRoundTripper{
+		RoundTripper:    rt,
+		compressionType: strings.ToLower(compressionType),
+	}, nil
 }
 
 func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -47,13 +144,16 @@ func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 		return r.RoundTripper.RoundTrip(req)
 	}
 
-	// Gzip the body.
+	// Compress the body.
 	buf := bytes.NewBuffer([]byte{})
-	gzipWriter := gzip.NewWriter(buf)
-	_, copyErr := io.Copy(gzipWriter, req.Body)
+	compressWriter, err := NewCompressionWriter(r.compressionType, buf)
+	if err != nil {
+		return nil, err
+	}
+	_, copyErr := io.Copy(compressWriter, req.Body)
 	closeErr := req.Body.Close()
 
-	if err := gzipWriter.Close(); err != nil {
+	if err = compressWriter.Close(); err != nil {
 		return nil, err
 	}
 
@@ -71,9 +171,9 @@ func (r *CompressRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 		return nil, err
 	}
 
-	// Clone the headers and add gzip encoding header.
+	// Clone the headers and add the encoding header.
 	cReq.Header = req.Header.Clone()
-	cReq.Header.Add(headerContentEncoding, headerValueGZIP)
+	cReq.Header.Add(headerContentEncoding, r.compressionType)
 
 	return r.RoundTripper.RoundTrip(cReq)
 }
@@ -95,7 +195,7 @@ func WithErrorHandler(e ErrorHandler) DecompressorOption {
 // HTTPContentDecompressor is a middleware that offloads the task of handling compressed
 // HTTP requests by identifying the compression format in the "Content-Encoding" header and re-writing
 // request body so that the handlers further in the chain can work on decompressed data.
-// It supports gzip and deflate/zlib compression.
+// It supports all the compression types in the registry: gzip, deflate/zlib, snappy and zstd.
 func HTTPContentDecompressor(h http.Handler, opts ...DecompressorOption) http.Handler {
 	d := &decompressor{}
 	for _, o := range opts {
@@ -129,21 +229,11 @@ func (d *decompressor) wrap(h http.Handler) http.Handler {
 }
 
 func newBodyReader(r *http.Request) (io.ReadCloser, error) {
-	switch r.Header.Get("Content-Encoding") {
-	case "gzip":
-		gr, err := gzip.NewReader(r.Body)
-		if err != nil {
-			return nil, err
-		}
-		return gr, nil
-	case "deflate", "zlib":
-		zr, err := zlib.NewReader(r.Body)
-		if err != nil {
-			return nil, err
-		}
-		return zr, nil
+	encoding := r.Header.Get(headerContentEncoding)
+	if !IsSupportedCompression(encoding) {
+		return nil, nil
 	}
-	return nil, nil
+	return NewDecompressionReader(encoding, r.Body)
 }
 
 // defaultErrorHandler writes the error message in plain text.
@@ -19,12 +19,15 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -33,30 +36,50 @@ import (
 
 func TestHTTPClientCompression(t *testing.T) {
 	testBody := []byte("uncompressed_text")
-	compressedBody, _ := compressGzip(testBody)
 
 	tests := []struct {
 		name     string
 		encoding string
-		reqBody  []byte
 	}{
 		{
 			name:     "NoCompression",
 			encoding: "",
-			reqBody:  testBody,
 		},
 		{
 			name:     "ValidGzip",
 			encoding: "gzip",
-			reqBody:  compressedBody.Bytes(),
+		},
+		{
+			name:     "ValidZlib",
+			encoding: "zlib",
+		},
+		{
+			name:     "ValidDeflate",
+			encoding: "deflate",
+		},
+		{
+			name:     "ValidSnappy",
+			encoding: "snappy",
+		},
+		{
+			name:     "ValidZstd",
+			encoding: "zstd",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				body, err := ioutil.ReadAll(r.Body)
+				assert.Equal(t, tt.encoding, r.Header.Get("Content-Encoding"))
+				body := io.Reader(r.Body)
+				if tt.encoding != "" {
+					dr, err := NewDecompressionReader(tt.encoding, r.Body)
+					require.NoError(t, err)
+					defer dr.Close()
+					body = dr
+				}
+				content, err := ioutil.ReadAll(body)
 				require.NoError(t, err, "failed to read request body: %v", err)
-				assert.EqualValues(t, tt.reqBody, body)
+				assert.EqualValues(t, testBody, content)
 				w.WriteHeader(200)
 			})
 
@@ -79,8 +102,9 @@ func TestHTTPClientCompression(t *testing.T) {
 			require.NoError(t, err, "failed to create request to test handler")
 
 			client := http.Client{}
-			if tt.encoding == "gzip" {
-				client.Transport = NewCompressRoundTripper(http.DefaultTransport)
+			if tt.encoding != "" {
+				client.Transport, err = NewCompressRoundTripper(http.DefaultTransport, tt.encoding)
+				require.NoError(t, err)
 			}
 			res, err := client.Do(req)
 			require.NoError(t, err)
@@ -93,6 +117,47 @@ func TestHTTPClientCompression(t *testing.T) {
 	}
 }
 
+func TestNewCompressRoundTripperUnsupported(t *testing.T) {
+	_, err := NewCompressRoundTripper(http.DefaultTransport, "lz4")
+	assert.EqualError(t, err, `unsupported compression type "lz4"`)
+	assert.False(t, IsSupportedCompression("lz4"))
+	assert.True(t, IsSupportedCompression("ZSTD"))
+}
+
+func TestDecompressionReaderInvalidInput(t *testing.T) {
+	for encoding := range codecs {
+		t.Run(encoding, func(t *testing.T) {
+			r, err := NewDecompressionReader(encoding, bytes.NewBufferString("uncompressed_text"))
+			if err == nil {
+				_, err = ioutil.ReadAll(r)
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	testBody := bytes.Repeat([]byte("uncompressed_text"), 1000)
+	for encoding := range codecs {
+		t.Run(encoding, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewCompressionWriter(encoding, &buf)
+			require.NoError(t, err)
+			_, err = w.Write(testBody)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			assert.Less(t, buf.Len(), len(testBody))
+
+			r, err := NewDecompressionReader(encoding, &buf)
+			require.NoError(t, err)
+			content, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, testBody, content)
+		})
+	}
+}
+
 func TestHTTPContentDecompressionHandler(t *testing.T) {
 	testBody := []byte("uncompressed_text")
 	tests := []struct {
@@ -126,6 +191,38 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 			},
 			respCode: 200,
 		},
+		{
+			name:     "ValidDeflate",
+			encoding: "deflate",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return compressZlib(testBody)
+			},
+			respCode: 200,
+		},
+		{
+			name:     "ValidSnappy",
+			encoding: "snappy",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return compressSnappy(testBody)
+			},
+			respCode: 200,
+		},
+		{
+			name:     "ValidZstd",
+			encoding: "zstd",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return compressZstd(testBody)
+			},
+			respCode: 200,
+		},
+		{
+			name:     "UnknownEncodingPassedThrough",
+			encoding: "identity",
+			reqBodyFunc: func() (*bytes.Buffer, error) {
+				return bytes.NewBuffer(testBody), nil
+			},
+			respCode: 200,
+		},
 		{
 			name:     "InvalidGzip",
 			encoding: "gzip",
@@ -217,3 +314,34 @@ func compressZlib(body []byte) (*bytes.Buffer, error) {
 
 	return &buf, nil
 }
+
+func compressSnappy(body []byte) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+
+	sw := snappy.NewBufferedWriter(&buf)
+	defer sw.Close()
+
+	_, err := sw.Write(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+func compressZstd(body []byte) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+
+	zw, err := zstd.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	defer zw.Close()
+
+	_, err = zw.Write(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
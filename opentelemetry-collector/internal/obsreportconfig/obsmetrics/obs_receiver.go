@@ -43,6 +43,9 @@ const (
 	// RefusedLogRecordsKey used to identify log records refused (ie.: not ingested) by the
 	// Collector.
 	RefusedLogRecordsKey = "refused_log_records"
+
+	// RateLimitedRequestsKey used to identify requests refused by the rate limits of a receiver.
+	RateLimitedRequestsKey = "rate_limited_requests"
 )
 
 var (
@@ -83,4 +86,8 @@ var (
 		ReceiverPrefix+RefusedLogRecordsKey,
 		"Number of log records that could not be pushed into the pipeline.",
 		stats.UnitDimensionless)
+	ReceiverRateLimitedRequests = stats.Int64(
+		ReceiverPrefix+RateLimitedRequestsKey,
+		"Number of requests refused because the client exceeded the receiver rate limits.",
+		stats.UnitDimensionless)
 )
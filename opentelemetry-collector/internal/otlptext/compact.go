@@ -0,0 +1,290 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptext
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+	conventions "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+)
+
+// NewCompactTracesMarshaler returns a pdata.TracesMarshaler that encodes to
+// one line per span, grouped by trace and indented to show the span tree.
+func NewCompactTracesMarshaler() pdata.TracesMarshaler {
+	return compactTracesMarshaler{}
+}
+
+// NewCompactMetricsMarshaler returns a pdata.MetricsMarshaler that encodes
+// to one line per data point.
+func NewCompactMetricsMarshaler() pdata.MetricsMarshaler {
+	return compactMetricsMarshaler{}
+}
+
+// NewCompactLogsMarshaler returns a pdata.LogsMarshaler that encodes to one
+// line per log record.
+func NewCompactLogsMarshaler() pdata.LogsMarshaler {
+	return compactLogsMarshaler{}
+}
+
+type compactTracesMarshaler struct{}
+
+// spanNode is a span with the children found for it in the same batch.
+type spanNode struct {
+	span     pdata.Span
+	service  string
+	children []*spanNode
+	printed  bool
+}
+
+// MarshalTraces pdata.Traces to compact text.
+func (compactTracesMarshaler) MarshalTraces(td pdata.Traces) ([]byte, error) {
+	var traceIDs []pdata.TraceID
+	traces := map[pdata.TraceID][]*spanNode{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		service := serviceName(rs.Resource())
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if _, ok := traces[span.TraceID()]; !ok {
+					traceIDs = append(traceIDs, span.TraceID())
+				}
+				traces[span.TraceID()] = append(traces[span.TraceID()], &spanNode{span: span, service: service})
+			}
+		}
+	}
+
+	buf := dataBuffer{}
+	for _, traceID := range traceIDs {
+		nodes := traces[traceID]
+		buf.logEntry("Trace %s", traceID.HexString())
+		for _, root := range buildSpanTree(nodes) {
+			buf.logSpanTree(root, 1)
+		}
+		// Spans whose parent chain loops back on itself are never reached
+		// from a root, print them at the top level rather than losing them.
+		for _, n := range nodes {
+			if !n.printed {
+				buf.logSpanTree(n, 1)
+			}
+		}
+	}
+	return buf.buf.Bytes(), nil
+}
+
+// buildSpanTree links the nodes of one trace to their parents and returns
+// the roots: spans without a parent or whose parent is not in the batch.
+func buildSpanTree(nodes []*spanNode) []*spanNode {
+	byID := make(map[pdata.SpanID]*spanNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.span.SpanID()] = n
+	}
+
+	var roots []*spanNode
+	for _, n := range nodes {
+		parentID := n.span.ParentSpanID()
+		if parent, ok := byID[parentID]; ok && !parentID.IsEmpty() && parent != n {
+			parent.children = append(parent.children, n)
+			continue
+		}
+		roots = append(roots, n)
+	}
+
+	sortByStart(roots)
+	for _, n := range nodes {
+		sortByStart(n.children)
+	}
+	return roots
+}
+
+func sortByStart(nodes []*spanNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].span.StartTimestamp() < nodes[j].span.StartTimestamp()
+	})
+}
+
+func (b *dataBuffer) logSpanTree(n *spanNode, depth int) {
+	if n.printed {
+		return
+	}
+	n.printed = true
+
+	span := n.span
+	var line strings.Builder
+	line.WriteString(strings.Repeat("  ", depth))
+	line.WriteString(span.Name())
+	if n.service != "" {
+		line.WriteString(" [" + n.service + "]")
+	}
+	line.WriteString(" " + spanKindString(span.Kind()))
+	line.WriteString(" " + span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()).String())
+	line.WriteString(" span=" + span.SpanID().HexString())
+	if code := span.Status().Code(); code != pdata.StatusCodeUnset {
+		line.WriteString(" status=" + statusCodeString(code))
+		if msg := span.Status().Message(); msg != "" {
+			line.WriteString(" " + strconv.Quote(msg))
+		}
+	}
+	b.logEntry("%s", line.String())
+
+	for _, child := range n.children {
+		b.logSpanTree(child, depth+1)
+	}
+}
+
+func spanKindString(kind pdata.SpanKind) string {
+	return strings.ToLower(strings.TrimPrefix(kind.String(), "SPAN_KIND_"))
+}
+
+func statusCodeString(code pdata.StatusCode) string {
+	return strings.ToLower(strings.TrimPrefix(code.String(), "STATUS_CODE_"))
+}
+
+type compactMetricsMarshaler struct{}
+
+// MarshalMetrics pdata.Metrics to compact text.
+func (compactMetricsMarshaler) MarshalMetrics(md pdata.Metrics) ([]byte, error) {
+	buf := dataBuffer{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		service := serviceName(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			metrics := ilms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				buf.logCompactMetric(metrics.At(k), service)
+			}
+		}
+	}
+	return buf.buf.Bytes(), nil
+}
+
+func (b *dataBuffer) logCompactMetric(m pdata.Metric, service string) {
+	suffix := ""
+	if service != "" {
+		suffix = " [" + service + "]"
+	}
+
+	switch m.DataType() {
+	case pdata.MetricDataTypeGauge:
+		b.logCompactNumberDataPoints(m.Name(), m.Gauge().DataPoints(), suffix)
+	case pdata.MetricDataTypeSum:
+		b.logCompactNumberDataPoints(m.Name(), m.Sum().DataPoints(), suffix)
+	case pdata.MetricDataTypeHistogram:
+		ps := m.Histogram().DataPoints()
+		for i := 0; i < ps.Len(); i++ {
+			p := ps.At(i)
+			b.logEntry("%s%s count=%d sum=%s%s", m.Name(), attributesToString(p.Attributes()),
+				p.Count(), strconv.FormatFloat(p.Sum(), 'f', -1, 64), suffix)
+		}
+	case pdata.MetricDataTypeSummary:
+		ps := m.Summary().DataPoints()
+		for i := 0; i < ps.Len(); i++ {
+			p := ps.At(i)
+			b.logEntry("%s%s count=%d sum=%s%s", m.Name(), attributesToString(p.Attributes()),
+				p.Count(), strconv.FormatFloat(p.Sum(), 'f', -1, 64), suffix)
+		}
+	default:
+		b.logEntry("%s %s%s", m.Name(), m.DataType().String(), suffix)
+	}
+}
+
+func (b *dataBuffer) logCompactNumberDataPoints(name string, ps pdata.NumberDataPointSlice, suffix string) {
+	for i := 0; i < ps.Len(); i++ {
+		p := ps.At(i)
+		value := ""
+		switch p.Type() {
+		case pdata.MetricValueTypeInt:
+			value = " " + strconv.FormatInt(p.IntVal(), 10)
+		case pdata.MetricValueTypeDouble:
+			value = " " + strconv.FormatFloat(p.DoubleVal(), 'f', -1, 64)
+		}
+		b.logEntry("%s%s%s%s", name, attributesToString(p.Attributes()), value, suffix)
+	}
+}
+
+type compactLogsMarshaler struct{}
+
+// MarshalLogs pdata.Logs to compact text.
+func (compactLogsMarshaler) MarshalLogs(ld pdata.Logs) ([]byte, error) {
+	buf := dataBuffer{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		service := serviceName(rl.Resource())
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				var line strings.Builder
+				line.WriteString(lr.Timestamp().String())
+				if severity := lr.SeverityText(); severity != "" {
+					line.WriteString(" " + severity)
+				}
+				if service != "" {
+					line.WriteString(" [" + service + "]")
+				}
+				line.WriteString(" " + strconv.Quote(lr.Body().AsString()))
+				if attrs := attributesToString(lr.Attributes()); attrs != "" {
+					line.WriteString(" " + attrs)
+				}
+				buf.logEntry("%s", line.String())
+			}
+		}
+	}
+	return buf.buf.Bytes(), nil
+}
+
+// attributesToString formats the attributes as {k=v, ...} sorted by key,
+// or returns an empty string when there are none.
+func attributesToString(am pdata.AttributeMap) string {
+	if am.Len() == 0 {
+		return ""
+	}
+	keys := make([]string, 0, am.Len())
+	values := make(map[string]string, am.Len())
+	am.Range(func(k string, v pdata.AttributeValue) bool {
+		keys = append(keys, k)
+		values[k] = v.AsString()
+		return true
+	})
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(k + "=" + strconv.Quote(values[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func serviceName(res pdata.Resource) string {
+	if v, ok := res.Attributes().Get(conventions.AttributeServiceName); ok {
+		return v.AsString()
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlptext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestCompactTracesTree(t *testing.T) {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "frontend")
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	traceID := pdata.NewTraceID([16]byte{1})
+
+	child := spans.AppendEmpty()
+	child.SetTraceID(traceID)
+	child.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	child.SetParentSpanID(pdata.NewSpanID([8]byte{1}))
+	child.SetName("SELECT users")
+	child.SetKind(pdata.SpanKindClient)
+	child.SetStartTimestamp(pdata.Timestamp(1000))
+	child.SetEndTimestamp(pdata.Timestamp(3000))
+	child.Status().SetCode(pdata.StatusCodeError)
+	child.Status().SetMessage("timeout")
+
+	root := spans.AppendEmpty()
+	root.SetTraceID(traceID)
+	root.SetSpanID(pdata.NewSpanID([8]byte{1}))
+	root.SetName("GET /users")
+	root.SetKind(pdata.SpanKindServer)
+	root.SetStartTimestamp(pdata.Timestamp(0))
+	root.SetEndTimestamp(pdata.Timestamp(5000))
+
+	buf, err := NewCompactTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Trace 01000000000000000000000000000000",
+		"  GET /users [frontend] server 5µs span=0100000000000000",
+		"    SELECT users [frontend] client 2µs span=0200000000000000 status=error \"timeout\"",
+	}, strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n"))
+}
+
+func TestCompactTracesParentCycle(t *testing.T) {
+	td := pdata.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+	for i := byte(1); i <= 2; i++ {
+		span := spans.AppendEmpty()
+		span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{i}))
+		span.SetParentSpanID(pdata.NewSpanID([8]byte{3 - i}))
+	}
+
+	buf, err := NewCompactTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(buf), "\n"))
+}
+
+func TestCompactMetrics(t *testing.T) {
+	tests := []struct {
+		name  string
+		md    pdata.Metrics
+		empty bool
+	}{
+		{"empty metrics", pdata.NewMetrics(), true},
+		{"metrics with all types and datapoints", testdata.GeneratMetricsAllTypesWithSampleDatapoints(), false},
+		{"metrics with all types without datapoints", testdata.GenerateMetricsAllTypesEmptyDataPoint(), false},
+		{"metrics with invalid metric types", testdata.GenerateMetricsMetricTypeInvalid(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := NewCompactMetricsMarshaler().MarshalMetrics(tt.md)
+			assert.NoError(t, err)
+			if tt.empty {
+				assert.Empty(t, buf)
+			} else {
+				assert.NotEmpty(t, buf)
+			}
+		})
+	}
+}
+
+func TestCompactLogs(t *testing.T) {
+	ld := pdata.NewLogs()
+	lr := ld.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs().AppendEmpty()
+	lr.SetSeverityText("Info")
+	lr.Body().SetStringVal("hello world")
+	lr.Attributes().InsertString("user", "bob")
+
+	buf, err := NewCompactLogsMarshaler().MarshalLogs(ld)
+	require.NoError(t, err)
+	assert.Equal(t, "1970-01-01 00:00:00 +0000 UTC Info \"hello world\" {user=\"bob\"}\n", string(buf))
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retention lets the components keeping data after their consumer
+// returned, like a batch or a queue, tell the components upstream when that
+// data is released.
+package retention
+
+import (
+	"context"
+	"sync"
+)
+
+// Holder is interested in the data sent along with a context until it is released.
+type Holder interface {
+	// Retain is called by a component keeping the data after it returned, the
+	// returned function must be called once the data is released.
+	Retain() (release func())
+}
+
+type holderKey struct{}
+
+// NewContext returns a context carrying the Holder.
+func NewContext(ctx context.Context, h Holder) context.Context {
+	if h == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, holderKey{}, h)
+}
+
+// FromContext returns the Holder carried by the context, if any.
+func FromContext(ctx context.Context) Holder {
+	h, _ := ctx.Value(holderKey{}).(Holder)
+	return h
+}
+
+// Retain retains the data sent along with the context for all the holders it carries.
+// The returned function releases it and is safe to call more than once.
+func Retain(ctx context.Context) func() {
+	h := FromContext(ctx)
+	if h == nil {
+		return func() {}
+	}
+	return Once(h.Retain())
+}
+
+type holders []Holder
+
+func (hs holders) Retain() func() {
+	releases := make([]func(), len(hs))
+	for i, h := range hs {
+		releases[i] = h.Retain()
+	}
+	return func() {
+		for _, release := range releases {
+			release()
+		}
+	}
+}
+
+// Combine returns a Holder retaining the data for all the given holders, the nil ones are ignored.
+func Combine(hs ...Holder) Holder {
+	var combined holders
+	for _, h := range hs {
+		switch h := h.(type) {
+		case nil:
+		case holders:
+			combined = append(combined, h...)
+		default:
+			combined = append(combined, h)
+		}
+	}
+	switch len(combined) {
+	case 0:
+		return nil
+	case 1:
+		return combined[0]
+	}
+	return combined
+}
+
+// Once returns a function calling release only the first time it is called.
+func Once(release func()) func() {
+	var once sync.Once
+	return func() { once.Do(release) }
+}
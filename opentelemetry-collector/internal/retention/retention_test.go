@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/internal/retention/retentiontest"
+)
+
+func TestRetainWithoutHolder(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+	assert.Equal(t, context.Background(), NewContext(context.Background(), nil))
+	Retain(context.Background())()
+}
+
+func TestRetain(t *testing.T) {
+	h := &retentiontest.CountingHolder{}
+	ctx := NewContext(context.Background(), h)
+	assert.Same(t, h, FromContext(ctx))
+
+	release := Retain(ctx)
+	assert.EqualValues(t, 1, h.Held())
+	release()
+	release()
+	assert.EqualValues(t, 0, h.Held())
+}
+
+func TestCombine(t *testing.T) {
+	assert.Nil(t, Combine())
+	assert.Nil(t, Combine(nil, nil))
+
+	h1 := &retentiontest.CountingHolder{}
+	assert.Same(t, h1, Combine(nil, h1))
+
+	h2 := &retentiontest.CountingHolder{}
+	h3 := &retentiontest.CountingHolder{}
+	combined := Combine(Combine(h1, h2), nil, h3)
+	assert.Len(t, combined, 3)
+
+	release := combined.Retain()
+	assert.Equal(t, []int64{1, 1, 1}, []int64{h1.Held(), h2.Held(), h3.Held()})
+	release()
+	assert.Equal(t, []int64{0, 0, 0}, []int64{h1.Held(), h2.Held(), h3.Held()})
+}
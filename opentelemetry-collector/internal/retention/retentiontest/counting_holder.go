@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retentiontest provides helpers to test the components retaining data.
+package retentiontest
+
+import (
+	"sync/atomic"
+)
+
+// CountingHolder is a retention.Holder counting the retentions of the data.
+type CountingHolder struct {
+	retained int64
+	released int64
+}
+
+// Retain records a retention until the returned function is called.
+func (ch *CountingHolder) Retain() func() {
+	atomic.AddInt64(&ch.retained, 1)
+	return func() { atomic.AddInt64(&ch.released, 1) }
+}
+
+// Retained returns the number of retentions, released or not.
+func (ch *CountingHolder) Retained() int64 {
+	return atomic.LoadInt64(&ch.retained)
+}
+
+// Held returns the number of retentions which were not released yet.
+func (ch *CountingHolder) Held() int64 {
+	return atomic.LoadInt64(&ch.retained) - atomic.LoadInt64(&ch.released)
+}
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testcomponents
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/connector/connectorhelper"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// ExampleConnectorCfg is for testing purposes. We are defining an example config and factory
+// for "exampleconnector" connector type.
+type ExampleConnectorCfg struct {
+	config.ConnectorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+	ExtraSetting             string                   `mapstructure:"extra"`
+}
+
+const connType = "exampleconnector"
+
+// ExampleConnectorFactory is factory for ExampleConnector.
+var ExampleConnectorFactory = connectorhelper.NewFactory(
+	connType,
+	createConnectorDefaultConfig,
+	connectorhelper.WithTracesToTraces(createTracesToTracesConnector),
+	connectorhelper.WithTracesToMetrics(createTracesToMetricsConnector),
+	connectorhelper.WithMetricsToMetrics(createMetricsToMetricsConnector),
+	connectorhelper.WithLogsToLogs(createLogsToLogsConnector))
+
+// CreateDefaultConfig creates the default configuration for the Connector.
+func createConnectorDefaultConfig() config.Connector {
+	return &ExampleConnectorCfg{
+		ConnectorSettings: config.NewConnectorSettings(config.NewID(connType)),
+		ExtraSetting:      "some connector string",
+	}
+}
+
+func createTracesToTracesConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	nextConsumer consumer.Traces,
+) (component.TracesConnector, error) {
+	return &ExampleConnector{nextTraces: nextConsumer}, nil
+}
+
+func createTracesToMetricsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	nextConsumer consumer.Metrics,
+) (component.TracesConnector, error) {
+	return &ExampleConnector{nextMetrics: nextConsumer}, nil
+}
+
+func createMetricsToMetricsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	nextConsumer consumer.Metrics,
+) (component.MetricsConnector, error) {
+	return &ExampleConnector{nextMetrics: nextConsumer}, nil
+}
+
+func createLogsToLogsConnector(
+	_ context.Context,
+	_ component.ConnectorCreateSettings,
+	_ config.Connector,
+	nextConsumer consumer.Logs,
+) (component.LogsConnector, error) {
+	return &ExampleConnector{nextLogs: nextConsumer}, nil
+}
+
+// ExampleConnector forwards the data it consumes to the next pipelines when the data types
+// match, and emits a "span_count" gauge for every batch of traces when connecting traces to metrics.
+type ExampleConnector struct {
+	nextTraces  consumer.Traces
+	nextMetrics consumer.Metrics
+	nextLogs    consumer.Logs
+	Started     bool
+	Stopped     bool
+}
+
+// Start tells the Connector to start.
+func (c *ExampleConnector) Start(_ context.Context, _ component.Host) error {
+	c.Started = true
+	return nil
+}
+
+// Shutdown is invoked during shutdown.
+func (c *ExampleConnector) Shutdown(_ context.Context) error {
+	c.Stopped = true
+	return nil
+}
+
+func (c *ExampleConnector) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces forwards the traces, or their span count, to the next pipelines.
+func (c *ExampleConnector) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if c.nextTraces != nil {
+		return c.nextTraces.ConsumeTraces(ctx, td)
+	}
+	md := pdata.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("span_count")
+	m.SetDataType(pdata.MetricDataTypeGauge)
+	m.Gauge().DataPoints().AppendEmpty().SetIntVal(int64(td.SpanCount()))
+	return c.nextMetrics.ConsumeMetrics(ctx, md)
+}
+
+// ConsumeMetrics forwards the metrics to the next pipelines.
+func (c *ExampleConnector) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	return c.nextMetrics.ConsumeMetrics(ctx, md)
+}
+
+// ConsumeLogs forwards the logs to the next pipelines.
+func (c *ExampleConnector) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	return c.nextLogs.ConsumeLogs(ctx, ld)
+}
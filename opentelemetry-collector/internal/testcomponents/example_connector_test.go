@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testcomponents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestExampleConnector(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	conn, err := ExampleConnectorFactory.CreateTracesToMetricsConnector(
+		context.Background(), componenttest.NewNopConnectorCreateSettings(), ExampleConnectorFactory.CreateDefaultConfig(), sink)
+	require.NoError(t, err)
+
+	host := componenttest.NewNopHost()
+	assert.False(t, conn.(*ExampleConnector).Started)
+	assert.NoError(t, conn.Start(context.Background(), host))
+	assert.True(t, conn.(*ExampleConnector).Started)
+
+	assert.NoError(t, conn.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+	require.Len(t, sink.AllMetrics(), 1)
+	metric := sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "span_count", metric.Name())
+	assert.EqualValues(t, 2, metric.Gauge().DataPoints().At(0).IntVal())
+
+	assert.False(t, conn.(*ExampleConnector).Stopped)
+	assert.NoError(t, conn.Shutdown(context.Background()))
+	assert.True(t, conn.(*ExampleConnector).Stopped)
+}
@@ -118,6 +118,15 @@ func (rec *Receiver) EndMetricsOp(
 	rec.endOp(receiverCtx, format, numReceivedPoints, err, config.MetricsDataType)
 }
 
+// RecordRateLimitedRequest records that a request was refused because the client
+// exceeded the rate limits of the receiver.
+func (rec *Receiver) RecordRateLimitedRequest(ctx context.Context) {
+	if obsreportconfig.Level == configtelemetry.LevelNone {
+		return
+	}
+	_ = stats.RecordWithTags(ctx, rec.mutators, obsmetrics.ReceiverRateLimitedRequests.M(1))
+}
+
 // startOp creates the span used to trace the operation. Returning
 // the updated context with the created span.
 func (rec *Receiver) startOp(receiverCtx context.Context, operationSuffix string) context.Context {
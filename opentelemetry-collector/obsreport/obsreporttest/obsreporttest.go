@@ -132,6 +132,14 @@ func CheckReceiverMetrics(t *testing.T, receiver config.ComponentID, protocol st
 	checkValueForView(t, receiverTags, droppedMetricPoints, "receiver/refused_metric_points")
 }
 
+// CheckReceiverRateLimitedRequests checks that for the current exported value for the receiver rate limited requests
+// matches the given value.
+// When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
+func CheckReceiverRateLimitedRequests(t *testing.T, receiver config.ComponentID, protocol string, rateLimitedRequests int64) {
+	receiverTags := tagsForReceiverView(receiver, protocol)
+	checkValueForView(t, receiverTags, rateLimitedRequests, "receiver/rate_limited_requests")
+}
+
 // CheckScraperMetrics checks that for the current exported values for metrics scraper metrics match given values.
 // When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
 func CheckScraperMetrics(t *testing.T, receiver config.ComponentID, scraper config.ComponentID, scrapedMetricPoints, erroredMetricPoints int64) {
@@ -55,6 +55,18 @@ func TestCheckReceiverTracesViews(t *testing.T) {
 	obsreporttest.CheckReceiverTraces(t, receiver, transport, 7, 0)
 }
 
+func TestCheckReceiverRateLimitedRequestsViews(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	rec := obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: receiver, Transport: transport})
+	rec.RecordRateLimitedRequest(context.Background())
+	rec.RecordRateLimitedRequest(context.Background())
+
+	obsreporttest.CheckReceiverRateLimitedRequests(t, receiver, transport, 2)
+}
+
 func TestCheckReceiverMetricsViews(t *testing.T) {
 	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
 	require.NoError(t, err)
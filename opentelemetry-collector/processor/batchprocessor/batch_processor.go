@@ -16,7 +16,11 @@ package batchprocessor
 
 import (
 	"context"
+	"errors"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,9 +28,11 @@ import (
 	"go.opencensus.io/tag"
 	"go.uber.org/zap"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/internal/retention"
 	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 )
@@ -38,17 +44,38 @@ import (
 //
 // Batches are sent out with any of the following conditions:
 // - batch size reaches cfg.SendBatchSize
+// - batch size in bytes reaches cfg.SendBatchMaxSizeBytes
 // - cfg.Timeout is elapsed since the timestamp when the previous batch was sent out.
+//
+// When cfg.MetadataKeys is set, a separate batch is kept for each distinct combination
+// of the values of those keys found in the client.Client of the incoming context.
+// Likewise, when cfg.ResourceAttributeKeys is set, a separate batch is kept for each
+// distinct combination of the values of those resource attributes.
 type batchProcessor struct {
-	logger           *zap.Logger
-	exportCtx        context.Context
-	timer            *time.Timer
-	timeout          time.Duration
-	sendBatchSize    int
-	sendBatchMaxSize int
+	logger                *zap.Logger
+	timeout               time.Duration
+	sendBatchSize         int
+	sendBatchMaxSize      int
+	sendBatchMaxSizeBytes int
+
+	// newBatch creates an empty batch for a new shard.
+	newBatch func() batch
+
+	// tagCtx is the context carrying the processor tag, used as the base of every shard's export context.
+	tagCtx context.Context
 
-	newItem chan interface{}
-	batch   batch
+	// metadataKeys are the lowercase client metadata keys used to partition the incoming data.
+	metadataKeys []string
+	// resourceKeys are the resource attribute keys used to partition the incoming data.
+	resourceKeys []string
+	// metadataLimit is the maximum number of distinct metadata and resource attribute combinations.
+	metadataLimit int
+
+	// batcher is the only shard used when neither metadataKeys nor resourceKeys are configured.
+	batcher *shard
+
+	lock   sync.Mutex
+	shards map[string]*shard
 
 	shutdownC  chan struct{}
 	goroutines sync.WaitGroup
@@ -56,6 +83,27 @@ type batchProcessor struct {
 	telemetryLevel configtelemetry.Level
 }
 
+// shard is a single batch with its own export context and processing goroutine.
+type shard struct {
+	processor *batchProcessor
+	exportCtx context.Context
+	timer     *time.Timer
+	newItem   chan interface{}
+	batch     batch
+
+	// held are the retained items of the batch, oldest first.
+	held []heldItem
+}
+
+// heldItem is an item retained for the retention.Holder found in the context it
+// was received with, released once its data is exported.
+type heldItem struct {
+	data    interface{}
+	holder  retention.Holder
+	release func()
+	count   int
+}
+
 type batch interface {
 	// export the current batch
 	export(ctx context.Context, sendBatchMaxSize int) error
@@ -66,6 +114,10 @@ type batch interface {
 	// size returns the size in bytes of the current batch
 	size() int
 
+	// byteCount returns the size in bytes of the current batch as tracked when
+	// a maximum size in bytes is configured, 0 otherwise
+	byteCount() int
+
 	// add item to the current batch
 	add(item interface{})
 }
@@ -74,23 +126,54 @@ var _ consumer.Traces = (*batchProcessor)(nil)
 var _ consumer.Metrics = (*batchProcessor)(nil)
 var _ consumer.Logs = (*batchProcessor)(nil)
 
-func newBatchProcessor(set component.ProcessorCreateSettings, cfg *Config, batch batch, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
-	exportCtx, err := tag.New(context.Background(), tag.Insert(processorTagKey, cfg.ID().String()))
+var errTooManyBatchers = errors.New("too many batcher metadata-value combinations")
+
+func newBatchProcessor(set component.ProcessorCreateSettings, cfg *Config, newBatch func(sendBatchMaxSizeBytes int) batch, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
+	tagCtx, err := tag.New(context.Background(), tag.Insert(processorTagKey, cfg.ID().String()))
 	if err != nil {
 		return nil, err
 	}
-	return &batchProcessor{
+
+	metadataKeys := make([]string, 0, len(cfg.MetadataKeys))
+	for _, k := range cfg.MetadataKeys {
+		metadataKeys = append(metadataKeys, strings.ToLower(k))
+	}
+	sort.Strings(metadataKeys)
+
+	bp := &batchProcessor{
 		logger:         set.Logger,
-		exportCtx:      exportCtx,
 		telemetryLevel: telemetryLevel,
 
-		sendBatchSize:    int(cfg.SendBatchSize),
-		sendBatchMaxSize: int(cfg.SendBatchMaxSize),
-		timeout:          cfg.Timeout,
-		newItem:          make(chan interface{}, runtime.NumCPU()),
-		batch:            batch,
-		shutdownC:        make(chan struct{}, 1),
-	}, nil
+		sendBatchSize:         int(cfg.SendBatchSize),
+		sendBatchMaxSize:      int(cfg.SendBatchMaxSize),
+		sendBatchMaxSizeBytes: int(cfg.SendBatchMaxSizeBytes),
+		timeout:               cfg.Timeout,
+		newBatch:              func() batch { return newBatch(int(cfg.SendBatchMaxSizeBytes)) },
+		tagCtx:                tagCtx,
+		metadataKeys:          metadataKeys,
+		resourceKeys:          cfg.ResourceAttributeKeys,
+		metadataLimit:         int(cfg.MetadataCardinalityLimit),
+		shards:                map[string]*shard{},
+		shutdownC:             make(chan struct{}, 1),
+	}
+	if len(metadataKeys) == 0 && len(bp.resourceKeys) == 0 {
+		bp.batcher = bp.newShard(nil)
+	}
+	return bp, nil
+}
+
+// newShard creates a shard whose exports carry the given client metadata.
+func (bp *batchProcessor) newShard(md map[string][]string) *shard {
+	exportCtx := bp.tagCtx
+	if len(bp.metadataKeys) > 0 {
+		exportCtx = client.NewContext(exportCtx, &client.Client{Metadata: md})
+	}
+	return &shard{
+		processor: bp,
+		exportCtx: exportCtx,
+		newItem:   make(chan interface{}, runtime.NumCPU()),
+		batch:     bp.newBatch(),
+	}
 }
 
 func (bp *batchProcessor) Capabilities() consumer.Capabilities {
@@ -99,8 +182,9 @@ func (bp *batchProcessor) Capabilities() consumer.Capabilities {
 
 // Start is invoked during service startup.
 func (bp *batchProcessor) Start(context.Context, component.Host) error {
-	bp.goroutines.Add(1)
-	go bp.startProcessingCycle()
+	if bp.batcher != nil {
+		bp.startShard(bp.batcher)
+	}
 	return nil
 }
 
@@ -113,111 +197,233 @@ func (bp *batchProcessor) Shutdown(context.Context) error {
 	return nil
 }
 
-func (bp *batchProcessor) startProcessingCycle() {
-	defer bp.goroutines.Done()
-	bp.timer = time.NewTimer(bp.timeout)
+func (bp *batchProcessor) startShard(b *shard) {
+	b.timer = time.NewTimer(bp.timeout)
+	bp.goroutines.Add(1)
+	go b.startProcessingCycle()
+}
+
+func (b *shard) startProcessingCycle() {
+	defer b.processor.goroutines.Done()
 	for {
 		select {
-		case <-bp.shutdownC:
+		case <-b.processor.shutdownC:
 		DONE:
 			for {
 				select {
-				case item := <-bp.newItem:
-					bp.processItem(item)
+				case item := <-b.newItem:
+					b.processItem(item)
 				default:
 					break DONE
 				}
 			}
 			// This is the close of the channel
-			if bp.batch.itemCount() > 0 {
+			if b.batch.itemCount() > 0 {
 				// TODO: Set a timeout on sendTraces or
 				// make it cancellable using the context that Shutdown gets as a parameter
-				bp.sendItems(statTimeoutTriggerSend)
+				b.sendItems(statTimeoutTriggerSend)
 			}
 			return
-		case item := <-bp.newItem:
+		case item := <-b.newItem:
 			if item == nil {
 				continue
 			}
-			bp.processItem(item)
-		case <-bp.timer.C:
-			if bp.batch.itemCount() > 0 {
-				bp.sendItems(statTimeoutTriggerSend)
+			b.processItem(item)
+		case <-b.timer.C:
+			if b.batch.itemCount() > 0 {
+				b.sendItems(statTimeoutTriggerSend)
 			}
-			bp.resetTimer()
+			b.resetTimer()
 		}
 	}
 }
 
-func (bp *batchProcessor) processItem(item interface{}) {
-	bp.batch.add(item)
+func (b *shard) processItem(item interface{}) {
+	if hi, ok := item.(*heldItem); ok {
+		before := b.batch.itemCount()
+		b.batch.add(hi.data)
+		hi.count = b.batch.itemCount() - before
+		b.held = append(b.held, *hi)
+	} else {
+		b.batch.add(item)
+	}
 	sent := false
-	for bp.batch.itemCount() >= bp.sendBatchSize {
+	for b.batch.itemCount() >= b.processor.sendBatchSize || b.reachedMaxSizeBytes() {
 		sent = true
-		bp.sendItems(statBatchSizeTriggerSend)
+		b.sendItems(statBatchSizeTriggerSend)
 	}
 
 	if sent {
-		bp.stopTimer()
-		bp.resetTimer()
+		b.stopTimer()
+		b.resetTimer()
 	}
 }
 
-func (bp *batchProcessor) stopTimer() {
-	if !bp.timer.Stop() {
-		<-bp.timer.C
+func (b *shard) reachedMaxSizeBytes() bool {
+	return b.processor.sendBatchMaxSizeBytes > 0 && b.batch.byteCount() >= b.processor.sendBatchMaxSizeBytes
+}
+
+func (b *shard) stopTimer() {
+	if !b.timer.Stop() {
+		<-b.timer.C
 	}
 }
 
-func (bp *batchProcessor) resetTimer() {
-	bp.timer.Reset(bp.timeout)
+func (b *shard) resetTimer() {
+	b.timer.Reset(b.processor.timeout)
 }
 
-func (bp *batchProcessor) sendItems(triggerMeasure *stats.Int64Measure) {
+func (b *shard) sendItems(triggerMeasure *stats.Int64Measure) {
 	// Add that it came form the trace pipeline?
-	stats.Record(bp.exportCtx, triggerMeasure.M(1), statBatchSendSize.M(int64(bp.batch.itemCount())))
+	stats.Record(b.exportCtx, triggerMeasure.M(1), statBatchSendSize.M(int64(b.batch.itemCount())))
 
-	if bp.telemetryLevel == configtelemetry.LevelDetailed {
-		stats.Record(bp.exportCtx, statBatchSendSizeBytes.M(int64(bp.batch.size())))
+	if b.processor.telemetryLevel == configtelemetry.LevelDetailed {
+		stats.Record(b.exportCtx, statBatchSendSizeBytes.M(int64(b.batch.size())))
 	}
 
-	if err := bp.batch.export(bp.exportCtx, bp.sendBatchMaxSize); err != nil {
-		bp.logger.Warn("Sender failed", zap.Error(err))
+	ctx := b.exportCtx
+	if len(b.held) > 0 {
+		holders := make([]retention.Holder, len(b.held))
+		for i := range b.held {
+			holders[i] = b.held[i].holder
+		}
+		ctx = retention.NewContext(ctx, retention.Combine(holders...))
+	}
+	before := b.batch.itemCount()
+	if err := b.batch.export(ctx, b.processor.sendBatchMaxSize); err != nil {
+		b.processor.logger.Warn("Sender failed", zap.Error(err))
 	}
+	b.releaseSent(before - b.batch.itemCount())
 }
 
-// ConsumeTraces implements TracesProcessor
-func (bp *batchProcessor) ConsumeTraces(_ context.Context, td pdata.Traces) error {
-	bp.newItem <- td
+// releaseSent releases the oldest retained items covering the sent items, or
+// all of them once the batch is empty.
+func (b *shard) releaseSent(sent int) {
+	if b.batch.itemCount() == 0 {
+		sent = -1
+	}
+	i := 0
+	for ; i < len(b.held); i++ {
+		if sent >= 0 && b.held[i].count > sent {
+			b.held[i].count -= sent
+			break
+		}
+		sent -= b.held[i].count
+		b.held[i].release()
+	}
+	b.held = append(b.held[:0], b.held[i:]...)
+}
+
+// shardFor returns the shard responsible for the client metadata found in ctx and
+// the given resource attribute values, creating and starting it if this is the first
+// time the combination of values is seen.
+func (bp *batchProcessor) shardFor(ctx context.Context, resourceKey string) (*shard, error) {
+	if bp.batcher != nil {
+		return bp.batcher, nil
+	}
+
+	var md map[string][]string
+	if c, ok := client.FromContext(ctx); ok {
+		md = c.Metadata
+	}
+
+	var key strings.Builder
+	selected := map[string][]string{}
+	for i, k := range bp.metadataKeys {
+		if i > 0 {
+			key.WriteByte(0)
+		}
+		vals := md[k]
+		if len(vals) == 0 {
+			continue
+		}
+		selected[k] = vals
+		for _, v := range vals {
+			key.WriteString(strconv.Quote(v))
+		}
+	}
+	key.WriteByte(1)
+	key.WriteString(resourceKey)
+
+	bp.lock.Lock()
+	defer bp.lock.Unlock()
+
+	b, ok := bp.shards[key.String()]
+	if ok {
+		return b, nil
+	}
+	if len(bp.shards) >= bp.metadataLimit {
+		return nil, errTooManyBatchers
+	}
+	b = bp.newShard(selected)
+	bp.startShard(b)
+	bp.shards[key.String()] = b
+	return b, nil
+}
+
+// retain wraps the data to retain it for the retention.Holder found in ctx until it is exported.
+func retain(ctx context.Context, data interface{}) interface{} {
+	h := retention.FromContext(ctx)
+	if h == nil {
+		return data
+	}
+	return &heldItem{data: data, holder: h, release: retention.Once(h.Retain())}
+}
+
+func (bp *batchProcessor) consume(ctx context.Context, data interface{}) error {
+	if len(bp.resourceKeys) == 0 {
+		b, err := bp.shardFor(ctx, "")
+		if err != nil {
+			return err
+		}
+		b.newItem <- retain(ctx, data)
+		return nil
+	}
+
+	// Find all the shards first, so that no data is sent if any is refused.
+	parts := partitionByResource(data, bp.resourceKeys)
+	shards := make([]*shard, len(parts))
+	for i, part := range parts {
+		b, err := bp.shardFor(ctx, part.key)
+		if err != nil {
+			return err
+		}
+		shards[i] = b
+	}
+	for i, part := range parts {
+		shards[i].newItem <- retain(ctx, part.data)
+	}
 	return nil
 }
 
+// ConsumeTraces implements TracesProcessor
+func (bp *batchProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	return bp.consume(ctx, td)
+}
+
 // ConsumeMetrics implements MetricsProcessor
-func (bp *batchProcessor) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
-	// First thing is convert into a different internal format
-	bp.newItem <- md
-	return nil
+func (bp *batchProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	return bp.consume(ctx, md)
 }
 
 // ConsumeLogs implements LogsProcessor
-func (bp *batchProcessor) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
-	bp.newItem <- ld
-	return nil
+func (bp *batchProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	return bp.consume(ctx, ld)
 }
 
 // newBatchTracesProcessor creates a new batch processor that batches traces by size or with timeout
 func newBatchTracesProcessor(set component.ProcessorCreateSettings, next consumer.Traces, cfg *Config, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, newBatchTraces(next), telemetryLevel)
+	return newBatchProcessor(set, cfg, func(sendBatchMaxSizeBytes int) batch { return newBatchTraces(next, sendBatchMaxSizeBytes) }, telemetryLevel)
 }
 
 // newBatchMetricsProcessor creates a new batch processor that batches metrics by size or with timeout
 func newBatchMetricsProcessor(set component.ProcessorCreateSettings, next consumer.Metrics, cfg *Config, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, newBatchMetrics(next), telemetryLevel)
+	return newBatchProcessor(set, cfg, func(sendBatchMaxSizeBytes int) batch { return newBatchMetrics(next, sendBatchMaxSizeBytes) }, telemetryLevel)
 }
 
 // newBatchLogsProcessor creates a new batch processor that batches logs by size or with timeout
 func newBatchLogsProcessor(set component.ProcessorCreateSettings, next consumer.Logs, cfg *Config, telemetryLevel configtelemetry.Level) (*batchProcessor, error) {
-	return newBatchProcessor(set, cfg, newBatchLogs(next), telemetryLevel)
+	return newBatchProcessor(set, cfg, func(sendBatchMaxSizeBytes int) batch { return newBatchLogs(next, sendBatchMaxSizeBytes) }, telemetryLevel)
 }
 
 type batchTraces struct {
@@ -225,10 +431,14 @@ type batchTraces struct {
 	traceData    pdata.Traces
 	spanCount    int
 	sizer        pdata.TracesSizer
+
+	// maxBytes is the maximum size in bytes of an exported batch, 0 means no maximum.
+	maxBytes int
+	bytes    int
 }
 
-func newBatchTraces(nextConsumer consumer.Traces) *batchTraces {
-	return &batchTraces{nextConsumer: nextConsumer, traceData: pdata.NewTraces(), sizer: otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)}
+func newBatchTraces(nextConsumer consumer.Traces, sendBatchMaxSizeBytes int) *batchTraces {
+	return &batchTraces{nextConsumer: nextConsumer, traceData: pdata.NewTraces(), sizer: otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer), maxBytes: sendBatchMaxSizeBytes}
 }
 
 // add updates current batchTraces by adding new TraceData object
@@ -240,6 +450,9 @@ func (bt *batchTraces) add(item interface{}) {
 	}
 
 	bt.spanCount += newSpanCount
+	if bt.maxBytes > 0 {
+		bt.bytes += bt.sizer.TracesSize(td)
+	}
 	td.ResourceSpans().MoveAndAppendTo(bt.traceData.ResourceSpans())
 }
 
@@ -253,6 +466,17 @@ func (bt *batchTraces) export(ctx context.Context, sendBatchMaxSize int) error {
 		bt.traceData = pdata.NewTraces()
 		bt.spanCount = 0
 	}
+	if bt.maxBytes > 0 {
+		rest := req
+		req = splitTracesBytes(bt.maxBytes, rest, bt.sizer)
+		if rest != req {
+			// Keep the rest in front of the pending data, which was added after it.
+			bt.spanCount += rest.SpanCount()
+			bt.traceData.ResourceSpans().MoveAndAppendTo(rest.ResourceSpans())
+			bt.traceData = rest
+		}
+		bt.bytes = bt.sizer.TracesSize(bt.traceData)
+	}
 	return bt.nextConsumer.ConsumeTraces(ctx, req)
 }
 
@@ -264,15 +488,23 @@ func (bt *batchTraces) size() int {
 	return bt.sizer.TracesSize(bt.traceData)
 }
 
+func (bt *batchTraces) byteCount() int {
+	return bt.bytes
+}
+
 type batchMetrics struct {
 	nextConsumer   consumer.Metrics
 	metricData     pdata.Metrics
 	dataPointCount int
 	sizer          pdata.MetricsSizer
+
+	// maxBytes is the maximum size in bytes of an exported batch, 0 means no maximum.
+	maxBytes int
+	bytes    int
 }
 
-func newBatchMetrics(nextConsumer consumer.Metrics) *batchMetrics {
-	return &batchMetrics{nextConsumer: nextConsumer, metricData: pdata.NewMetrics(), sizer: otlp.NewProtobufMetricsMarshaler().(pdata.MetricsSizer)}
+func newBatchMetrics(nextConsumer consumer.Metrics, sendBatchMaxSizeBytes int) *batchMetrics {
+	return &batchMetrics{nextConsumer: nextConsumer, metricData: pdata.NewMetrics(), sizer: otlp.NewProtobufMetricsMarshaler().(pdata.MetricsSizer), maxBytes: sendBatchMaxSizeBytes}
 }
 
 func (bm *batchMetrics) export(ctx context.Context, sendBatchMaxSize int) error {
@@ -285,6 +517,17 @@ func (bm *batchMetrics) export(ctx context.Context, sendBatchMaxSize int) error
 		bm.metricData = pdata.NewMetrics()
 		bm.dataPointCount = 0
 	}
+	if bm.maxBytes > 0 {
+		rest := req
+		req = splitMetricsBytes(bm.maxBytes, rest, bm.sizer)
+		if rest != req {
+			// Keep the rest in front of the pending data, which was added after it.
+			bm.dataPointCount += rest.DataPointCount()
+			bm.metricData.ResourceMetrics().MoveAndAppendTo(rest.ResourceMetrics())
+			bm.metricData = rest
+		}
+		bm.bytes = bm.sizer.MetricsSize(bm.metricData)
+	}
 	return bm.nextConsumer.ConsumeMetrics(ctx, req)
 }
 
@@ -296,6 +539,10 @@ func (bm *batchMetrics) size() int {
 	return bm.sizer.MetricsSize(bm.metricData)
 }
 
+func (bm *batchMetrics) byteCount() int {
+	return bm.bytes
+}
+
 func (bm *batchMetrics) add(item interface{}) {
 	md := item.(pdata.Metrics)
 
@@ -304,6 +551,9 @@ func (bm *batchMetrics) add(item interface{}) {
 		return
 	}
 	bm.dataPointCount += newDataPointCount
+	if bm.maxBytes > 0 {
+		bm.bytes += bm.sizer.MetricsSize(md)
+	}
 	md.ResourceMetrics().MoveAndAppendTo(bm.metricData.ResourceMetrics())
 }
 
@@ -312,10 +562,14 @@ type batchLogs struct {
 	logData      pdata.Logs
 	logCount     int
 	sizer        pdata.LogsSizer
+
+	// maxBytes is the maximum size in bytes of an exported batch, 0 means no maximum.
+	maxBytes int
+	bytes    int
 }
 
-func newBatchLogs(nextConsumer consumer.Logs) *batchLogs {
-	return &batchLogs{nextConsumer: nextConsumer, logData: pdata.NewLogs(), sizer: otlp.NewProtobufLogsMarshaler().(pdata.LogsSizer)}
+func newBatchLogs(nextConsumer consumer.Logs, sendBatchMaxSizeBytes int) *batchLogs {
+	return &batchLogs{nextConsumer: nextConsumer, logData: pdata.NewLogs(), sizer: otlp.NewProtobufLogsMarshaler().(pdata.LogsSizer), maxBytes: sendBatchMaxSizeBytes}
 }
 
 func (bl *batchLogs) export(ctx context.Context, sendBatchMaxSize int) error {
@@ -328,6 +582,17 @@ func (bl *batchLogs) export(ctx context.Context, sendBatchMaxSize int) error {
 		bl.logData = pdata.NewLogs()
 		bl.logCount = 0
 	}
+	if bl.maxBytes > 0 {
+		rest := req
+		req = splitLogsBytes(bl.maxBytes, rest, bl.sizer)
+		if rest != req {
+			// Keep the rest in front of the pending data, which was added after it.
+			bl.logCount += rest.LogRecordCount()
+			bl.logData.ResourceLogs().MoveAndAppendTo(rest.ResourceLogs())
+			bl.logData = rest
+		}
+		bl.bytes = bl.sizer.LogsSize(bl.logData)
+	}
 	return bl.nextConsumer.ConsumeLogs(ctx, req)
 }
 
@@ -339,6 +604,10 @@ func (bl *batchLogs) size() int {
 	return bl.sizer.LogsSize(bl.logData)
 }
 
+func (bl *batchLogs) byteCount() int {
+	return bl.bytes
+}
+
 func (bl *batchLogs) add(item interface{}) {
 	ld := item.(pdata.Logs)
 
@@ -347,5 +616,8 @@ func (bl *batchLogs) add(item interface{}) {
 		return
 	}
 	bl.logCount += newLogsCount
+	if bl.maxBytes > 0 {
+		bl.bytes += bl.sizer.LogsSize(ld)
+	}
 	ld.ResourceLogs().MoveAndAppendTo(bl.logData.ResourceLogs())
 }
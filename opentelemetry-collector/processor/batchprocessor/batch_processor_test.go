@@ -17,6 +17,8 @@ package batchprocessor
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -25,11 +27,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/stats/view"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/retention"
+	"go.opentelemetry.io/collector/internal/retention/retentiontest"
 	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
@@ -381,7 +386,7 @@ func TestBatchMetrics_UnevenBatchMaxSize(t *testing.T) {
 	dataPointsPerMetric := 2
 	sendBatchMaxSize := 99
 
-	batchMetrics := newBatchMetrics(sink)
+	batchMetrics := newBatchMetrics(sink, 0)
 	md := testdata.GenerateMetricsManyMetricsSameResource(metricsCount)
 
 	batchMetrics.add(md)
@@ -790,3 +795,285 @@ func TestShutdown(t *testing.T) {
 	factory := NewFactory()
 	componenttest.VerifyProcessorShutdown(t, factory, factory.CreateDefaultConfig())
 }
+
+// metadataTracesSink records the span count exported for each tenant found in the client metadata.
+type metadataTracesSink struct {
+	mu              sync.Mutex
+	spanCountByOrg  map[string]int
+	exportsWithNoMD int
+}
+
+func (mts *metadataTracesSink) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (mts *metadataTracesSink) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	mts.mu.Lock()
+	defer mts.mu.Unlock()
+	c, ok := client.FromContext(ctx)
+	if !ok || len(c.Metadata["x-scope-orgid"]) == 0 {
+		mts.exportsWithNoMD++
+		return nil
+	}
+	mts.spanCountByOrg[strings.Join(c.Metadata["x-scope-orgid"], ",")] += td.SpanCount()
+	return nil
+}
+
+func contextWithOrgID(orgID string) context.Context {
+	return client.NewContext(context.Background(), &client.Client{
+		Metadata: map[string][]string{"x-scope-orgid": {orgID}},
+	})
+}
+
+func TestBatchProcessorSpansBatchedByMetadata(t *testing.T) {
+	sink := &metadataTracesSink{spanCountByOrg: map[string]int{}}
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1000
+	cfg.Timeout = 10 * time.Minute
+	cfg.MetadataKeys = []string{"X-Scope-OrgID"}
+	creationSet := componenttest.NewNopProcessorCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 100
+	spansPerRequest := 5
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		td := testdata.GenerateTracesManySpansSameResource(spansPerRequest)
+		ctx := contextWithOrgID(fmt.Sprintf("org-%d", requestNum%3))
+		assert.NoError(t, batcher.ConsumeTraces(ctx, td))
+	}
+	td := testdata.GenerateTracesManySpansSameResource(spansPerRequest)
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.Len(t, batcher.shards, 4)
+	assert.Equal(t, map[string]int{
+		"org-0": 34 * spansPerRequest,
+		"org-1": 33 * spansPerRequest,
+		"org-2": 33 * spansPerRequest,
+	}, sink.spanCountByOrg)
+	assert.Equal(t, 1, sink.exportsWithNoMD)
+}
+
+func TestBatchProcessorMetadataCardinalityLimit(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"x-scope-orgid"}
+	cfg.MetadataCardinalityLimit = 2
+	creationSet := componenttest.NewNopProcessorCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, batcher.ConsumeTraces(contextWithOrgID("org-0"), testdata.GenerateTracesOneSpan()))
+	assert.NoError(t, batcher.ConsumeTraces(contextWithOrgID("org-1"), testdata.GenerateTracesOneSpan()))
+	assert.NoError(t, batcher.ConsumeTraces(contextWithOrgID("org-0"), testdata.GenerateTracesOneSpan()))
+	assert.ErrorIs(t, batcher.ConsumeTraces(contextWithOrgID("org-2"), testdata.GenerateTracesOneSpan()), errTooManyBatchers)
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.Equal(t, 3, sink.SpanCount())
+}
+
+func TestBatchProcessorSentByMaxSizeBytes(t *testing.T) {
+	sizer := otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 100000
+	cfg.Timeout = 10 * time.Minute
+	spansPerRequest := 10
+	requestSize := sizer.TracesSize(testdata.GenerateTracesManySpansSameResource(spansPerRequest))
+	cfg.SendBatchMaxSizeBytes = uint32(requestSize * 3 / 2)
+	creationSet := componenttest.NewNopProcessorCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 20
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		td := testdata.GenerateTracesManySpansSameResource(spansPerRequest)
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerRequest, sink.SpanCount())
+	assert.Greater(t, len(sink.AllTraces()), requestCount/2)
+	for _, td := range sink.AllTraces() {
+		assert.LessOrEqual(t, sizer.TracesSize(td), int(cfg.SendBatchMaxSizeBytes))
+	}
+}
+
+func TestBatchTracesMaxSizeBytesKeepsOrder(t *testing.T) {
+	sizer := otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)
+	sink := new(consumertest.TracesSink)
+	bt := newBatchTraces(sink, sizer.TracesSize(testdata.GenerateTracesManySpansSameResource(5)))
+
+	td := testdata.GenerateTracesManySpansSameResource(12)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetName(strconv.Itoa(i))
+	}
+	bt.add(td)
+
+	// The batches are split by count, then by size, the rest of both splits must be sent in order.
+	for bt.itemCount() > 0 {
+		require.NoError(t, bt.export(context.Background(), 8))
+	}
+
+	var names []string
+	for _, sent := range sink.AllTraces() {
+		assert.Less(t, sent.SpanCount(), 8)
+		rss := sent.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			ilss := rss.At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				for k := 0; k < ilss.At(j).Spans().Len(); k++ {
+					names = append(names, ilss.At(j).Spans().At(k).Name())
+				}
+			}
+		}
+	}
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}, names)
+}
+
+func TestBatchProcessorLogsSentByMaxSizeBytes(t *testing.T) {
+	sizer := otlp.NewProtobufLogsMarshaler().(pdata.LogsSizer)
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 100000
+	cfg.Timeout = 10 * time.Minute
+	logsPerRequest := 10
+	requestSize := sizer.LogsSize(testdata.GenerateLogsManyLogRecordsSameResource(logsPerRequest))
+	cfg.SendBatchMaxSizeBytes = uint32(requestSize / 2)
+	creationSet := componenttest.NewNopProcessorCreateSettings()
+	batcher, err := newBatchLogsProcessor(creationSet, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 10
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		ld := testdata.GenerateLogsManyLogRecordsSameResource(logsPerRequest)
+		assert.NoError(t, batcher.ConsumeLogs(context.Background(), ld))
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*logsPerRequest, sink.LogRecordCount())
+	for _, ld := range sink.AllLogs() {
+		assert.LessOrEqual(t, sizer.LogsSize(ld), int(cfg.SendBatchMaxSizeBytes))
+	}
+}
+
+func tracesWithServiceNames(names ...string) pdata.Traces {
+	td := pdata.NewTraces()
+	for _, name := range names {
+		rs := testdata.GenerateTracesOneSpan().ResourceSpans().At(0)
+		rs.Resource().Attributes().UpsertString("service.name", name)
+		rs.CopyTo(td.ResourceSpans().AppendEmpty())
+	}
+	return td
+}
+
+func TestBatchProcessorSpansBatchedByResourceAttributes(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 1000
+	cfg.Timeout = 10 * time.Minute
+	cfg.ResourceAttributeKeys = []string{"service.name"}
+	creationSet := componenttest.NewNopProcessorCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), tracesWithServiceNames("a", "b", "a")))
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), tracesWithServiceNames("b")))
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), testdata.GenerateTracesOneSpan()))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.Len(t, batcher.shards, 3)
+	spanCountByService := map[string]int{}
+	for _, td := range sink.AllTraces() {
+		services := map[string]bool{}
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			name := ""
+			if v, ok := rss.At(i).Resource().Attributes().Get("service.name"); ok {
+				name = v.StringVal()
+			}
+			services[name] = true
+			spanCountByService[name] += rss.At(i).InstrumentationLibrarySpans().At(0).Spans().Len()
+		}
+		assert.Len(t, services, 1, "a batch must hold a single service")
+	}
+	assert.Equal(t, map[string]int{"a": 2, "b": 2, "": 1}, spanCountByService)
+}
+
+func TestBatchProcessorResourceAttributesCardinalityLimit(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.ResourceAttributeKeys = []string{"service.name"}
+	cfg.MetadataCardinalityLimit = 2
+	creationSet := componenttest.NewNopProcessorCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), tracesWithServiceNames("a", "b")))
+	// Nothing is sent when any of the resources is refused.
+	assert.ErrorIs(t, batcher.ConsumeTraces(context.Background(), tracesWithServiceNames("a", "c")), errTooManyBatchers)
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.Equal(t, 2, sink.SpanCount())
+}
+
+// holderTracesSink records whether the batches are exported with a retention.Holder.
+type holderTracesSink struct {
+	consumertest.TracesSink
+	mu      sync.Mutex
+	holders []retention.Holder
+}
+
+func (s *holderTracesSink) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	s.mu.Lock()
+	s.holders = append(s.holders, retention.FromContext(ctx))
+	s.mu.Unlock()
+	return s.TracesSink.ConsumeTraces(ctx, td)
+}
+
+func TestBatchProcessorRetainsUntilSent(t *testing.T) {
+	sink := new(holderTracesSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 10
+	cfg.SendBatchMaxSize = 10
+	cfg.Timeout = time.Hour
+	creationSet := componenttest.NewNopProcessorCreateSettings()
+	batcher, err := newBatchTracesProcessor(creationSet, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, err)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	holders := []*retentiontest.CountingHolder{{}, {}, {}}
+	for _, h := range holders[:2] {
+		require.NoError(t, batcher.ConsumeTraces(retention.NewContext(context.Background(), h), testdata.GenerateTracesManySpansSameResource(4)))
+	}
+	assert.EqualValues(t, 1, holders[0].Held())
+	assert.EqualValues(t, 1, holders[1].Held())
+
+	// The first 10 spans are sent, the last 2 of the third request stay in the batch.
+	require.NoError(t, batcher.ConsumeTraces(retention.NewContext(context.Background(), holders[2]), testdata.GenerateTracesManySpansSameResource(4)))
+	assert.Eventually(t, func() bool { return holders[0].Held() == 0 && holders[1].Held() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, 10, sink.SpanCount())
+	assert.EqualValues(t, 1, holders[2].Held())
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, 12, sink.SpanCount())
+	assert.EqualValues(t, 0, holders[2].Held())
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	require.Len(t, sink.holders, 2)
+	for _, h := range sink.holders {
+		assert.NotNil(t, h)
+	}
+}
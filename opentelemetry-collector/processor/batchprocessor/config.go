@@ -16,6 +16,8 @@ package batchprocessor
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -35,6 +37,26 @@ type Config struct {
 	// Larger batches are split into smaller units.
 	// Default value is 0, that means no maximum size.
 	SendBatchMaxSize uint32 `mapstructure:"send_batch_max_size,omitempty"`
+
+	// SendBatchMaxSizeBytes is the maximum size in bytes of the protobuf encoding of a batch.
+	// Batches reaching this size are sent, and larger batches are split into smaller units.
+	// Default value is 0, that means no maximum size in bytes.
+	SendBatchMaxSizeBytes uint32 `mapstructure:"send_batch_max_size_bytes,omitempty"`
+
+	// MetadataKeys is a list of client.Client metadata keys that will be used to form distinct batches.
+	// Each distinct combination of values for these keys is batched separately, and the resulting batches
+	// are exported with a context carrying the corresponding metadata.
+	// Default value is empty, that means a single batch is used for all data.
+	MetadataKeys []string `mapstructure:"metadata_keys,omitempty"`
+
+	// ResourceAttributeKeys is a list of resource attribute keys that will be used to form distinct batches.
+	// Each distinct combination of values for these keys is batched separately.
+	// Default value is empty, that means resources are not taken into account.
+	ResourceAttributeKeys []string `mapstructure:"resource_attribute_keys,omitempty"`
+
+	// MetadataCardinalityLimit limits the number of distinct combinations of metadata and resource attribute
+	// values batched separately. Data carrying a new combination once the limit is reached is refused.
+	MetadataCardinalityLimit uint32 `mapstructure:"metadata_cardinality_limit,omitempty"`
 }
 
 var _ config.Processor = (*Config)(nil)
@@ -44,5 +66,23 @@ func (cfg *Config) Validate() error {
 	if cfg.SendBatchMaxSize > 0 && cfg.SendBatchMaxSize < cfg.SendBatchSize {
 		return errors.New("send_batch_max_size must be greater or equal to send_batch_size")
 	}
+	uniq := map[string]bool{}
+	for _, k := range cfg.MetadataKeys {
+		l := strings.ToLower(k)
+		if uniq[l] {
+			return fmt.Errorf("duplicate entry in metadata_keys: %q (case-insensitive)", l)
+		}
+		uniq[l] = true
+	}
+	uniq = map[string]bool{}
+	for _, k := range cfg.ResourceAttributeKeys {
+		if uniq[k] {
+			return fmt.Errorf("duplicate entry in resource_attribute_keys: %q", k)
+		}
+		uniq[k] = true
+	}
+	if (len(cfg.MetadataKeys) > 0 || len(cfg.ResourceAttributeKeys) > 0) && cfg.MetadataCardinalityLimit == 0 {
+		return errors.New("metadata_cardinality_limit must be greater than zero when metadata_keys or resource_attribute_keys is set")
+	}
 	return nil
 }
@@ -53,6 +53,31 @@ func TestLoadConfig(t *testing.T) {
 			SendBatchSize:     sendBatchSize,
 			SendBatchMaxSize:  sendBatchMaxSize,
 			Timeout:           timeout,
+
+			MetadataCardinalityLimit: defaultMetadataCardinalityLimit,
+		})
+
+	p2 := cfg.Processors[config.NewIDWithName(typeStr, "3")]
+	assert.Equal(t, p2,
+		&Config{
+			ProcessorSettings: config.NewProcessorSettings(config.NewIDWithName(typeStr, "3")),
+			SendBatchSize:     defaultSendBatchSize,
+			Timeout:           defaultTimeout,
+
+			MetadataKeys:             []string{"x-scope-orgid"},
+			MetadataCardinalityLimit: 50,
+		})
+
+	p3 := cfg.Processors[config.NewIDWithName(typeStr, "4")]
+	assert.Equal(t, p3,
+		&Config{
+			ProcessorSettings:     config.NewProcessorSettings(config.NewIDWithName(typeStr, "4")),
+			SendBatchSize:         defaultSendBatchSize,
+			SendBatchMaxSizeBytes: 5242880,
+			Timeout:               defaultTimeout,
+
+			ResourceAttributeKeys:    []string{"service.name"},
+			MetadataCardinalityLimit: defaultMetadataCardinalityLimit,
 		})
 }
 
@@ -83,3 +108,41 @@ func TestValidateConfig_InvalidBatchSize(t *testing.T) {
 	}
 	assert.Error(t, cfg.Validate())
 }
+
+func TestValidateConfig_DuplicateMetadataKeys(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:        config.NewProcessorSettings(config.NewIDWithName(typeStr, "2")),
+		SendBatchSize:            100,
+		MetadataKeys:             []string{"X-Scope-OrgID", "x-scope-orgid"},
+		MetadataCardinalityLimit: 10,
+	}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestValidateConfig_ZeroMetadataCardinalityLimit(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewIDWithName(typeStr, "2")),
+		SendBatchSize:     100,
+		MetadataKeys:      []string{"x-scope-orgid"},
+	}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestValidateConfig_DuplicateResourceAttributeKeys(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:        config.NewProcessorSettings(config.NewIDWithName(typeStr, "2")),
+		SendBatchSize:            100,
+		ResourceAttributeKeys:    []string{"service.name", "service.name"},
+		MetadataCardinalityLimit: 10,
+	}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestValidateConfig_ResourceAttributeKeysZeroCardinalityLimit(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:     config.NewProcessorSettings(config.NewIDWithName(typeStr, "2")),
+		SendBatchSize:         100,
+		ResourceAttributeKeys: []string{"service.name"},
+	}
+	assert.Error(t, cfg.Validate())
+}
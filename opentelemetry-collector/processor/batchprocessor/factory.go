@@ -31,6 +31,8 @@ const (
 
 	defaultSendBatchSize = uint32(8192)
 	defaultTimeout       = 200 * time.Millisecond
+
+	defaultMetadataCardinalityLimit = uint32(1000)
 )
 
 // NewFactory returns a new factory for the Batch processor.
@@ -48,6 +50,8 @@ func createDefaultConfig() config.Processor {
 		ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr)),
 		SendBatchSize:     defaultSendBatchSize,
 		Timeout:           defaultTimeout,
+
+		MetadataCardinalityLimit: defaultMetadataCardinalityLimit,
 	}
 }
 
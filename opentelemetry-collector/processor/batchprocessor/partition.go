@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// resourcePart is the part of the incoming data whose resources share the same
+// values for the resource attribute keys.
+type resourcePart struct {
+	key  string
+	data interface{}
+}
+
+// partitionByResource splits the data into parts whose resources share the same
+// values for the given attribute keys, in the order the values are first seen.
+// Data whose resources all share the same values is returned as is.
+func partitionByResource(data interface{}, keys []string) []resourcePart {
+	switch d := data.(type) {
+	case pdata.Traces:
+		rss := d.ResourceSpans()
+		return partition(data, rss.Len(),
+			func(i int) pdata.Resource { return rss.At(i).Resource() },
+			func() interface{} { return pdata.NewTraces() },
+			func(i int, dest interface{}) { rss.At(i).CopyTo(dest.(pdata.Traces).ResourceSpans().AppendEmpty()) },
+			keys)
+	case pdata.Metrics:
+		rms := d.ResourceMetrics()
+		return partition(data, rms.Len(),
+			func(i int) pdata.Resource { return rms.At(i).Resource() },
+			func() interface{} { return pdata.NewMetrics() },
+			func(i int, dest interface{}) { rms.At(i).CopyTo(dest.(pdata.Metrics).ResourceMetrics().AppendEmpty()) },
+			keys)
+	case pdata.Logs:
+		rls := d.ResourceLogs()
+		return partition(data, rls.Len(),
+			func(i int) pdata.Resource { return rls.At(i).Resource() },
+			func() interface{} { return pdata.NewLogs() },
+			func(i int, dest interface{}) { rls.At(i).CopyTo(dest.(pdata.Logs).ResourceLogs().AppendEmpty()) },
+			keys)
+	}
+	return []resourcePart{{data: data}}
+}
+
+func partition(
+	data interface{},
+	length int,
+	resourceAt func(i int) pdata.Resource,
+	newData func() interface{},
+	copyTo func(i int, dest interface{}),
+	keys []string,
+) []resourcePart {
+	resourceKeys := make([]string, length)
+	same := true
+	for i := 0; i < length; i++ {
+		resourceKeys[i] = resourceKey(resourceAt(i).Attributes(), keys)
+		same = same && resourceKeys[i] == resourceKeys[0]
+	}
+	if length == 0 {
+		return []resourcePart{{key: resourceKey(pdata.NewAttributeMap(), keys), data: data}}
+	}
+	if same {
+		return []resourcePart{{key: resourceKeys[0], data: data}}
+	}
+
+	var parts []resourcePart
+	index := map[string]int{}
+	for i, key := range resourceKeys {
+		j, ok := index[key]
+		if !ok {
+			j = len(parts)
+			index[key] = j
+			parts = append(parts, resourcePart{key: key, data: newData()})
+		}
+		copyTo(i, parts[j].data)
+	}
+	return parts
+}
+
+// resourceKey identifies the combination of values of the attribute keys.
+func resourceKey(attrs pdata.AttributeMap, keys []string) string {
+	var key strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			key.WriteByte(0)
+		}
+		if v, ok := attrs.Get(k); ok {
+			key.WriteString(strconv.Quote(v.AsString()))
+		}
+	}
+	return key.String()
+}
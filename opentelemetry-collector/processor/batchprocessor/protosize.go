@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+// The size of every piece of the protobuf encoding of the data is derived from the
+// size of data holding only that piece, nested in otherwise empty containers.
+
+const (
+	// emptyMessageFieldSize is the size of an empty embedded message field, its tag and zero length.
+	emptyMessageFieldSize = 2
+	// maxFieldOverhead is an upper bound of the size of the tag and length of an embedded message field.
+	maxFieldOverhead = 1 + 5
+)
+
+func varintSize(x int) int {
+	n := 1
+	for x >= 0x80 {
+		x >>= 7
+		n++
+	}
+	return n
+}
+
+// fieldContentSize returns the size of the content of an embedded message field
+// whose encoding is fieldSize bytes long.
+func fieldContentSize(fieldSize int) int {
+	for n := 1; n < 10; n++ {
+		if content := fieldSize - 1 - n; varintSize(content) == n {
+			return content
+		}
+	}
+	return fieldSize
+}
+
+// resourceHeaderSize returns the size of the fields of a resource container other
+// than its instrumentation library containers, given the size of data holding only
+// that resource container.
+func resourceHeaderSize(dataSize int) int {
+	return fieldContentSize(dataSize)
+}
+
+// libraryHeaderSize returns the size of the fields of an instrumentation library
+// container other than its items, given the size of data holding only that
+// instrumentation library container in an empty resource container.
+func libraryHeaderSize(dataSize int) int {
+	return fieldContentSize(fieldContentSize(dataSize) - emptyMessageFieldSize)
+}
+
+// itemFieldSize returns the size of the field of an item, given the size of data
+// holding only that item in empty containers.
+func itemFieldSize(dataSize int) int {
+	return libraryHeaderSize(dataSize) - emptyMessageFieldSize
+}
@@ -68,3 +68,90 @@ func splitLogs(size int, src pdata.Logs) pdata.Logs {
 
 	return dest
 }
+
+// splitLogsBytes removes log records from the input data and returns a new data whose
+// protobuf size does not exceed maxBytes. At least one log record is always returned,
+// so a log record larger than maxBytes is returned on its own.
+func splitLogsBytes(maxBytes int, src pdata.Logs, sizer pdata.LogsSizer) pdata.Logs {
+	if sizer.LogsSize(src) <= maxBytes {
+		return src
+	}
+	dest := pdata.NewLogs()
+	destBytes := 0
+	full := false
+
+	// scratch holds the log record being measured in empty containers.
+	scratch := pdata.NewLogs()
+	scratchLogs := scratch.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty().Logs()
+
+	src.ResourceLogs().RemoveIf(func(srcRs pdata.ResourceLogs) bool {
+		// If we are done skip everything else.
+		if full {
+			return false
+		}
+
+		header := pdata.NewLogs()
+		headerRs := header.ResourceLogs().AppendEmpty()
+		srcRs.Resource().CopyTo(headerRs.Resource())
+		headerRs.SetSchemaUrl(srcRs.SchemaUrl())
+		rsBytes := maxFieldOverhead + resourceHeaderSize(sizer.LogsSize(header))
+		var destRs pdata.ResourceLogs
+		hasDestRs := false
+
+		srcRs.InstrumentationLibraryLogs().RemoveIf(func(srcIls pdata.InstrumentationLibraryLogs) bool {
+			// If we are done skip everything else.
+			if full {
+				return false
+			}
+
+			header := pdata.NewLogs()
+			headerIls := header.ResourceLogs().AppendEmpty().InstrumentationLibraryLogs().AppendEmpty()
+			srcIls.InstrumentationLibrary().CopyTo(headerIls.InstrumentationLibrary())
+			headerIls.SetSchemaUrl(srcIls.SchemaUrl())
+			ilsBytes := maxFieldOverhead + libraryHeaderSize(sizer.LogsSize(header))
+			var destIls pdata.InstrumentationLibraryLogs
+			hasDestIls := false
+
+			srcIls.Logs().RemoveIf(func(srcLog pdata.LogRecord) bool {
+				// If we are done skip everything else.
+				if full {
+					return false
+				}
+
+				srcLog.CopyTo(scratchLogs.AppendEmpty())
+				needed := itemFieldSize(sizer.LogsSize(scratch))
+				if !hasDestIls {
+					needed += ilsBytes
+				}
+				if !hasDestRs {
+					needed += rsBytes
+				}
+				if destBytes > 0 && destBytes+needed > maxBytes {
+					full = true
+					scratchLogs.RemoveIf(func(pdata.LogRecord) bool { return true })
+					return false
+				}
+
+				if !hasDestRs {
+					destRs = dest.ResourceLogs().AppendEmpty()
+					srcRs.Resource().CopyTo(destRs.Resource())
+					destRs.SetSchemaUrl(srcRs.SchemaUrl())
+					hasDestRs = true
+				}
+				if !hasDestIls {
+					destIls = destRs.InstrumentationLibraryLogs().AppendEmpty()
+					srcIls.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+					destIls.SetSchemaUrl(srcIls.SchemaUrl())
+					hasDestIls = true
+				}
+				destBytes += needed
+				scratchLogs.MoveAndAppendTo(destIls.Logs())
+				return true
+			})
+			return srcIls.Logs().Len() == 0
+		})
+		return srcRs.InstrumentationLibraryLogs().Len() == 0
+	})
+
+	return dest
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -80,6 +81,27 @@ func TestSplitLogs(t *testing.T) {
 	assert.Equal(t, "test-log-int-0-19", split.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(4).Name())
 }
 
+func TestSplitLogsBytes(t *testing.T) {
+	sizer := otlp.NewProtobufLogsMarshaler().(pdata.LogsSizer)
+	ld := testdata.GenerateLogsManyLogRecordsSameResource(20)
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	for i := 0; i < logs.Len(); i++ {
+		logs.At(i).SetName(getTestLogName(0, i))
+	}
+
+	maxBytes := sizer.LogsSize(ld) / 4
+	logCount := 0
+	for ld.LogRecordCount() > 0 {
+		split := splitLogsBytes(maxBytes, ld, sizer)
+		assert.LessOrEqual(t, sizer.LogsSize(split), maxBytes)
+		logCount += split.LogRecordCount()
+		if split == ld {
+			break
+		}
+	}
+	assert.Equal(t, 20, logCount)
+}
+
 func TestSplitLogsMultipleResourceLogs(t *testing.T) {
 	td := testdata.GenerateLogsManyLogRecordsSameResource(20)
 	logs := td.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
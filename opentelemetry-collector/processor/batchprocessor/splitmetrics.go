@@ -154,3 +154,90 @@ func splitMetric(ms, dest pdata.Metric, size int) (int, bool) {
 	}
 	return size, false
 }
+
+// splitMetricsBytes removes metrics from the input data and returns a new data whose
+// protobuf size does not exceed maxBytes. At least one metric is always returned,
+// so a metric larger than maxBytes is returned on its own.
+func splitMetricsBytes(maxBytes int, src pdata.Metrics, sizer pdata.MetricsSizer) pdata.Metrics {
+	if sizer.MetricsSize(src) <= maxBytes {
+		return src
+	}
+	dest := pdata.NewMetrics()
+	destBytes := 0
+	full := false
+
+	// scratch holds the metric being measured in empty containers.
+	scratch := pdata.NewMetrics()
+	scratchMetrics := scratch.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty().Metrics()
+
+	src.ResourceMetrics().RemoveIf(func(srcRs pdata.ResourceMetrics) bool {
+		// If we are done skip everything else.
+		if full {
+			return false
+		}
+
+		header := pdata.NewMetrics()
+		headerRs := header.ResourceMetrics().AppendEmpty()
+		srcRs.Resource().CopyTo(headerRs.Resource())
+		headerRs.SetSchemaUrl(srcRs.SchemaUrl())
+		rsBytes := maxFieldOverhead + resourceHeaderSize(sizer.MetricsSize(header))
+		var destRs pdata.ResourceMetrics
+		hasDestRs := false
+
+		srcRs.InstrumentationLibraryMetrics().RemoveIf(func(srcIls pdata.InstrumentationLibraryMetrics) bool {
+			// If we are done skip everything else.
+			if full {
+				return false
+			}
+
+			header := pdata.NewMetrics()
+			headerIls := header.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
+			srcIls.InstrumentationLibrary().CopyTo(headerIls.InstrumentationLibrary())
+			headerIls.SetSchemaUrl(srcIls.SchemaUrl())
+			ilsBytes := maxFieldOverhead + libraryHeaderSize(sizer.MetricsSize(header))
+			var destIls pdata.InstrumentationLibraryMetrics
+			hasDestIls := false
+
+			srcIls.Metrics().RemoveIf(func(srcMetric pdata.Metric) bool {
+				// If we are done skip everything else.
+				if full {
+					return false
+				}
+
+				srcMetric.CopyTo(scratchMetrics.AppendEmpty())
+				needed := itemFieldSize(sizer.MetricsSize(scratch))
+				if !hasDestIls {
+					needed += ilsBytes
+				}
+				if !hasDestRs {
+					needed += rsBytes
+				}
+				if destBytes > 0 && destBytes+needed > maxBytes {
+					full = true
+					scratchMetrics.RemoveIf(func(pdata.Metric) bool { return true })
+					return false
+				}
+
+				if !hasDestRs {
+					destRs = dest.ResourceMetrics().AppendEmpty()
+					srcRs.Resource().CopyTo(destRs.Resource())
+					destRs.SetSchemaUrl(srcRs.SchemaUrl())
+					hasDestRs = true
+				}
+				if !hasDestIls {
+					destIls = destRs.InstrumentationLibraryMetrics().AppendEmpty()
+					srcIls.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+					destIls.SetSchemaUrl(srcIls.SchemaUrl())
+					hasDestIls = true
+				}
+				destBytes += needed
+				scratchMetrics.MoveAndAppendTo(destIls.Metrics())
+				return true
+			})
+			return srcIls.Metrics().Len() == 0
+		})
+		return srcRs.InstrumentationLibraryMetrics().Len() == 0
+	})
+
+	return dest
+}
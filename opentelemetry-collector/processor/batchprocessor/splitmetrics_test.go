@@ -20,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -179,6 +180,27 @@ func BenchmarkCloneMetrics(b *testing.B) {
 	}
 }
 
+func TestSplitMetricsBytes(t *testing.T) {
+	sizer := otlp.NewProtobufMetricsMarshaler().(pdata.MetricsSizer)
+	md := testdata.GenerateMetricsManyMetricsSameResource(20)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		metrics.At(i).SetName(getTestMetricName(0, i))
+	}
+
+	maxBytes := sizer.MetricsSize(md) / 4
+	metricCount := 0
+	for md.MetricCount() > 0 {
+		split := splitMetricsBytes(maxBytes, md, sizer)
+		assert.LessOrEqual(t, sizer.MetricsSize(split), maxBytes)
+		metricCount += split.MetricCount()
+		if split == md {
+			break
+		}
+	}
+	assert.Equal(t, 20, metricCount)
+}
+
 func TestSplitMetricsUneven(t *testing.T) {
 	md := testdata.GenerateMetricsManyMetricsSameResource(10)
 	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
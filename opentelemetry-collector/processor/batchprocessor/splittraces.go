@@ -68,3 +68,90 @@ func splitTraces(size int, src pdata.Traces) pdata.Traces {
 
 	return dest
 }
+
+// splitTracesBytes removes spans from the input data and returns a new data whose
+// protobuf size does not exceed maxBytes. At least one span is always returned,
+// so a span larger than maxBytes is returned on its own.
+func splitTracesBytes(maxBytes int, src pdata.Traces, sizer pdata.TracesSizer) pdata.Traces {
+	if sizer.TracesSize(src) <= maxBytes {
+		return src
+	}
+	dest := pdata.NewTraces()
+	destBytes := 0
+	full := false
+
+	// scratch holds the span being measured in empty containers.
+	scratch := pdata.NewTraces()
+	scratchSpans := scratch.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty().Spans()
+
+	src.ResourceSpans().RemoveIf(func(srcRs pdata.ResourceSpans) bool {
+		// If we are done skip everything else.
+		if full {
+			return false
+		}
+
+		header := pdata.NewTraces()
+		headerRs := header.ResourceSpans().AppendEmpty()
+		srcRs.Resource().CopyTo(headerRs.Resource())
+		headerRs.SetSchemaUrl(srcRs.SchemaUrl())
+		rsBytes := maxFieldOverhead + resourceHeaderSize(sizer.TracesSize(header))
+		var destRs pdata.ResourceSpans
+		hasDestRs := false
+
+		srcRs.InstrumentationLibrarySpans().RemoveIf(func(srcIls pdata.InstrumentationLibrarySpans) bool {
+			// If we are done skip everything else.
+			if full {
+				return false
+			}
+
+			header := pdata.NewTraces()
+			headerIls := header.ResourceSpans().AppendEmpty().InstrumentationLibrarySpans().AppendEmpty()
+			srcIls.InstrumentationLibrary().CopyTo(headerIls.InstrumentationLibrary())
+			headerIls.SetSchemaUrl(srcIls.SchemaUrl())
+			ilsBytes := maxFieldOverhead + libraryHeaderSize(sizer.TracesSize(header))
+			var destIls pdata.InstrumentationLibrarySpans
+			hasDestIls := false
+
+			srcIls.Spans().RemoveIf(func(srcSpan pdata.Span) bool {
+				// If we are done skip everything else.
+				if full {
+					return false
+				}
+
+				srcSpan.CopyTo(scratchSpans.AppendEmpty())
+				needed := itemFieldSize(sizer.TracesSize(scratch))
+				if !hasDestIls {
+					needed += ilsBytes
+				}
+				if !hasDestRs {
+					needed += rsBytes
+				}
+				if destBytes > 0 && destBytes+needed > maxBytes {
+					full = true
+					scratchSpans.RemoveIf(func(pdata.Span) bool { return true })
+					return false
+				}
+
+				if !hasDestRs {
+					destRs = dest.ResourceSpans().AppendEmpty()
+					srcRs.Resource().CopyTo(destRs.Resource())
+					destRs.SetSchemaUrl(srcRs.SchemaUrl())
+					hasDestRs = true
+				}
+				if !hasDestIls {
+					destIls = destRs.InstrumentationLibrarySpans().AppendEmpty()
+					srcIls.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+					destIls.SetSchemaUrl(srcIls.SchemaUrl())
+					hasDestIls = true
+				}
+				destBytes += needed
+				scratchSpans.MoveAndAppendTo(destIls.Spans())
+				return true
+			})
+			return srcIls.Spans().Len() == 0
+		})
+		return srcRs.InstrumentationLibrarySpans().Len() == 0
+	})
+
+	return dest
+}
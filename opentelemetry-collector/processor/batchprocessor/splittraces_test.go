@@ -15,11 +15,14 @@
 package batchprocessor
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/otlp"
 	"go.opentelemetry.io/collector/model/pdata"
 )
 
@@ -127,6 +130,65 @@ func TestSplitTracesMultipleResourceSpans_SplitSizeGreaterThanSpanSize(t *testin
 	assert.Equal(t, "test-span-1-4", split.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(4).Name())
 }
 
+func TestSplitTracesBytes(t *testing.T) {
+	sizer := otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)
+	td := testdata.GenerateTracesManySpansSameResource(20)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetName(getTestSpanName(0, i))
+	}
+	// add second index to resource spans
+	testdata.GenerateTracesManySpansSameResource(20).
+		ResourceSpans().At(0).CopyTo(td.ResourceSpans().AppendEmpty())
+	spans = td.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).SetName(getTestSpanName(1, i))
+	}
+
+	totalSize := sizer.TracesSize(td)
+	maxBytes := totalSize / 3
+	var names []string
+	for td.SpanCount() > 0 {
+		split := splitTracesBytes(maxBytes, td, sizer)
+		assert.LessOrEqual(t, sizer.TracesSize(split), maxBytes)
+		// Not much smaller than the limit, unless this is the remaining data.
+		if split != td {
+			assert.Greater(t, sizer.TracesSize(split), maxBytes-maxBytes/10)
+		}
+		for i := 0; i < split.ResourceSpans().Len(); i++ {
+			ilss := split.ResourceSpans().At(i).InstrumentationLibrarySpans()
+			for j := 0; j < ilss.Len(); j++ {
+				for k := 0; k < ilss.At(j).Spans().Len(); k++ {
+					names = append(names, ilss.At(j).Spans().At(k).Name())
+				}
+			}
+		}
+		if split == td {
+			break
+		}
+	}
+	require.Len(t, names, 40)
+	assert.Equal(t, "test-span-0-0", names[0])
+	assert.Equal(t, "test-span-1-19", names[39])
+}
+
+func TestSplitTracesBytes_noop(t *testing.T) {
+	sizer := otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)
+	td := testdata.GenerateTracesManySpansSameResource(20)
+	split := splitTracesBytes(sizer.TracesSize(td), td, sizer)
+	assert.Equal(t, td, split)
+}
+
+func TestSplitTracesBytes_SpanLargerThanMaxBytes(t *testing.T) {
+	sizer := otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)
+	td := testdata.GenerateTracesManySpansSameResource(3)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetName(strings.Repeat("x", 1000))
+
+	split := splitTracesBytes(100, td, sizer)
+	assert.Equal(t, 1, split.SpanCount())
+	assert.Equal(t, 2, td.SpanCount())
+}
+
 func BenchmarkSplitTraces(b *testing.B) {
 	td := pdata.NewTraces()
 	rms := td.ResourceSpans()
@@ -51,6 +51,22 @@ type Config struct {
 	// MemorySpikePercentage is the maximum, in percents against the total memory,
 	// spike expected between the measurements of memory usage.
 	MemorySpikePercentage uint32 `mapstructure:"spike_limit_percentage"`
+
+	// PerPipeline makes every pipeline using this processor track the bytes of data
+	// it holds, so that only the pipeline holding the most data is limited when the
+	// memory usage is above the soft limit.
+	PerPipeline bool `mapstructure:"per_pipeline"`
+	// PipelineLimitMiB is the maximum amount of data, in MiB, a single pipeline can
+	// hold before refusing data regardless of the memory usage of the process.
+	// Requires PerPipeline, defaults to zero, so no limit is applied.
+	PipelineLimitMiB uint32 `mapstructure:"pipeline_limit_mib"`
+
+	// SoftLimitMode is the action taken when the memory usage is between the soft
+	// and the hard limit, either "refuse" (default) the data or "sample" it down.
+	SoftLimitMode string `mapstructure:"soft_limit_mode"`
+	// SoftLimitSamplingPercentage is the percentage of data kept when SoftLimitMode
+	// is "sample". Defaults to 50.
+	SoftLimitSamplingPercentage uint32 `mapstructure:"soft_limit_sampling_percentage"`
 }
 
 var _ config.Processor = (*Config)(nil)
@@ -60,5 +76,11 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
+// Values of SoftLimitMode.
+const (
+	softLimitModeRefuse = "refuse"
+	softLimitModeSample = "sample"
+)
+
 // Name of BallastSizeMiB config option.
 const ballastSizeMibKey = "ballast_size_mib"
@@ -54,4 +54,16 @@ func TestLoadConfig(t *testing.T) {
 			MemorySpikeLimitMiB: 500,
 			BallastSizeMiB:      2000,
 		})
+
+	p2 := cfg.Processors[config.NewIDWithName(typeStr, "per-pipeline")]
+	assert.Equal(t, p2,
+		&Config{
+			ProcessorSettings:           config.NewProcessorSettings(config.NewIDWithName(typeStr, "per-pipeline")),
+			CheckInterval:               time.Second,
+			MemoryLimitMiB:              4000,
+			PerPipeline:                 true,
+			PipelineLimitMiB:            1000,
+			SoftLimitMode:               "sample",
+			SoftLimitSamplingPercentage: 25,
+		})
 }
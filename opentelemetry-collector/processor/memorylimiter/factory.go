@@ -30,6 +30,9 @@ const (
 
 var processorCapabilities = consumer.Capabilities{MutatesData: false}
 
+// samplingProcessorCapabilities are the capabilities when data is sampled above the soft limit.
+var samplingProcessorCapabilities = consumer.Capabilities{MutatesData: true}
+
 // NewFactory returns a new factory for the Memory Limiter processor.
 func NewFactory() component.ProcessorFactory {
 	return processorhelper.NewFactory(
@@ -60,9 +63,9 @@ func createTracesProcessor(
 	}
 	return processorhelper.NewTracesProcessor(
 		cfg,
-		nextConsumer,
+		ml.trackTraces(nextConsumer),
 		ml.processTraces,
-		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithCapabilities(ml.capabilities()),
 		processorhelper.WithStart(ml.start),
 		processorhelper.WithShutdown(ml.shutdown))
 }
@@ -79,9 +82,9 @@ func createMetricsProcessor(
 	}
 	return processorhelper.NewMetricsProcessor(
 		cfg,
-		nextConsumer,
+		ml.trackMetrics(nextConsumer),
 		ml.processMetrics,
-		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithCapabilities(ml.capabilities()),
 		processorhelper.WithShutdown(ml.shutdown))
 }
 
@@ -97,8 +100,8 @@ func createLogsProcessor(
 	}
 	return processorhelper.NewLogsProcessor(
 		cfg,
-		nextConsumer,
+		ml.trackLogs(nextConsumer),
 		ml.processLogs,
-		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithCapabilities(ml.capabilities()),
 		processorhelper.WithShutdown(ml.shutdown))
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"runtime"
 	"sync/atomic"
 	"time"
@@ -26,10 +27,12 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/extension/ballastextension"
 	"go.opentelemetry.io/collector/internal/iruntime"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor/processorhelper"
 )
 
 const (
@@ -55,6 +58,28 @@ var (
 	errPercentageLimitOutOfRange = errors.New(
 		"memoryLimitPercentage and memorySpikePercentage must be greater than zero and less than or equal to hundred",
 	)
+
+	errPipelineLimitWithoutPerPipeline = errors.New(
+		"pipelineLimitMiB requires perPipeline")
+
+	errSoftLimitModeInvalid = errors.New(
+		"softLimitMode must be either refuse or sample")
+
+	errSamplingPercentageOutOfRange = errors.New(
+		"softLimitSamplingPercentage must be greater than zero and less than hundred")
+)
+
+// defaultSoftLimitSamplingPercentage is the percentage of data kept in sample mode
+// when not configured.
+const defaultSoftLimitSamplingPercentage = 50
+
+// decision is the action taken on the data received by the processor.
+type decision int
+
+const (
+	accept decision = iota
+	sample
+	refuse
 )
 
 // make it overridable by tests
@@ -68,6 +93,16 @@ type memoryLimiter struct {
 
 	// forceDrop is used atomically to indicate when data should be dropped.
 	forceDrop int64
+	// hardLimited is used atomically to indicate when memory usage is above the hard limit.
+	hardLimited int64
+
+	// samplingRatio is the ratio of data kept above the soft limit, zero when
+	// the data is refused instead.
+	samplingRatio float64
+	randFloat     func() float64
+
+	// pipeline is only set when limiting per pipeline.
+	pipeline *pipelineTracker
 
 	ticker *time.Ticker
 
@@ -96,6 +131,13 @@ func newMemoryLimiter(logger *zap.Logger, cfg *Config) (*memoryLimiter, error) {
 	if cfg.MemoryLimitMiB == 0 && cfg.MemoryLimitPercentage == 0 {
 		return nil, errLimitOutOfRange
 	}
+	if cfg.PipelineLimitMiB != 0 && !cfg.PerPipeline {
+		return nil, errPipelineLimitWithoutPerPipeline
+	}
+	samplingRatio, err := getSamplingRatio(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	usageChecker, err := getMemUsageChecker(cfg, logger)
 	if err != nil {
@@ -105,23 +147,48 @@ func newMemoryLimiter(logger *zap.Logger, cfg *Config) (*memoryLimiter, error) {
 	logger.Info("Memory limiter configured",
 		zap.Uint64("limit_mib", usageChecker.memAllocLimit/mibBytes),
 		zap.Uint64("spike_limit_mib", usageChecker.memSpikeLimit/mibBytes),
-		zap.Duration("check_interval", cfg.CheckInterval))
+		zap.Duration("check_interval", cfg.CheckInterval),
+		zap.Bool("per_pipeline", cfg.PerPipeline),
+		zap.Float64("soft_limit_sampling_ratio", samplingRatio))
 
 	ml := &memoryLimiter{
 		usageChecker:   *usageChecker,
 		memCheckWait:   cfg.CheckInterval,
 		ticker:         time.NewTicker(cfg.CheckInterval),
 		readMemStatsFn: runtime.ReadMemStats,
+		samplingRatio:  samplingRatio,
+		randFloat:      rand.Float64,
 		logger:         logger,
 		obsrep: obsreport.NewProcessor(obsreport.ProcessorSettings{
 			Level:       configtelemetry.GetMetricsLevelFlagValue(),
 			ProcessorID: cfg.ID(),
 		}),
 	}
+	if cfg.PerPipeline {
+		ml.pipeline = newPipelineTracker(cfg.ID(), uint64(cfg.PipelineLimitMiB)*mibBytes)
+	}
 
 	return ml, nil
 }
 
+func getSamplingRatio(cfg *Config) (float64, error) {
+	switch cfg.SoftLimitMode {
+	case "", softLimitModeRefuse:
+		return 0, nil
+	case softLimitModeSample:
+	default:
+		return 0, errSoftLimitModeInvalid
+	}
+	percentage := cfg.SoftLimitSamplingPercentage
+	if percentage == 0 {
+		percentage = defaultSoftLimitSamplingPercentage
+	}
+	if percentage >= 100 {
+		return 0, errSamplingPercentageOutOfRange
+	}
+	return float64(percentage) / 100, nil
+}
+
 func getMemUsageChecker(cfg *Config, logger *zap.Logger) (*memUsageChecker, error) {
 	memAllocLimit := uint64(cfg.MemoryLimitMiB) * mibBytes
 	memSpikeLimit := uint64(cfg.MemorySpikeLimitMiB) * mibBytes
@@ -154,12 +221,42 @@ func (ml *memoryLimiter) start(_ context.Context, host component.Host) error {
 
 func (ml *memoryLimiter) shutdown(context.Context) error {
 	ml.ticker.Stop()
+	if ml.pipeline != nil {
+		ml.pipeline.unregister()
+	}
 	return nil
 }
 
+func (ml *memoryLimiter) capabilities() consumer.Capabilities {
+	if ml.samplingRatio > 0 {
+		return samplingProcessorCapabilities
+	}
+	return processorCapabilities
+}
+
+// decide returns the action to take on the data received by the processor.
+func (ml *memoryLimiter) decide() decision {
+	if ml.pipeline != nil && ml.pipeline.overLimit() {
+		return refuse
+	}
+	if !ml.forcingDrop() {
+		return accept
+	}
+	// Only the pipeline holding the most data is limited. When no pipeline holds
+	// any data, the memory cannot be attributed and all pipelines are limited.
+	if ml.pipeline != nil && !ml.pipeline.holdsMost() {
+		return accept
+	}
+	if ml.samplingRatio > 0 && !ml.hardLimitReached() {
+		return sample
+	}
+	return refuse
+}
+
 func (ml *memoryLimiter) processTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
 	numSpans := td.SpanCount()
-	if ml.forcingDrop() {
+	switch ml.decide() {
+	case refuse:
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -168,6 +265,14 @@ func (ml *memoryLimiter) processTraces(ctx context.Context, td pdata.Traces) (pd
 		ml.obsrep.TracesRefused(ctx, numSpans)
 
 		return td, errForcedDrop
+	case sample:
+		ml.sampleTraces(td)
+		sampled := td.SpanCount()
+		ml.obsrep.TracesDropped(ctx, numSpans-sampled)
+		if sampled == 0 {
+			return td, processorhelper.ErrSkipProcessingData
+		}
+		numSpans = sampled
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -178,7 +283,8 @@ func (ml *memoryLimiter) processTraces(ctx context.Context, td pdata.Traces) (pd
 
 func (ml *memoryLimiter) processMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
 	numDataPoints := md.DataPointCount()
-	if ml.forcingDrop() {
+	switch ml.decide() {
+	case refuse:
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -186,6 +292,14 @@ func (ml *memoryLimiter) processMetrics(ctx context.Context, md pdata.Metrics) (
 		// 	callstack.
 		ml.obsrep.MetricsRefused(ctx, numDataPoints)
 		return md, errForcedDrop
+	case sample:
+		ml.sampleMetrics(md)
+		sampled := md.DataPointCount()
+		ml.obsrep.MetricsDropped(ctx, numDataPoints-sampled)
+		if sampled == 0 {
+			return md, processorhelper.ErrSkipProcessingData
+		}
+		numDataPoints = sampled
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -196,7 +310,8 @@ func (ml *memoryLimiter) processMetrics(ctx context.Context, md pdata.Metrics) (
 
 func (ml *memoryLimiter) processLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
 	numRecords := ld.LogRecordCount()
-	if ml.forcingDrop() {
+	switch ml.decide() {
+	case refuse:
 		// TODO: actually to be 100% sure that this is "refused" and not "dropped"
 		// 	it is necessary to check the pipeline to see if this is directly connected
 		// 	to a receiver (ie.: a receiver is on the call stack). For now it
@@ -205,6 +320,14 @@ func (ml *memoryLimiter) processLogs(ctx context.Context, ld pdata.Logs) (pdata.
 		ml.obsrep.LogsRefused(ctx, numRecords)
 
 		return ld, errForcedDrop
+	case sample:
+		ml.sampleLogs(ld)
+		sampled := ld.LogRecordCount()
+		ml.obsrep.LogsDropped(ctx, numRecords-sampled)
+		if sampled == 0 {
+			return ld, processorhelper.ErrSkipProcessingData
+		}
+		numRecords = sampled
 	}
 
 	// Even if the next consumer returns error record the data as accepted by
@@ -252,6 +375,19 @@ func (ml *memoryLimiter) setForcingDrop(b bool) {
 	atomic.StoreInt64(&ml.forceDrop, i)
 }
 
+// hardLimitReached indicates when memory usage is above the hard limit.
+func (ml *memoryLimiter) hardLimitReached() bool {
+	return atomic.LoadInt64(&ml.hardLimited) != 0
+}
+
+func (ml *memoryLimiter) setHardLimitReached(b bool) {
+	var i int64
+	if b {
+		i = 1
+	}
+	atomic.StoreInt64(&ml.hardLimited, i)
+}
+
 func memstatToZapField(ms *runtime.MemStats) zap.Field {
 	return zap.Uint64("cur_mem_mib", ms.Alloc/mibBytes)
 }
@@ -300,6 +436,7 @@ func (ml *memoryLimiter) checkMemLimits() {
 		}
 	}
 
+	ml.setHardLimitReached(ml.usageChecker.aboveHardLimit(ms))
 	ml.setForcingDrop(mustForceDrop)
 }
 
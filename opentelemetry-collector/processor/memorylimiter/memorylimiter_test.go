@@ -31,6 +31,8 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/extension/ballastextension"
 	"go.opentelemetry.io/collector/internal/iruntime"
+	"go.opentelemetry.io/collector/internal/retention"
+	"go.opentelemetry.io/collector/internal/testdata"
 	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/processor/processorhelper"
@@ -42,6 +44,9 @@ func TestNew(t *testing.T) {
 		checkInterval       time.Duration
 		memoryLimitMiB      uint32
 		memorySpikeLimitMiB uint32
+		pipelineLimitMiB    uint32
+		softLimitMode       string
+		samplingPercentage  uint32
 	}
 	sink := new(consumertest.TracesSink)
 	tests := []struct {
@@ -74,6 +79,46 @@ func TestNew(t *testing.T) {
 			},
 			wantErr: errMemSpikeLimitOutOfRange,
 		},
+		{
+			name: "pipelineLimit_without_perPipeline",
+			args: args{
+				nextConsumer:     sink,
+				checkInterval:    100 * time.Millisecond,
+				memoryLimitMiB:   1024,
+				pipelineLimitMiB: 256,
+			},
+			wantErr: errPipelineLimitWithoutPerPipeline,
+		},
+		{
+			name: "invalid_softLimitMode",
+			args: args{
+				nextConsumer:   sink,
+				checkInterval:  100 * time.Millisecond,
+				memoryLimitMiB: 1024,
+				softLimitMode:  "drop",
+			},
+			wantErr: errSoftLimitModeInvalid,
+		},
+		{
+			name: "samplingPercentage_out_of_range",
+			args: args{
+				nextConsumer:       sink,
+				checkInterval:      100 * time.Millisecond,
+				memoryLimitMiB:     1024,
+				softLimitMode:      softLimitModeSample,
+				samplingPercentage: 100,
+			},
+			wantErr: errSamplingPercentageOutOfRange,
+		},
+		{
+			name: "success_sample",
+			args: args{
+				nextConsumer:   sink,
+				checkInterval:  100 * time.Millisecond,
+				memoryLimitMiB: 1024,
+				softLimitMode:  softLimitModeSample,
+			},
+		},
 		{
 			name: "success",
 			args: args{
@@ -89,6 +134,9 @@ func TestNew(t *testing.T) {
 			cfg.CheckInterval = tt.args.checkInterval
 			cfg.MemoryLimitMiB = tt.args.memoryLimitMiB
 			cfg.MemorySpikeLimitMiB = tt.args.memorySpikeLimitMiB
+			cfg.PipelineLimitMiB = tt.args.pipelineLimitMiB
+			cfg.SoftLimitMode = tt.args.softLimitMode
+			cfg.SoftLimitSamplingPercentage = tt.args.samplingPercentage
 			got, err := newMemoryLimiter(zap.NewNop(), cfg)
 			if err != tt.wantErr {
 				t.Errorf("newMemoryLimiter() error = %v, wantErr %v", err, tt.wantErr)
@@ -314,6 +362,260 @@ func TestLogMemoryPressureResponse(t *testing.T) {
 	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, ld))
 }
 
+// blockingLogs is a logs consumer blocking until unblock is closed, like a slow exporter would.
+type blockingLogs struct {
+	consumertest.Consumer
+	blocked chan struct{}
+	unblock chan struct{}
+}
+
+func (bl *blockingLogs) ConsumeLogs(context.Context, pdata.Logs) error {
+	close(bl.blocked)
+	<-bl.unblock
+	return nil
+}
+
+func TestPerPipelineMemoryPressureResponse(t *testing.T) {
+	var currentMemAlloc uint64
+	cfg := createDefaultConfig().(*Config)
+	cfg.CheckInterval = time.Second
+	cfg.MemoryLimitMiB = 1
+	cfg.PerPipeline = true
+	newLimiter := func() *memoryLimiter {
+		ml, err := newMemoryLimiter(zap.NewNop(), cfg)
+		require.NoError(t, err)
+		ml.readMemStatsFn = func(ms *runtime.MemStats) {
+			ms.Alloc = currentMemAlloc
+		}
+		t.Cleanup(func() { assert.NoError(t, ml.shutdown(context.Background())) })
+		return ml
+	}
+
+	tracesML := newLimiter()
+	tp, err := processorhelper.NewTracesProcessor(cfg, tracesML.trackTraces(consumertest.NewNop()), tracesML.processTraces)
+	require.NoError(t, err)
+
+	logsML := newLimiter()
+	slowExporter := &blockingLogs{
+		Consumer: consumertest.NewNop(),
+		blocked:  make(chan struct{}),
+		unblock:  make(chan struct{}),
+	}
+	lp, err := processorhelper.NewLogsProcessor(cfg, logsML.trackLogs(slowExporter), logsML.processLogs)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// The logs pipeline holds data in its slow exporter.
+	done := make(chan error)
+	go func() {
+		done <- lp.ConsumeLogs(ctx, testdata.GenerateLogsOneLogRecord())
+	}()
+	<-slowExporter.blocked
+	assert.Greater(t, logsML.pipeline.heldBytes(), int64(0))
+
+	// Above memAllocLimit only the logs pipeline is limited.
+	currentMemAlloc = 2 * mibBytes
+	tracesML.checkMemLimits()
+	logsML.checkMemLimits()
+	assert.NoError(t, tp.ConsumeTraces(ctx, testdata.GenerateTracesOneSpan()))
+	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, testdata.GenerateLogsOneLogRecord()))
+
+	close(slowExporter.unblock)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int64(0), logsML.pipeline.heldBytes())
+
+	// No pipeline holds data anymore, the memory usage cannot be attributed.
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, testdata.GenerateTracesOneSpan()))
+	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, testdata.GenerateLogsOneLogRecord()))
+
+	// Below memAllocLimit.
+	currentMemAlloc = 0
+	tracesML.checkMemLimits()
+	assert.NoError(t, tp.ConsumeTraces(ctx, testdata.GenerateTracesOneSpan()))
+}
+
+// queuedLogs keeps the logs after returning, like a batch processor or a sending queue.
+type queuedLogs struct {
+	consumertest.Consumer
+	releases []func()
+}
+
+func (ql *queuedLogs) ConsumeLogs(ctx context.Context, _ pdata.Logs) error {
+	ql.releases = append(ql.releases, retention.Retain(ctx))
+	return nil
+}
+
+func TestPerPipelineQueuedData(t *testing.T) {
+	var currentMemAlloc uint64
+	cfg := createDefaultConfig().(*Config)
+	cfg.CheckInterval = time.Second
+	cfg.MemoryLimitMiB = 1
+	cfg.PerPipeline = true
+	newLimiter := func() *memoryLimiter {
+		ml, err := newMemoryLimiter(zap.NewNop(), cfg)
+		require.NoError(t, err)
+		ml.readMemStatsFn = func(ms *runtime.MemStats) {
+			ms.Alloc = currentMemAlloc
+		}
+		t.Cleanup(func() { assert.NoError(t, ml.shutdown(context.Background())) })
+		return ml
+	}
+
+	tracesML := newLimiter()
+	tp, err := processorhelper.NewTracesProcessor(cfg, tracesML.trackTraces(consumertest.NewNop()), tracesML.processTraces)
+	require.NoError(t, err)
+
+	logsML := newLimiter()
+	queue := &queuedLogs{Consumer: consumertest.NewNop()}
+	lp, err := processorhelper.NewLogsProcessor(cfg, logsML.trackLogs(queue), logsML.processLogs)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// The logs stay held by the pipeline after the queue returned.
+	require.NoError(t, lp.ConsumeLogs(ctx, testdata.GenerateLogsOneLogRecord()))
+	require.NoError(t, lp.ConsumeLogs(ctx, testdata.GenerateLogsOneLogRecord()))
+	require.Len(t, queue.releases, 2)
+	held := logsML.pipeline.heldBytes()
+	assert.Greater(t, held, int64(0))
+	assert.Equal(t, int64(0), tracesML.pipeline.heldBytes())
+
+	// Above memAllocLimit only the logs pipeline is limited.
+	currentMemAlloc = 2 * mibBytes
+	tracesML.checkMemLimits()
+	logsML.checkMemLimits()
+	assert.NoError(t, tp.ConsumeTraces(ctx, testdata.GenerateTracesOneSpan()))
+	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, testdata.GenerateLogsOneLogRecord()))
+
+	// Releasing the data more than once has no effect.
+	queue.releases[0]()
+	queue.releases[0]()
+	assert.Equal(t, held/2, logsML.pipeline.heldBytes())
+	queue.releases[1]()
+	assert.Equal(t, int64(0), logsML.pipeline.heldBytes())
+}
+
+func TestPipelineLimit(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.CheckInterval = time.Second
+	cfg.MemoryLimitMiB = 1024
+	cfg.PerPipeline = true
+	cfg.PipelineLimitMiB = 1
+	ml, err := newMemoryLimiter(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, ml.shutdown(context.Background())) }()
+
+	ml.readMemStatsFn = func(ms *runtime.MemStats) {}
+	ml.checkMemLimits()
+	assert.Equal(t, accept, ml.decide())
+
+	h := ml.pipeline.hold(mibBytes)
+	assert.Equal(t, refuse, ml.decide())
+	h.release()
+	assert.Equal(t, accept, ml.decide())
+}
+
+func TestPipelineTrackerUnregister(t *testing.T) {
+	id := config.NewIDWithName(typeStr, "unregister")
+	pt1 := newPipelineTracker(id, 0)
+	pt2 := newPipelineTracker(id, 0)
+	assert.Same(t, pt1.group, pt2.group)
+	assert.Len(t, pt1.group.trackers, 2)
+
+	pt1.unregister()
+	assert.Len(t, pt2.group.trackers, 1)
+	pt2.unregister()
+	assert.NotContains(t, pipelineGroups.groups, id)
+}
+
+func TestSoftLimitSampling(t *testing.T) {
+	var currentMemAlloc uint64
+	keep := false
+	ml := &memoryLimiter{
+		usageChecker: memUsageChecker{
+			memAllocLimit: 1024,
+			memSpikeLimit: 512,
+		},
+		readMemStatsFn: func(ms *runtime.MemStats) {
+			ms.Alloc = currentMemAlloc
+		},
+		samplingRatio: 0.5,
+		randFloat: func() float64 {
+			keep = !keep
+			if keep {
+				return 0.25
+			}
+			return 0.75
+		},
+		obsrep: obsreport.NewProcessor(obsreport.ProcessorSettings{
+			Level:       configtelemetry.LevelNone,
+			ProcessorID: config.NewID(typeStr),
+		}),
+		logger: zap.NewNop(),
+	}
+	assert.True(t, ml.capabilities().MutatesData)
+
+	tracesSink := new(consumertest.TracesSink)
+	tp, err := processorhelper.NewTracesProcessor(
+		&Config{ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr))},
+		tracesSink,
+		ml.processTraces,
+		processorhelper.WithCapabilities(ml.capabilities()))
+	require.NoError(t, err)
+	logsSink := new(consumertest.LogsSink)
+	lp, err := processorhelper.NewLogsProcessor(
+		&Config{ProcessorSettings: config.NewProcessorSettings(config.NewID(typeStr))},
+		logsSink,
+		ml.processLogs,
+		processorhelper.WithCapabilities(ml.capabilities()))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	newTraces := func() pdata.Traces {
+		td := testdata.GenerateTracesTwoSpansSameResource()
+		spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+		spans.At(0).SetTraceID(pdata.NewTraceID([16]byte{15: 1}))
+		spans.At(1).SetTraceID(pdata.NewTraceID([16]byte{8: 0xff, 15: 1}))
+		return td
+	}
+
+	// Below the soft limit.
+	currentMemAlloc = 100
+	ml.checkMemLimits()
+	assert.NoError(t, tp.ConsumeTraces(ctx, newTraces()))
+	assert.Equal(t, 2, tracesSink.SpanCount())
+	assert.NoError(t, lp.ConsumeLogs(ctx, testdata.GenerateLogsManyLogRecordsSameResource(4)))
+	assert.Equal(t, 4, logsSink.LogRecordCount())
+
+	// Between the soft and the hard limit data is sampled down.
+	tracesSink.Reset()
+	logsSink.Reset()
+	currentMemAlloc = 600
+	ml.checkMemLimits()
+	assert.NoError(t, tp.ConsumeTraces(ctx, newTraces()))
+	require.Equal(t, 1, tracesSink.SpanCount())
+	assert.Equal(t, pdata.NewTraceID([16]byte{15: 1}),
+		tracesSink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+	assert.NoError(t, lp.ConsumeLogs(ctx, testdata.GenerateLogsManyLogRecordsSameResource(4)))
+	assert.Equal(t, 2, logsSink.LogRecordCount())
+
+	// Data entirely sampled out is not sent further.
+	tracesSink.Reset()
+	td := newTraces()
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().RemoveIf(func(span pdata.Span) bool {
+		return span.TraceID() == pdata.NewTraceID([16]byte{15: 1})
+	})
+	assert.NoError(t, tp.ConsumeTraces(ctx, td))
+	assert.Len(t, tracesSink.AllTraces(), 0)
+
+	// Above the hard limit data is refused.
+	currentMemAlloc = 1800
+	ml.checkMemLimits()
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, newTraces()))
+	assert.Equal(t, errForcedDrop, lp.ConsumeLogs(ctx, testdata.GenerateLogsManyLogRecordsSameResource(4)))
+}
+
 func TestGetDecision(t *testing.T) {
 	t.Run("fixed_limit", func(t *testing.T) {
 		d, err := getMemUsageChecker(&Config{MemoryLimitMiB: 100, MemorySpikeLimitMiB: 20}, zap.NewNop())
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorylimiter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/internal/retention"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// pipelineGroups holds, for every memory_limiter configuration, the pipelines
+// using it, so that the one holding the most data can be told apart.
+var pipelineGroups = struct {
+	sync.Mutex
+	groups map[config.ComponentID]*pipelineGroup
+}{groups: map[config.ComponentID]*pipelineGroup{}}
+
+type pipelineGroup struct {
+	mu       sync.RWMutex
+	trackers map[*pipelineTracker]struct{}
+}
+
+// pipelineTracker tracks the bytes held by a pipeline, that is the data accepted
+// by the processor whose processing by the rest of the pipeline has not completed,
+// including the data kept by a batch processor or a sending queue downstream.
+type pipelineTracker struct {
+	id    config.ComponentID
+	group *pipelineGroup
+	limit int64
+
+	// held is used atomically.
+	held int64
+}
+
+func newPipelineTracker(id config.ComponentID, limit uint64) *pipelineTracker {
+	pipelineGroups.Lock()
+	defer pipelineGroups.Unlock()
+
+	group, ok := pipelineGroups.groups[id]
+	if !ok {
+		group = &pipelineGroup{trackers: map[*pipelineTracker]struct{}{}}
+		pipelineGroups.groups[id] = group
+	}
+	pt := &pipelineTracker{id: id, group: group, limit: int64(limit)}
+	group.mu.Lock()
+	group.trackers[pt] = struct{}{}
+	group.mu.Unlock()
+	return pt
+}
+
+// unregister removes the pipeline from its group, it must be called when the
+// processor shuts down.
+func (pt *pipelineTracker) unregister() {
+	pipelineGroups.Lock()
+	defer pipelineGroups.Unlock()
+
+	pt.group.mu.Lock()
+	delete(pt.group.trackers, pt)
+	empty := len(pt.group.trackers) == 0
+	pt.group.mu.Unlock()
+	if empty && pipelineGroups.groups[pt.id] == pt.group {
+		delete(pipelineGroups.groups, pt.id)
+	}
+}
+
+func (pt *pipelineTracker) heldBytes() int64 {
+	return atomic.LoadInt64(&pt.held)
+}
+
+// overLimit indicates when the pipeline holds more than its own limit.
+func (pt *pipelineTracker) overLimit() bool {
+	return pt.limit > 0 && pt.heldBytes() >= pt.limit
+}
+
+// holdsMost indicates when no other pipeline of the group holds more bytes than this one.
+func (pt *pipelineTracker) holdsMost() bool {
+	held := pt.heldBytes()
+	pt.group.mu.RLock()
+	defer pt.group.mu.RUnlock()
+	for other := range pt.group.trackers {
+		if other.heldBytes() > held {
+			return false
+		}
+	}
+	return true
+}
+
+// hold accounts n bytes as held by the pipeline until the returned data is
+// released by the processor and by every component which retained it.
+func (pt *pipelineTracker) hold(n int) *heldData {
+	atomic.AddInt64(&pt.held, int64(n))
+	return &heldData{tracker: pt, size: int64(n), refs: 1}
+}
+
+// heldData is the retention.Holder passed downstream with the data accepted by the processor.
+type heldData struct {
+	tracker *pipelineTracker
+	size    int64
+
+	// refs is used atomically.
+	refs int64
+}
+
+func (h *heldData) Retain() func() {
+	atomic.AddInt64(&h.refs, 1)
+	return retention.Once(h.release)
+}
+
+func (h *heldData) release() {
+	if atomic.AddInt64(&h.refs, -1) == 0 {
+		atomic.AddInt64(&h.tracker.held, -h.size)
+	}
+}
+
+// withHolder returns the context passed to the next consumer, letting the
+// components retaining the data keep it accounted to the pipeline.
+func withHolder(ctx context.Context, h *heldData) context.Context {
+	return retention.NewContext(ctx, retention.Combine(retention.FromContext(ctx), h))
+}
+
+// trackingTraces accounts the traces sent to the rest of the pipeline as held
+// until the next consumer returns and every component retaining them released them.
+type trackingTraces struct {
+	consumer.Traces
+	tracker *pipelineTracker
+	sizer   pdata.TracesSizer
+}
+
+func (tt *trackingTraces) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	h := tt.tracker.hold(tt.sizer.TracesSize(td))
+	defer h.release()
+	return tt.Traces.ConsumeTraces(withHolder(ctx, h), td)
+}
+
+// trackingMetrics accounts the metrics sent to the rest of the pipeline as held
+// until the next consumer returns and every component retaining them released them.
+type trackingMetrics struct {
+	consumer.Metrics
+	tracker *pipelineTracker
+	sizer   pdata.MetricsSizer
+}
+
+func (tm *trackingMetrics) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	h := tm.tracker.hold(tm.sizer.MetricsSize(md))
+	defer h.release()
+	return tm.Metrics.ConsumeMetrics(withHolder(ctx, h), md)
+}
+
+// trackingLogs accounts the logs sent to the rest of the pipeline as held
+// until the next consumer returns and every component retaining them released them.
+type trackingLogs struct {
+	consumer.Logs
+	tracker *pipelineTracker
+	sizer   pdata.LogsSizer
+}
+
+func (tl *trackingLogs) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	h := tl.tracker.hold(tl.sizer.LogsSize(ld))
+	defer h.release()
+	return tl.Logs.ConsumeLogs(withHolder(ctx, h), ld)
+}
+
+func (ml *memoryLimiter) trackTraces(next consumer.Traces) consumer.Traces {
+	if ml.pipeline == nil {
+		return next
+	}
+	return &trackingTraces{Traces: next, tracker: ml.pipeline, sizer: otlp.NewProtobufTracesMarshaler().(pdata.TracesSizer)}
+}
+
+func (ml *memoryLimiter) trackMetrics(next consumer.Metrics) consumer.Metrics {
+	if ml.pipeline == nil {
+		return next
+	}
+	return &trackingMetrics{Metrics: next, tracker: ml.pipeline, sizer: otlp.NewProtobufMetricsMarshaler().(pdata.MetricsSizer)}
+}
+
+func (ml *memoryLimiter) trackLogs(next consumer.Logs) consumer.Logs {
+	if ml.pipeline == nil {
+		return next
+	}
+	return &trackingLogs{Logs: next, tracker: ml.pipeline, sizer: otlp.NewProtobufLogsMarshaler().(pdata.LogsSizer)}
+}
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorylimiter
+
+import (
+	"encoding/binary"
+	"math"
+
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+// sampleTraces keeps the spans of the traces whose ID falls within the sampling ratio,
+// so that sampled traces are kept whole across batches and pipelines.
+func (ml *memoryLimiter) sampleTraces(td pdata.Traces) {
+	threshold := uint64(ml.samplingRatio * math.MaxUint64)
+	td.ResourceSpans().RemoveIf(func(rs pdata.ResourceSpans) bool {
+		rs.InstrumentationLibrarySpans().RemoveIf(func(ils pdata.InstrumentationLibrarySpans) bool {
+			ils.Spans().RemoveIf(func(span pdata.Span) bool {
+				traceID := span.TraceID().Bytes()
+				// The right-most bytes of the trace ID are random.
+				return binary.BigEndian.Uint64(traceID[8:]) >= threshold
+			})
+			return ils.Spans().Len() == 0
+		})
+		return rs.InstrumentationLibrarySpans().Len() == 0
+	})
+}
+
+// sampleMetrics keeps the metrics selected randomly within the sampling ratio.
+func (ml *memoryLimiter) sampleMetrics(md pdata.Metrics) {
+	md.ResourceMetrics().RemoveIf(func(rm pdata.ResourceMetrics) bool {
+		rm.InstrumentationLibraryMetrics().RemoveIf(func(ilm pdata.InstrumentationLibraryMetrics) bool {
+			ilm.Metrics().RemoveIf(func(pdata.Metric) bool {
+				return ml.randFloat() >= ml.samplingRatio
+			})
+			return ilm.Metrics().Len() == 0
+		})
+		return rm.InstrumentationLibraryMetrics().Len() == 0
+	})
+}
+
+// sampleLogs keeps the log records selected randomly within the sampling ratio.
+func (ml *memoryLimiter) sampleLogs(ld pdata.Logs) {
+	ld.ResourceLogs().RemoveIf(func(rl pdata.ResourceLogs) bool {
+		rl.InstrumentationLibraryLogs().RemoveIf(func(ill pdata.InstrumentationLibraryLogs) bool {
+			ill.Logs().RemoveIf(func(pdata.LogRecord) bool {
+				return ml.randFloat() >= ml.samplingRatio
+			})
+			return ill.Logs().Len() == 0
+		})
+		return rl.InstrumentationLibraryLogs().Len() == 0
+	})
+}
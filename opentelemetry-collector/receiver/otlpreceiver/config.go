@@ -28,6 +28,10 @@ const (
 	protoGRPC          = "grpc"
 	protoHTTP          = "http"
 	protocolsFieldName = "protocols"
+
+	// Rate limit key values.
+	rateLimitKeyIP        = "ip"
+	rateLimitKeyPrincipal = "principal"
 )
 
 // Protocols is the configuration for the supported protocols.
@@ -36,11 +40,54 @@ type Protocols struct {
 	HTTP *confighttp.HTTPServerSettings `mapstructure:"http"`
 }
 
+// RateLimitSettings defines the per-client admission control of the receiver.
+// The limits are shared by the gRPC and HTTP endpoints.
+type RateLimitSettings struct {
+	// Key identifies the client a request is accounted to, either "ip" (default) for the
+	// address of the peer or "principal" for the identity established by the authenticator.
+	// Requests whose client cannot be identified share a single budget.
+	Key string `mapstructure:"key"`
+
+	// RequestsPerSecond is the number of requests per second a client is allowed to send,
+	// 0 disables the limit.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+
+	// RequestsBurst is the number of requests a client can send at once.
+	// Defaults to one second worth of requests.
+	RequestsBurst int `mapstructure:"requests_burst"`
+
+	// BytesPerSecond is the number of uncompressed request bytes per second a client is
+	// allowed to send, 0 disables the limit.
+	BytesPerSecond float64 `mapstructure:"bytes_per_second"`
+
+	// BytesBurst is the number of bytes a client can send at once.
+	// Defaults to one second worth of bytes.
+	BytesBurst int `mapstructure:"bytes_burst"`
+}
+
+// Validate checks the rate limit configuration is valid
+func (rls *RateLimitSettings) Validate() error {
+	switch rls.Key {
+	case "", rateLimitKeyIP, rateLimitKeyPrincipal:
+	default:
+		return fmt.Errorf("rate_limit key must be either %q or %q, got %q", rateLimitKeyIP, rateLimitKeyPrincipal, rls.Key)
+	}
+	if rls.RequestsPerSecond < 0 || rls.BytesPerSecond < 0 || rls.RequestsBurst < 0 || rls.BytesBurst < 0 {
+		return fmt.Errorf("rate_limit values must not be negative")
+	}
+	if rls.RequestsPerSecond == 0 && rls.BytesPerSecond == 0 {
+		return fmt.Errorf("rate_limit requires requests_per_second or bytes_per_second")
+	}
+	return nil
+}
+
 // Config defines configuration for OTLP receiver.
 type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+	// RateLimit configures per-client rate limits, disabled if not set.
+	RateLimit *RateLimitSettings `mapstructure:"rate_limit"`
 }
 
 var _ config.Receiver = (*Config)(nil)
@@ -52,6 +99,9 @@ func (cfg *Config) Validate() error {
 		cfg.HTTP == nil {
 		return fmt.Errorf("must specify at least one protocol when using the OTLP receiver")
 	}
+	if cfg.RateLimit != nil {
+		return cfg.RateLimit.Validate()
+	}
 	return nil
 }
 
@@ -42,7 +42,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 10)
+	assert.Equal(t, len(cfg.Receivers), 11)
 
 	assert.Equal(t, cfg.Receivers[config.NewID(typeStr)], factory.CreateDefaultConfig())
 
@@ -190,6 +190,16 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		})
+
+	defaultRateLimit := factory.CreateDefaultConfig().(*Config)
+	defaultRateLimit.SetIDName("ratelimit")
+	defaultRateLimit.RateLimit = &RateLimitSettings{
+		Key:               "principal",
+		RequestsPerSecond: 100,
+		RequestsBurst:     200,
+		BytesPerSecond:    1048576,
+	}
+	assert.Equal(t, cfg.Receivers[config.NewIDWithName(typeStr, "ratelimit")], defaultRateLimit)
 }
 
 func TestFailedLoadConfig(t *testing.T) {
@@ -210,3 +220,47 @@ func TestFailedLoadConfig(t *testing.T) {
 	_, err = configtest.LoadConfigAndValidate(path.Join(".", "testdata", "bad_empty_config.yaml"), factories)
 	assert.EqualError(t, err, "error reading receivers configuration for otlp: empty config for OTLP receiver")
 }
+
+func TestValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit RateLimitSettings
+		expectErr string
+	}{
+		{
+			name:      "requests",
+			rateLimit: RateLimitSettings{RequestsPerSecond: 10},
+		},
+		{
+			name:      "bytes by principal",
+			rateLimit: RateLimitSettings{Key: "principal", BytesPerSecond: 1024, BytesBurst: 4096},
+		},
+		{
+			name:      "invalid key",
+			rateLimit: RateLimitSettings{Key: "tenant", RequestsPerSecond: 10},
+			expectErr: `rate_limit key must be either "ip" or "principal", got "tenant"`,
+		},
+		{
+			name:      "negative",
+			rateLimit: RateLimitSettings{RequestsPerSecond: 10, BytesBurst: -1},
+			expectErr: "rate_limit values must not be negative",
+		},
+		{
+			name:      "no limit",
+			rateLimit: RateLimitSettings{RequestsBurst: 10},
+			expectErr: "rate_limit requires requests_per_second or bytes_per_second",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.RateLimit = &tt.rateLimit
+			err := cfg.Validate()
+			if tt.expectErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -51,8 +51,10 @@ func (r *Receiver) Export(ctx context.Context, ld pdata.Logs) (otlpgrpc.LogsResp
 		return otlpgrpc.NewLogsResponse(), nil
 	}
 
-	if c, ok := client.FromGRPC(ctx); ok {
-		ctx = client.NewContext(ctx, c)
+	if _, ok := client.FromContext(ctx); !ok {
+		if c, ok := client.FromGRPC(ctx); ok {
+			ctx = client.NewContext(ctx, c)
+		}
 	}
 
 	ctx = r.obsrecv.StartLogsOp(ctx)
@@ -51,8 +51,10 @@ func (r *Receiver) Export(ctx context.Context, md pdata.Metrics) (otlpgrpc.Metri
 		return otlpgrpc.NewMetricsResponse(), nil
 	}
 
-	if c, ok := client.FromGRPC(ctx); ok {
-		ctx = client.NewContext(ctx, c)
+	if _, ok := client.FromContext(ctx); !ok {
+		if c, ok := client.FromGRPC(ctx); ok {
+			ctx = client.NewContext(ctx, c)
+		}
 	}
 
 	ctx = r.obsrecv.StartMetricsOp(ctx)
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit implements the per-client admission control of the OTLP receiver.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// clientIdleTimeout is the time after which the state of a client that did not
+// send any request is dropped.
+const clientIdleTimeout = 5 * time.Minute
+
+// Settings defines the limits applied to every client independently.
+// A zero rate disables the corresponding limit.
+type Settings struct {
+	RequestsPerSecond float64
+	RequestsBurst     int
+	BytesPerSecond    float64
+	BytesBurst        int
+}
+
+// Limiter keeps a pair of token buckets, one for requests and one for bytes,
+// for every client it has seen recently.
+type Limiter struct {
+	settings Settings
+	now      func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*clientState
+	lastSweep time.Time
+}
+
+type clientState struct {
+	requests *rate.Limiter
+	bytes    *rate.Limiter
+	lastSeen time.Time
+}
+
+// New returns a Limiter enforcing the given settings. A zero burst defaults to
+// one second worth of the corresponding rate.
+func New(settings Settings) *Limiter {
+	if settings.RequestsBurst <= 0 {
+		settings.RequestsBurst = defaultBurst(settings.RequestsPerSecond)
+	}
+	if settings.BytesBurst <= 0 {
+		settings.BytesBurst = defaultBurst(settings.BytesPerSecond)
+	}
+	return &Limiter{
+		settings: settings,
+		now:      time.Now,
+		clients:  map[string]*clientState{},
+	}
+}
+
+func defaultBurst(perSecond float64) int {
+	if perSecond < 1 {
+		return 1
+	}
+	return int(math.Ceil(perSecond))
+}
+
+// Allow reports whether a request of the given size in bytes sent by the client
+// identified by key is admitted. If it is not, Allow returns how long the client
+// should wait before retrying. Requests larger than the bytes burst are admitted
+// only when the bytes bucket of the client is full, and drain it entirely.
+func (l *Limiter) Allow(key string, size int) (time.Duration, bool) {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	cs := l.client(key, now)
+
+	var reservations []*rate.Reservation
+	var delay time.Duration
+	if cs.requests != nil {
+		r := cs.requests.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > delay {
+			delay = d
+		}
+	}
+	if cs.bytes != nil && size > 0 {
+		if size > l.settings.BytesBurst {
+			size = l.settings.BytesBurst
+		}
+		r := cs.bytes.ReserveN(now, size)
+		reservations = append(reservations, r)
+		if d := r.DelayFrom(now); d > delay {
+			delay = d
+		}
+	}
+
+	if delay == 0 {
+		return 0, true
+	}
+	// Give back the tokens, the request is refused.
+	for _, r := range reservations {
+		r.CancelAt(now)
+	}
+	return delay, false
+}
+
+func (l *Limiter) client(key string, now time.Time) *clientState {
+	cs, ok := l.clients[key]
+	if !ok {
+		cs = &clientState{}
+		if l.settings.RequestsPerSecond > 0 {
+			cs.requests = rate.NewLimiter(rate.Limit(l.settings.RequestsPerSecond), l.settings.RequestsBurst)
+		}
+		if l.settings.BytesPerSecond > 0 {
+			cs.bytes = rate.NewLimiter(rate.Limit(l.settings.BytesPerSecond), l.settings.BytesBurst)
+		}
+		l.clients[key] = cs
+	}
+	cs.lastSeen = now
+	return cs
+}
+
+// sweep drops the clients that have been idle for longer than clientIdleTimeout.
+// It must be called with the lock held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < clientIdleTimeout {
+		return
+	}
+	l.lastSweep = now
+	for key, cs := range l.clients {
+		if now.Sub(cs.lastSeen) >= clientIdleTimeout {
+			delete(l.clients, key)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(settings Settings) (*Limiter, *time.Time) {
+	l := New(settings)
+	now := time.Unix(1000, 0)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestAllowRequests(t *testing.T) {
+	l, now := newTestLimiter(Settings{RequestsPerSecond: 1, RequestsBurst: 2})
+
+	for i := 0; i < 2; i++ {
+		_, ok := l.Allow("a", 0)
+		assert.True(t, ok)
+	}
+	delay, ok := l.Allow("a", 0)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, delay)
+
+	// Other clients have their own budget.
+	_, ok = l.Allow("b", 0)
+	assert.True(t, ok)
+
+	*now = now.Add(time.Second)
+	_, ok = l.Allow("a", 0)
+	assert.True(t, ok)
+	_, ok = l.Allow("a", 0)
+	assert.False(t, ok)
+}
+
+func TestAllowBytes(t *testing.T) {
+	l, now := newTestLimiter(Settings{BytesPerSecond: 100, BytesBurst: 200})
+
+	_, ok := l.Allow("a", 150)
+	assert.True(t, ok)
+	delay, ok := l.Allow("a", 100)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, delay)
+
+	*now = now.Add(500 * time.Millisecond)
+	_, ok = l.Allow("a", 100)
+	assert.True(t, ok)
+}
+
+func TestAllowOversizedRequest(t *testing.T) {
+	l, now := newTestLimiter(Settings{BytesPerSecond: 100, BytesBurst: 200})
+
+	_, ok := l.Allow("a", 1000)
+	assert.True(t, ok)
+	delay, ok := l.Allow("a", 1000)
+	assert.False(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+
+	*now = now.Add(2 * time.Second)
+	_, ok = l.Allow("a", 1000)
+	assert.True(t, ok)
+}
+
+func TestAllowRefusedRequestDoesNotConsume(t *testing.T) {
+	l, now := newTestLimiter(Settings{RequestsPerSecond: 10, RequestsBurst: 10, BytesPerSecond: 100, BytesBurst: 100})
+
+	_, ok := l.Allow("a", 100)
+	assert.True(t, ok)
+	for i := 0; i < 20; i++ {
+		_, ok = l.Allow("a", 100)
+		assert.False(t, ok)
+	}
+
+	// Only the bytes budget was exhausted, refused requests gave back their request tokens.
+	*now = now.Add(time.Second)
+	for i := 0; i < 9; i++ {
+		_, ok = l.Allow("a", 1)
+		assert.True(t, ok)
+	}
+}
+
+func TestDefaultBurst(t *testing.T) {
+	l := New(Settings{RequestsPerSecond: 2.5, BytesPerSecond: 0.5})
+	assert.Equal(t, 3, l.settings.RequestsBurst)
+	assert.Equal(t, 1, l.settings.BytesBurst)
+}
+
+func TestIdleClientsAreDropped(t *testing.T) {
+	l, now := newTestLimiter(Settings{RequestsPerSecond: 1})
+
+	l.Allow("a", 0)
+	*now = now.Add(clientIdleTimeout / 2)
+	l.Allow("b", 0)
+	assert.Len(t, l.clients, 2)
+
+	*now = now.Add(clientIdleTimeout / 2)
+	l.Allow("b", 0)
+	assert.Len(t, l.clients, 1)
+	assert.Contains(t, l.clients, "b")
+}
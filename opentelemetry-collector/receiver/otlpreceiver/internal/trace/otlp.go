@@ -52,8 +52,10 @@ func (r *Receiver) Export(ctx context.Context, td pdata.Traces) (otlpgrpc.Traces
 		return otlpgrpc.NewTracesResponse(), nil
 	}
 
-	if c, ok := client.FromGRPC(ctx); ok {
-		ctx = client.NewContext(ctx, c)
+	if _, ok := client.FromContext(ctx); !ok {
+		if c, ok := client.FromGRPC(ctx); ok {
+			ctx = client.NewContext(ctx, c)
+		}
 	}
 
 	ctx = r.obsrecv.StartTracesOp(ctx)
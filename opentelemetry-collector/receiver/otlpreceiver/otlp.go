@@ -51,6 +51,7 @@ type otlpReceiver struct {
 	traceReceiver   *trace.Receiver
 	metricsReceiver *metrics.Receiver
 	logReceiver     *logs.Receiver
+	rateLimiter     *rateLimiter
 	shutdownWG      sync.WaitGroup
 
 	logger *zap.Logger
@@ -67,6 +68,9 @@ func newOtlpReceiver(cfg *Config, logger *zap.Logger) *otlpReceiver {
 	if cfg.HTTP != nil {
 		r.httpMux = mux.NewRouter()
 	}
+	if cfg.RateLimit != nil {
+		r.rateLimiter = newRateLimiter(cfg)
+	}
 
 	return r
 }
@@ -115,6 +119,9 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 		if err != nil {
 			return err
 		}
+		if r.rateLimiter != nil {
+			opts = append(opts, grpc.ChainUnaryInterceptor(r.rateLimiter.unaryServerInterceptor))
+		}
 		r.serverGRPC = grpc.NewServer(opts...)
 
 		if r.traceReceiver != nil {
@@ -147,8 +154,12 @@ func (r *otlpReceiver) startProtocolServers(host component.Host) error {
 		}
 	}
 	if r.cfg.HTTP != nil {
+		var handler http.Handler = r.httpMux
+		if r.rateLimiter != nil {
+			handler = r.rateLimiter.httpHandler(handler)
+		}
 		r.serverHTTP = r.cfg.HTTP.ToServer(
-			r.httpMux,
+			handler,
 			confighttp.WithErrorHandler(errorHandler),
 		)
 		err = r.startHTTPServer(r.cfg.HTTP, host)
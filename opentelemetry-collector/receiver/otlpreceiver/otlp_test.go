@@ -30,11 +30,13 @@ import (
 	"github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -423,6 +425,62 @@ func TestOTLPReceiverInvalidContentEncoding(t *testing.T) {
 	}
 }
 
+func TestHTTPConsumerErrorStatusCode(t *testing.T) {
+	throttled, err := status.New(codes.ResourceExhausted, "throttled").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+		retryAfter string
+	}{
+		{
+			name:       "NotGRPCError",
+			err:        errors.New("my error"),
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "Internal",
+			err:        status.New(codes.Internal, "").Err(),
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "ResourceExhausted",
+			err:        throttled.Err(),
+			statusCode: http.StatusTooManyRequests,
+			retryAfter: "2",
+		},
+		{
+			name:       "Unavailable",
+			err:        status.New(codes.Unavailable, "unavailable").Err(),
+			statusCode: http.StatusServiceUnavailable,
+		},
+	}
+	addr := testutil.GetAvailableLocalAddress(t)
+
+	sink := &internalconsumertest.ErrOrSinkConsumer{TracesSink: new(consumertest.TracesSink)}
+	ocr := newHTTPReceiver(t, addr, sink, nil)
+	require.NoError(t, ocr.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, ocr.Shutdown(context.Background())) })
+
+	traceBytes, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(testdata.GenerateTracesOneSpan())
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink.SetConsumeError(test.err)
+			resp, err := http.Post(fmt.Sprintf("http://%s/v1/traces", addr), "application/x-protobuf", bytes.NewReader(traceBytes))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.statusCode, resp.StatusCode)
+			assert.Equal(t, test.retryAfter, resp.Header.Get("Retry-After"))
+		})
+	}
+}
+
 func TestGRPCNewPortAlreadyUsed(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	ln, err := net.Listen("tcp", addr)
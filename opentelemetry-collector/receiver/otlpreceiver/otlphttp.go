@@ -17,11 +17,15 @@ package otlpreceiver
 import (
 	"bytes"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
 	"github.com/gogo/protobuf/types"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -52,7 +56,7 @@ func handleTraces(
 
 	_, err = tracesReceiver.Export(req.Context(), td)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusInternalServerError)
+		writeConsumerError(resp, contentType, err)
 		return
 	}
 
@@ -79,7 +83,7 @@ func handleMetrics(
 
 	_, err = metricsReceiver.Export(req.Context(), md)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusInternalServerError)
+		writeConsumerError(resp, contentType, err)
 		return
 	}
 
@@ -106,7 +110,7 @@ func handleLogs(
 
 	_, err = logsReceiver.Export(req.Context(), ld)
 	if err != nil {
-		writeError(resp, contentType, err, http.StatusInternalServerError)
+		writeConsumerError(resp, contentType, err)
 		return
 	}
 
@@ -137,6 +141,41 @@ func writeError(w http.ResponseWriter, contentType string, err error, statusCode
 	}
 }
 
+// writeConsumerError encodes the error returned by the next consumer. The RESOURCE_EXHAUSTED and
+// UNAVAILABLE gRPC status codes are reported as 429 Too Many Requests and 503 Service Unavailable,
+// with the Retry-After header set from the RetryInfo detail, so that the clients back off.
+func writeConsumerError(w http.ResponseWriter, contentType string, err error) {
+	s, ok := status.FromError(err)
+	if !ok {
+		writeErrorMsg(w, contentType, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	statusCode := http.StatusInternalServerError
+	switch s.Code() {
+	case codes.ResourceExhausted:
+		statusCode = http.StatusTooManyRequests
+	case codes.Unavailable:
+		statusCode = http.StatusServiceUnavailable
+	}
+	if statusCode != http.StatusInternalServerError {
+		if delay := retryDelay(s); delay > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		}
+	}
+	writeResponse(w, contentType, statusCode, s.Proto())
+}
+
+// retryDelay returns the delay of the RetryInfo detail of the status, or 0 if it has none.
+func retryDelay(s *status.Status) time.Duration {
+	for _, detail := range s.Details() {
+		if ri, ok := detail.(*errdetails.RetryInfo); ok && ri.RetryDelay != nil {
+			return ri.RetryDelay.AsDuration()
+		}
+	}
+	return 0
+}
+
 // writeErrorMsg encodes the HTTP error message inside a rpc.Status message as required
 // by the OTLP protocol.
 func writeErrorMsg(w http.ResponseWriter, contentType string, errMsg string, statusCode int) {
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/ratelimit"
+)
+
+// rateLimiter applies the RateLimitSettings to the requests received on both protocols.
+type rateLimiter struct {
+	key      string
+	limiter  *ratelimit.Limiter
+	obsrecvs map[string]*obsreport.Receiver
+}
+
+func newRateLimiter(cfg *Config) *rateLimiter {
+	rls := cfg.RateLimit
+	return &rateLimiter{
+		key: rls.Key,
+		limiter: ratelimit.New(ratelimit.Settings{
+			RequestsPerSecond: rls.RequestsPerSecond,
+			RequestsBurst:     rls.RequestsBurst,
+			BytesPerSecond:    rls.BytesPerSecond,
+			BytesBurst:        rls.BytesBurst,
+		}),
+		obsrecvs: map[string]*obsreport.Receiver{
+			protoGRPC: obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: cfg.ID(), Transport: protoGRPC}),
+			protoHTTP: obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverID: cfg.ID(), Transport: protoHTTP}),
+		},
+	}
+}
+
+// allow reports whether the request is admitted, and if not, records the rejection and
+// returns the delay after which the client should retry.
+func (rl *rateLimiter) allow(ctx context.Context, transport string, size int) (time.Duration, bool) {
+	delay, ok := rl.limiter.Allow(rl.clientKey(ctx), size)
+	if !ok {
+		rl.obsrecvs[transport].RecordRateLimitedRequest(ctx)
+	}
+	return delay, ok
+}
+
+func (rl *rateLimiter) clientKey(ctx context.Context) string {
+	c, ok := client.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if rl.key == rateLimitKeyPrincipal {
+		return c.Principal
+	}
+	return c.IP
+}
+
+// unaryServerInterceptor refuses the requests exceeding the limits with RESOURCE_EXHAUSTED.
+// It must run after the interceptors recording the client.Client.
+func (rl *rateLimiter) unaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	size := 0
+	if sizer, ok := req.(interface{ Size() int }); ok {
+		size = sizer.Size()
+	}
+	if delay, ok := rl.allow(ctx, protoGRPC, size); !ok {
+		return nil, rateLimitedStatus(delay).Err()
+	}
+	return handler(ctx, req)
+}
+
+// httpHandler refuses the requests exceeding the limits with 429 Too Many Requests.
+// It must run after the handler recording the client.Client and the decompression of the body.
+func (rl *rateLimiter) httpHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType := r.Header.Get("Content-Type")
+		body, ok := readAndCloseBody(w, r, contentType)
+		if !ok {
+			return
+		}
+		if delay, ok := rl.allow(r.Context(), protoHTTP, len(body)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			writeResponse(w, contentType, http.StatusTooManyRequests, rateLimitedStatus(delay).Proto())
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func rateLimitedStatus(delay time.Duration) *status.Status {
+	s := status.New(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded, retry after %v", delay))
+	if sd, err := s.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+		return sd
+	}
+	return s
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/internal/testutil"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+)
+
+func TestGRPCRateLimit(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetIDName(otlpReceiverName)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.RateLimit = &RateLimitSettings{RequestsPerSecond: 0.01, RequestsBurst: 2}
+
+	sink := new(consumertest.TracesSink)
+	r := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	cc, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer cc.Close()
+
+	td := testdata.GenerateTracesOneSpan()
+	require.NoError(t, exportTraces(cc, td))
+	require.NoError(t, exportTraces(cc, td))
+
+	err = exportTraces(cc, td)
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.ResourceExhausted, s.Code())
+	require.Len(t, s.Details(), 1)
+	retryInfo, ok := s.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Second, retryInfo.RetryDelay.AsDuration().Round(time.Second))
+
+	assert.Len(t, sink.AllTraces(), 2)
+	obsreporttest.CheckReceiverRateLimitedRequests(t, config.NewIDWithName(typeStr, otlpReceiverName), "grpc", 1)
+}
+
+func TestHTTPRateLimit(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	td := testdata.GenerateTracesOneSpan()
+	traceBytes, err := otlp.NewProtobufTracesMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.SetIDName(otlpReceiverName)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.RateLimit = &RateLimitSettings{BytesPerSecond: float64(len(traceBytes)), BytesBurst: len(traceBytes)}
+
+	sink := new(consumertest.TracesSink)
+	r := newReceiver(t, factory, cfg, sink, nil)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, r.Shutdown(context.Background())) })
+
+	url := "http://" + addr + "/v1/traces"
+	resp, err := http.DefaultClient.Do(createHTTPProtobufRequest(t, url, "", traceBytes))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.DefaultClient.Do(createHTTPProtobufRequest(t, url, "", traceBytes))
+	require.NoError(t, err)
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+
+	errStatus := &spb.Status{}
+	require.NoError(t, proto.Unmarshal(respBytes, errStatus))
+	assert.Equal(t, int32(codes.ResourceExhausted), errStatus.Code)
+
+	assert.Len(t, sink.AllTraces(), 1)
+	obsreporttest.CheckReceiverRateLimitedRequests(t, config.NewIDWithName(typeStr, otlpReceiverName), "http", 1)
+}
+
+func TestRateLimiterClientKey(t *testing.T) {
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "1.2.3.4", Principal: "alice"})
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.RateLimit = &RateLimitSettings{RequestsPerSecond: 1}
+	assert.Equal(t, "1.2.3.4", newRateLimiter(cfg).clientKey(ctx))
+
+	cfg.RateLimit.Key = rateLimitKeyPrincipal
+	assert.Equal(t, "alice", newRateLimiter(cfg).clientKey(ctx))
+	assert.Equal(t, "", newRateLimiter(cfg).clientKey(context.Background()))
+}
@@ -24,6 +24,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"sync"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -34,6 +35,7 @@ import (
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/config/configunmarshaler"
@@ -62,6 +64,9 @@ const (
 // - execute calls setupConfigurationComponents to handle configuration.
 //   If configuration parser fails, collector's config can be reloaded.
 //   Collector can be shutdown if parser gets a shutdown error.
+// - If the parser provider is watchable, execute starts watchConfigUpdates, which reloads
+//   the service, rebuilding only the changed components, each time the configuration is
+//   updated. An invalid updated configuration is ignored, keeping the current service.
 // - execute runs runAndWaitForShutdownEvent and waits for a shutdown event.
 //   SIGINT and SIGTERM, errors, and (*Collector).Shutdown can trigger the shutdown events.
 // - Upon shutdown, pipelines are notified, then pipelines and extensions are shut down.
@@ -76,6 +81,8 @@ type Collector struct {
 	tracerProvider      trace.TracerProvider
 	zPagesSpanProcessor *zpages.SpanProcessor
 
+	// serviceMu serializes the reloads of the service and its final shutdown.
+	serviceMu    sync.Mutex
 	service      *service
 	stateChannel chan State
 
@@ -157,7 +164,9 @@ func New(set CollectorSettings) (*Collector, error) {
 	for _, addFlags := range addFlagsFns {
 		addFlags(flagSet)
 	}
-	rootCmd.Flags().AddGoFlagSet(flagSet)
+	// The flags are shared with the subcommands, which load the configuration the same way.
+	rootCmd.PersistentFlags().AddGoFlagSet(flagSet)
+	rootCmd.AddCommand(col.newValidateCommand(), col.newPrintConfigCommand())
 	col.rootCmd = rootCmd
 
 	return col, nil
@@ -236,28 +245,52 @@ func (col *Collector) runAndWaitForShutdownEvent() {
 	col.stateChannel <- Closing
 }
 
-// setupConfigurationComponents loads the config and starts the components. If all the steps succeeds it
-// sets the col.service with the service currently running.
-func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
+// configError is returned by reloadService when the updated configuration cannot be loaded or
+// its components cannot be built, in which case the current service keeps running.
+type configError struct {
+	err error
+}
+
+func (ce *configError) Error() string {
+	return ce.err.Error()
+}
+
+func (ce *configError) Unwrap() error {
+	return ce.err
+}
+
+// loadConfig retrieves, unmarshals and validates the configuration.
+func (col *Collector) loadConfig() (*config.Config, error) {
 	col.logger.Info("Loading configuration...")
 
+	return col.readConfig()
+}
+
+// readConfig retrieves, unmarshals and validates the configuration, without logging, so that
+// it can be used before the logger is set up.
+func (col *Collector) readConfig() (*config.Config, error) {
 	cp, err := col.parserProvider.Get()
 	if err != nil {
-		return fmt.Errorf("cannot load configuration's parser: %w", err)
+		return nil, fmt.Errorf("cannot load configuration's parser: %w", err)
 	}
 
 	cfg, err := col.configUnmarshaler.Unmarshal(cp, col.factories)
 	if err != nil {
-		return fmt.Errorf("cannot load configuration: %w", err)
+		return nil, fmt.Errorf("cannot load configuration: %w", err)
 	}
 
 	if err = cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	return cfg, nil
+}
+
+// buildService builds, without starting it, the service for the given configuration.
+func (col *Collector) buildService(cfg *config.Config) (*service, error) {
 	col.logger.Info("Applying configuration...")
 
-	service, err := newService(&svcSettings{
+	return newService(&svcSettings{
 		BuildInfo:           col.info,
 		Factories:           col.factories,
 		Config:              cfg,
@@ -266,6 +299,17 @@ func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 		ZPagesSpanProcessor: col.zPagesSpanProcessor,
 		AsyncErrorChannel:   col.asyncErrorChannel,
 	})
+}
+
+// setupConfigurationComponents loads the config and starts the components. If all the steps succeeds it
+// sets the col.service with the service currently running.
+func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
+	cfg, err := col.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	service, err := col.buildService(cfg)
 	if err != nil {
 		return err
 	}
@@ -277,26 +321,35 @@ func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 
 	col.service = service
 
-	// If provider is watchable start a goroutine watching for updates.
-	if watchable, ok := col.parserProvider.(parserprovider.Watchable); ok {
-		go func() {
-			err := watchable.WatchForUpdate()
-			switch {
-			// TODO: Move configsource.ErrSessionClosed to providerparser package to avoid depending on configsource.
-			case errors.Is(err, configsource.ErrSessionClosed):
-				// This is the case of shutdown of the whole collector server, nothing to do.
-				col.logger.Info("Config WatchForUpdate closed", zap.Error(err))
+	return nil
+}
+
+// watchConfigUpdates reloads the service each time the watchable parser provider reports
+// a configuration update, until the provider is closed.
+func (col *Collector) watchConfigUpdates(watchable parserprovider.Watchable) {
+	for {
+		err := watchable.WatchForUpdate()
+		switch {
+		// TODO: Move configsource.ErrSessionClosed to providerparser package to avoid depending on configsource.
+		case errors.Is(err, configsource.ErrSessionClosed):
+			// This is the case of shutdown of the whole collector server, nothing to do.
+			col.logger.Info("Config WatchForUpdate closed", zap.Error(err))
+			return
+		case err != nil:
+			col.logger.Warn("Config WatchForUpdated exited", zap.Error(err))
+		default:
+			col.logger.Info("Config updated")
+		}
+
+		if err := col.reloadService(context.Background()); err != nil {
+			var cfgErr *configError
+			if !errors.As(err, &cfgErr) {
+				col.asyncErrorChannel <- err
 				return
-			default:
-				col.logger.Warn("Config WatchForUpdated exited", zap.Error(err))
-				if err := col.reloadService(context.Background()); err != nil {
-					col.asyncErrorChannel <- err
-				}
 			}
-		}()
+			col.logger.Error("Failed to apply the updated configuration, keeping the current one", zap.Error(err))
+		}
 	}
-
-	return nil
 }
 
 func (col *Collector) execute(ctx context.Context) error {
@@ -325,6 +378,11 @@ func (col *Collector) execute(ctx context.Context) error {
 		return err
 	}
 
+	// If provider is watchable start a goroutine watching for updates.
+	if watchable, ok := col.parserProvider.(parserprovider.Watchable); ok {
+		go col.watchConfigUpdates(watchable)
+	}
+
 	// Everything is ready, now run until an event requiring shutdown happens.
 	col.runAndWaitForShutdownEvent()
 
@@ -340,11 +398,13 @@ func (col *Collector) execute(ctx context.Context) error {
 		}
 	}
 
+	col.serviceMu.Lock()
 	if col.service != nil {
 		if err := col.service.Shutdown(ctx); err != nil {
 			errs = append(errs, fmt.Errorf("failed to shutdown service: %w", err))
 		}
 	}
+	col.serviceMu.Unlock()
 
 	if err := collectorTelemetry.shutdown(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to shutdown collector telemetry: %w", err))
@@ -357,16 +417,50 @@ func (col *Collector) execute(ctx context.Context) error {
 	return consumererror.Combine(errs)
 }
 
-// reloadService shutdowns the current col.service and setups a new one according
-// to the latest configuration. It requires that col.parserProvider and col.factories
-// are properly populated to finish successfully.
+// reloadService replaces the current col.service by a new one according to the latest
+// configuration, only rebuilding the components affected by the configuration changes
+// when possible. It requires that col.parserProvider and col.factories are properly
+// populated to finish successfully.
+//
+// If the latest configuration cannot be loaded or its components cannot be built a
+// *configError is returned and the current col.service keeps running.
 func (col *Collector) reloadService(ctx context.Context) error {
+	col.serviceMu.Lock()
+	defer col.serviceMu.Unlock()
+
 	if closeable, ok := col.parserProvider.(parserprovider.Closeable); ok {
 		if err := closeable.Close(ctx); err != nil {
 			return fmt.Errorf("failed close current config provider: %w", err)
 		}
 	}
 
+	cfg, err := col.loadConfig()
+	if err != nil {
+		return &configError{err: fmt.Errorf("failed to setup configuration components: %w", err)}
+	}
+
+	if col.service != nil {
+		newService, err := col.service.reload(ctx, cfg)
+		switch {
+		case err == nil:
+			col.service = newService
+			return nil
+		case newService != nil:
+			col.service = newService
+			return fmt.Errorf("failed to reload the service: %w", err)
+		case !errors.Is(err, errFullReloadRequired):
+			return &configError{err: fmt.Errorf("failed to reload the service: %w", err)}
+		}
+		col.logger.Info("Rebuilding the whole service...")
+	}
+
+	// Build the new service before shutting down the retiring one, so that the latter keeps
+	// running if the configuration cannot be applied.
+	service, err := col.buildService(cfg)
+	if err != nil {
+		return &configError{err: fmt.Errorf("failed to setup configuration components: %w", err)}
+	}
+
 	if col.service != nil {
 		retiringService := col.service
 		col.service = nil
@@ -375,9 +469,10 @@ func (col *Collector) reloadService(ctx context.Context) error {
 		}
 	}
 
-	if err := col.setupConfigurationComponents(ctx); err != nil {
+	if err := service.Start(ctx); err != nil {
 		return fmt.Errorf("failed to setup configuration components: %w", err)
 	}
+	col.service = service
 
 	return nil
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
@@ -46,9 +47,9 @@ func TestCollector_Start(t *testing.T) {
 	factories, err := defaultcomponents.Components()
 	require.NoError(t, err)
 
-	loggingHookCalled := false
+	loggingHookCalled := atomic.NewBool(false)
 	hook := func(entry zapcore.Entry) error {
-		loggingHookCalled = true
+		loggingHookCalled.Store(true)
 		return nil
 	}
 
@@ -77,7 +78,7 @@ func TestCollector_Start(t *testing.T) {
 	assert.Equal(t, Starting, <-col.GetStateChannel())
 	assert.Equal(t, Running, <-col.GetStateChannel())
 	assert.Equal(t, col.logger, col.GetLogger())
-	assert.True(t, loggingHookCalled)
+	assert.True(t, loggingHookCalled.Load())
 
 	// All labels added to all collector metrics by default are listed below.
 	// These labels are hard coded here in order to avoid inadvertent changes:
@@ -213,6 +214,7 @@ func assertZPages(t *testing.T) {
 		"/debug/pipelinez",
 		"/debug/servicez",
 		"/debug/extensionz",
+		"/debug/tapz",
 	}
 
 	const defaultZPagesPort = "55679"
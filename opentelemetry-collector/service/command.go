@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/parserprovider"
+)
+
+// redactedValue replaces the values of the sensitive settings printed by the print-config command.
+const redactedValue = "[REDACTED]"
+
+// newValidateCommand returns the command loading and validating the configuration, it exits
+// without starting any component.
+func (col *Collector) newValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration and exit without starting any component",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := col.readConfigAndClose(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(),
+				"The configuration is valid: %d receivers, %d processors, %d exporters, %d connectors, %d extensions and %d pipelines.\n",
+				len(cfg.Receivers), len(cfg.Processors), len(cfg.Exporters), len(cfg.Connectors), len(cfg.Extensions), len(cfg.Service.Pipelines))
+			return nil
+		},
+	}
+}
+
+// newPrintConfigCommand returns the command printing the effective configuration, once merged
+// from all sources and completed with the default settings of the components, with the sensitive
+// settings redacted. It exits without starting any component.
+func (col *Collector) newPrintConfigCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "print-config",
+		Short: "Print the effective configuration, with secrets redacted, and exit without starting any component",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := col.readConfigAndClose(cmd.Context())
+			if err != nil {
+				return err
+			}
+			out, err := yaml.Marshal(effectiveConfig(cfg))
+			if err != nil {
+				return fmt.Errorf("cannot marshal the configuration: %w", err)
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+}
+
+// readConfigAndClose reads the configuration then closes the parser provider, as no update
+// is watched by the commands exiting after reading it.
+func (col *Collector) readConfigAndClose(ctx context.Context) (*config.Config, error) {
+	cfg, err := col.readConfig()
+	if closeable, ok := col.parserProvider.(parserprovider.Closeable); ok {
+		if closeErr := closeable.Close(ctx); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close config: %w", closeErr)
+		}
+	}
+	return cfg, err
+}
+
+// effectiveConfig returns the configuration as a map laid out like the configuration files.
+func effectiveConfig(cfg *config.Config) map[string]interface{} {
+	result := map[string]interface{}{
+		"receivers":  componentsToMap(cfg.Receivers),
+		"processors": componentsToMap(cfg.Processors),
+		"exporters":  componentsToMap(cfg.Exporters),
+		"extensions": componentsToMap(cfg.Extensions),
+	}
+	if len(cfg.Connectors) != 0 {
+		result["connectors"] = componentsToMap(cfg.Connectors)
+	}
+
+	pipelines := make(map[string]interface{}, len(cfg.Service.Pipelines))
+	for name, pipeline := range cfg.Service.Pipelines {
+		p := map[string]interface{}{
+			"receivers": idsToStrings(pipeline.Receivers),
+			"exporters": idsToStrings(pipeline.Exporters),
+		}
+		if len(pipeline.Processors) != 0 {
+			p["processors"] = idsToStrings(pipeline.Processors)
+		}
+		pipelines[name] = p
+	}
+	service := map[string]interface{}{"pipelines": pipelines}
+	if len(cfg.Service.Extensions) != 0 {
+		service["extensions"] = idsToStrings(cfg.Service.Extensions)
+	}
+	result["service"] = service
+
+	return result
+}
+
+// componentsToMap returns the map of the component IDs to their settings, components is one of
+// config.Receivers, config.Processors, config.Exporters, config.Connectors or config.Extensions.
+func componentsToMap(components interface{}) map[string]interface{} {
+	v := reflect.ValueOf(components)
+	result := make(map[string]interface{}, v.Len())
+	for _, key := range v.MapKeys() {
+		result[key.Interface().(config.ComponentID).String()] = settingToValue(v.MapIndex(key), false)
+	}
+	return result
+}
+
+func idsToStrings(ids []config.ComponentID) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = id.String()
+	}
+	return result
+}
+
+// settingToValue converts a setting to the value it would have in the configuration files,
+// following the mapstructure tags of the structs. The string values of the sensitive settings
+// are redacted.
+func settingToValue(v reflect.Value, sensitive bool) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if stringer, ok := v.Interface().(fmt.Stringer); ok && (v.Kind() != reflect.Struct || !hasExportedFields(v.Type())) {
+		// Durations, IDs and alike are configured with their string representation.
+		return redactIf(sensitive, stringer.String())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		result := make(map[string]interface{})
+		structToMap(v, result)
+		return result
+	case reflect.Map:
+		result := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			result[name] = settingToValue(v.MapIndex(key), sensitive || isSensitive(name))
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			result[i] = settingToValue(v.Index(i), sensitive)
+		}
+		return result
+	case reflect.String:
+		return redactIf(sensitive, v.String())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	}
+	return v.Interface()
+}
+
+// structToMap adds the exported fields of the struct to result, keyed by their mapstructure names.
+func structToMap(v reflect.Value, result map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			// Unexported fields are not configurable.
+			continue
+		}
+
+		name := strings.ToLower(field.Name)
+		squash := false
+		if tag, ok := field.Tag.Lookup("mapstructure"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				squash = squash || opt == "squash"
+			}
+		}
+
+		fv := v.Field(i)
+		if squash {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				structToMap(fv, result)
+			}
+			continue
+		}
+		result[name] = settingToValue(fv, isSensitive(name))
+	}
+}
+
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitive returns true if the setting of the given name holds a secret.
+func isSensitive(name string) bool {
+	name = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, secret := range []string{"password", "secret", "token", "apikey", "privatekey", "authorization"} {
+		if strings.Contains(name, secret) && !strings.HasSuffix(name, "file") {
+			return true
+		}
+	}
+	return false
+}
+
+func redactIf(sensitive bool, value string) interface{} {
+	if sensitive && value != "" {
+		return redactedValue
+	}
+	return value
+}
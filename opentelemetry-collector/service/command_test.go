@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/service/defaultcomponents"
+)
+
+func TestCollector_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		configFile string
+		expectErr  string
+		expectOut  string
+	}{
+		{
+			name:       "valid",
+			configFile: "testdata/otelcol-config.yaml",
+			expectOut:  "The configuration is valid: 1 receivers, 1 processors, 1 exporters, 0 connectors, 1 extensions and 1 pipelines.\n",
+		},
+		{
+			name:       "invalid",
+			configFile: "testdata/otelcol-invalid.yaml",
+			expectErr:  `invalid configuration: pipeline "traces" references exporter "otlp/missing" which does not exist`,
+		},
+		{
+			name:       "missing",
+			configFile: "testdata/otelcol-missing.yaml",
+			expectErr:  "cannot load configuration's parser",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runCollectorCommand(t, "validate", "--config="+tt.configFile)
+			if tt.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectOut, out)
+		})
+	}
+}
+
+func TestCollector_PrintConfig(t *testing.T) {
+	out, err := runCollectorCommand(t, "print-config", "--config=testdata/otelcol-print-config.yaml", "--set=processors.batch.timeout=2s")
+	require.NoError(t, err)
+
+	// The secrets are redacted.
+	assert.NotContains(t, out, "some-secret-token")
+	assert.Contains(t, out, "authorization: '[REDACTED]'")
+	// The settings are merged from all sources and completed with the defaults.
+	assert.Contains(t, out, "endpoint: locahost:55678")
+	assert.Contains(t, out, "timeout: 2s")
+	assert.Contains(t, out, "send_batch_size: 8192")
+	assert.Contains(t, out, "endpoint: 0.0.0.0:4317")
+	assert.Contains(t, out, "exporters:\n      - otlp\n")
+}
+
+func TestIsSensitive(t *testing.T) {
+	for _, name := range []string{"password", "client_secret", "bearer_token", "api_key", "private-key", "Authorization"} {
+		assert.True(t, isSensitive(name), name)
+	}
+	for _, name := range []string{"endpoint", "key_file", "token_file", "metadata_keys", "auth"} {
+		assert.False(t, isSensitive(name), name)
+	}
+}
+
+func runCollectorCommand(t *testing.T, args ...string) (string, error) {
+	factories, err := defaultcomponents.Components()
+	require.NoError(t, err)
+
+	col, err := New(CollectorSettings{BuildInfo: component.DefaultBuildInfo(), Factories: factories})
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	col.rootCmd.SetOut(out)
+	col.rootCmd.SetErr(new(bytes.Buffer))
+	col.rootCmd.SetArgs(args)
+	err = col.Run()
+	return out.String(), err
+}
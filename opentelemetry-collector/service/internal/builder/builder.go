@@ -28,6 +28,7 @@ const (
 	zapKindProcessor   = "processor"
 	zapKindLogExporter = "exporter"
 	zapKindExtension   = "extension"
+	zapKindConnector   = "connector"
 	zapNameKey         = "name"
 )
 
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/service/internal/fanoutconsumer"
+	"go.opentelemetry.io/collector/service/internal/tap"
+)
+
+// connectorDataTypes is the pair of data types a connector consumes and emits.
+type connectorDataTypes struct {
+	input  config.DataType
+	output config.DataType
+}
+
+// builtConnector is a connector that is built based on a config. A connector is
+// created once for every pair of data types it consumes and emits.
+type builtConnector struct {
+	logger         *zap.Logger
+	connByDataType map[connectorDataTypes]component.Connector
+
+	// Taps are the points recording the data sent by the connector to each
+	// attached pipeline.
+	Taps []*tap.Point
+}
+
+// Start the connector.
+func (bconn *builtConnector) Start(ctx context.Context, host component.Host) error {
+	var errors []error
+	for _, conn := range bconn.connByDataType {
+		if err := conn.Start(ctx, host); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return consumererror.Combine(errors)
+}
+
+// Shutdown all the components of the connector.
+func (bconn *builtConnector) Shutdown(ctx context.Context) error {
+	var errors []error
+	for _, conn := range bconn.connByDataType {
+		if err := conn.Shutdown(ctx); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return consumererror.Combine(errors)
+}
+
+// tap returns a new point recording the data sent by the connector to the pipeline.
+func (bconn *builtConnector) tap(pipeline *builtPipeline, connectorID config.ComponentID) *tap.Point {
+	point := tap.NewPoint(pipeline.name, zapKindReceiver, connectorID.String())
+	bconn.Taps = append(bconn.Taps, point)
+	return point
+}
+
+func (bconn *builtConnector) getTracesConnector() consumer.Traces {
+	var conns []consumer.Traces
+	for dataTypes, conn := range bconn.connByDataType {
+		if dataTypes.input == config.TracesDataType {
+			conns = append(conns, conn.(component.TracesConnector))
+		}
+	}
+	return fanoutconsumer.NewTraces(conns)
+}
+
+func (bconn *builtConnector) getMetricsConnector() consumer.Metrics {
+	var conns []consumer.Metrics
+	for dataTypes, conn := range bconn.connByDataType {
+		if dataTypes.input == config.MetricsDataType {
+			conns = append(conns, conn.(component.MetricsConnector))
+		}
+	}
+	return fanoutconsumer.NewMetrics(conns)
+}
+
+func (bconn *builtConnector) getLogsConnector() consumer.Logs {
+	var conns []consumer.Logs
+	for dataTypes, conn := range bconn.connByDataType {
+		if dataTypes.input == config.LogsDataType {
+			conns = append(conns, conn.(component.LogsConnector))
+		}
+	}
+	return fanoutconsumer.NewLogs(conns)
+}
+
+// Connectors is a map of connectors created from connector configs.
+type Connectors map[config.ComponentID]*builtConnector
+
+// StartAll starts all connectors.
+func (conns Connectors) StartAll(ctx context.Context, host component.Host) error {
+	for _, conn := range conns {
+		conn.logger.Info("Connector is starting...")
+
+		if err := conn.Start(ctx, newHostWrapper(host, conn.logger)); err != nil {
+			return err
+		}
+		conn.logger.Info("Connector started.")
+	}
+	return nil
+}
+
+// ShutdownAll stops all connectors.
+func (conns Connectors) ShutdownAll(ctx context.Context) error {
+	var errs []error
+	for _, conn := range conns {
+		if err := conn.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return consumererror.Combine(errs)
+}
+
+// buildConnectors builds the connectors used as exporters by the pipeline. The pipelines
+// using them as receivers must be already built.
+func (pb *pipelinesBuilder) buildConnectors(ctx context.Context, pipelineCfg *config.Pipeline, builtPipelines BuiltPipelines) error {
+	for _, connID := range pipelineCfg.Exporters {
+		connCfg, ok := pb.config.Connectors[connID]
+		if !ok {
+			continue
+		}
+
+		factory, ok := pb.connectorFactories[connID.Type()]
+		if !ok || factory == nil {
+			return fmt.Errorf("connector factory not found for type: %s", connID.Type())
+		}
+
+		bconn := pb.connectors[connID]
+		if bconn == nil {
+			bconn = &builtConnector{
+				logger:         pb.logger.With(zap.String(zapKindKey, zapKindConnector), zap.String(zapNameKey, connID.String())),
+				connByDataType: make(map[connectorDataTypes]component.Connector),
+			}
+			pb.connectors[connID] = bconn
+		}
+
+		// Find the pipelines receiving from the connector, by data type.
+		pipelinesToAttach := make(attachedPipelines)
+		for _, downstreamCfg := range pb.config.Service.Pipelines {
+			if !hasReceiver(downstreamCfg, connID) {
+				continue
+			}
+			downstream := builtPipelines[downstreamCfg]
+			if downstream == nil {
+				return fmt.Errorf("cannot find pipeline %q receiving from connector %v", downstreamCfg.Name, connID)
+			}
+			pipelinesToAttach[downstreamCfg.InputType] = append(pipelinesToAttach[downstreamCfg.InputType], downstream)
+		}
+
+		set := component.ConnectorCreateSettings{
+			TelemetryCreateSettings: component.TelemetryCreateSettings{
+				Logger:         bconn.logger,
+				TracerProvider: pb.tracerProvider,
+			},
+			BuildInfo: pb.buildInfo,
+		}
+		for outputType, pipelines := range pipelinesToAttach {
+			dataTypes := connectorDataTypes{input: pipelineCfg.InputType, output: outputType}
+			if _, built := bconn.connByDataType[dataTypes]; built {
+				// Already built for another pipeline exporting the same data type.
+				continue
+			}
+			conn, err := createConnector(ctx, set, factory, connCfg, bconn, dataTypes, pipelines)
+			if err != nil {
+				if err == componenterror.ErrDataTypeIsNotSupported {
+					return fmt.Errorf("connector %v does not support connecting %s to %s but it was used this way by pipeline %q",
+						connID, dataTypes.input, dataTypes.output, pipelineCfg.Name)
+				}
+				return fmt.Errorf("cannot create connector %v: %w", connID, err)
+			}
+
+			// Check if the factory really created the connector.
+			if conn == nil {
+				return fmt.Errorf("factory for %v produced a nil connector", connID)
+			}
+
+			bconn.connByDataType[dataTypes] = conn
+			set.Logger.Info("Connector was built.",
+				zap.String("input_datatype", string(dataTypes.input)),
+				zap.String("output_datatype", string(dataTypes.output)))
+		}
+	}
+	return nil
+}
+
+func createConnector(
+	ctx context.Context,
+	set component.ConnectorCreateSettings,
+	factory component.ConnectorFactory,
+	cfg config.Connector,
+	bconn *builtConnector,
+	dataTypes connectorDataTypes,
+	pipelines []*builtPipeline,
+) (component.Connector, error) {
+	var next interface{}
+	switch dataTypes.output {
+	case config.TracesDataType:
+		next = buildFanoutTraceConsumer(bconn, cfg.ID(), pipelines)
+	case config.MetricsDataType:
+		next = buildFanoutMetricConsumer(bconn, cfg.ID(), pipelines)
+	case config.LogsDataType:
+		next = buildFanoutLogConsumer(bconn, cfg.ID(), pipelines)
+	default:
+		return nil, componenterror.ErrDataTypeIsNotSupported
+	}
+
+	switch dataTypes.input {
+	case config.TracesDataType:
+		switch dataTypes.output {
+		case config.TracesDataType:
+			return factory.CreateTracesToTracesConnector(ctx, set, cfg, next.(consumer.Traces))
+		case config.MetricsDataType:
+			return factory.CreateTracesToMetricsConnector(ctx, set, cfg, next.(consumer.Metrics))
+		case config.LogsDataType:
+			return factory.CreateTracesToLogsConnector(ctx, set, cfg, next.(consumer.Logs))
+		}
+	case config.MetricsDataType:
+		switch dataTypes.output {
+		case config.TracesDataType:
+			return factory.CreateMetricsToTracesConnector(ctx, set, cfg, next.(consumer.Traces))
+		case config.MetricsDataType:
+			return factory.CreateMetricsToMetricsConnector(ctx, set, cfg, next.(consumer.Metrics))
+		case config.LogsDataType:
+			return factory.CreateMetricsToLogsConnector(ctx, set, cfg, next.(consumer.Logs))
+		}
+	case config.LogsDataType:
+		switch dataTypes.output {
+		case config.TracesDataType:
+			return factory.CreateLogsToTracesConnector(ctx, set, cfg, next.(consumer.Traces))
+		case config.MetricsDataType:
+			return factory.CreateLogsToMetricsConnector(ctx, set, cfg, next.(consumer.Metrics))
+		case config.LogsDataType:
+			return factory.CreateLogsToLogsConnector(ctx, set, cfg, next.(consumer.Logs))
+		}
+	}
+	return nil, componenterror.ErrDataTypeIsNotSupported
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/testcomponents"
+	"go.opentelemetry.io/collector/internal/testdata"
+)
+
+func TestBuildConnectors(t *testing.T) {
+	factories, err := testcomponents.ExampleComponents()
+	require.NoError(t, err)
+	cfg, err := configtest.LoadConfigAndValidate(path.Join("testdata", "connectors_builder.yaml"), factories)
+	require.NoError(t, err)
+
+	allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
+	require.NoError(t, err)
+	pipelineProcessors, connectors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
+	require.NoError(t, err)
+	require.Len(t, pipelineProcessors, 3)
+
+	// The connector is created once for traces to traces and once for traces to metrics.
+	connID := config.NewID("exampleconnector")
+	require.Len(t, connectors, 1)
+	require.NotNil(t, connectors[connID])
+	assert.Len(t, connectors[connID].connByDataType, 2)
+
+	assert.NoError(t, pipelineProcessors.StartProcessors(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, connectors.StartAll(context.Background(), componenttest.NewNopHost()))
+	for _, conn := range connectors[connID].connByDataType {
+		assert.True(t, conn.(*testcomponents.ExampleConnector).Started)
+	}
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	require.NoError(t, pipelineProcessors[cfg.Service.Pipelines["traces"]].firstTC.ConsumeTraces(context.Background(), td))
+
+	// The traces are exported by the first pipeline and forwarded to the second one.
+	tracesExp := allExporters[config.NewID("exampleexporter")].getTracesExporter().(*testcomponents.ExampleExporterConsumer)
+	require.Len(t, tracesExp.Traces, 1)
+	assert.EqualValues(t, td, tracesExp.Traces[0])
+	tracesExp2 := allExporters[config.NewIDWithName("exampleexporter", "2")].getTracesExporter().(*testcomponents.ExampleExporterConsumer)
+	require.Len(t, tracesExp2.Traces, 1)
+	assert.EqualValues(t, td, tracesExp2.Traces[0])
+
+	// The metrics pipeline receives the metric derived from the traces.
+	metricsExp := allExporters[config.NewID("exampleexporter")].getMetricExporter().(*testcomponents.ExampleExporterConsumer)
+	require.Len(t, metricsExp.Metrics, 1)
+	metric := metricsExp.Metrics[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "span_count", metric.Name())
+	assert.EqualValues(t, 2, metric.Gauge().DataPoints().At(0).IntVal())
+
+	// The connector recorded the data sent to each pipeline.
+	require.Len(t, connectors[connID].Taps, 2)
+	for _, point := range connectors[connID].Taps {
+		assert.Equal(t, "receiver", point.Kind)
+		assert.Equal(t, connID.String(), point.Component)
+		assert.EqualValues(t, 1, point.Stats().Batches)
+	}
+
+	assert.NoError(t, connectors.ShutdownAll(context.Background()))
+	for _, conn := range connectors[connID].connByDataType {
+		assert.True(t, conn.(*testcomponents.ExampleConnector).Stopped)
+	}
+	assert.NoError(t, pipelineProcessors.ShutdownProcessors(context.Background()))
+}
+
+func TestShutdownWithConnectors(t *testing.T) {
+	factories, err := testcomponents.ExampleComponents()
+	require.NoError(t, err)
+	cfg, err := configtest.LoadConfigAndValidate(path.Join("testdata", "connectors_builder.yaml"), factories)
+	require.NoError(t, err)
+
+	// The pipelines on both sides of the connector hold the data until they are shutdown.
+	flushingFactory := newFlushingProcessorFactory()
+	factories.Processors[flushingFactory.Type()] = flushingFactory
+	procID := config.NewID(flushingFactory.Type())
+	cfg.Processors[procID] = flushingFactory.CreateDefaultConfig()
+	cfg.Service.Pipelines["traces"].Processors = []config.ComponentID{procID}
+	cfg.Service.Pipelines["metrics"].Processors = []config.ComponentID{procID}
+
+	allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
+	require.NoError(t, err)
+	pipelineProcessors, connectors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
+	require.NoError(t, err)
+	require.NoError(t, pipelineProcessors.StartProcessors(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, connectors.StartAll(context.Background(), componenttest.NewNopHost()))
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	require.NoError(t, pipelineProcessors[cfg.Service.Pipelines["traces"]].firstTC.ConsumeTraces(context.Background(), td))
+	tracesExp := allExporters[config.NewID("exampleexporter")].getTracesExporter().(*testcomponents.ExampleExporterConsumer)
+	assert.Len(t, tracesExp.Traces, 0)
+
+	require.NoError(t, pipelineProcessors.ShutdownWithConnectors(context.Background(), cfg, connectors))
+	for _, conn := range connectors[config.NewID("exampleconnector")].connByDataType {
+		assert.True(t, conn.(*testcomponents.ExampleConnector).Stopped)
+	}
+
+	// The data flushed by the traces pipeline went through the downstream pipelines.
+	assert.Len(t, tracesExp.Traces, 1)
+	tracesExp2 := allExporters[config.NewIDWithName("exampleexporter", "2")].getTracesExporter().(*testcomponents.ExampleExporterConsumer)
+	assert.Len(t, tracesExp2.Traces, 1)
+	metricsExp := allExporters[config.NewID("exampleexporter")].getMetricExporter().(*testcomponents.ExampleExporterConsumer)
+	require.Len(t, metricsExp.Metrics, 1)
+	assert.Equal(t, "span_count", metricsExp.Metrics[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+}
+
+func TestBuildConnectors_NotSupportedDataType(t *testing.T) {
+	factories, err := testcomponents.ExampleComponents()
+	require.NoError(t, err)
+	cfg, err := configtest.LoadConfigAndValidate(path.Join("testdata", "not_supported_connector.yaml"), factories)
+	require.NoError(t, err)
+
+	allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
+	require.NoError(t, err)
+
+	pipelineProcessors, connectors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
+	assert.EqualError(t, err, `connector exampleconnector does not support connecting logs to metrics but it was used this way by pipeline "logs"`)
+	assert.Zero(t, len(pipelineProcessors))
+	assert.Zero(t, len(connectors))
+}
+
+func TestBuildPipelines_Cycle(t *testing.T) {
+	factories, err := testcomponents.ExampleComponents()
+	require.NoError(t, err)
+	cfg, err := configtest.LoadConfigAndValidate(path.Join("testdata", "connectors_builder.yaml"), factories)
+	require.NoError(t, err)
+
+	// Bypass the config validation, the builder must not loop forever.
+	cfg.Service.Pipelines["traces/2"].Exporters = append(cfg.Service.Pipelines["traces/2"].Exporters, config.NewID("exampleconnector"))
+
+	allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
+	require.NoError(t, err)
+	_, _, err = BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
+	assert.EqualError(t, err, `pipeline "traces/2" is part of a cycle created by connectors`)
+}
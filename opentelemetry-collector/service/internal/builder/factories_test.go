@@ -19,9 +19,12 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/extension/extensionhelper"
 	"go.opentelemetry.io/collector/internal/testcomponents"
+	"go.opentelemetry.io/collector/model/pdata"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 )
@@ -101,3 +104,61 @@ func newBadExtensionFactory() component.ExtensionFactory {
 		},
 	)
 }
+
+// newFlushingProcessorFactory returns the factory of processors holding the data they consume
+// until they are shutdown.
+func newFlushingProcessorFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		"flushing",
+		func() config.Processor {
+			cfg := config.NewProcessorSettings(config.NewID("flushing"))
+			return &cfg
+		},
+		processorhelper.WithTraces(func(_ context.Context, _ component.ProcessorCreateSettings, _ config.Processor, next consumer.Traces) (component.TracesProcessor, error) {
+			return &flushingProcessor{nextTraces: next}, nil
+		}),
+		processorhelper.WithMetrics(func(_ context.Context, _ component.ProcessorCreateSettings, _ config.Processor, next consumer.Metrics) (component.MetricsProcessor, error) {
+			return &flushingProcessor{nextMetrics: next}, nil
+		}))
+}
+
+type flushingProcessor struct {
+	nextTraces  consumer.Traces
+	nextMetrics consumer.Metrics
+	traces      []pdata.Traces
+	metrics     []pdata.Metrics
+}
+
+func (fp *flushingProcessor) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (fp *flushingProcessor) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, td := range fp.traces {
+		if err := fp.nextTraces.ConsumeTraces(ctx, td); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, md := range fp.metrics {
+		if err := fp.nextMetrics.ConsumeMetrics(ctx, md); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	fp.traces, fp.metrics = nil, nil
+	return consumererror.Combine(errs)
+}
+
+func (fp *flushingProcessor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (fp *flushingProcessor) ConsumeTraces(_ context.Context, td pdata.Traces) error {
+	fp.traces = append(fp.traces, td)
+	return nil
+}
+
+func (fp *flushingProcessor) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
+	fp.metrics = append(fp.metrics, md)
+	return nil
+}
@@ -16,23 +16,35 @@ package builder
 
 import (
 	"net/http"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 )
 
 // hostWrapper adds behavior on top of the component.Host being passed when starting the built components.
 type hostWrapper struct {
 	component.Host
 	*zap.Logger
+
+	// exportersUsed, when not nil, is set to 1 once GetExporters is called, it is used atomically.
+	exportersUsed *int32
 }
 
 func newHostWrapper(host component.Host, logger *zap.Logger) component.Host {
 	return &hostWrapper{
-		host,
-		logger,
+		Host:   host,
+		Logger: logger,
+	}
+}
+
+func (hw *hostWrapper) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	if hw.exportersUsed != nil {
+		atomic.StoreInt32(hw.exportersUsed, 1)
 	}
+	return hw.Host.GetExporters()
 }
 
 func (hw *hostWrapper) ReportFatalError(err error) {
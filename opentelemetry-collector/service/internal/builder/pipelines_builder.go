@@ -17,6 +17,8 @@ package builder
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync/atomic"
 
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -26,12 +28,14 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/service/internal/fanoutconsumer"
+	"go.opentelemetry.io/collector/service/internal/tap"
 )
 
 // builtPipeline is a pipeline that is built based on a config.
 // It can have a trace and/or a metrics consumer (the consumer is either the first
 // processor in the pipeline or the exporter if pipeline has no processors).
 type builtPipeline struct {
+	name    string
 	logger  *zap.Logger
 	firstTC consumer.Traces
 	firstMC consumer.Metrics
@@ -41,7 +45,20 @@ type builtPipeline struct {
 	// can mutate the TraceData or MetricsData input argument.
 	MutatesData bool
 
+	// Taps are the points recording the data consumed by the processors and
+	// exporters of the pipeline.
+	Taps []*tap.Point
+
 	processors []component.Processor
+
+	// exportersUsed is set to 1 when a processor gets the exporters from the host, it is used atomically.
+	exportersUsed int32
+}
+
+// UsesExporters indicates whether a processor of the pipeline got the exporters from the host,
+// and so may keep references to exporters which are not part of the pipeline.
+func (bp *builtPipeline) UsesExporters() bool {
+	return atomic.LoadInt32(&bp.exportersUsed) != 0
 }
 
 // BuiltPipelines is a map of build pipelines created from pipeline configs.
@@ -50,13 +67,13 @@ type BuiltPipelines map[*config.Pipeline]*builtPipeline
 func (bps BuiltPipelines) StartProcessors(ctx context.Context, host component.Host) error {
 	for _, bp := range bps {
 		bp.logger.Info("Pipeline is starting...")
-		hostWrapper := newHostWrapper(host, bp.logger)
+		hw := &hostWrapper{Host: host, Logger: bp.logger, exportersUsed: &bp.exportersUsed}
 		// Start in reverse order, starting from the back of processors pipeline.
 		// This is important so that processors that are earlier in the pipeline and
 		// reference processors that are later in the pipeline do not start sending
 		// data to later pipelines which are not yet started.
 		for i := len(bp.processors) - 1; i >= 0; i-- {
-			if err := bp.processors[i].Start(ctx, hostWrapper); err != nil {
+			if err := bp.processors[i].Start(ctx, hw); err != nil {
 				return err
 			}
 		}
@@ -68,30 +85,91 @@ func (bps BuiltPipelines) StartProcessors(ctx context.Context, host component.Ho
 func (bps BuiltPipelines) ShutdownProcessors(ctx context.Context) error {
 	var errs []error
 	for _, bp := range bps {
-		bp.logger.Info("Pipeline is shutting down...")
-		for _, p := range bp.processors {
-			if err := p.Shutdown(ctx); err != nil {
+		errs = append(errs, bp.shutdownProcessors(ctx)...)
+	}
+
+	return consumererror.Combine(errs)
+}
+
+// ShutdownWithConnectors stops the processors of the pipelines built from cfg and the connectors
+// linking them, so that the data flushed when shutting down still reaches the downstream pipelines:
+// a pipeline is shutdown before the connectors it exports to, and these connectors are shutdown
+// before the pipelines receiving from them.
+func (bps BuiltPipelines) ShutdownWithConnectors(ctx context.Context, cfg *config.Config, conns Connectors) error {
+	// The pipelines are built starting from the end of the chains of connectors,
+	// so shut them down in reverse order.
+	var order []*config.Pipeline
+	if cfg != nil {
+		var err error
+		if order, err = (&pipelinesBuilder{config: cfg}).pipelinesOrder(); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	stopped := make(map[config.ComponentID]bool)
+	stoppedPipelines := make(map[*config.Pipeline]bool)
+	for i := len(order) - 1; i >= 0; i-- {
+		// Every pipeline exporting to the connectors the pipeline receives from is already shutdown.
+		for _, rcvID := range order[i].Receivers {
+			conn, ok := conns[rcvID]
+			if !ok || stopped[rcvID] {
+				continue
+			}
+			stopped[rcvID] = true
+			if err := conn.Shutdown(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if bp, ok := bps[order[i]]; ok {
+			stoppedPipelines[order[i]] = true
+			errs = append(errs, bp.shutdownProcessors(ctx)...)
+		}
+	}
+	for pipeline, bp := range bps {
+		if !stoppedPipelines[pipeline] {
+			errs = append(errs, bp.shutdownProcessors(ctx)...)
+		}
+	}
+	for connID, conn := range conns {
+		if !stopped[connID] {
+			if err := conn.Shutdown(ctx); err != nil {
 				errs = append(errs, err)
 			}
 		}
-		bp.logger.Info("Pipeline is shutdown.")
 	}
 
 	return consumererror.Combine(errs)
 }
 
+// shutdownProcessors stops the processors from the first one, so that the data flushed by a
+// processor reaches the following ones.
+func (bp *builtPipeline) shutdownProcessors(ctx context.Context) []error {
+	var errs []error
+	bp.logger.Info("Pipeline is shutting down...")
+	for _, p := range bp.processors {
+		if err := p.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	bp.logger.Info("Pipeline is shutdown.")
+	return errs
+}
+
 // pipelinesBuilder builds Pipelines from config.
 type pipelinesBuilder struct {
-	logger         *zap.Logger
-	tracerProvider trace.TracerProvider
-	buildInfo      component.BuildInfo
-	config         *config.Config
-	exporters      Exporters
-	factories      map[config.Type]component.ProcessorFactory
+	logger             *zap.Logger
+	tracerProvider     trace.TracerProvider
+	buildInfo          component.BuildInfo
+	config             *config.Config
+	exporters          Exporters
+	factories          map[config.Type]component.ProcessorFactory
+	connectorFactories map[config.Type]component.ConnectorFactory
+	connectors         Connectors
 }
 
-// BuildPipelines builds pipeline processors from config. Requires exporters to be already
-// built via BuildExporters.
+// BuildPipelines builds pipeline processors and the connectors linking the pipelines from config.
+// Requires exporters to be already built via BuildExporters.
 func BuildPipelines(
 	logger *zap.Logger,
 	tracerProvider trace.TracerProvider,
@@ -99,19 +177,78 @@ func BuildPipelines(
 	config *config.Config,
 	exporters Exporters,
 	factories map[config.Type]component.ProcessorFactory,
-) (BuiltPipelines, error) {
-	pb := &pipelinesBuilder{logger, tracerProvider, buildInfo, config, exporters, factories}
+	connectorFactories map[config.Type]component.ConnectorFactory,
+) (BuiltPipelines, Connectors, error) {
+	pb := &pipelinesBuilder{logger, tracerProvider, buildInfo, config, exporters, factories, connectorFactories, make(Connectors)}
+
+	// A pipeline exporting to a connector needs the pipelines receiving from it,
+	// so build the pipelines starting from the end of the chains of connectors.
+	order, err := pb.pipelinesOrder()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	pipelineProcessors := make(BuiltPipelines)
-	for _, pipeline := range pb.config.Service.Pipelines {
+	for _, pipeline := range order {
+		if err := pb.buildConnectors(context.Background(), pipeline, pipelineProcessors); err != nil {
+			return nil, nil, err
+		}
 		firstProcessor, err := pb.buildPipeline(context.Background(), pipeline)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		pipelineProcessors[pipeline] = firstProcessor
 	}
 
-	return pipelineProcessors, nil
+	return pipelineProcessors, pb.connectors, nil
+}
+
+// pipelinesOrder returns the pipelines sorted so that every pipeline comes after the pipelines
+// receiving the data it exports to connectors.
+func (pb *pipelinesBuilder) pipelinesOrder() ([]*config.Pipeline, error) {
+	names := make([]string, 0, len(pb.config.Service.Pipelines))
+	for name := range pb.config.Service.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[*config.Pipeline]int)
+	order := make([]*config.Pipeline, 0, len(names))
+	var visit func(pipeline *config.Pipeline) error
+	visit = func(pipeline *config.Pipeline) error {
+		switch state[pipeline] {
+		case visiting:
+			return fmt.Errorf("pipeline %q is part of a cycle created by connectors", pipeline.Name)
+		case visited:
+			return nil
+		}
+		state[pipeline] = visiting
+		for _, expID := range pipeline.Exporters {
+			if _, ok := pb.config.Connectors[expID]; !ok {
+				continue
+			}
+			for _, name := range names {
+				if downstream := pb.config.Service.Pipelines[name]; hasReceiver(downstream, expID) {
+					if err := visit(downstream); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		state[pipeline] = visited
+		order = append(order, pipeline)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(pb.config.Service.Pipelines[name]); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
 }
 
 // Builds a pipeline of processors. Returns the first processor in the pipeline.
@@ -125,14 +262,15 @@ func (pb *pipelinesBuilder) buildPipeline(ctx context.Context, pipelineCfg *conf
 	var tc consumer.Traces
 	var mc consumer.Metrics
 	var lc consumer.Logs
+	var taps []*tap.Point
 
 	switch pipelineCfg.InputType {
 	case config.TracesDataType:
-		tc = pb.buildFanoutExportersTracesConsumer(pipelineCfg.Exporters)
+		tc, taps = pb.buildFanoutExportersTracesConsumer(pipelineCfg.Name, pipelineCfg.Exporters)
 	case config.MetricsDataType:
-		mc = pb.buildFanoutExportersMetricsConsumer(pipelineCfg.Exporters)
+		mc, taps = pb.buildFanoutExportersMetricsConsumer(pipelineCfg.Name, pipelineCfg.Exporters)
 	case config.LogsDataType:
-		lc = pb.buildFanoutExportersLogsConsumer(pipelineCfg.Exporters)
+		lc, taps = pb.buildFanoutExportersLogsConsumer(pipelineCfg.Name, pipelineCfg.Exporters)
 	}
 
 	mutatesConsumedData := false
@@ -209,6 +347,19 @@ func (pb *pipelinesBuilder) buildPipeline(ctx context.Context, pipelineCfg *conf
 		if tc == nil && mc == nil && lc == nil {
 			return nil, fmt.Errorf("factory for %v produced a nil processor", procID)
 		}
+
+		// Record the data consumed by the processor.
+		// Processors are built backwards, keep their points in the pipeline order.
+		point := tap.NewPoint(pipelineCfg.Name, zapKindProcessor, procID.String())
+		taps = append([]*tap.Point{point}, taps...)
+		switch pipelineCfg.InputType {
+		case config.TracesDataType:
+			tc = tap.NewTraces(point, tc)
+		case config.MetricsDataType:
+			mc = tap.NewMetrics(point, mc)
+		case config.LogsDataType:
+			lc = tap.NewLogs(point, lc)
+		}
 	}
 
 	pipelineLogger := pb.logger.With(zap.String("pipeline_name", pipelineCfg.Name),
@@ -216,60 +367,63 @@ func (pb *pipelinesBuilder) buildPipeline(ctx context.Context, pipelineCfg *conf
 	pipelineLogger.Info("Pipeline was built.")
 
 	bp := &builtPipeline{
-		pipelineLogger,
-		tc,
-		mc,
-		lc,
-		mutatesConsumedData,
-		processors,
+		name:        pipelineCfg.Name,
+		logger:      pipelineLogger,
+		firstTC:     tc,
+		firstMC:     mc,
+		firstLC:     lc,
+		MutatesData: mutatesConsumedData,
+		Taps:        taps,
+		processors:  processors,
 	}
 
 	return bp, nil
 }
 
-// Converts the list of exporter names to a list of corresponding builtExporters.
-func (pb *pipelinesBuilder) getBuiltExportersByIDs(exporterIDs []config.ComponentID) []*builtExporter {
-	var result []*builtExporter
-	for _, expID := range exporterIDs {
-		exporter := pb.exporters[expID]
-		result = append(result, exporter)
-	}
-
-	return result
-}
-
-func (pb *pipelinesBuilder) buildFanoutExportersTracesConsumer(exporterIDs []config.ComponentID) consumer.Traces {
-	builtExporters := pb.getBuiltExportersByIDs(exporterIDs)
-
+func (pb *pipelinesBuilder) buildFanoutExportersTracesConsumer(pipelineName string, exporterIDs []config.ComponentID) (consumer.Traces, []*tap.Point) {
 	var exporters []consumer.Traces
-	for _, builtExp := range builtExporters {
-		exporters = append(exporters, builtExp.getTracesExporter())
+	taps := make([]*tap.Point, len(exporterIDs))
+	for i, expID := range exporterIDs {
+		taps[i] = tap.NewPoint(pipelineName, zapKindLogExporter, expID.String())
+		if conn, ok := pb.connectors[expID]; ok {
+			exporters = append(exporters, tap.NewTraces(taps[i], conn.getTracesConnector()))
+			continue
+		}
+		exporters = append(exporters, tap.NewTraces(taps[i], pb.exporters[expID].getTracesExporter()))
 	}
 
 	// Create a junction point that fans out to all exporters.
-	return fanoutconsumer.NewTraces(exporters)
+	return fanoutconsumer.NewTraces(exporters), taps
 }
 
-func (pb *pipelinesBuilder) buildFanoutExportersMetricsConsumer(exporterIDs []config.ComponentID) consumer.Metrics {
-	builtExporters := pb.getBuiltExportersByIDs(exporterIDs)
-
+func (pb *pipelinesBuilder) buildFanoutExportersMetricsConsumer(pipelineName string, exporterIDs []config.ComponentID) (consumer.Metrics, []*tap.Point) {
 	var exporters []consumer.Metrics
-	for _, builtExp := range builtExporters {
-		exporters = append(exporters, builtExp.getMetricExporter())
+	taps := make([]*tap.Point, len(exporterIDs))
+	for i, expID := range exporterIDs {
+		taps[i] = tap.NewPoint(pipelineName, zapKindLogExporter, expID.String())
+		if conn, ok := pb.connectors[expID]; ok {
+			exporters = append(exporters, tap.NewMetrics(taps[i], conn.getMetricsConnector()))
+			continue
+		}
+		exporters = append(exporters, tap.NewMetrics(taps[i], pb.exporters[expID].getMetricExporter()))
 	}
 
 	// Create a junction point that fans out to all exporters.
-	return fanoutconsumer.NewMetrics(exporters)
+	return fanoutconsumer.NewMetrics(exporters), taps
 }
 
-func (pb *pipelinesBuilder) buildFanoutExportersLogsConsumer(exporterIDs []config.ComponentID) consumer.Logs {
-	builtExporters := pb.getBuiltExportersByIDs(exporterIDs)
-
-	exporters := make([]consumer.Logs, len(builtExporters))
-	for i, builtExp := range builtExporters {
-		exporters[i] = builtExp.getLogExporter()
+func (pb *pipelinesBuilder) buildFanoutExportersLogsConsumer(pipelineName string, exporterIDs []config.ComponentID) (consumer.Logs, []*tap.Point) {
+	exporters := make([]consumer.Logs, len(exporterIDs))
+	taps := make([]*tap.Point, len(exporterIDs))
+	for i, expID := range exporterIDs {
+		taps[i] = tap.NewPoint(pipelineName, zapKindLogExporter, expID.String())
+		if conn, ok := pb.connectors[expID]; ok {
+			exporters[i] = tap.NewLogs(taps[i], conn.getLogsConnector())
+			continue
+		}
+		exporters[i] = tap.NewLogs(taps[i], pb.exporters[expID].getLogExporter())
 	}
 
 	// Create a junction point that fans out to all exporters.
-	return fanoutconsumer.NewLogs(exporters)
+	return fanoutconsumer.NewLogs(exporters), taps
 }
@@ -121,7 +121,7 @@ func TestBuildPipelines_BuildVarious(t *testing.T) {
 
 			require.NoError(t, err)
 			require.EqualValues(t, 1, len(allExporters))
-			pipelineProcessors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors)
+			pipelineProcessors, _, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
 
 			assert.NoError(t, err)
 			require.NotNil(t, pipelineProcessors)
@@ -159,7 +159,7 @@ func TestBuildPipelines_BuildVarious(t *testing.T) {
 			}
 
 			// Send one custom data.
-			log := pdata.Logs{}
+			log := pdata.NewLogs()
 			require.NoError(t, processor.firstLC.ConsumeLogs(context.Background(), log))
 
 			// Now verify received data.
@@ -187,7 +187,7 @@ func testPipeline(t *testing.T, pipelineName string, exporterIDs []config.Compon
 	// BuildProcessors the pipeline
 	allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
 	assert.NoError(t, err)
-	pipelineProcessors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors)
+	pipelineProcessors, _, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
 
 	assert.NoError(t, err)
 	require.NotNil(t, pipelineProcessors)
@@ -232,6 +232,21 @@ func testPipeline(t *testing.T, pipelineName string, exporterIDs []config.Compon
 		assert.EqualValues(t, td, expConsumer.Traces[0])
 	}
 
+	// Verify that the processors and the exporters recorded the data, in the pipeline order.
+	pipelineCfg := cfg.Service.Pipelines[pipelineName]
+	require.Len(t, processor.Taps, len(pipelineCfg.Processors)+len(exporterIDs))
+	for i, point := range processor.Taps {
+		assert.Equal(t, pipelineName, point.Pipeline)
+		if i < len(pipelineCfg.Processors) {
+			assert.Equal(t, "processor", point.Kind)
+			assert.Equal(t, pipelineCfg.Processors[i].String(), point.Component)
+		} else {
+			assert.Equal(t, "exporter", point.Kind)
+			assert.Equal(t, exporterIDs[i-len(pipelineCfg.Processors)].String(), point.Component)
+		}
+		assert.EqualValues(t, 1, point.Stats().Items)
+	}
+
 	err = pipelineProcessors.ShutdownProcessors(context.Background())
 	assert.NoError(t, err)
 }
@@ -262,7 +277,7 @@ func TestBuildPipelines_NotSupportedDataType(t *testing.T) {
 			allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
 			assert.NoError(t, err)
 
-			pipelineProcessors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors)
+			pipelineProcessors, _, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
 			assert.Error(t, err)
 			assert.Zero(t, len(pipelineProcessors))
 		})
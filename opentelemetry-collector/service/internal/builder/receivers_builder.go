@@ -28,6 +28,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/service/internal/fanoutconsumer"
+	"go.opentelemetry.io/collector/service/internal/tap"
 )
 
 var errUnusedReceiver = errors.New("receiver defined but not used by any pipeline")
@@ -37,6 +38,10 @@ var errUnusedReceiver = errors.New("receiver defined but not used by any pipelin
 type builtReceiver struct {
 	logger   *zap.Logger
 	receiver component.Receiver
+
+	// Taps are the points recording the data sent by the receiver to each
+	// attached pipeline.
+	Taps []*tap.Point
 }
 
 // Start starts the receiver.
@@ -49,6 +54,19 @@ func (rcv *builtReceiver) Shutdown(ctx context.Context) error {
 	return rcv.receiver.Shutdown(ctx)
 }
 
+// tap returns a new point recording the data sent by the receiver to the pipeline.
+func (rcv *builtReceiver) tap(pipeline *builtPipeline, receiverID config.ComponentID) *tap.Point {
+	point := tap.NewPoint(pipeline.name, zapKindReceiver, receiverID.String())
+	rcv.Taps = append(rcv.Taps, point)
+	return point
+}
+
+// tapper creates the points recording the data sent to the pipelines attached to a
+// receiver or a connector.
+type tapper interface {
+	tap(pipeline *builtPipeline, id config.ComponentID) *tap.Point
+}
+
 // Receivers is a map of receivers created from receiver configs.
 type Receivers map[config.ComponentID]*builtReceiver
 
@@ -182,15 +200,15 @@ func attachReceiverToPipelines(
 
 	switch dataType {
 	case config.TracesDataType:
-		junction := buildFanoutTraceConsumer(builtPipelines)
+		junction := buildFanoutTraceConsumer(rcv, cfg.ID(), builtPipelines)
 		createdReceiver, err = factory.CreateTracesReceiver(ctx, set, cfg, junction)
 
 	case config.MetricsDataType:
-		junction := buildFanoutMetricConsumer(builtPipelines)
+		junction := buildFanoutMetricConsumer(rcv, cfg.ID(), builtPipelines)
 		createdReceiver, err = factory.CreateMetricsReceiver(ctx, set, cfg, junction)
 
 	case config.LogsDataType:
-		junction := buildFanoutLogConsumer(builtPipelines)
+		junction := buildFanoutLogConsumer(rcv, cfg.ID(), builtPipelines)
 		createdReceiver, err = factory.CreateLogsReceiver(ctx, set, cfg, junction)
 
 	default:
@@ -270,16 +288,16 @@ func (rb *receiversBuilder) buildReceiver(ctx context.Context, set component.Rec
 	return rcv, nil
 }
 
-func buildFanoutTraceConsumer(pipelines []*builtPipeline) consumer.Traces {
+func buildFanoutTraceConsumer(rcv tapper, receiverID config.ComponentID, pipelines []*builtPipeline) consumer.Traces {
 	// Optimize for the case when there is only one processor, no need to create junction point.
 	if len(pipelines) == 1 {
-		return pipelines[0].firstTC
+		return tap.NewTraces(rcv.tap(pipelines[0], receiverID), pipelines[0].firstTC)
 	}
 
 	var pipelineConsumers []consumer.Traces
 	anyPipelineMutatesData := false
 	for _, pipeline := range pipelines {
-		pipelineConsumers = append(pipelineConsumers, pipeline.firstTC)
+		pipelineConsumers = append(pipelineConsumers, tap.NewTraces(rcv.tap(pipeline, receiverID), pipeline.firstTC))
 		anyPipelineMutatesData = anyPipelineMutatesData || pipeline.MutatesData
 	}
 
@@ -295,16 +313,16 @@ func buildFanoutTraceConsumer(pipelines []*builtPipeline) consumer.Traces {
 	return fanoutconsumer.NewTraces(pipelineConsumers)
 }
 
-func buildFanoutMetricConsumer(pipelines []*builtPipeline) consumer.Metrics {
+func buildFanoutMetricConsumer(rcv tapper, receiverID config.ComponentID, pipelines []*builtPipeline) consumer.Metrics {
 	// Optimize for the case when there is only one processor, no need to create junction point.
 	if len(pipelines) == 1 {
-		return pipelines[0].firstMC
+		return tap.NewMetrics(rcv.tap(pipelines[0], receiverID), pipelines[0].firstMC)
 	}
 
 	var pipelineConsumers []consumer.Metrics
 	anyPipelineMutatesData := false
 	for _, pipeline := range pipelines {
-		pipelineConsumers = append(pipelineConsumers, pipeline.firstMC)
+		pipelineConsumers = append(pipelineConsumers, tap.NewMetrics(rcv.tap(pipeline, receiverID), pipeline.firstMC))
 		anyPipelineMutatesData = anyPipelineMutatesData || pipeline.MutatesData
 	}
 
@@ -320,16 +338,16 @@ func buildFanoutMetricConsumer(pipelines []*builtPipeline) consumer.Metrics {
 	return fanoutconsumer.NewMetrics(pipelineConsumers)
 }
 
-func buildFanoutLogConsumer(pipelines []*builtPipeline) consumer.Logs {
+func buildFanoutLogConsumer(rcv tapper, receiverID config.ComponentID, pipelines []*builtPipeline) consumer.Logs {
 	// Optimize for the case when there is only one processor, no need to create junction point.
 	if len(pipelines) == 1 {
-		return pipelines[0].firstLC
+		return tap.NewLogs(rcv.tap(pipelines[0], receiverID), pipelines[0].firstLC)
 	}
 
 	var pipelineConsumers []consumer.Logs
 	anyPipelineMutatesData := false
 	for _, pipeline := range pipelines {
-		pipelineConsumers = append(pipelineConsumers, pipeline.firstLC)
+		pipelineConsumers = append(pipelineConsumers, tap.NewLogs(rcv.tap(pipeline, receiverID), pipeline.firstLC))
 		anyPipelineMutatesData = anyPipelineMutatesData || pipeline.MutatesData
 	}
 
@@ -97,7 +97,7 @@ func testReceivers(t *testing.T, test testCase) {
 	// Build the pipeline
 	allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
 	assert.NoError(t, err)
-	pipelineProcessors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors)
+	pipelineProcessors, _, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
 	assert.NoError(t, err)
 	receivers, err := BuildReceivers(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, pipelineProcessors, factories.Receivers)
 
@@ -170,6 +170,15 @@ func testReceivers(t *testing.T, test testCase) {
 			assert.EqualValues(t, md, metricsConsumer.Metrics[0])
 		}
 	}
+
+	// Verify that the data sent to each attached pipeline was recorded.
+	var batches int64
+	for _, point := range receiver.Taps {
+		assert.Equal(t, "receiver", point.Kind)
+		assert.Equal(t, test.receiverID.String(), point.Component)
+		batches += point.Stats().Batches
+	}
+	assert.NotZero(t, batches)
 }
 
 func TestBuildReceivers_BuildCustom(t *testing.T) {
@@ -203,7 +212,7 @@ func TestBuildReceivers_BuildCustom(t *testing.T) {
 			}
 
 			assert.NoError(t, err)
-			pipelineProcessors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors)
+			pipelineProcessors, _, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
 			assert.NoError(t, err)
 			receivers, err := BuildReceivers(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, pipelineProcessors, factories.Receivers)
 
@@ -236,7 +245,7 @@ func TestBuildReceivers_BuildCustom(t *testing.T) {
 			}
 
 			// Send one data.
-			log := pdata.Logs{}
+			log := pdata.NewLogs()
 			producer := receiver.receiver.(*testcomponents.ExampleReceiverProducer)
 			require.NoError(t, producer.ConsumeLogs(context.Background(), log))
 
@@ -282,7 +291,7 @@ func TestBuildReceivers_Unused(t *testing.T) {
 	// Build the pipeline
 	allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
 	assert.NoError(t, err)
-	pipelineProcessors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors)
+	pipelineProcessors, _, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
 	assert.NoError(t, err)
 	receivers, err := BuildReceivers(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, pipelineProcessors, factories.Receivers)
 	assert.NoError(t, err)
@@ -319,7 +328,7 @@ func TestBuildReceivers_NotSupportedDataType(t *testing.T) {
 			allExporters, err := BuildExporters(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, factories.Exporters)
 			assert.NoError(t, err)
 
-			pipelineProcessors, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors)
+			pipelineProcessors, _, err := BuildPipelines(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, allExporters, factories.Processors, factories.Connectors)
 			assert.NoError(t, err)
 
 			receivers, err := BuildReceivers(zap.NewNop(), trace.NewNoopTracerProvider(), component.DefaultBuildInfo(), cfg, pipelineProcessors, factories.Receivers)
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/internal/otlptext"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+var (
+	tracesMarshaler  = otlptext.NewTextTracesMarshaler()
+	metricsMarshaler = otlptext.NewTextMetricsMarshaler()
+	logsMarshaler    = otlptext.NewTextLogsMarshaler()
+)
+
+// NewTraces wraps the traces consumer so that the data it consumes is recorded by the Point.
+func NewTraces(p *Point, next consumer.Traces) consumer.Traces {
+	return &tracesConsumer{point: p, next: next}
+}
+
+type tracesConsumer struct {
+	point *Point
+	next  consumer.Traces
+}
+
+var _ consumer.Traces = (*tracesConsumer)(nil)
+
+func (tc *tracesConsumer) Capabilities() consumer.Capabilities {
+	return tc.next.Capabilities()
+}
+
+// ConsumeTraces records the pdata.Traces and passes them to the wrapped consumer.
+func (tc *tracesConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	return tc.point.record(td.SpanCount(),
+		func() ([]byte, error) { return tracesMarshaler.MarshalTraces(td) },
+		func() error { return tc.next.ConsumeTraces(ctx, td) })
+}
+
+// NewMetrics wraps the metrics consumer so that the data it consumes is recorded by the Point.
+func NewMetrics(p *Point, next consumer.Metrics) consumer.Metrics {
+	return &metricsConsumer{point: p, next: next}
+}
+
+type metricsConsumer struct {
+	point *Point
+	next  consumer.Metrics
+}
+
+var _ consumer.Metrics = (*metricsConsumer)(nil)
+
+func (mc *metricsConsumer) Capabilities() consumer.Capabilities {
+	return mc.next.Capabilities()
+}
+
+// ConsumeMetrics records the pdata.Metrics and passes them to the wrapped consumer.
+func (mc *metricsConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	return mc.point.record(md.DataPointCount(),
+		func() ([]byte, error) { return metricsMarshaler.MarshalMetrics(md) },
+		func() error { return mc.next.ConsumeMetrics(ctx, md) })
+}
+
+// NewLogs wraps the logs consumer so that the data it consumes is recorded by the Point.
+func NewLogs(p *Point, next consumer.Logs) consumer.Logs {
+	return &logsConsumer{point: p, next: next}
+}
+
+type logsConsumer struct {
+	point *Point
+	next  consumer.Logs
+}
+
+var _ consumer.Logs = (*logsConsumer)(nil)
+
+func (lc *logsConsumer) Capabilities() consumer.Capabilities {
+	return lc.next.Capabilities()
+}
+
+// ConsumeLogs records the pdata.Logs and passes them to the wrapped consumer.
+func (lc *logsConsumer) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	return lc.point.record(ld.LogRecordCount(),
+		func() ([]byte, error) { return logsMarshaler.MarshalLogs(ld) },
+		func() error { return lc.next.ConsumeLogs(ctx, ld) })
+}
@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tap contains Traces/Metrics/Logs consumers that count the data
+// passing through a pipeline component and, while someone is watching,
+// keep a bounded copy of it rendered as text for the zPages.
+package tap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxCaptures is the number of batches kept by a Point while armed.
+	maxCaptures = 20
+	// maxCaptureSize bounds the rendered text kept for a single batch.
+	maxCaptureSize = 64 * 1024
+	// rateWindow is the minimum time between the samples used for Stats.Rate.
+	rateWindow = 10 * time.Second
+)
+
+// Capture is one batch of data captured by a Point.
+type Capture struct {
+	Time      time.Time
+	Items     int
+	Text      string
+	Truncated bool
+	Err       error
+}
+
+// Stats are the counters of a Point.
+type Stats struct {
+	Items       int64
+	Batches     int64
+	FailedItems int64
+	// Rate is the number of items per second, averaged over at least rateWindow.
+	Rate float64
+}
+
+type sample struct {
+	time  time.Time
+	items int64
+}
+
+// Point is a place in a pipeline where data is tapped: the input of a
+// processor or an exporter, or the output of a receiver into the pipeline.
+type Point struct {
+	Pipeline  string
+	Kind      string
+	Component string
+
+	items       int64
+	batches     int64
+	failedItems int64
+	armedUntil  int64
+
+	mu       sync.Mutex
+	captures []Capture
+	samples  [2]sample
+}
+
+// NewPoint returns a Point for the component of the given kind in the pipeline.
+func NewPoint(pipeline, kind, component string) *Point {
+	return &Point{
+		Pipeline:  pipeline,
+		Kind:      kind,
+		Component: component,
+		samples:   [2]sample{{time: time.Now()}, {time: time.Now()}},
+	}
+}
+
+// Arm starts capturing data for the given duration, or extends the current
+// capture. Captures are only kept while the Point is armed.
+func (p *Point) Arm(d time.Duration) {
+	atomic.StoreInt64(&p.armedUntil, time.Now().Add(d).UnixNano())
+}
+
+func (p *Point) armed() bool {
+	return time.Now().UnixNano() < atomic.LoadInt64(&p.armedUntil)
+}
+
+// Captures returns the batches captured so far, newest first.
+func (p *Point) Captures() []Capture {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	captures := make([]Capture, len(p.captures))
+	for i, c := range p.captures {
+		captures[len(captures)-1-i] = c
+	}
+	return captures
+}
+
+// Stats returns the counters of the Point.
+func (p *Point) Stats() Stats {
+	s := Stats{
+		Items:       atomic.LoadInt64(&p.items),
+		Batches:     atomic.LoadInt64(&p.batches),
+		FailedItems: atomic.LoadInt64(&p.failedItems),
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if now.Sub(p.samples[1].time) >= rateWindow {
+		p.samples[0] = p.samples[1]
+		p.samples[1] = sample{time: now, items: s.Items}
+	}
+	if elapsed := now.Sub(p.samples[0].time).Seconds(); elapsed > 0 {
+		s.Rate = float64(s.Items-p.samples[0].items) / elapsed
+	}
+	return s
+}
+
+// record counts a batch of items, calling consume to pass it on. While the
+// Point is armed, render is called before consume, so that the capture is
+// not affected by components that mutate the data.
+func (p *Point) record(items int, render func() ([]byte, error), consume func() error) error {
+	atomic.AddInt64(&p.items, int64(items))
+	atomic.AddInt64(&p.batches, 1)
+
+	if !p.armed() {
+		err := consume()
+		if err != nil {
+			atomic.AddInt64(&p.failedItems, int64(items))
+		}
+		return err
+	}
+
+	c := Capture{Time: time.Now(), Items: items}
+	buf, err := render()
+	if err != nil {
+		c.Text = err.Error()
+	} else {
+		if len(buf) > maxCaptureSize {
+			buf = buf[:maxCaptureSize]
+			c.Truncated = true
+		}
+		c.Text = string(buf)
+	}
+
+	err = consume()
+	if err != nil {
+		atomic.AddInt64(&p.failedItems, int64(items))
+	}
+	c.Err = err
+
+	p.mu.Lock()
+	if len(p.captures) == maxCaptures {
+		copy(p.captures, p.captures[1:])
+		p.captures = p.captures[:maxCaptures-1]
+	}
+	p.captures = append(p.captures, c)
+	p.mu.Unlock()
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tap
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/testdata"
+	"go.opentelemetry.io/collector/model/pdata"
+)
+
+func TestPointNotArmed(t *testing.T) {
+	p := NewPoint("traces", "processor", "batch")
+	sink := new(consumertest.TracesSink)
+	tc := NewTraces(p, sink)
+
+	require.NoError(t, tc.ConsumeTraces(context.Background(), testdata.GenerateTracesTwoSpansSameResource()))
+
+	assert.Equal(t, 2, sink.SpanCount())
+	assert.Empty(t, p.Captures())
+	stats := p.Stats()
+	assert.EqualValues(t, 2, stats.Items)
+	assert.EqualValues(t, 1, stats.Batches)
+	assert.EqualValues(t, 0, stats.FailedItems)
+}
+
+func TestPointArmed(t *testing.T) {
+	p := NewPoint("traces", "exporter", "otlp")
+	p.Arm(time.Minute)
+	tc := NewTraces(p, consumertest.NewErr(errors.New("backend down")))
+
+	td := testdata.GenerateTracesOneSpan()
+	assert.Error(t, tc.ConsumeTraces(context.Background(), td))
+
+	captures := p.Captures()
+	require.Len(t, captures, 1)
+	assert.Equal(t, 1, captures[0].Items)
+	assert.Contains(t, captures[0].Text, "Span #0")
+	assert.EqualError(t, captures[0].Err, "backend down")
+	assert.EqualValues(t, 1, p.Stats().FailedItems)
+}
+
+func TestPointCapturesBounded(t *testing.T) {
+	p := NewPoint("logs", "receiver", "otlp")
+	p.Arm(time.Minute)
+	lc := NewLogs(p, consumertest.NewNop())
+
+	for i := 1; i <= maxCaptures+5; i++ {
+		require.NoError(t, lc.ConsumeLogs(context.Background(), testdata.GenerateLogsManyLogRecordsSameResource(i)))
+	}
+
+	captures := p.Captures()
+	require.Len(t, captures, maxCaptures)
+	// Newest first.
+	assert.Equal(t, maxCaptures+5, captures[0].Items)
+	assert.Equal(t, 6, captures[maxCaptures-1].Items)
+}
+
+func TestPointCaptureTruncated(t *testing.T) {
+	p := NewPoint("logs", "receiver", "otlp")
+	p.Arm(time.Minute)
+	ld := testdata.GenerateLogsOneLogRecord()
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Body().SetStringVal(strings.Repeat("x", 2*maxCaptureSize))
+
+	require.NoError(t, NewLogs(p, consumertest.NewNop()).ConsumeLogs(context.Background(), ld))
+
+	captures := p.Captures()
+	require.Len(t, captures, 1)
+	assert.True(t, captures[0].Truncated)
+	assert.Len(t, captures[0].Text, maxCaptureSize)
+}
+
+// mutatingMetrics clears the data it consumes, like a processor mutating data would.
+type mutatingMetrics struct {
+	consumertest.MetricsSink
+}
+
+func (m *mutatingMetrics) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: true}
+}
+
+func (m *mutatingMetrics) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	md.ResourceMetrics().RemoveIf(func(pdata.ResourceMetrics) bool { return true })
+	return m.MetricsSink.ConsumeMetrics(ctx, md)
+}
+
+func TestPointCapturesBeforeConsume(t *testing.T) {
+	p := NewPoint("metrics", "processor", "filter")
+	p.Arm(time.Minute)
+	mc := NewMetrics(p, &mutatingMetrics{})
+	assert.True(t, mc.Capabilities().MutatesData)
+
+	require.NoError(t, mc.ConsumeMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+
+	captures := p.Captures()
+	require.Len(t, captures, 1)
+	assert.Equal(t, 2, captures[0].Items)
+	assert.Contains(t, captures[0].Text, "Metric #0")
+}
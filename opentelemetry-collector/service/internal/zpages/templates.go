@@ -36,6 +36,8 @@ var (
 	footerTemplate          = parseTemplate("footer")
 	pipelinesTableTemplate  = parseTemplate("pipelines_table")
 	propertiesTableTemplate = parseTemplate("properties_table")
+	tapTableTemplate        = parseTemplate("tap_table")
+	tapCapturesTemplate     = parseTemplate("tap_captures")
 )
 
 func parseTemplate(name string) *template.Template {
@@ -54,6 +56,8 @@ func parseTemplate(name string) *template.Template {
 // HeaderData contains data for the header template.
 type HeaderData struct {
 	Title string
+	// Refresh is the number of seconds after which the page reloads, 0 disables reloading.
+	Refresh int
 }
 
 // WriteHTMLHeader writes the header.
@@ -134,6 +138,54 @@ func WriteHTMLPropertiesTable(w io.Writer, chd PropertiesTableData) {
 	}
 }
 
+// TapTableData contains data for the tap points table template.
+type TapTableData struct {
+	ComponentEndpoint string
+	Rows              []TapTableRowData
+}
+
+// TapTableRowData contains data for one row in the tap points table template.
+type TapTableRowData struct {
+	Pipeline    string
+	Kind        string
+	Component   string
+	Items       int64
+	Batches     int64
+	FailedItems int64
+	Rate        float64
+}
+
+// WriteHTMLTapTable writes the table of tap points with their throughput counters.
+// It does not write the header or footer.
+func WriteHTMLTapTable(w io.Writer, ttd TapTableData) {
+	if err := tapTableTemplate.Execute(w, ttd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
+// TapCapturesData contains data for the tap captures template.
+type TapCapturesData struct {
+	ArmedFor string
+	Refresh  int
+	Captures []TapCaptureData
+}
+
+// TapCaptureData contains data for one batch in the tap captures template.
+type TapCaptureData struct {
+	Time      string
+	Items     int
+	Text      string
+	Truncated bool
+	Err       string
+}
+
+// WriteHTMLTapCaptures writes the batches captured by a tap point.
+func WriteHTMLTapCaptures(w io.Writer, tcd TapCapturesData) {
+	if err := tapCapturesTemplate.Execute(w, tcd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
 // WriteHTMLFooter writes the footer.
 func WriteHTMLFooter(w io.Writer) {
 	if err := footerTemplate.Execute(w, nil); err != nil {
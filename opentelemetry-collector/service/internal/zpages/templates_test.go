@@ -82,6 +82,36 @@ func TestNoCrash(t *testing.T) {
 	assert.NotPanics(t, func() {
 		WriteHTMLPropertiesTable(buf, PropertiesTableData{Name: "Bar", Properties: [][2]string{{"key", "value"}}})
 	})
+	assert.NotPanics(t, func() {
+		WriteHTMLTapTable(buf, TapTableData{
+			ComponentEndpoint: "pagez",
+			Rows: []TapTableRowData{{
+				Pipeline:  "traces",
+				Kind:      "processor",
+				Component: "batch",
+				Items:     10,
+				Batches:   2,
+				Rate:      0.5,
+			}},
+		})
+	})
+	assert.NotPanics(t, func() {
+		WriteHTMLTapCaptures(buf, TapCapturesData{
+			ArmedFor: "1m0s",
+			Refresh:  5,
+			Captures: []TapCaptureData{{Time: "now", Items: 1, Text: "Span #0", Truncated: true, Err: "failed"}},
+		})
+	})
 	assert.NotPanics(t, func() { WriteHTMLFooter(buf) })
 	assert.NotPanics(t, func() { WriteHTMLFooter(buf) })
 }
+
+func TestWriteHTMLHeaderRefresh(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteHTMLHeader(buf, HeaderData{Title: "Foo"})
+	assert.NotContains(t, buf.String(), "http-equiv")
+
+	buf.Reset()
+	WriteHTMLHeader(buf, HeaderData{Title: "Foo", Refresh: 5})
+	assert.Contains(t, buf.String(), `<meta http-equiv="refresh" content="5">`)
+}
@@ -247,14 +247,15 @@ H4sIAAAAAAAC/7LRT8pPqbTjstHPKMnNsQMEAAD//wEFevAPAAAA
 	"/templates/header.html": {
 		name:    "header.html",
 		local:   "../templates/header.html",
-		size:    467,
+		size:    558,
 		modtime: 0,
 		compressed: `
-H4sIAAAAAAAC/5TRMU8sIRAH8P4+BY/25eC9szGGxUItLIwW11giO7uMB8wG5rxsLvfdDdnTxNhoBeFP
-fpnM3/y5fbzZPj/dicAp2pVph4guj52ELK0J4Hq7EkIIk4Cd8MGVCtzJPQ/rS3mOGDmCPR7Vtl1OJ6OX
-lyWNmHeiQOxkDVTY71mgpyxFKDB0UuvD4aBogswQIQGXWSHpwb21Xwo9Sf1d4jlCDQD8wQTmqV5pPVDm
-qkaiMYKbsCpPSTfpenAJ49w9OIaCLv6995Sr/AXtqQc1Aqc+tgn/qwv1T6czpzD3ONJ6wrxTCbPy9ROv
-vuDEoocBiqjF/5RszGuV1uhFsCujl0bMC/Vz62vzZe1hY98DAAD//7qRGmLTAQAA
+H4sIAAAAAAAC/5TSvW7bMBAH8N1PwXItRLbuUhSUOrQdOgQJAi8ZGeooXswPhTzbEAS9e0AoThxkSSYS
+/PN+4MepL3+v/+zubv4xR8F3G1UH5nUcWg6Rd8qB7rsNY4ypAKSZcToXoJYfyDY/+RrNc8PQMnELNkNx
+y3JR4IjGBh4PeGx5XnPOTIoEkVo+z69FFxjE/owQkodunsWuTpZFyXVlTT3GPcvgW15cymQOxNCkyJnL
+YFsu5el0EmmESOAhAOVJYJJWH+sugSZx+V6iyUNxAHRm6h3KLyltilTEkNLgQY9YhElBVum31QH91F5p
+gozaf/1vUiz8E7RJPYgBKPS+nvC7+CG+yfDMCYw9DqkZMe5FwChMecGLyTgS68FCZiWbj5KVeSi8U3IV
+uo2S62er+9RPtRW2b57dbbsnAAAA//8DABXho0QuAgAA
 `,
 	},
 
@@ -289,6 +290,36 @@ AAA=
 `,
 	},
 
+	"/templates/tap_captures.html": {
+		name:    "tap_captures.html",
+		local:   "../templates/tap_captures.html",
+		size:    414,
+		modtime: 0,
+		compressed: `
+H4sIAAAAAAAC/1yPwWrrMBBF9/6KIZi3ylP2qSIopYVuuij5AUUa24JYEiOlJAzz78VW00BXYi7nzhzp
+bF5srhcKcYQ6IXhbLWRbSgsoXcYJ6hQKuDTnFDFWGBIBs3qmGf1bIpFtI7IdEQgHwjJhAfxCui3gZ4tE
+itK7bDrm/xAGiKmCatexiHQ6m4/UBFxLPdywPjoYvUjHTDaOCH2IHq9b6B3sD38X6ZNh7p06hhlF9O5k
+4N/sbZmeYM3fK85FBMLyMocBeqdeiUR+OV2yjVDq7YyHjUvnRHsg9Bsz2HBGv2+L1o7eLaxhXg1XX50J
+fxTwWkXuN450ic5WXH6ilKr38dElNF2b5BsAAP//AwB1bEE0ngEAAA==
+`,
+	},
+
+	"/templates/tap_table.html": {
+		name:    "tap_table.html",
+		local:   "../templates/tap_table.html",
+		size:    1547,
+		modtime: 0,
+		compressed: `
+H4sIAAAAAAAC/7RVTY/TMBC9768YmWVPNGGvxTESCCTEBe0/cOJJatUdW46h0OD/jvLZhJy2ai6Vrb55
+bzzznsKDzA1CHf4YzFhuvUK/q50sNFV7eM/EAwAAD74/9BcFhTW1k5Q9gzS6osxgGQTPxQ/t0GhCnuaC
+p0EtqsQT5bX70P/+nV9W0JVAgRTQtxLfNakN6T/bk7OEFDbU+BbwVG/I/0mG4oBbKnyV2qCCrR/S8af/
+KfB0dGPTPErYZ5BMS/tCyllNIcYB4CVVCI/enjUp/P2uO3Y1L/ZcD7AeugNdAv5CusJn/w8xmJIii2Pl
+7U9Se3iDiEzMmNDUuC4VTYOkYBfjYlBN08olY3Ji7F76uhGOJG02biPgEg4ey4y1M43x48UN/ZA8Ybbq
+8elSjCOfA6Y9LBBHTSpbdMjEuoCnUtzQ+eAZ5nV1CBNvZ5wY78Y3JOqOjH2C7tin85pCCext8lyyzsLJ
+iwyjn66ls/Rgu4sHnnbfAPEPAAD//wMA2V0CzQsGAAA=
+`,
+	},
+
 	"/templates": {
 		name:  "templates",
 		local: `../templates/`,
@@ -305,5 +336,7 @@ var _escDirs = map[string][]os.FileInfo{
 		_escData["/templates/header.html"],
 		_escData["/templates/pipelines_table.html"],
 		_escData["/templates/properties_table.html"],
+		_escData["/templates/tap_captures.html"],
+		_escData["/templates/tap_table.html"],
 	},
 }
@@ -15,16 +15,40 @@
 package parserprovider
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
 
 	"go.opentelemetry.io/collector/config/configparser"
+	"go.opentelemetry.io/collector/config/experimental/configsource"
 )
 
-type fileProvider struct{}
+// fileWatchSettleTime is the time the configuration file must stay unchanged after a write
+// before it is reloaded, to avoid loading a partially written file.
+const fileWatchSettleTime = 200 * time.Millisecond
+
+type fileProvider struct {
+	mu       sync.Mutex
+	fileName string
+	closeCh  chan struct{}
+	// hupCh receives SIGHUP from the first call to Get on, so that the signal is not lost,
+	// nor terminates the process, between two calls to WatchForUpdate.
+	hupCh chan os.Signal
+}
 
 // NewFile returns a new ParserProvider that reads the configuration from a file configured
 // via the --config command line flag.
+//
+// The returned ParserProvider is Watchable: WatchForUpdate returns when the file is
+// written or when the process receives SIGHUP.
 func NewFile() ParserProvider {
 	return &fileProvider{}
 }
@@ -35,6 +59,18 @@ func (fl *fileProvider) Get() (*configparser.ConfigMap, error) {
 		return nil, errors.New("config file not specified")
 	}
 
+	// Watch the file even if it cannot be loaded, to load it again once fixed.
+	fl.mu.Lock()
+	fl.fileName = fileName
+	if fl.closeCh == nil {
+		fl.closeCh = make(chan struct{})
+	}
+	if fl.hupCh == nil {
+		fl.hupCh = make(chan os.Signal, 1)
+		signal.Notify(fl.hupCh, syscall.SIGHUP)
+	}
+	fl.mu.Unlock()
+
 	cp, err := configparser.NewParserFromFile(fileName)
 	if err != nil {
 		return nil, fmt.Errorf("error loading config file %q: %v", fileName, err)
@@ -42,3 +78,66 @@ func (fl *fileProvider) Get() (*configparser.ConfigMap, error) {
 
 	return cp, nil
 }
+
+// WatchForUpdate blocks until the configuration file is written or the process receives
+// SIGHUP. It returns configsource.ErrSessionClosed once the provider is closed.
+//
+// The directory of the file is watched, rather than the file itself, so that editors
+// replacing the file with a new one are handled. If the directory cannot be watched only
+// SIGHUP triggers an update.
+func (fl *fileProvider) WatchForUpdate() error {
+	fl.mu.Lock()
+	fileName, closeCh, hupCh := fl.fileName, fl.closeCh, fl.hupCh
+	fl.mu.Unlock()
+	if closeCh == nil {
+		// Either Get was never called or the provider was already closed.
+		return configsource.ErrSessionClosed
+	}
+
+	var eventsCh chan fsnotify.Event
+	var errorsCh chan error
+	if watcher, err := fsnotify.NewWatcher(); err == nil {
+		defer watcher.Close()
+		if err = watcher.Add(filepath.Dir(fileName)); err == nil {
+			eventsCh, errorsCh = watcher.Events, watcher.Errors
+		}
+	}
+
+	fileName = filepath.Clean(fileName)
+	var settleCh <-chan time.Time
+	for {
+		select {
+		case <-closeCh:
+			return configsource.ErrSessionClosed
+		case <-hupCh:
+			return nil
+		case <-settleCh:
+			return nil
+		case event, ok := <-eventsCh:
+			if !ok {
+				eventsCh = nil
+				continue
+			}
+			if filepath.Clean(event.Name) == fileName && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				settleCh = time.After(fileWatchSettleTime)
+			}
+		case _, ok := <-errorsCh:
+			// Errors are only drained so the watcher keeps delivering events.
+			if !ok {
+				errorsCh = nil
+			}
+		}
+	}
+}
+
+// Close ends the current WatchForUpdate, if any. Get must be called again before watching
+// for updates.
+func (fl *fileProvider) Close(context.Context) error {
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+	if fl.closeCh != nil {
+		close(fl.closeCh)
+		fl.closeCh = nil
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parserprovider
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/experimental/configsource"
+)
+
+func TestFile_WatchForUpdate(t *testing.T) {
+	fileName := copyConfigFile(t)
+	fl := NewFile()
+	cp, err := fl.Get()
+	require.NoError(t, err)
+	require.NotNil(t, cp)
+
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- fl.(Watchable).WatchForUpdate()
+	}()
+
+	// Keep writing until the watcher is in place.
+	require.Eventually(t, func() bool {
+		f, err := os.OpenFile(fileName, os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = f.WriteString("\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		select {
+		case err = <-watchErr:
+			assert.NoError(t, err)
+			return true
+		case <-time.After(2 * fileWatchSettleTime):
+			return false
+		}
+	}, 10*time.Second, time.Millisecond)
+
+	assert.NoError(t, fl.(Closeable).Close(context.Background()))
+}
+
+func TestFile_WatchForUpdateSIGHUP(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP cannot be sent on windows")
+	}
+
+	copyConfigFile(t)
+	fl := NewFile()
+	_, err := fl.Get()
+	require.NoError(t, err)
+
+	// SIGHUP is caught from Get on, even if it is sent before WatchForUpdate.
+	proc, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, proc.Signal(syscall.SIGHUP))
+
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- fl.(Watchable).WatchForUpdate()
+	}()
+	select {
+	case err = <-watchErr:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("WatchForUpdate did not return on SIGHUP")
+	}
+
+	assert.NoError(t, fl.(Closeable).Close(context.Background()))
+}
+
+func TestFile_Close(t *testing.T) {
+	copyConfigFile(t)
+	fl := NewFile()
+
+	// Nothing to watch before Get.
+	assert.ErrorIs(t, fl.(Watchable).WatchForUpdate(), configsource.ErrSessionClosed)
+
+	_, err := fl.Get()
+	require.NoError(t, err)
+
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- fl.(Watchable).WatchForUpdate()
+	}()
+	require.NoError(t, fl.(Closeable).Close(context.Background()))
+	assert.ErrorIs(t, <-watchErr, configsource.ErrSessionClosed)
+
+	// Nothing to watch once closed, until the next Get.
+	assert.ErrorIs(t, fl.(Watchable).WatchForUpdate(), configsource.ErrSessionClosed)
+}
+
+func TestSetFlag_WatchForUpdate(t *testing.T) {
+	copyConfigFile(t)
+	sfl := NewSetFlag(NewFile())
+	_, err := sfl.Get()
+	require.NoError(t, err)
+
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- sfl.(Watchable).WatchForUpdate()
+	}()
+	require.NoError(t, sfl.(Closeable).Close(context.Background()))
+	assert.ErrorIs(t, <-watchErr, configsource.ErrSessionClosed)
+
+	// A base provider which is not watchable has nothing to watch.
+	sfl = NewSetFlag(&inMemoryProvider{})
+	assert.ErrorIs(t, sfl.(Watchable).WatchForUpdate(), configsource.ErrSessionClosed)
+	assert.NoError(t, sfl.(Closeable).Close(context.Background()))
+}
+
+// copyConfigFile copies the test configuration file to a temporary directory and sets
+// the --config flag to it.
+func copyConfigFile(t *testing.T) string {
+	content, err := os.ReadFile(filepath.Join("testdata", "otelcol-config.yaml"))
+	require.NoError(t, err)
+	fileName := filepath.Join(t.TempDir(), "otelcol-config.yaml")
+	require.NoError(t, os.WriteFile(fileName, content, 0600))
+
+	flags := new(flag.FlagSet)
+	Flags(flags)
+	require.NoError(t, flags.Parse([]string{"--config=" + fileName}))
+	return fileName
+}
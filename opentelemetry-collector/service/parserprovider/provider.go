@@ -32,9 +32,12 @@ type ParserProvider interface {
 type Watchable interface {
 	// WatchForUpdate waits for updates on any of the values retrieved from config sources.
 	// It blocks until configuration updates are received and can
-	// return an error if anything fails. WatchForUpdate is used once during the
-	// first evaluation of the configuration and is not used to watch configuration
-	// changes continuously.
+	// return an error if anything fails. WatchForUpdate watches the values retrieved by the
+	// last call to Get, the configuration is retrieved again before watching for the next
+	// updates.
+	//
+	// WatchForUpdate must return configsource.ErrSessionClosed, or an error wrapping it,
+	// once the ParserProvider is closed.
 	WatchForUpdate() error
 }
 
@@ -16,6 +16,7 @@ package parserprovider
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/magiconair/properties"
 
 	"go.opentelemetry.io/collector/config/configparser"
+	"go.opentelemetry.io/collector/config/experimental/configsource"
 )
 
 type setFlagProvider struct {
@@ -34,6 +36,9 @@ type setFlagProvider struct {
 //
 // The implementation reads set flag(s) from the cmd and concatenates them as a "properties" file.
 // Then the properties file is read and properties are set to the loaded Parser.
+//
+// The returned ParserProvider is Watchable and Closeable, forwarding to the "base" one
+// if it implements these interfaces.
 func NewSetFlag(base ParserProvider) ParserProvider {
 	return &setFlagProvider{
 		base: base,
@@ -75,3 +80,20 @@ func (sfl *setFlagProvider) Get() (*configparser.ConfigMap, error) {
 	}
 	return cp, cp.MergeStringMap(prop)
 }
+
+// WatchForUpdate forwards to the base ParserProvider, if it is Watchable, and returns
+// configsource.ErrSessionClosed otherwise since there is nothing to watch.
+func (sfl *setFlagProvider) WatchForUpdate() error {
+	if watchable, ok := sfl.base.(Watchable); ok {
+		return watchable.WatchForUpdate()
+	}
+	return configsource.ErrSessionClosed
+}
+
+// Close forwards to the base ParserProvider, if it is Closeable.
+func (sfl *setFlagProvider) Close(ctx context.Context) error {
+	if closeable, ok := sfl.base.(Closeable); ok {
+		return closeable.Close(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/service/internal/builder"
+)
+
+// errFullReloadRequired is returned by reload when the configuration changes cannot be
+// applied without rebuilding the whole service.
+var errFullReloadRequired = errors.New("configuration changes require rebuilding the whole service")
+
+// reload returns a new service running the given configuration. Only the components affected
+// by the configuration changes are rebuilt, the other ones are moved to the new service, so
+// that, for instance, unchanged receivers keep listening. The pipelines whose processors got
+// the exporters from the host are rebuilt whenever an exporter changes or is removed.
+//
+// The current service keeps running if the changed components cannot be built. Otherwise, the
+// retiring components are shutdown and the new ones are started, any error at this stage leaves
+// the returned service partially started. Changes to the extensions, and configurations with
+// connectors linking the pipelines, cannot be applied this way and errFullReloadRequired is returned.
+func (srv *service) reload(ctx context.Context, cfg *config.Config) (*service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if srv.config == nil ||
+		len(srv.config.Connectors) != 0 || len(cfg.Connectors) != 0 ||
+		!reflect.DeepEqual(srv.config.Extensions, cfg.Extensions) ||
+		!reflect.DeepEqual(srv.config.Service.Extensions, cfg.Service.Extensions) {
+		return nil, errFullReloadRequired
+	}
+
+	changedExps := changedExporters(srv.config, cfg)
+	changedPipes := changedPipelines(srv.config, cfg, changedExps)
+	if len(changedExps) != 0 || removedExporters(srv.config, cfg) {
+		// The processors getting the exporters from the host, like the routing processor, may
+		// keep references to retiring exporters outside of their pipeline.
+		for name := range cfg.Service.Pipelines {
+			if pipeline, ok := srv.config.Service.Pipelines[name]; ok && srv.builtPipelines[pipeline].UsesExporters() {
+				changedPipes[name] = true
+			}
+		}
+	}
+	changedRcvs := changedReceivers(srv.config, cfg, changedPipes)
+
+	newSrv := &service{
+		factories:           srv.factories,
+		buildInfo:           srv.buildInfo,
+		config:              cfg,
+		logger:              srv.logger,
+		tracerProvider:      srv.tracerProvider,
+		zPagesSpanProcessor: srv.zPagesSpanProcessor,
+		asyncErrorChannel:   srv.asyncErrorChannel,
+		builtExtensions:     srv.builtExtensions,
+		current:             srv.current,
+	}
+
+	// Build the changed components only, from copies of the configuration restricted to them,
+	// starting from the exporters as buildPipelines does.
+	expCfg := *cfg
+	expCfg.Exporters = make(config.Exporters)
+	for expID := range changedExps {
+		expCfg.Exporters[expID] = cfg.Exporters[expID]
+	}
+	newExporters, err := builder.BuildExporters(srv.logger, srv.tracerProvider, srv.buildInfo, &expCfg, srv.factories.Exporters)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build builtExporters: %w", err)
+	}
+	newSrv.builtExporters = make(builder.Exporters, len(cfg.Exporters))
+	for expID, exp := range newExporters {
+		newSrv.builtExporters[expID] = exp
+	}
+	for expID := range cfg.Exporters {
+		if !changedExps[expID] {
+			newSrv.builtExporters[expID] = srv.builtExporters[expID]
+		}
+	}
+
+	pipeCfg := *cfg
+	pipeCfg.Service.Pipelines = make(config.Pipelines)
+	for name := range changedPipes {
+		pipeCfg.Service.Pipelines[name] = cfg.Service.Pipelines[name]
+	}
+	newPipelines, _, err := builder.BuildPipelines(srv.logger, srv.tracerProvider, srv.buildInfo, &pipeCfg, newSrv.builtExporters, srv.factories.Processors, srv.factories.Connectors)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build pipelines: %w", err)
+	}
+	newSrv.builtPipelines = make(builder.BuiltPipelines, len(cfg.Service.Pipelines))
+	for pipeline, bp := range newPipelines {
+		newSrv.builtPipelines[pipeline] = bp
+	}
+	for name, pipeline := range cfg.Service.Pipelines {
+		if !changedPipes[name] {
+			newSrv.builtPipelines[pipeline] = srv.builtPipelines[srv.config.Service.Pipelines[name]]
+		}
+	}
+
+	rcvCfg := *cfg
+	rcvCfg.Receivers = make(config.Receivers)
+	for rcvID := range changedRcvs {
+		rcvCfg.Receivers[rcvID] = cfg.Receivers[rcvID]
+	}
+	newReceivers, err := builder.BuildReceivers(srv.logger, srv.tracerProvider, srv.buildInfo, &rcvCfg, newSrv.builtPipelines, srv.factories.Receivers)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build receivers: %w", err)
+	}
+	newSrv.builtReceivers = make(builder.Receivers, len(cfg.Receivers))
+	for rcvID, rcv := range newReceivers {
+		newSrv.builtReceivers[rcvID] = rcv
+	}
+	for rcvID := range cfg.Receivers {
+		if rcv, ok := srv.builtReceivers[rcvID]; ok && !changedRcvs[rcvID] {
+			newSrv.builtReceivers[rcvID] = rcv
+		}
+	}
+
+	srv.logger.Info("Reloading the service...",
+		zap.Int("receivers", len(changedRcvs)),
+		zap.Int("pipelines", len(changedPipes)),
+		zap.Int("exporters", len(changedExps)))
+
+	// The extensions kept running now reach the new service.
+	srv.current.Store(newSrv)
+
+	// Shutdown the retiring components, in the same order as shutdownPipelines.
+	retiringReceivers := make(builder.Receivers)
+	for rcvID, rcv := range srv.builtReceivers {
+		if _, ok := cfg.Receivers[rcvID]; !ok || changedRcvs[rcvID] {
+			retiringReceivers[rcvID] = rcv
+		}
+	}
+	retiringPipelines := make(builder.BuiltPipelines)
+	for name, pipeline := range srv.config.Service.Pipelines {
+		if _, ok := cfg.Service.Pipelines[name]; !ok || changedPipes[name] {
+			retiringPipelines[pipeline] = srv.builtPipelines[pipeline]
+		}
+	}
+	retiringExporters := make(builder.Exporters)
+	for expID, exp := range srv.builtExporters {
+		if _, ok := cfg.Exporters[expID]; !ok || changedExps[expID] {
+			retiringExporters[expID] = exp
+		}
+	}
+
+	if err = retiringReceivers.ShutdownAll(ctx); err != nil {
+		return newSrv, fmt.Errorf("failed to stop retiring receivers: %w", err)
+	}
+	if err = retiringPipelines.ShutdownProcessors(ctx); err != nil {
+		return newSrv, fmt.Errorf("failed to shutdown retiring processors: %w", err)
+	}
+	if err = retiringExporters.ShutdownAll(ctx); err != nil {
+		return newSrv, fmt.Errorf("failed to shutdown retiring exporters: %w", err)
+	}
+
+	// Start the new components, in the same order as startPipelines.
+	if err = newExporters.StartAll(ctx, newSrv); err != nil {
+		return newSrv, fmt.Errorf("cannot start builtExporters: %w", err)
+	}
+	if err = newPipelines.StartProcessors(ctx, newSrv); err != nil {
+		return newSrv, fmt.Errorf("cannot start processors: %w", err)
+	}
+	if err = newReceivers.StartAll(ctx, newSrv); err != nil {
+		return newSrv, fmt.Errorf("cannot start receivers: %w", err)
+	}
+
+	return newSrv, nil
+}
+
+// changedExporters returns the IDs of the exporters of newCfg which must be built: the new
+// exporters and the ones whose configuration or consumed data types changed.
+func changedExporters(oldCfg, newCfg *config.Config) map[config.ComponentID]bool {
+	oldDataTypes := exportersDataTypes(oldCfg)
+	newDataTypes := exportersDataTypes(newCfg)
+
+	changed := make(map[config.ComponentID]bool)
+	for expID, expCfg := range newCfg.Exporters {
+		oldExpCfg, ok := oldCfg.Exporters[expID]
+		if !ok || !reflect.DeepEqual(oldExpCfg, expCfg) || !reflect.DeepEqual(oldDataTypes[expID], newDataTypes[expID]) {
+			changed[expID] = true
+		}
+	}
+	return changed
+}
+
+// removedExporters indicates whether exporters of oldCfg are not part of newCfg.
+func removedExporters(oldCfg, newCfg *config.Config) bool {
+	for expID := range oldCfg.Exporters {
+		if _, ok := newCfg.Exporters[expID]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// exportersDataTypes returns the data types consumed by each exporter of cfg.
+func exportersDataTypes(cfg *config.Config) map[config.ComponentID]map[config.DataType]bool {
+	result := make(map[config.ComponentID]map[config.DataType]bool)
+	for _, pipeline := range cfg.Service.Pipelines {
+		for _, expID := range pipeline.Exporters {
+			if _, ok := result[expID]; !ok {
+				result[expID] = make(map[config.DataType]bool)
+			}
+			result[expID][pipeline.InputType] = true
+		}
+	}
+	return result
+}
+
+// changedPipelines returns the names of the pipelines of newCfg which must be built: the new
+// pipelines, the ones whose processors or exporters changed and the ones with a changed exporter.
+func changedPipelines(oldCfg, newCfg *config.Config, changedExps map[config.ComponentID]bool) map[string]bool {
+	changed := make(map[string]bool)
+	for name, pipeline := range newCfg.Service.Pipelines {
+		if !samePipeline(oldCfg, newCfg, oldCfg.Service.Pipelines[name], pipeline, changedExps) {
+			changed[name] = true
+		}
+	}
+	return changed
+}
+
+func samePipeline(oldCfg, newCfg *config.Config, oldPipeline, newPipeline *config.Pipeline, changedExps map[config.ComponentID]bool) bool {
+	if oldPipeline == nil ||
+		oldPipeline.InputType != newPipeline.InputType ||
+		!reflect.DeepEqual(oldPipeline.Processors, newPipeline.Processors) ||
+		!reflect.DeepEqual(oldPipeline.Exporters, newPipeline.Exporters) {
+		return false
+	}
+	for _, procID := range newPipeline.Processors {
+		if !reflect.DeepEqual(oldCfg.Processors[procID], newCfg.Processors[procID]) {
+			return false
+		}
+	}
+	for _, expID := range newPipeline.Exporters {
+		if changedExps[expID] {
+			return false
+		}
+	}
+	return true
+}
+
+// changedReceivers returns the IDs of the receivers of newCfg which must be built: the new
+// receivers, the ones whose configuration or attached pipelines changed and the ones attached
+// to a changed pipeline.
+func changedReceivers(oldCfg, newCfg *config.Config, changedPipes map[string]bool) map[config.ComponentID]bool {
+	oldAttached := receiversPipelines(oldCfg)
+	newAttached := receiversPipelines(newCfg)
+
+	changed := make(map[config.ComponentID]bool)
+	for rcvID, rcvCfg := range newCfg.Receivers {
+		oldRcvCfg, ok := oldCfg.Receivers[rcvID]
+		if !ok || !reflect.DeepEqual(oldRcvCfg, rcvCfg) || !reflect.DeepEqual(oldAttached[rcvID], newAttached[rcvID]) {
+			changed[rcvID] = true
+			continue
+		}
+		for _, name := range newAttached[rcvID] {
+			if changedPipes[name] {
+				changed[rcvID] = true
+				break
+			}
+		}
+	}
+	return changed
+}
+
+// receiversPipelines returns the sorted names of the pipelines attached to each receiver of cfg.
+func receiversPipelines(cfg *config.Config) map[config.ComponentID][]string {
+	result := make(map[config.ComponentID][]string)
+	for name, pipeline := range cfg.Service.Pipelines {
+		for _, rcvID := range pipeline.Receivers {
+			result[rcvID] = append(result[rcvID], name)
+		}
+	}
+	for _, names := range result {
+		sort.Strings(names)
+	}
+	return result
+}
@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+func TestService_reload(t *testing.T) {
+	tests := []struct {
+		name             string
+		update           func(cfg *config.Config)
+		changedPipelines []string
+		changedReceiver  bool
+		changedExporter  bool
+	}{
+		{
+			name:   "unchanged",
+			update: func(cfg *config.Config) {},
+		},
+		{
+			name: "changed_pipeline",
+			update: func(cfg *config.Config) {
+				cfg.Service.Pipelines["metrics"].Processors = nil
+			},
+			changedPipelines: []string{"metrics"},
+			changedReceiver:  true,
+		},
+		{
+			name: "added_pipeline",
+			update: func(cfg *config.Config) {
+				rcvID := config.NewIDWithName("nop", "2")
+				rcvCfg := config.NewReceiverSettings(rcvID)
+				cfg.Receivers[rcvID] = &rcvCfg
+				cfg.Service.Pipelines["traces/2"] = &config.Pipeline{
+					Name:      "traces/2",
+					InputType: config.TracesDataType,
+					Receivers: []config.ComponentID{rcvID},
+					Exporters: []config.ComponentID{config.NewID("nop")},
+				}
+			},
+			changedPipelines: []string{"traces/2"},
+		},
+		{
+			name: "removed_pipeline",
+			update: func(cfg *config.Config) {
+				// The nop exporter no longer consumes logs, the other pipelines using it must be rebuilt.
+				delete(cfg.Service.Pipelines, "logs")
+			},
+			changedPipelines: []string{"metrics", "traces"},
+			changedReceiver:  true,
+			changedExporter:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := createExampleService(t)
+			require.NoError(t, srv.Start(context.Background()))
+
+			cfg := loadExampleConfig(t)
+			tt.update(cfg)
+			newSrv, err := srv.reload(context.Background(), cfg)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				assert.NoError(t, newSrv.Shutdown(context.Background()))
+			})
+
+			assert.Equal(t, cfg, newSrv.config)
+			assert.Len(t, newSrv.builtPipelines, len(cfg.Service.Pipelines))
+			for name, pipeline := range cfg.Service.Pipelines {
+				oldPipeline := srv.builtPipelines[srv.config.Service.Pipelines[name]]
+				require.NotNil(t, newSrv.builtPipelines[pipeline], name)
+				if contains(tt.changedPipelines, name) {
+					assert.NotSame(t, oldPipeline, newSrv.builtPipelines[pipeline], name)
+				} else {
+					assert.Same(t, oldPipeline, newSrv.builtPipelines[pipeline], name)
+				}
+			}
+
+			rcvID := config.NewID("nop")
+			if tt.changedReceiver {
+				assert.NotSame(t, srv.builtReceivers[rcvID], newSrv.builtReceivers[rcvID])
+			} else {
+				assert.Same(t, srv.builtReceivers[rcvID], newSrv.builtReceivers[rcvID])
+			}
+
+			expID := config.NewID("nop")
+			if tt.changedExporter {
+				assert.NotSame(t, srv.builtExporters[expID], newSrv.builtExporters[expID])
+			} else {
+				assert.Same(t, srv.builtExporters[expID], newSrv.builtExporters[expID])
+			}
+
+			extID := config.NewID("nop")
+			assert.Same(t, srv.builtExtensions[extID], newSrv.builtExtensions[extID])
+		})
+	}
+}
+
+func TestService_reloadChangedExtensions(t *testing.T) {
+	srv := createExampleService(t)
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	cfg := loadExampleConfig(t)
+	cfg.Service.Extensions = nil
+	newSrv, err := srv.reload(context.Background(), cfg)
+	assert.ErrorIs(t, err, errFullReloadRequired)
+	assert.Nil(t, newSrv)
+}
+
+func TestService_reloadWithConnectors(t *testing.T) {
+	srv := createExampleService(t)
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	cfg := loadExampleConfig(t)
+	connID := config.NewIDWithName("nop", "conn")
+	connCfg := config.NewConnectorSettings(connID)
+	cfg.Connectors = config.Connectors{connID: &connCfg}
+	cfg.Service.Pipelines["traces"].Exporters = append(cfg.Service.Pipelines["traces"].Exporters, connID)
+	cfg.Service.Pipelines["metrics"].Receivers = append(cfg.Service.Pipelines["metrics"].Receivers, connID)
+	newSrv, err := srv.reload(context.Background(), cfg)
+	assert.ErrorIs(t, err, errFullReloadRequired)
+	assert.Nil(t, newSrv)
+}
+
+func TestService_reloadPipelinesUsingExporters(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+	factories.Processors["exporters"] = newExportersProcessorFactory()
+
+	// The traces are processed by a processor getting the exporters from the host.
+	procID := config.NewID("exporters")
+	procCfg := config.NewProcessorSettings(procID)
+	expID := config.NewIDWithName("nop", "2")
+	expCfg := config.NewExporterSettings(expID)
+	oldCfg := loadExampleConfig(t)
+	oldCfg.Processors[procID] = &procCfg
+	oldCfg.Service.Pipelines["traces"].Processors = []config.ComponentID{procID}
+	oldCfg.Exporters[expID] = &expCfg
+	oldCfg.Service.Pipelines["logs"].Exporters = append(oldCfg.Service.Pipelines["logs"].Exporters, expID)
+	srv, err := newService(&svcSettings{
+		BuildInfo: component.DefaultBuildInfo(),
+		Factories: factories,
+		Logger:    zap.NewNop(),
+		Config:    oldCfg,
+	})
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	// Remove the second exporter, the traces pipeline must not keep a reference to it.
+	cfg := loadExampleConfig(t)
+	cfg.Processors[procID] = &procCfg
+	cfg.Service.Pipelines["traces"].Processors = []config.ComponentID{procID}
+	newSrv, err := srv.reload(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, newSrv.Shutdown(context.Background()))
+	})
+
+	for name, changed := range map[string]bool{"traces": true, "metrics": false, "logs": true} {
+		oldPipeline := srv.builtPipelines[oldCfg.Service.Pipelines[name]]
+		if changed {
+			assert.NotSame(t, oldPipeline, newSrv.builtPipelines[cfg.Service.Pipelines[name]], name)
+		} else {
+			assert.Same(t, oldPipeline, newSrv.builtPipelines[cfg.Service.Pipelines[name]], name)
+		}
+	}
+}
+
+func TestService_reloadZPages(t *testing.T) {
+	srv := createExampleService(t)
+	require.NoError(t, srv.Start(context.Background()))
+
+	mux := http.NewServeMux()
+	srv.RegisterZPages(mux, "/debug")
+
+	cfg := loadExampleConfig(t)
+	delete(cfg.Service.Pipelines, "logs")
+	newSrv, err := srv.reload(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, newSrv.Shutdown(context.Background()))
+	})
+
+	// The pages registered by the old service show the pipelines of the new one.
+	body := getZPage(t, mux, "/debug/pipelinez")
+	assert.Contains(t, body, "traces")
+	assert.NotContains(t, body, "logs")
+	body = getZPage(t, mux, "/debug/tapz")
+	assert.NotContains(t, body, "logs")
+}
+
+func TestService_reloadBuildError(t *testing.T) {
+	srv := createExampleService(t)
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	cfg := loadExampleConfig(t)
+	unknownID := config.NewID("unknown")
+	expCfg := config.NewExporterSettings(unknownID)
+	cfg.Exporters[unknownID] = &expCfg
+	cfg.Service.Pipelines["traces"].Exporters = append(cfg.Service.Pipelines["traces"].Exporters, unknownID)
+	newSrv, err := srv.reload(context.Background(), cfg)
+	assert.Error(t, err)
+	assert.Nil(t, newSrv)
+}
+
+// newExportersProcessorFactory returns the factory of a traces processor getting the exporters
+// from the host when started.
+func newExportersProcessorFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		"exporters",
+		func() config.Processor {
+			cfg := config.NewProcessorSettings(config.NewID("exporters"))
+			return &cfg
+		},
+		processorhelper.WithTraces(func(_ context.Context, _ component.ProcessorCreateSettings, cfg config.Processor, next consumer.Traces) (component.TracesProcessor, error) {
+			return processorhelper.NewTracesProcessor(cfg, next,
+				func(_ context.Context, td pdata.Traces) (pdata.Traces, error) { return td, nil },
+				processorhelper.WithStart(func(_ context.Context, host component.Host) error {
+					host.GetExporters()
+					return nil
+				}))
+		}))
+}
+
+func loadExampleConfig(t *testing.T) *config.Config {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+	cfg, err := configtest.LoadConfigAndValidate(path.Join(".", "testdata", "otelcol-nop.yaml"), factories)
+	require.NoError(t, err)
+	return cfg
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -17,6 +17,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	"go.opentelemetry.io/contrib/zpages"
 	"go.opentelemetry.io/otel/trace"
@@ -41,7 +42,12 @@ type service struct {
 	builtExporters  builder.Exporters
 	builtReceivers  builder.Receivers
 	builtPipelines  builder.BuiltPipelines
+	builtConnectors builder.Connectors
 	builtExtensions builder.Extensions
+
+	// current holds the service replacing this one when the configuration is reloaded,
+	// the extensions are kept across reloads and use it to reach the running service.
+	current *atomic.Value
 }
 
 func newService(set *svcSettings) (*service, error) {
@@ -53,7 +59,9 @@ func newService(set *svcSettings) (*service, error) {
 		tracerProvider:      set.TracerProvider,
 		zPagesSpanProcessor: set.ZPagesSpanProcessor,
 		asyncErrorChannel:   set.AsyncErrorChannel,
+		current:             &atomic.Value{},
 	}
+	srv.current.Store(srv)
 
 	if err := srv.config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -118,6 +126,8 @@ func (srv *service) GetFactory(kind component.Kind, componentType config.Type) c
 		return srv.factories.Exporters[componentType]
 	case component.KindExtension:
 		return srv.factories.Extensions[componentType]
+	case component.KindConnector:
+		return srv.factories.Connectors[componentType]
 	}
 	return nil
 }
@@ -169,8 +179,8 @@ func (srv *service) buildPipelines() error {
 	}
 
 	// Create pipelines and their processors and plug exporters to the
-	// end of the pipelines.
-	srv.builtPipelines, err = builder.BuildPipelines(srv.logger, srv.tracerProvider, srv.buildInfo, srv.config, srv.builtExporters, srv.factories.Processors)
+	// end of the pipelines, along with the connectors linking them.
+	srv.builtPipelines, srv.builtConnectors, err = builder.BuildPipelines(srv.logger, srv.tracerProvider, srv.buildInfo, srv.config, srv.builtExporters, srv.factories.Processors, srv.factories.Connectors)
 	if err != nil {
 		return fmt.Errorf("cannot build pipelines: %w", err)
 	}
@@ -195,6 +205,11 @@ func (srv *service) startPipelines(ctx context.Context) error {
 		return fmt.Errorf("cannot start processors: %w", err)
 	}
 
+	srv.logger.Info("Starting connectors...")
+	if err := srv.builtConnectors.StartAll(ctx, srv); err != nil {
+		return fmt.Errorf("cannot start connectors: %w", err)
+	}
+
 	srv.logger.Info("Starting receivers...")
 	if err := srv.builtReceivers.StartAll(ctx, srv); err != nil {
 		return fmt.Errorf("cannot start receivers: %w", err)
@@ -216,10 +231,10 @@ func (srv *service) shutdownPipelines(ctx context.Context) error {
 		errs = append(errs, fmt.Errorf("failed to stop receivers: %w", err))
 	}
 
-	srv.logger.Info("Stopping processors...")
-	err = srv.builtPipelines.ShutdownProcessors(ctx)
+	srv.logger.Info("Stopping processors and connectors...")
+	err = srv.builtPipelines.ShutdownWithConnectors(ctx, srv.config, srv.builtConnectors)
 	if err != nil {
-		errs = append(errs, fmt.Errorf("failed to shutdown processors: %w", err))
+		errs = append(errs, fmt.Errorf("failed to shutdown processors and connectors: %w", err))
 	}
 
 	srv.logger.Info("Stopping exporters...")
@@ -60,6 +60,11 @@ func TestService_GetFactory(t *testing.T) {
 	factory = srv.GetFactory(component.KindExtension, "wrongtype")
 	assert.EqualValues(t, nil, factory)
 
+	factory = srv.GetFactory(component.KindConnector, "nop")
+	assert.EqualValues(t, factories.Connectors["nop"], factory)
+	factory = srv.GetFactory(component.KindConnector, "wrongtype")
+	assert.EqualValues(t, nil, factory)
+
 	// Try retrieve non existing component.Kind.
 	factory = srv.GetFactory(42, "nop")
 	assert.EqualValues(t, nil, factory)
@@ -113,3 +118,30 @@ func createExampleService(t *testing.T) *service {
 	require.NoError(t, err)
 	return srv
 }
+
+func TestService_Connectors(t *testing.T) {
+	factories, err := componenttest.NopFactories()
+	require.NoError(t, err)
+	cfg, err := configtest.LoadConfigAndValidate(path.Join(".", "testdata", "otelcol-nop.yaml"), factories)
+	require.NoError(t, err)
+
+	// Derive the metrics from the traces.
+	connID := config.NewIDWithName("nop", "conn")
+	connCfg := config.NewConnectorSettings(connID)
+	cfg.Connectors = config.Connectors{connID: &connCfg}
+	cfg.Service.Pipelines["traces"].Exporters = append(cfg.Service.Pipelines["traces"].Exporters, connID)
+	cfg.Service.Pipelines["metrics"].Receivers = append(cfg.Service.Pipelines["metrics"].Receivers, connID)
+
+	srv, err := newService(&svcSettings{
+		BuildInfo: component.DefaultBuildInfo(),
+		Factories: factories,
+		Logger:    zap.NewNop(),
+		Config:    cfg,
+	})
+	require.NoError(t, err)
+	assert.Len(t, srv.builtConnectors, 1)
+	assert.Contains(t, srv.builtConnectors, connID)
+
+	assert.NoError(t, srv.Start(context.Background()))
+	assert.NoError(t, srv.Shutdown(context.Background()))
+}
@@ -15,6 +15,7 @@
 package service
 
 import (
+	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -117,8 +118,14 @@ func (tel *colTelemetry) initOnce(asyncErrorChannel chan<- error, ballastSizeByt
 		Handler: mux,
 	}
 
+	// Listen before returning, so that the metrics are served once the collector is running.
+	listener, err := net.Listen("tcp", metricsAddr)
+	if err != nil {
+		return err
+	}
+
 	go func() {
-		serveErr := tel.server.ListenAndServe()
+		serveErr := tel.server.Serve(listener)
 		if serveErr != nil && serveErr != http.ErrServerClosed {
 			asyncErrorChannel <- serveErr
 		}
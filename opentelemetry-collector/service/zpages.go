@@ -18,11 +18,14 @@ import (
 	"net/http"
 	"path"
 	"sort"
+	"time"
 
 	otelzpages "go.opentelemetry.io/contrib/zpages"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/internal/version"
+	"go.opentelemetry.io/collector/service/internal/tap"
 	"go.opentelemetry.io/collector/service/internal/zpages"
 )
 
@@ -31,20 +34,36 @@ const (
 	servicezPath   = "servicez"
 	pipelinezPath  = "pipelinez"
 	extensionzPath = "extensionz"
+	tapzPath       = "tapz"
 
 	zPipelineName  = "zpipelinename"
 	zComponentName = "zcomponentname"
 	zComponentKind = "zcomponentkind"
 	zExtensionName = "zextensionname"
+
+	// tapArmDuration is how long a tap point keeps capturing data after its page was served.
+	tapArmDuration = time.Minute
+	// tapRefreshSeconds is the reload period of the page of a tap point.
+	tapRefreshSeconds = 5
 )
 
+// RegisterZPages registers the pages of the service. They are served by the service running
+// the current configuration, since the zpages extension is kept when the configuration is reloaded.
 func (srv *service) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
 	mux.Handle(path.Join(pathPrefix, tracezPath), otelzpages.NewTracezHandler(srv.zPagesSpanProcessor))
-	mux.HandleFunc(path.Join(pathPrefix, servicezPath), srv.handleServicezRequest)
-	mux.HandleFunc(path.Join(pathPrefix, pipelinezPath), srv.handlePipelinezRequest)
-	mux.HandleFunc(path.Join(pathPrefix, extensionzPath), func(w http.ResponseWriter, r *http.Request) {
-		handleExtensionzRequest(srv, w, r)
-	})
+	mux.HandleFunc(path.Join(pathPrefix, servicezPath), srv.currentHandler((*service).handleServicezRequest))
+	mux.HandleFunc(path.Join(pathPrefix, pipelinezPath), srv.currentHandler((*service).handlePipelinezRequest))
+	mux.HandleFunc(path.Join(pathPrefix, tapzPath), srv.currentHandler((*service).handleTapzRequest))
+	mux.HandleFunc(path.Join(pathPrefix, extensionzPath), srv.currentHandler(func(current *service, w http.ResponseWriter, r *http.Request) {
+		handleExtensionzRequest(current, w, r)
+	}))
+}
+
+// currentHandler returns a handler calling h with the service running the current configuration.
+func (srv *service) currentHandler(h func(*service, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h(srv.current.Load().(*service), w, r)
+	}
 }
 
 func (srv *service) handleServicezRequest(w http.ResponseWriter, r *http.Request) {
@@ -61,6 +80,11 @@ func (srv *service) handleServicezRequest(w http.ResponseWriter, r *http.Request
 		ComponentEndpoint: extensionzPath,
 		Link:              true,
 	})
+	zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+		Name:              "Tap",
+		ComponentEndpoint: tapzPath,
+		Link:              true,
+	})
 	zpages.WriteHTMLPropertiesTable(w, zpages.PropertiesTableData{Name: "Build And Runtime", Properties: version.RuntimeVar()})
 	zpages.WriteHTMLFooter(w)
 }
@@ -81,6 +105,11 @@ func (srv *service) handlePipelinezRequest(w http.ResponseWriter, r *http.Reques
 		zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
 			Name: componentKind + ": " + fullName,
 		})
+		zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+			Name:              "Tap the data passing through " + componentName,
+			ComponentEndpoint: tapzPath + "?" + r.Form.Encode(),
+			Link:              true,
+		})
 		// TODO: Add config + status info.
 	}
 	zpages.WriteHTMLFooter(w)
@@ -155,3 +184,114 @@ func getExtensionsSummaryTableData(host component.Host) zpages.SummaryExtensions
 	})
 	return data
 }
+
+func (srv *service) handleTapzRequest(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm() // nolint:errcheck
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	pipelineName := r.Form.Get(zPipelineName)
+	componentName := r.Form.Get(zComponentName)
+	componentKind := r.Form.Get(zComponentKind)
+
+	points := srv.getTapPoints()
+	var selected *tap.Point
+	for _, p := range points {
+		if p.Pipeline == pipelineName && p.Component == componentName && p.Kind == componentKind {
+			selected = p
+			break
+		}
+	}
+
+	header := zpages.HeaderData{Title: "Tap"}
+	if selected != nil {
+		// Keep capturing for as long as someone is watching the page.
+		selected.Arm(tapArmDuration)
+		header.Refresh = tapRefreshSeconds
+	}
+	zpages.WriteHTMLHeader(w, header)
+	zpages.WriteHTMLTapTable(w, getTapTableData(points))
+	if selected != nil {
+		zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{
+			Name: pipelineName + " " + componentKind + ": " + componentName,
+		})
+		zpages.WriteHTMLTapCaptures(w, getTapCapturesData(selected))
+	}
+	zpages.WriteHTMLFooter(w)
+}
+
+// getTapPoints returns the tap points of all pipelines, sorted by pipeline, then
+// receivers and connectors, processors in the pipeline order and exporters.
+func (srv *service) getTapPoints() []*tap.Point {
+	var points []*tap.Point
+	rcvIDs := make([]config.ComponentID, 0, len(srv.builtReceivers))
+	for rcvID := range srv.builtReceivers {
+		rcvIDs = append(rcvIDs, rcvID)
+	}
+	sort.Slice(rcvIDs, func(i, j int) bool {
+		return rcvIDs[i].String() < rcvIDs[j].String()
+	})
+	for _, rcvID := range rcvIDs {
+		points = append(points, srv.builtReceivers[rcvID].Taps...)
+	}
+	connIDs := make([]config.ComponentID, 0, len(srv.builtConnectors))
+	for connID := range srv.builtConnectors {
+		connIDs = append(connIDs, connID)
+	}
+	sort.Slice(connIDs, func(i, j int) bool {
+		return connIDs[i].String() < connIDs[j].String()
+	})
+	for _, connID := range connIDs {
+		points = append(points, srv.builtConnectors[connID].Taps...)
+	}
+	for _, p := range srv.builtPipelines {
+		points = append(points, p.Taps...)
+	}
+
+	kindOrder := map[string]int{"receiver": 0, "processor": 1, "exporter": 2}
+	sort.SliceStable(points, func(i, j int) bool {
+		if points[i].Pipeline != points[j].Pipeline {
+			return points[i].Pipeline < points[j].Pipeline
+		}
+		return kindOrder[points[i].Kind] < kindOrder[points[j].Kind]
+	})
+	return points
+}
+
+func getTapTableData(points []*tap.Point) zpages.TapTableData {
+	data := zpages.TapTableData{
+		ComponentEndpoint: tapzPath,
+		Rows:              make([]zpages.TapTableRowData, 0, len(points)),
+	}
+	for _, p := range points {
+		stats := p.Stats()
+		data.Rows = append(data.Rows, zpages.TapTableRowData{
+			Pipeline:    p.Pipeline,
+			Kind:        p.Kind,
+			Component:   p.Component,
+			Items:       stats.Items,
+			Batches:     stats.Batches,
+			FailedItems: stats.FailedItems,
+			Rate:        stats.Rate,
+		})
+	}
+	return data
+}
+
+func getTapCapturesData(p *tap.Point) zpages.TapCapturesData {
+	data := zpages.TapCapturesData{
+		ArmedFor: tapArmDuration.String(),
+		Refresh:  tapRefreshSeconds,
+	}
+	for _, c := range p.Captures() {
+		cd := zpages.TapCaptureData{
+			Time:      c.Time.Format(time.RFC3339Nano),
+			Items:     c.Items,
+			Text:      c.Text,
+			Truncated: c.Truncated,
+		}
+		if c.Err != nil {
+			cd.Err = c.Err.Error()
+		}
+		data.Captures = append(data.Captures, cd)
+	}
+	return data
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTapzPage(t *testing.T) {
+	srv := createExampleService(t)
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	points := srv.getTapPoints()
+	require.Len(t, points, 9)
+	for i, kind := range []string{"receiver", "processor", "exporter"} {
+		assert.Equal(t, "logs", points[i].Pipeline)
+		assert.Equal(t, kind, points[i].Kind)
+		assert.Equal(t, "nop", points[i].Component)
+	}
+
+	mux := http.NewServeMux()
+	srv.RegisterZPages(mux, "/debug")
+
+	body := getZPage(t, mux, "/debug/tapz")
+	assert.Contains(t, body, "zcomponentkind=processor")
+	assert.NotContains(t, body, "http-equiv")
+
+	body = getZPage(t, mux, "/debug/tapz?zpipelinename=traces&zcomponentname=nop&zcomponentkind=processor")
+	assert.Contains(t, body, "http-equiv")
+	assert.Contains(t, body, "No data captured yet.")
+}
+
+func getZPage(t *testing.T, handler http.Handler, url string) string {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}